@@ -1,16 +1,26 @@
 package common
 
+import "time"
+
 type Book struct {
-	ID            int     `json:"id"`
-	Title         string  `json:"title"`
-	Year          int     `json:"year"`
-	Rating        float64 `json:"rating"`
-	Author        string  `json:"author"`
-	Publisher     string  `json:"publisher"`
-	PublishedYear int     `json:"published_year"`
-	ISBN          string  `json:"isbn"`
-	Genre         string  `json:"genre"`
-	Description   string  `json:"description"`
-	CoverImage    string  `json:"cover_image"` // URL atau path ke gambar sampul
+	ID             int        `json:"id"`
+	Title          string     `json:"title"`
+	Year           int        `json:"year"`
+	Rating         float64    `json:"rating"`
+	Author         string     `json:"author"`
+	Publisher      string     `json:"publisher"`
+	PublishedYear  int        `json:"published_year"`
+	ISBN           string     `json:"isbn"`
+	Genre          string     `json:"genre"`
+	Description    string     `json:"description"`
+	CoverImage     string     `json:"cover_image"`          // URL atau path ke gambar sampul
+	Classification string     `json:"classification"`       // nomor klasifikasi DDC, e.g. "813.54"
+	CallNumber     string     `json:"call_number"`          // nomor panggil lokal, e.g. "813.54 HIR l"
+	WorkID         *int       `json:"work_id,omitempty"`    // karya yang diwakili edisi ini
+	Edition        string     `json:"edition"`              // e.g. "Cetakan ke-5"
+	Language       string     `json:"language"`             // kode bahasa, e.g. "ind" atau "eng" untuk terjemahan
+	DeletedAt      *time.Time `json:"deleted_at,omitempty"` // diisi jika buku ada di trash
+	DeletedBy      string     `json:"deleted_by,omitempty"`
+	ChangedBy      string     `json:"-"` // pelaku perubahan untuk riwayat versi, tidak disimpan di tabel books
 	// ... tambahkan field lain sesuai kebutuhan
 }
@@ -30,11 +30,57 @@ type Config struct {
 	EmailUser     string
 	EmailPassword string
 	EmailFrom     string
+	EmailDriver   string // "smtp" or "log"; "log" writes messages to the application log instead of sending them
 
-	// External service configuration (example)
-	ExternalServiceAPIKey  string
-	ExternalServiceBaseURL string
-	DatabaseURL            string
+	// Self-service configuration
+	EmailVerificationHours int    // validity of the code confirming a new email address
+	PasswordSetupURL       string // page where imported members choose their password; the token is appended as ?token=
+	PasswordSetupHours     int    // validity of the password setup link in roster welcome emails
+
+	// Metadata provider configuration (book enrichment)
+	MetadataProviders  string // comma separated, in priority order, e.g. "mirror,openlibrary"
+	OpenLibraryBaseURL string
+	MetadataMirrorPath string
+	MetadataFixtureDir string // when set, HTTP providers replay recorded responses from this directory
+	MetadataTimeout    int    // per provider, in seconds
+	MetadataRetries    int
+	DatabaseURL        string
+
+	// File storage configuration (cover images, etc.)
+	StorageDriver    string // "local" or "s3"
+	StorageLocalPath string
+	S3Endpoint       string // e.g. "https://s3.amazonaws.com" or a MinIO/stub URL
+	S3Region         string
+	S3Bucket         string
+	S3AccessKey      string
+	S3SecretKey      string
+	CoverMaxSize     int // maximum cover upload size in bytes
+
+	// Trash configuration (soft-deleted records)
+	TrashRetentionDays      int // soft-deleted records older than this are purged
+	TrashPurgeIntervalHours int
+
+	// Lending configuration
+	LoanPeriods             string // loan period in days per membership type, e.g. "Regular:14,Student:21"
+	DefaultLoanDays         int
+	ReserveOvernightDueHour int // hour of the next day when overnight course reserve loans are due
+	MaxActiveLoans          int // physical + digital loans per member; 0 disables the limit
+	MaxRenewals             int // renewals per loan; 0 disables the limit
+	FineBlockThreshold      int // fine balance that blocks borrowing until it is paid down; 0 disables fine blocks
+	BlockCheckIntervalHours int // how often fine blocks are placed and lifted for all members
+	DigitalLoanDays         int
+	DownloadLinkTTLMinutes  int
+	DigitalMaxSize          int    // maximum e-book/audiobook upload size in bytes
+	DownloadSigningKey      string // HMAC key for download links; defaults to JWT_SECRET_KEY
+
+	// Privacy configuration
+	AnonymizeIntervalMinutes int // how often expired digital loans of opted-out members are anonymized
+
+	// Membership configuration
+	MembershipMonths             int // validity of a new or renewed membership; 0 means memberships never expire
+	MembershipRenewalFee         int // default renewal fee, charged to the member's fines
+	MembershipReminderDays       int // how many days before the end date members are reminded to renew
+	MembershipCheckIntervalHours int // how often lapsed memberships are expired and reminders are sent
 }
 
 // LoadConfig loads configuration from environment variables or a .env file
@@ -66,10 +112,59 @@ func LoadConfig() (*Config, error) {
 		EmailUser:     getEnv("EMAIL_USER", "your_email_username"),
 		EmailPassword: getEnv("EMAIL_PASSWORD", "your_email_password"),
 		EmailFrom:     getEnv("EMAIL_FROM", "your_email_address"),
+		EmailDriver:   getEnv("EMAIL_DRIVER", "log"),
+
+		// Self-service configuration
+		EmailVerificationHours: getEnvAsInt("EMAIL_VERIFICATION_HOURS", 24),
+		PasswordSetupURL:       getEnv("PASSWORD_SETUP_URL", "http://localhost:8080/password/setup"),
+		PasswordSetupHours:     getEnvAsInt("PASSWORD_SETUP_HOURS", 72),
 
-		// External service configuration
-		ExternalServiceAPIKey:  getEnv("EXTERNAL_SERVICE_API_KEY", "your_api_key"),
-		ExternalServiceBaseURL: getEnv("EXTERNAL_SERVICE_BASE_URL", "https://api.example.com"),
+		// Metadata provider configuration
+		MetadataProviders:  getEnv("METADATA_PROVIDERS", "openlibrary"),
+		OpenLibraryBaseURL: getEnv("OPENLIBRARY_BASE_URL", "https://openlibrary.org"),
+		MetadataMirrorPath: getEnv("METADATA_MIRROR_PATH", "./data/metadata_mirror.json"),
+		MetadataFixtureDir: getEnv("METADATA_FIXTURE_DIR", ""),
+		MetadataTimeout:    getEnvAsInt("METADATA_TIMEOUT", 5),
+		MetadataRetries:    getEnvAsInt("METADATA_RETRIES", 2),
+
+		// File storage configuration
+		StorageDriver:    getEnv("STORAGE_DRIVER", "local"),
+		StorageLocalPath: getEnv("STORAGE_LOCAL_PATH", "./storage"),
+		S3Endpoint:       getEnv("S3_ENDPOINT", "https://s3.amazonaws.com"),
+		S3Region:         getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:         getEnv("S3_BUCKET", "perpustakaan"),
+		S3AccessKey:      getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:      getEnv("S3_SECRET_KEY", ""),
+		CoverMaxSize:     getEnvAsInt("COVER_MAX_SIZE", 5*1024*1024), // 5 MB
+
+		// Trash configuration
+		TrashRetentionDays:      getEnvAsInt("TRASH_RETENTION_DAYS", 30),
+		TrashPurgeIntervalHours: getEnvAsInt("TRASH_PURGE_INTERVAL_HOURS", 24),
+
+		// Lending configuration
+		LoanPeriods:             getEnv("LOAN_PERIODS", "Regular:14,Student:21,Premium:28"),
+		DefaultLoanDays:         getEnvAsInt("DEFAULT_LOAN_DAYS", 14),
+		ReserveOvernightDueHour: getEnvAsInt("RESERVE_OVERNIGHT_DUE_HOUR", 10),
+		MaxActiveLoans:          getEnvAsInt("MAX_ACTIVE_LOANS", 5),
+		MaxRenewals:             getEnvAsInt("MAX_RENEWALS", 2),
+		FineBlockThreshold:      getEnvAsInt("FINE_BLOCK_THRESHOLD", 50000),
+		BlockCheckIntervalHours: getEnvAsInt("BLOCK_CHECK_INTERVAL_HOURS", 1),
+		DigitalLoanDays:         getEnvAsInt("DIGITAL_LOAN_DAYS", 14),
+		DownloadLinkTTLMinutes:  getEnvAsInt("DOWNLOAD_LINK_TTL_MINUTES", 15),
+		DigitalMaxSize:          getEnvAsInt("DIGITAL_MAX_SIZE", 200*1024*1024), // 200 MB
+		DownloadSigningKey:      getEnv("DOWNLOAD_SIGNING_KEY", ""),
+
+		// Privacy configuration
+		AnonymizeIntervalMinutes: getEnvAsInt("ANONYMIZE_INTERVAL_MINUTES", 60),
+
+		// Membership configuration
+		MembershipMonths:             getEnvAsInt("MEMBERSHIP_MONTHS", 12),
+		MembershipRenewalFee:         getEnvAsInt("MEMBERSHIP_RENEWAL_FEE", 0),
+		MembershipReminderDays:       getEnvAsInt("MEMBERSHIP_REMINDER_DAYS", 14),
+		MembershipCheckIntervalHours: getEnvAsInt("MEMBERSHIP_CHECK_INTERVAL_HOURS", 24),
+	}
+	if cfg.DownloadSigningKey == "" {
+		cfg.DownloadSigningKey = cfg.JWTSecretKey
 	}
 
 	return cfg, nil
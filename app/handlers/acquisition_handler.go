@@ -0,0 +1,311 @@
+package handlers
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/services"
+	"Restful-Perpustakaan-API/app/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// AcquisitionHandler handles HTTP requests for vendors, budget funds, purchase orders and receiving
+type AcquisitionHandler struct {
+	acquisitionService *services.AcquisitionService
+}
+
+// NewAcquisitionHandler creates a new instance of AcquisitionHandler
+func NewAcquisitionHandler(acquisitionService *services.AcquisitionService) *AcquisitionHandler {
+	return &AcquisitionHandler{acquisitionService: acquisitionService}
+}
+
+// GetAllVendors handles GET requests to retrieve all vendors
+func (h *AcquisitionHandler) GetAllVendors(w http.ResponseWriter, r *http.Request) {
+	vendors, err := h.acquisitionService.GetAllVendors()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, vendors)
+}
+
+// GetVendorByID handles GET requests to retrieve a vendor by ID
+func (h *AcquisitionHandler) GetVendorByID(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid vendor ID", http.StatusBadRequest)
+		return
+	}
+	vendor, err := h.acquisitionService.GetVendorByID(id)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, vendor)
+}
+
+// CreateVendor handles POST requests to create a vendor;
+// body: {"name": "CV Buku Nusantara", "contact_name": "", "email": "", "phone": "", "address": ""}
+func (h *AcquisitionHandler) CreateVendor(w http.ResponseWriter, r *http.Request) {
+	var vendor models.Vendor
+	if err := json.NewDecoder(r.Body).Decode(&vendor); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	vendor.ID = 0
+	if err := h.acquisitionService.CreateVendor(&vendor); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, vendor)
+}
+
+// UpdateVendor handles PUT requests to update a vendor
+func (h *AcquisitionHandler) UpdateVendor(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid vendor ID", http.StatusBadRequest)
+		return
+	}
+	var vendor models.Vendor
+	if err := json.NewDecoder(r.Body).Decode(&vendor); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	vendor.ID = id
+	if err := h.acquisitionService.UpdateVendor(&vendor); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, vendor)
+}
+
+// DeleteVendor handles DELETE requests to remove a vendor without purchase orders
+func (h *AcquisitionHandler) DeleteVendor(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid vendor ID", http.StatusBadRequest)
+		return
+	}
+	if err := h.acquisitionService.DeleteVendor(id); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetFunds handles GET requests to retrieve budget funds with their remaining amounts; optional ?fiscal_year=
+func (h *AcquisitionHandler) GetFunds(w http.ResponseWriter, r *http.Request) {
+	fiscalYear, err := parseYearParam(r, "fiscal_year", 0)
+	if err != nil {
+		http.Error(w, "Invalid fiscal_year", http.StatusBadRequest)
+		return
+	}
+	funds, err := h.acquisitionService.GetFunds(fiscalYear)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, funds)
+}
+
+// GetFundByID handles GET requests to retrieve a budget fund by ID
+func (h *AcquisitionHandler) GetFundByID(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid fund ID", http.StatusBadRequest)
+		return
+	}
+	fund, err := h.acquisitionService.GetFundByID(id)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, fund)
+}
+
+// CreateFund handles POST requests to create a budget fund;
+// body: {"code": "BUKU-2026", "name": "Pengadaan buku cetak", "fiscal_year": 2026, "allocated": 150000000}
+func (h *AcquisitionHandler) CreateFund(w http.ResponseWriter, r *http.Request) {
+	var fund models.Fund
+	if err := json.NewDecoder(r.Body).Decode(&fund); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	fund.ID = 0
+	if err := h.acquisitionService.CreateFund(&fund); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, fund)
+}
+
+// UpdateFund handles PUT requests to update a budget fund
+func (h *AcquisitionHandler) UpdateFund(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid fund ID", http.StatusBadRequest)
+		return
+	}
+	var fund models.Fund
+	if err := json.NewDecoder(r.Body).Decode(&fund); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	fund.ID = id
+	updated, err := h.acquisitionService.UpdateFund(&fund)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, updated)
+}
+
+// DeleteFund handles DELETE requests to remove a budget fund without purchase orders
+func (h *AcquisitionHandler) DeleteFund(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid fund ID", http.StatusBadRequest)
+		return
+	}
+	if err := h.acquisitionService.DeleteFund(id); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetOrders handles GET requests to retrieve purchase orders; optional ?status=
+func (h *AcquisitionHandler) GetOrders(w http.ResponseWriter, r *http.Request) {
+	orders, err := h.acquisitionService.GetOrders(r.URL.Query().Get("status"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, orders)
+}
+
+// GetOrderByID handles GET requests to retrieve a purchase order with its lines
+func (h *AcquisitionHandler) GetOrderByID(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid order ID", http.StatusBadRequest)
+		return
+	}
+	order, err := h.acquisitionService.GetOrderByID(id)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, order)
+}
+
+// CreateOrder handles POST requests to place a purchase order;
+// body: {"vendor_id": 1, "fund_id": 2, "note": "", "lines": [{"isbn": "9786020332956", "title": "...", "author": "...", "quantity": 3, "unit_price": 95000}]}
+func (h *AcquisitionHandler) CreateOrder(w http.ResponseWriter, r *http.Request) {
+	var order models.PurchaseOrder
+	if err := json.NewDecoder(r.Body).Decode(&order); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	order.ID = 0
+	if err := h.acquisitionService.CreateOrder(&order); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, order)
+}
+
+// AddOrderLine handles POST requests to add a line to an open purchase order
+func (h *AcquisitionHandler) AddOrderLine(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid order ID", http.StatusBadRequest)
+		return
+	}
+	var line models.PurchaseOrderLine
+	if err := json.NewDecoder(r.Body).Decode(&line); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	line.ID = 0
+	order, err := h.acquisitionService.AddLine(id, &line)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, order)
+}
+
+// CancelOrder handles POST requests to cancel the outstanding part of a purchase order
+func (h *AcquisitionHandler) CancelOrder(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid order ID", http.StatusBadRequest)
+		return
+	}
+	order, err := h.acquisitionService.CancelOrder(id)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, order)
+}
+
+// ReceiveOrder handles POST requests to receive (part of) a purchase order;
+// body: {"lines": [{"line_id": 4, "quantity": 2, "barcodes": ["B000123", "B000124"], "location_id": 7}]}
+func (h *AcquisitionHandler) ReceiveOrder(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid order ID", http.StatusBadRequest)
+		return
+	}
+	var req models.ReceiveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	order, err := h.acquisitionService.Receive(id, &req)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, order)
+}
+
+// GetBudgetReport handles GET requests for the budget-remaining report; ?from_year=&to_year= default to the current year
+func (h *AcquisitionHandler) GetBudgetReport(w http.ResponseWriter, r *http.Request) {
+	currentYear := time.Now().Year()
+	fromYear, err := parseYearParam(r, "from_year", currentYear)
+	if err != nil {
+		http.Error(w, "Invalid from_year", http.StatusBadRequest)
+		return
+	}
+	toYear, err := parseYearParam(r, "to_year", fromYear)
+	if err != nil {
+		http.Error(w, "Invalid to_year", http.StatusBadRequest)
+		return
+	}
+	report, err := h.acquisitionService.GetBudgetReport(fromYear, toYear)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, report)
+}
+
+// parseYearParam reads a year from the query string, returning fallback if it is absent
+func parseYearParam(r *http.Request, name string, fallback int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return fallback, nil
+	}
+	return strconv.Atoi(value)
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/services"
+	"Restful-Perpustakaan-API/app/utils"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// BlockHandler handles HTTP requests for member borrowing blocks
+type BlockHandler struct {
+	blockService *services.BlockService
+}
+
+// NewBlockHandler creates a new instance of BlockHandler
+func NewBlockHandler(blockService *services.BlockService) *BlockHandler {
+	return &BlockHandler{blockService: blockService}
+}
+
+// GetActiveBlocks handles GET requests for all blocks currently in force
+func (h *BlockHandler) GetActiveBlocks(w http.ResponseWriter, r *http.Request) {
+	blocks, err := h.blockService.GetActiveBlocks()
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, blocks)
+}
+
+// GetMemberBlocks handles GET requests for the block history of a member
+func (h *BlockHandler) GetMemberBlocks(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid member ID", http.StatusBadRequest)
+		return
+	}
+	blocks, err := h.blockService.GetBlocks(id)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, blocks)
+}
+
+// CreateBlock handles POST requests placing a manual block on a member;
+// body: {"type": "behavior", "reason": "...", "starts_at": "...", "ends_at": "..."} (starts_at and ends_at optional)
+func (h *BlockHandler) CreateBlock(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid member ID", http.StatusBadRequest)
+		return
+	}
+	var request struct {
+		Type     string     `json:"type"`
+		Reason   string     `json:"reason"`
+		StartsAt time.Time  `json:"starts_at"`
+		EndsAt   *time.Time `json:"ends_at"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	actor, ok := currentActor(w, r)
+	if !ok {
+		return
+	}
+	block := models.MemberBlock{
+		MemberID:  id,
+		Type:      request.Type,
+		Reason:    request.Reason,
+		StartsAt:  request.StartsAt,
+		EndsAt:    request.EndsAt,
+		CreatedBy: actor,
+	}
+	if err := h.blockService.CreateBlock(&block); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, block)
+}
+
+// LiftBlock handles POST requests lifting a manual block early; body: {"note": "..."} (optional)
+func (h *BlockHandler) LiftBlock(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid block ID", http.StatusBadRequest)
+		return
+	}
+	var request struct {
+		Note string `json:"note"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	actor, ok := currentActor(w, r)
+	if !ok {
+		return
+	}
+	block, err := h.blockService.LiftBlock(id, actor, request.Note)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, block)
+}
+
+// RefreshFineBlocks handles POST requests to place and lift automatic fine blocks immediately
+func (h *BlockHandler) RefreshFineBlocks(w http.ResponseWriter, r *http.Request) {
+	result, err := h.blockService.RefreshFineBlocks()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, result)
+}
+
+// GetMyBlocks handles GET requests for the blocks in force on the logged-in member, with their reasons
+func (h *BlockHandler) GetMyBlocks(w http.ResponseWriter, r *http.Request) {
+	memberID, ok := currentMemberID(w, r)
+	if !ok {
+		return
+	}
+	blocks, err := h.blockService.GetMemberActiveBlocks(memberID)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, blocks)
+}
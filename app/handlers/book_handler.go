@@ -2,15 +2,21 @@ package handlers
 
 import (
 	"Restful-Perpustakaan-API/app/models"
-	_ "Restful-Perpustakaan-API/app/utils"
+	"Restful-Perpustakaan-API/app/services"
+	"Restful-Perpustakaan-API/app/utils"
 	"Restful-Perpustakaan-API/database"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gorilla/mux"
 )
 
+// maxImportSize is the largest catalog file accepted by ImportBooks (10 MB)
+const maxImportSize = 10 << 20
+
 // Book represents a book entity
 type Book struct {
 	ID            int     `json:"id"`
@@ -61,17 +67,22 @@ func GetBook(id int) models.BookModel {
 		return models.BookModel{}
 	}
 	return models.BookModel{
-		ID:            book.ID,
-		Title:         book.Title,
-		Author:        book.Author,
-		Year:          book.Year,
-		Rating:        book.Rating,
-		Publisher:     book.Publisher,
-		PublishedYear: book.PublishedYear,
-		ISBN:          book.ISBN,
-		Genre:         book.Genre,
-		Description:   book.Description,
-		CoverImage:    book.CoverImage, // URL atau path ke gambar sampul
+		ID:             book.ID,
+		Title:          book.Title,
+		Author:         book.Author,
+		Year:           book.Year,
+		Rating:         book.Rating,
+		Publisher:      book.Publisher,
+		PublishedYear:  book.PublishedYear,
+		ISBN:           book.ISBN,
+		Genre:          book.Genre,
+		Description:    book.Description,
+		CoverImage:     book.CoverImage, // URL atau path ke gambar sampul
+		Classification: book.Classification,
+		CallNumber:     book.CallNumber,
+		WorkID:         book.WorkID,
+		Edition:        book.Edition,
+		Language:       book.Language,
 		// Copy other fields as needed
 	}
 }
@@ -93,60 +104,263 @@ func GetBookByID(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(book)
 }
 
-// CreateBook adds a new book to the database
-func CreateBook(w http.ResponseWriter, r *http.Request) {
-	var newBook models.Book
-	err := json.NewDecoder(r.Body).Decode(&newBook)
+// DeleteBook removes a book from the database by its ID
+func DeleteBook(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	id, err := strconv.Atoi(params["id"])
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		http.Error(w, "Invalid book ID", http.StatusBadRequest)
 		return
 	}
-	err = database.CreateBook(&newBook)
+	err = database.DeleteBook(id)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// BookHandler handles HTTP requests related to books backed by BookService
+type BookHandler struct {
+	bookService *services.BookService
+}
+
+// NewBookHandler creates a new instance of BookHandler
+func NewBookHandler(bookService *services.BookService) *BookHandler {
+	return &BookHandler{bookService: bookService}
+}
+
+// CreateBook handles POST requests to add a new book to the catalogue
+func (h *BookHandler) CreateBook(w http.ResponseWriter, r *http.Request) {
+	var newBook models.Book
+	if err := json.NewDecoder(r.Body).Decode(&newBook); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.bookService.CreateBook(&newBook); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(newBook)
+	writeJSON(w, newBook)
 }
 
-// UpdateBook updates an existing book in the database
-func UpdateBook(w http.ResponseWriter, r *http.Request) {
-	params := mux.Vars(r)
-	id, err := strconv.Atoi(params["id"])
+// UpdateBook handles PUT requests to update a book; the change is recorded in the book's history
+func (h *BookHandler) UpdateBook(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
 	if err != nil {
 		http.Error(w, "Invalid book ID", http.StatusBadRequest)
 		return
 	}
+	actor, ok := currentActor(w, r)
+	if !ok {
+		return
+	}
 	var updatedBook models.Book
-	err = json.NewDecoder(r.Body).Decode(&updatedBook)
-	if err != nil {
+	if err := json.NewDecoder(r.Body).Decode(&updatedBook); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 	updatedBook.ID = id
-	err = database.UpdateBook(&updatedBook)
+	updatedBook.ChangedBy = actor
+	if err := h.bookService.UpdateBook(&updatedBook); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, updatedBook)
+}
+
+// ImportBooks handles POST requests to import many books from a CSV or JSONL body.
+// Query parameters: format (csv|jsonl), mode (transactional|best_effort), dry_run (bool)
+// and mapping ("Judul:title,Pengarang:author").
+func (h *BookHandler) ImportBooks(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	opts := models.BookImportOptions{
+		Format: query.Get("format"),
+		Mode:   query.Get("mode"),
+	}
+	if opts.Format == "" {
+		opts.Format = formatFromContentType(r.Header.Get("Content-Type"))
+	}
+	if dryRun := query.Get("dry_run"); dryRun != "" {
+		value, err := strconv.ParseBool(dryRun)
+		if err != nil {
+			http.Error(w, "Invalid dry_run value", http.StatusBadRequest)
+			return
+		}
+		opts.DryRun = value
+	}
+	mapping, err := parseColumnMapping(query.Get("mapping"))
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	opts.Mapping = mapping
+
+	result, err := h.bookService.ImportBooks(http.MaxBytesReader(w, r.Body, maxImportSize), opts)
+	if err != nil {
+		utils.HandleError(w, err)
 		return
 	}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(updatedBook)
+	switch {
+	case result.DryRun:
+		w.WriteHeader(http.StatusOK)
+	case result.Imported > 0:
+		w.WriteHeader(http.StatusCreated)
+	case result.Failed > 0:
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+	json.NewEncoder(w).Encode(result)
 }
 
-// DeleteBook removes a book from the database by its ID
-func DeleteBook(w http.ResponseWriter, r *http.Request) {
-	params := mux.Vars(r)
-	id, err := strconv.Atoi(params["id"])
+// ExportBooks handles GET requests to stream the whole catalog as CSV or JSONL
+func (h *BookHandler) ExportBooks(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = models.BookFormatCSV
+	}
+
+	switch format {
+	case models.BookFormatCSV:
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	case models.BookFormatJSONL:
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	default:
+		http.Error(w, "Invalid export format", http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Disposition", "attachment; filename=\"books."+format+"\"")
+
+	// Header sudah terkirim saat streaming dimulai, jadi error hanya bisa dicatat
+	if err := h.bookService.ExportBooks(w, format); err != nil {
+		utils.GetLogger().WithError(err).Error("failed to export books")
+	}
+}
+
+// formatFromContentType guesses the import format from the request Content-Type
+func formatFromContentType(contentType string) string {
+	switch {
+	case strings.HasPrefix(contentType, "text/csv"):
+		return models.BookFormatCSV
+	case strings.HasPrefix(contentType, "application/x-ndjson"), strings.HasPrefix(contentType, "application/jsonl"):
+		return models.BookFormatJSONL
+	default:
+		return ""
+	}
+}
+
+// parseColumnMapping parses a "source:field,source:field" mapping string
+func parseColumnMapping(value string) (map[string]string, error) {
+	mapping := make(map[string]string)
+	if value == "" {
+		return mapping, nil
+	}
+	for _, pair := range strings.Split(value, ",") {
+		parts := strings.SplitN(pair, ":", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, errors.New("invalid mapping entry: " + pair)
+		}
+		mapping[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return mapping, nil
+}
+
+// ImportMarc handles POST requests to import MARC 21 (format=marc) or MARCXML (format=marcxml) records.
+// The response lists, per record, the created book and the MARC fields that were not mapped.
+func (h *BookHandler) ImportMarc(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	format := query.Get("format")
+	if format == "" {
+		format = models.BookFormatMARC
+		if ct := r.Header.Get("Content-Type"); strings.Contains(ct, "xml") {
+			format = models.BookFormatMARCXML
+		}
+	}
+	dryRun := false
+	if value := query.Get("dry_run"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			http.Error(w, "Invalid dry_run value", http.StatusBadRequest)
+			return
+		}
+		dryRun = parsed
+	}
+
+	result, err := h.bookService.ImportMarc(http.MaxBytesReader(w, r.Body, maxImportSize), format, dryRun)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if result.Imported > 0 {
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(result)
+}
+
+// ExportMarc handles GET requests to download a single book as MARC 21 or MARCXML
+func (h *BookHandler) ExportMarc(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
 	if err != nil {
 		http.Error(w, "Invalid book ID", http.StatusBadRequest)
 		return
 	}
-	err = database.DeleteBook(id)
+	format, ok := marcContentType(w, r.URL.Query().Get("format"))
+	if !ok {
+		http.Error(w, "Invalid MARC format", http.StatusBadRequest)
+		return
+	}
+
+	book, err := h.bookService.GetBookByID(id)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusNotFound)
 		return
 	}
-	w.WriteHeader(http.StatusNoContent)
+	w.Header().Set("Content-Disposition", "attachment; filename=\"book-"+strconv.Itoa(book.ID)+marcFileExtension(format)+"\"")
+
+	if err := h.bookService.ExportMarc(w, format, book); err != nil {
+		utils.GetLogger().WithError(err).Error("failed to export MARC record")
+	}
+}
+
+// ExportCatalogMarc handles GET requests to stream the whole catalog as MARC 21 or MARCXML
+func (h *BookHandler) ExportCatalogMarc(w http.ResponseWriter, r *http.Request) {
+	format, ok := marcContentType(w, r.URL.Query().Get("format"))
+	if !ok {
+		http.Error(w, "Invalid MARC format", http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Disposition", "attachment; filename=\"catalog"+marcFileExtension(format)+"\"")
+
+	if err := h.bookService.ExportCatalogMarc(w, format); err != nil {
+		utils.GetLogger().WithError(err).Error("failed to export MARC catalog")
+	}
+}
+
+// marcContentType sets the Content-Type for a MARC format, defaulting to MARC 21
+func marcContentType(w http.ResponseWriter, format string) (string, bool) {
+	switch format {
+	case "", models.BookFormatMARC:
+		w.Header().Set("Content-Type", "application/marc")
+		return models.BookFormatMARC, true
+	case models.BookFormatMARCXML:
+		w.Header().Set("Content-Type", "application/marcxml+xml")
+		return models.BookFormatMARCXML, true
+	default:
+		return "", false
+	}
+}
+
+// marcFileExtension returns the download file extension for a MARC format
+func marcFileExtension(format string) string {
+	if format == models.BookFormatMARCXML {
+		return ".xml"
+	}
+	return ".mrc"
 }
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"Restful-Perpustakaan-API/app/services"
+	"Restful-Perpustakaan-API/app/utils"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// CardHandler handles HTTP requests for library cards: issuing, replacing, rendering and scanning
+type CardHandler struct {
+	cardService *services.CardService
+}
+
+// NewCardHandler creates a new instance of CardHandler
+func NewCardHandler(cardService *services.CardService) *CardHandler {
+	return &CardHandler{cardService: cardService}
+}
+
+// GetCard handles GET requests from staff for the active card of a member; see writeCard for the formats
+func (h *CardHandler) GetCard(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid member ID", http.StatusBadRequest)
+		return
+	}
+	h.writeCard(w, r, id)
+}
+
+// GetMyCard handles GET requests for the active card of the logged-in member; see writeCard for the formats
+func (h *CardHandler) GetMyCard(w http.ResponseWriter, r *http.Request) {
+	memberID, ok := currentMemberID(w, r)
+	if !ok {
+		return
+	}
+	h.writeCard(w, r, memberID)
+}
+
+// writeCard writes the active card of a member as JSON. ?format=png or ?format=svg renders the card
+// number as an image; ?type=barcode (Code 128, default) or ?type=qr selects the symbology.
+func (h *CardHandler) writeCard(w http.ResponseWriter, r *http.Request, id int) {
+	format := r.URL.Query().Get("format")
+	if format == "" || format == "json" {
+		card, err := h.cardService.GetCard(id)
+		if err != nil {
+			utils.HandleError(w, err)
+			return
+		}
+		writeJSON(w, card)
+		return
+	}
+
+	image, contentType, err := h.cardService.RenderCard(id, r.URL.Query().Get("type"), format)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	// Gambar berubah saat kartu diganti, jadi jangan disimpan di cache
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", contentType)
+	if _, err := w.Write(image); err != nil {
+		utils.GetLogger().WithError(err).Error("failed to serve library card image")
+	}
+}
+
+// GetCards handles GET requests for all cards issued to a member, including replaced ones
+func (h *CardHandler) GetCards(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid member ID", http.StatusBadRequest)
+		return
+	}
+	cards, err := h.cardService.GetCards(id)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, cards)
+}
+
+// IssueCard handles POST requests to issue the first library card of a member
+func (h *CardHandler) IssueCard(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid member ID", http.StatusBadRequest)
+		return
+	}
+	card, err := h.cardService.IssueCard(id)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, card)
+}
+
+// ReplaceCard handles POST requests to replace a lost or damaged card; body: {"reason": "lost"}
+func (h *CardHandler) ReplaceCard(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid member ID", http.StatusBadRequest)
+		return
+	}
+	var request struct {
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	card, err := h.cardService.ReplaceCard(id, request.Reason)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, card)
+}
+
+// LookupCard handles GET requests from the desk scanner or kiosk to find the member holding a card
+func (h *CardHandler) LookupCard(w http.ResponseWriter, r *http.Request) {
+	lookup, err := h.cardService.LookupCard(mux.Vars(r)["number"])
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, lookup)
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/services"
+	"Restful-Perpustakaan-API/app/utils"
+	"encoding/json"
+	"net/http"
+)
+
+// CopyHandler handles HTTP requests for the physical copies of books
+type CopyHandler struct {
+	copyService *services.CopyService
+}
+
+// NewCopyHandler creates a new instance of CopyHandler
+func NewCopyHandler(copyService *services.CopyService) *CopyHandler {
+	return &CopyHandler{copyService: copyService}
+}
+
+// GetCopiesByBookID handles GET requests to retrieve the copies of a book
+func (h *CopyHandler) GetCopiesByBookID(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid book ID", http.StatusBadRequest)
+		return
+	}
+	copies, err := h.copyService.GetCopiesByBookID(id)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, copies)
+}
+
+// CreateCopy handles POST requests to add a copy to a book
+func (h *CopyHandler) CreateCopy(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid book ID", http.StatusBadRequest)
+		return
+	}
+	var bookCopy models.BookCopy
+	if err := json.NewDecoder(r.Body).Decode(&bookCopy); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	bookCopy.ID = 0
+	bookCopy.BookID = id
+	if err := h.copyService.CreateCopy(&bookCopy); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, bookCopy)
+}
+
+// UpdateCopy handles PUT requests to update a copy, e.g. to move it to another shelf
+func (h *CopyHandler) UpdateCopy(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid copy ID", http.StatusBadRequest)
+		return
+	}
+	var bookCopy models.BookCopy
+	if err := json.NewDecoder(r.Body).Decode(&bookCopy); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	bookCopy.ID = id
+	if err := h.copyService.UpdateCopy(&bookCopy); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, bookCopy)
+}
+
+// DeleteCopy handles DELETE requests to delete a copy
+func (h *CopyHandler) DeleteCopy(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid copy ID", http.StatusBadRequest)
+		return
+	}
+	if err := h.copyService.DeleteCopy(id); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
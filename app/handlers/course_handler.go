@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/services"
+	"Restful-Perpustakaan-API/app/utils"
+	"encoding/json"
+	"net/http"
+)
+
+// CourseHandler handles HTTP requests for academic terms, courses and course reserves
+type CourseHandler struct {
+	courseService *services.CourseService
+}
+
+// NewCourseHandler creates a new instance of CourseHandler
+func NewCourseHandler(courseService *services.CourseService) *CourseHandler {
+	return &CourseHandler{courseService: courseService}
+}
+
+// GetAllTerms handles GET requests to retrieve all terms
+func (h *CourseHandler) GetAllTerms(w http.ResponseWriter, r *http.Request) {
+	terms, err := h.courseService.GetAllTerms()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, terms)
+}
+
+// GetTermByID handles GET requests to retrieve a term by ID
+func (h *CourseHandler) GetTermByID(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid term ID", http.StatusBadRequest)
+		return
+	}
+	term, err := h.courseService.GetTermByID(id)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, term)
+}
+
+// CreateTerm handles POST requests to create a term;
+// body: {"name": "Ganjil 2026/2027", "start_date": "2026-08-24T00:00:00Z", "end_date": "2026-12-18T00:00:00Z"}
+func (h *CourseHandler) CreateTerm(w http.ResponseWriter, r *http.Request) {
+	var term models.Term
+	if err := json.NewDecoder(r.Body).Decode(&term); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	term.ID = 0
+	if err := h.courseService.CreateTerm(&term); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, term)
+}
+
+// UpdateTerm handles PUT requests to update a term
+func (h *CourseHandler) UpdateTerm(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid term ID", http.StatusBadRequest)
+		return
+	}
+	var term models.Term
+	if err := json.NewDecoder(r.Body).Decode(&term); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	term.ID = id
+	if err := h.courseService.UpdateTerm(&term); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, term)
+}
+
+// DeleteTerm handles DELETE requests to remove a term without courses
+func (h *CourseHandler) DeleteTerm(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid term ID", http.StatusBadRequest)
+		return
+	}
+	if err := h.courseService.DeleteTerm(id); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetAllCourses handles GET requests to retrieve all courses
+func (h *CourseHandler) GetAllCourses(w http.ResponseWriter, r *http.Request) {
+	courses, err := h.courseService.GetAllCourses()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, courses)
+}
+
+// GetCourseByID handles GET requests to retrieve a course by ID
+func (h *CourseHandler) GetCourseByID(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid course ID", http.StatusBadRequest)
+		return
+	}
+	course, err := h.courseService.GetCourseByID(id)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, course)
+}
+
+// CreateCourse handles POST requests to create a course;
+// body: {"term_id": 1, "code": "IF2110", "name": "Algoritma dan Struktur Data", "lecturer": "..."}
+func (h *CourseHandler) CreateCourse(w http.ResponseWriter, r *http.Request) {
+	var course models.Course
+	if err := json.NewDecoder(r.Body).Decode(&course); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	course.ID = 0
+	if err := h.courseService.CreateCourse(&course); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, course)
+}
+
+// UpdateCourse handles PUT requests to update a course
+func (h *CourseHandler) UpdateCourse(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid course ID", http.StatusBadRequest)
+		return
+	}
+	var course models.Course
+	if err := json.NewDecoder(r.Body).Decode(&course); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	course.ID = id
+	if err := h.courseService.UpdateCourse(&course); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, course)
+}
+
+// DeleteCourse handles DELETE requests to remove a course and its reserves
+func (h *CourseHandler) DeleteCourse(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid course ID", http.StatusBadRequest)
+		return
+	}
+	if err := h.courseService.DeleteCourse(id); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetReserves handles GET requests to list the reserves of a course
+func (h *CourseHandler) GetReserves(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid course ID", http.StatusBadRequest)
+		return
+	}
+	reserves, err := h.courseService.GetReserves(id)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, reserves)
+}
+
+// AddReserve handles POST requests to put a book on reserve for a course;
+// body: {"book_id": 12, "copy_id": 40, "loan_rule": "two_hour|overnight"}
+func (h *CourseHandler) AddReserve(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid course ID", http.StatusBadRequest)
+		return
+	}
+	var reserve models.CourseReserve
+	if err := json.NewDecoder(r.Body).Decode(&reserve); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	reserve.ID = 0
+	if err := h.courseService.AddReserve(id, &reserve); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, reserve)
+}
+
+// DeleteReserve handles DELETE requests to take a book off reserve
+func (h *CourseHandler) DeleteReserve(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid reserve ID", http.StatusBadRequest)
+		return
+	}
+	if err := h.courseService.DeleteReserve(id); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"Restful-Perpustakaan-API/app/services"
+	"Restful-Perpustakaan-API/app/utils"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// coverFormField is the multipart form field used for cover uploads
+const coverFormField = "cover"
+
+// coverMultipartOverhead is the room left above the maximum cover size for multipart headers and boundaries
+const coverMultipartOverhead = 64 << 10
+
+// CoverHandler handles HTTP requests for book cover images
+type CoverHandler struct {
+	coverService *services.CoverService
+}
+
+// NewCoverHandler creates a new instance of CoverHandler
+func NewCoverHandler(coverService *services.CoverService) *CoverHandler {
+	return &CoverHandler{coverService: coverService}
+}
+
+// UploadCover handles PUT requests to upload a JPEG or PNG cover for a book.
+// The image can be sent as the raw request body or as the "cover" field of a multipart form.
+func (h *CoverHandler) UploadCover(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid book ID", http.StatusBadRequest)
+		return
+	}
+
+	maxSize := int64(h.coverService.MaxSize())
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize+coverMultipartOverhead)
+
+	var body io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(maxSize); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				http.Error(w, "Cover image is too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, "Invalid multipart form", http.StatusBadRequest)
+			return
+		}
+		file, _, err := r.FormFile(coverFormField)
+		if err != nil {
+			http.Error(w, "Missing cover file", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	book, err := h.coverService.UploadCover(id, body)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, book)
+}
+
+// GetCover handles GET requests to serve a book cover; ?size=thumb|medium|original
+func (h *CoverHandler) GetCover(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid book ID", http.StatusBadRequest)
+		return
+	}
+
+	cover, contentType, etag, err := h.coverService.GetCover(id, r.URL.Query().Get("size"))
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	defer cover.Close()
+
+	// URL sampul tetap sama walau gambarnya diganti, jadi klien wajib revalidasi lewat ETag setiap kali
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	if _, err := io.Copy(w, cover); err != nil {
+		utils.GetLogger().WithError(err).Error("failed to serve cover image")
+	}
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"Restful-Perpustakaan-API/app/services"
+	"Restful-Perpustakaan-API/app/utils"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// DataSubjectHandler handles HTTP requests for personal data export and erasure
+type DataSubjectHandler struct {
+	dataSubjectService *services.DataSubjectService
+}
+
+// NewDataSubjectHandler creates a new instance of DataSubjectHandler
+func NewDataSubjectHandler(dataSubjectService *services.DataSubjectService) *DataSubjectHandler {
+	return &DataSubjectHandler{dataSubjectService: dataSubjectService}
+}
+
+// ExportMyData handles GET requests for a ZIP of the personal data of the logged-in member
+func (h *DataSubjectHandler) ExportMyData(w http.ResponseWriter, r *http.Request) {
+	memberID, ok := currentMemberID(w, r)
+	if !ok {
+		return
+	}
+	archive, err := h.dataSubjectService.ExportData(memberID)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	filename := fmt.Sprintf("member-%d-data-%s.zip", memberID, time.Now().Format("20060102"))
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	if _, err := w.Write(archive); err != nil {
+		utils.GetLogger().WithError(err).Error("failed to serve personal data export")
+	}
+}
+
+// RequestErasure handles POST requests from the logged-in member to erase their personal data;
+// body: {"reason": "..."}
+func (h *DataSubjectHandler) RequestErasure(w http.ResponseWriter, r *http.Request) {
+	memberID, ok := currentMemberID(w, r)
+	if !ok {
+		return
+	}
+	var request struct {
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	erasure, err := h.dataSubjectService.RequestErasure(memberID, request.Reason)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, erasure)
+}
+
+// GetMyErasureRequests handles GET requests for the erasure requests of the logged-in member
+func (h *DataSubjectHandler) GetMyErasureRequests(w http.ResponseWriter, r *http.Request) {
+	memberID, ok := currentMemberID(w, r)
+	if !ok {
+		return
+	}
+	requests, err := h.dataSubjectService.GetMyErasureRequests(memberID)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, requests)
+}
+
+// GetErasureRequests handles GET requests for the erasure requests to review; ?status=pending filters them
+func (h *DataSubjectHandler) GetErasureRequests(w http.ResponseWriter, r *http.Request) {
+	requests, err := h.dataSubjectService.GetErasureRequests(r.URL.Query().Get("status"))
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, requests)
+}
+
+// ApproveErasure handles POST requests approving an erasure request, which anonymizes the member;
+// body: {"note": "..."}
+func (h *DataSubjectHandler) ApproveErasure(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid erasure request ID", http.StatusBadRequest)
+		return
+	}
+	var request struct {
+		Note string `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	actor, ok := currentActor(w, r)
+	if !ok {
+		return
+	}
+	erasure, err := h.dataSubjectService.ApproveErasure(id, actor, request.Note)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, erasure)
+}
+
+// RejectErasure handles POST requests rejecting an erasure request; body: {"note": "reason for the rejection"}
+func (h *DataSubjectHandler) RejectErasure(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid erasure request ID", http.StatusBadRequest)
+		return
+	}
+	var request struct {
+		Note string `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	actor, ok := currentActor(w, r)
+	if !ok {
+		return
+	}
+	erasure, err := h.dataSubjectService.RejectErasure(id, actor, request.Note)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, erasure)
+}
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"Restful-Perpustakaan-API/app/services"
+	"Restful-Perpustakaan-API/app/utils"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// digitalFormField is the multipart form field used for digital file uploads
+const digitalFormField = "file"
+
+// digitalMultipartOverhead is the room left above the maximum file size for multipart headers and boundaries
+const digitalMultipartOverhead = 64 << 10
+
+// DigitalHandler handles HTTP requests for e-books, audiobooks and digital loans
+type DigitalHandler struct {
+	digitalService *services.DigitalService
+}
+
+// NewDigitalHandler creates a new instance of DigitalHandler
+func NewDigitalHandler(digitalService *services.DigitalService) *DigitalHandler {
+	return &DigitalHandler{digitalService: digitalService}
+}
+
+// GetDigitalItems handles GET requests to list the digital items of a book with their available licenses
+func (h *DigitalHandler) GetDigitalItems(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid book ID", http.StatusBadRequest)
+		return
+	}
+	items, err := h.digitalService.GetItemsByBookID(id)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, items)
+}
+
+// UploadDigitalItem handles POST requests to attach a PDF, EPUB or audio file to a book; ?license_count=3.
+// The file can be sent as the raw request body or as the "file" field of a multipart form.
+func (h *DigitalHandler) UploadDigitalItem(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid book ID", http.StatusBadRequest)
+		return
+	}
+	licenseCount, err := strconv.Atoi(r.URL.Query().Get("license_count"))
+	if err != nil {
+		http.Error(w, "Invalid license_count", http.StatusBadRequest)
+		return
+	}
+
+	maxSize := int64(h.digitalService.MaxSize())
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize+digitalMultipartOverhead)
+
+	var body io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(maxSize); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				http.Error(w, "File is too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, "Invalid multipart form", http.StatusBadRequest)
+			return
+		}
+		file, _, err := r.FormFile(digitalFormField)
+		if err != nil {
+			http.Error(w, "Missing file", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	item, err := h.digitalService.UploadItem(id, body, licenseCount)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, item)
+}
+
+// DeleteDigitalItem handles DELETE requests to remove a digital item
+func (h *DigitalHandler) DeleteDigitalItem(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid digital item ID", http.StatusBadRequest)
+		return
+	}
+	if err := h.digitalService.DeleteItem(id); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// BorrowDigitalItem handles POST requests from the logged-in member to borrow a digital item.
+// The response includes a signed download link.
+func (h *DigitalHandler) BorrowDigitalItem(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid digital item ID", http.StatusBadRequest)
+		return
+	}
+	memberID, ok := currentMemberID(w, r)
+	if !ok {
+		return
+	}
+
+	loan, err := h.digitalService.Borrow(id, memberID)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, loan)
+}
+
+// GetDigitalLoansByMemberID handles GET requests to retrieve a member's active digital loans
+func (h *DigitalHandler) GetDigitalLoansByMemberID(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid member ID", http.StatusBadRequest)
+		return
+	}
+	loans, err := h.digitalService.GetActiveLoansByMemberID(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, loans)
+}
+
+// CreateDownloadLink handles POST requests from the borrowing member to issue a fresh signed download link for
+// an active digital loan
+func (h *DigitalHandler) CreateDownloadLink(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid digital loan ID", http.StatusBadRequest)
+		return
+	}
+	memberID, ok := currentMemberID(w, r)
+	if !ok {
+		return
+	}
+	link, err := h.digitalService.CreateDownloadLink(id, memberID)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, link)
+}
+
+// Download handles GET requests for a signed download link; ?expires=...&signature=...
+func (h *DigitalHandler) Download(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid digital loan ID", http.StatusBadRequest)
+		return
+	}
+
+	file, item, err := h.digitalService.OpenDownload(id, r.URL.Query())
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	defer file.Close()
+
+	// Tautan bersifat pribadi dan kedaluwarsa, jadi tidak boleh disimpan oleh cache bersama
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.Header().Set("Content-Type", item.ContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(item.Size))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"book-%d.%s\"", item.BookID, item.Format))
+	if _, err := io.Copy(w, file); err != nil {
+		utils.GetLogger().WithError(err).Error("failed to serve digital file")
+	}
+}
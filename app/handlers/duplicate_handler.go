@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"Restful-Perpustakaan-API/app/services"
+	"Restful-Perpustakaan-API/app/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// DuplicateHandler handles HTTP requests for reviewing and merging duplicate member accounts
+type DuplicateHandler struct {
+	duplicateService *services.DuplicateService
+}
+
+// NewDuplicateHandler creates a new instance of DuplicateHandler
+func NewDuplicateHandler(duplicateService *services.DuplicateService) *DuplicateHandler {
+	return &DuplicateHandler{duplicateService: duplicateService}
+}
+
+// GetDuplicates handles GET requests for the review list of probable duplicate members;
+// ?min_score=0.7 hides weaker matches (default 0.5)
+func (h *DuplicateHandler) GetDuplicates(w http.ResponseWriter, r *http.Request) {
+	var minScore float64
+	if value := r.URL.Query().Get("min_score"); value != "" {
+		var err error
+		if minScore, err = strconv.ParseFloat(value, 64); err != nil {
+			http.Error(w, "Invalid min_score", http.StatusBadRequest)
+			return
+		}
+	}
+	candidates, err := h.duplicateService.FindDuplicates(minScore)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, candidates)
+}
+
+// DismissDuplicate handles POST requests marking two members as different people;
+// body: {"member_id": 1, "other_member_id": 2}
+func (h *DuplicateHandler) DismissDuplicate(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		MemberID      int `json:"member_id"`
+		OtherMemberID int `json:"other_member_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	actor, ok := currentActor(w, r)
+	if !ok {
+		return
+	}
+	dismissal, err := h.duplicateService.DismissDuplicate(request.MemberID, request.OtherMemberID, actor)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, dismissal)
+}
+
+// MergeMember handles POST requests merging a duplicate account into the member in the path;
+// body: {"duplicate_id": 2}
+func (h *DuplicateHandler) MergeMember(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid member ID", http.StatusBadRequest)
+		return
+	}
+	var request struct {
+		DuplicateID int `json:"duplicate_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	actor, ok := currentActor(w, r)
+	if !ok {
+		return
+	}
+	merge, err := h.duplicateService.MergeMembers(id, request.DuplicateID, actor)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, merge)
+}
+
+// GetMerges handles GET requests for the merges a member was part of
+func (h *DuplicateHandler) GetMerges(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid member ID", http.StatusBadRequest)
+		return
+	}
+	merges, err := h.duplicateService.GetMerges(id)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, merges)
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"Restful-Perpustakaan-API/app/services"
+	"Restful-Perpustakaan-API/app/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// EnrichmentHandler handles HTTP requests for book metadata enrichment
+type EnrichmentHandler struct {
+	enrichmentService *services.EnrichmentService
+}
+
+// NewEnrichmentHandler creates a new instance of EnrichmentHandler
+func NewEnrichmentHandler(enrichmentService *services.EnrichmentService) *EnrichmentHandler {
+	return &EnrichmentHandler{enrichmentService: enrichmentService}
+}
+
+// EnrichISBN handles POST requests to fetch metadata for an ISBN and apply it to the catalog.
+// Query parameters: overwrite, create and dry_run (all booleans, default false).
+func (h *EnrichmentHandler) EnrichISBN(w http.ResponseWriter, r *http.Request) {
+	isbn := mux.Vars(r)["isbn"]
+
+	var opts services.EnrichmentOptions
+	flags := map[string]*bool{"overwrite": &opts.Overwrite, "create": &opts.Create, "dry_run": &opts.DryRun}
+	for name, target := range flags {
+		value := r.URL.Query().Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			http.Error(w, "Invalid "+name+" value", http.StatusBadRequest)
+			return
+		}
+		*target = parsed
+	}
+
+	result, err := h.enrichmentService.EnrichISBN(r.Context(), isbn, opts)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if result.Created && !result.DryRun {
+		w.WriteHeader(http.StatusCreated)
+	}
+	writeJSON(w, result)
+}
+
+// StartReenrichment handles POST requests to start a background re-enrichment of the whole catalog
+func (h *EnrichmentHandler) StartReenrichment(w http.ResponseWriter, r *http.Request) {
+	overwrite, _ := strconv.ParseBool(r.URL.Query().Get("overwrite"))
+
+	job, err := h.enrichmentService.StartReenrichment(overwrite)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Location", "/admin/books/enrich/"+strconv.Itoa(job.ID))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	writeJSON(w, job)
+}
+
+// GetReenrichmentJob handles GET requests to check the progress of a re-enrichment job
+func (h *EnrichmentHandler) GetReenrichmentJob(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid job ID", http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.enrichmentService.GetJob(id)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, job)
+}
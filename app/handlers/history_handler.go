@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/services"
+	"Restful-Perpustakaan-API/app/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// HistoryHandler handles HTTP requests for the change history of books and members
+type HistoryHandler struct {
+	historyService *services.HistoryService
+}
+
+// NewHistoryHandler creates a new instance of HistoryHandler
+func NewHistoryHandler(historyService *services.HistoryService) *HistoryHandler {
+	return &HistoryHandler{historyService: historyService}
+}
+
+// GetBookHistory handles GET requests for the version history of a book
+func (h *HistoryHandler) GetBookHistory(w http.ResponseWriter, r *http.Request) {
+	h.getHistory(w, r, models.HistoryEntityBook)
+}
+
+// GetMemberHistory handles GET requests for the version history of a member
+func (h *HistoryHandler) GetMemberHistory(w http.ResponseWriter, r *http.Request) {
+	h.getHistory(w, r, models.HistoryEntityMember)
+}
+
+// DiffBookVersions handles GET requests to compare two versions of a book; ?from=1&to=3
+func (h *HistoryHandler) DiffBookVersions(w http.ResponseWriter, r *http.Request) {
+	h.diffVersions(w, r, models.HistoryEntityBook)
+}
+
+// DiffMemberVersions handles GET requests to compare two versions of a member; ?from=1&to=3
+func (h *HistoryHandler) DiffMemberVersions(w http.ResponseWriter, r *http.Request) {
+	h.diffVersions(w, r, models.HistoryEntityMember)
+}
+
+// RevertBook handles POST requests to revert a book to a previous version
+func (h *HistoryHandler) RevertBook(w http.ResponseWriter, r *http.Request) {
+	id, version, ok := parseVersionParams(w, r)
+	if !ok {
+		return
+	}
+	actor, ok := currentActor(w, r)
+	if !ok {
+		return
+	}
+	book, err := h.historyService.RevertBook(id, version, actor)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, book)
+}
+
+// RevertMember handles POST requests to revert a member to a previous version
+func (h *HistoryHandler) RevertMember(w http.ResponseWriter, r *http.Request) {
+	id, version, ok := parseVersionParams(w, r)
+	if !ok {
+		return
+	}
+	actor, ok := currentActor(w, r)
+	if !ok {
+		return
+	}
+	member, err := h.historyService.RevertMember(id, version, actor)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, member)
+}
+
+// getHistory writes the version history of a record
+func (h *HistoryHandler) getHistory(w http.ResponseWriter, r *http.Request, entityType string) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+	versions, err := h.historyService.GetHistory(entityType, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, versions)
+}
+
+// diffVersions writes the difference between the versions given in the from and to query parameters
+func (h *HistoryHandler) diffVersions(w http.ResponseWriter, r *http.Request, entityType string) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+	from, err := strconv.Atoi(r.URL.Query().Get("from"))
+	if err != nil {
+		http.Error(w, "Invalid from version", http.StatusBadRequest)
+		return
+	}
+	to, err := strconv.Atoi(r.URL.Query().Get("to"))
+	if err != nil {
+		http.Error(w, "Invalid to version", http.StatusBadRequest)
+		return
+	}
+
+	diff, err := h.historyService.DiffVersions(entityType, id, from, to)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, diff)
+}
+
+// parseVersionParams reads the {id} and {version} path parameters, writing a 400 response if either is invalid
+func parseVersionParams(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	version, err := strconv.Atoi(mux.Vars(r)["version"])
+	if err != nil {
+		http.Error(w, "Invalid version", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	return id, version, true
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/services"
+	"Restful-Perpustakaan-API/app/utils"
+	"encoding/json"
+	"net/http"
+)
+
+// HoldHandler handles HTTP requests for holds (reservations)
+type HoldHandler struct {
+	holdService *services.HoldService
+}
+
+// NewHoldHandler creates a new instance of HoldHandler
+func NewHoldHandler(holdService *services.HoldService) *HoldHandler {
+	return &HoldHandler{holdService: holdService}
+}
+
+// PlaceHold handles POST requests from the logged-in member to place a hold on a book.
+// Body: {"book_id": 12, "any_edition": true}; any_edition lets any edition of the work fill the hold.
+// A member_id in the body must be the member's own ID.
+func (h *HoldHandler) PlaceHold(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		MemberID   int  `json:"member_id"`
+		BookID     int  `json:"book_id"`
+		AnyEdition bool `json:"any_edition"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	memberID, ok := holdMemberID(w, r, request.MemberID)
+	if !ok {
+		return
+	}
+	hold, err := h.holdService.PlaceHold(memberID, request.BookID, request.AnyEdition)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeCreatedHold(w, hold)
+}
+
+// PlaceWorkHold handles POST requests from the logged-in member to place an "any edition" hold on a work;
+// a member_id in the body must be the member's own ID
+func (h *HoldHandler) PlaceWorkHold(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid work ID", http.StatusBadRequest)
+		return
+	}
+	var request struct {
+		MemberID int `json:"member_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	memberID, ok := holdMemberID(w, r, request.MemberID)
+	if !ok {
+		return
+	}
+	hold, err := h.holdService.PlaceWorkHold(memberID, id)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeCreatedHold(w, hold)
+}
+
+// CancelHold handles DELETE requests from the logged-in member to cancel one of their holds
+func (h *HoldHandler) CancelHold(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid hold ID", http.StatusBadRequest)
+		return
+	}
+	memberID, ok := currentMemberID(w, r)
+	if !ok {
+		return
+	}
+	if err := h.holdService.CancelHold(id, memberID); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetHoldsByMemberID handles GET requests from the logged-in member for their own holds with their queue positions
+func (h *HoldHandler) GetHoldsByMemberID(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid member ID", http.StatusBadRequest)
+		return
+	}
+	if _, ok := holdMemberID(w, r, id); !ok {
+		return
+	}
+	holds, err := h.holdService.GetHoldsByMemberID(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, holds)
+}
+
+// GetHoldQueue handles GET requests for the queue of holds a book can fill
+func (h *HoldHandler) GetHoldQueue(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid book ID", http.StatusBadRequest)
+		return
+	}
+	queue, err := h.holdService.GetHoldQueue(id)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, queue)
+}
+
+// holdMemberID returns the logged-in member, writing a 403 response if requested names another member;
+// requested 0 means the logged-in member
+func holdMemberID(w http.ResponseWriter, r *http.Request, requested int) (int, bool) {
+	memberID, ok := currentMemberID(w, r)
+	if !ok {
+		return 0, false
+	}
+	if requested != 0 && requested != memberID {
+		http.Error(w, "Holds can only be managed by the member who places them", http.StatusForbidden)
+		return 0, false
+	}
+	return memberID, true
+}
+
+// writeCreatedHold writes a newly placed hold with status 201
+func writeCreatedHold(w http.ResponseWriter, hold *models.Hold) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, hold)
+}
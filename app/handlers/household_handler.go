@@ -0,0 +1,287 @@
+package handlers
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/services"
+	"Restful-Perpustakaan-API/app/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// HouseholdHandler handles HTTP requests for household accounts, guardian consent and acting for dependents
+type HouseholdHandler struct {
+	householdService *services.HouseholdService
+}
+
+// NewHouseholdHandler creates a new instance of HouseholdHandler
+func NewHouseholdHandler(householdService *services.HouseholdService) *HouseholdHandler {
+	return &HouseholdHandler{householdService: householdService}
+}
+
+// GetHouseholds handles GET requests for all households
+func (h *HouseholdHandler) GetHouseholds(w http.ResponseWriter, r *http.Request) {
+	households, err := h.householdService.GetHouseholds()
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, households)
+}
+
+// GetHousehold handles GET requests for a household with its members
+func (h *HouseholdHandler) GetHousehold(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid household ID", http.StatusBadRequest)
+		return
+	}
+	household, err := h.householdService.GetHousehold(id)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, household)
+}
+
+// CreateHousehold handles POST requests to create a household; body: {"name": "Keluarga Santoso", "primary_member_id": 1}
+func (h *HouseholdHandler) CreateHousehold(w http.ResponseWriter, r *http.Request) {
+	var household models.Household
+	if err := json.NewDecoder(r.Body).Decode(&household); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.householdService.CreateHousehold(&household); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, household)
+}
+
+// DeleteHousehold handles DELETE requests to dissolve a household
+func (h *HouseholdHandler) DeleteHousehold(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid household ID", http.StatusBadRequest)
+		return
+	}
+	if err := h.householdService.DeleteHousehold(id); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetHouseholdFines handles GET requests for the combined fines of a household
+func (h *HouseholdHandler) GetHouseholdFines(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid household ID", http.StatusBadRequest)
+		return
+	}
+	fines, err := h.householdService.GetHouseholdFines(id)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, fines)
+}
+
+// AddMember handles POST requests to link a dependent to a household;
+// body: {"member_id": 2, "guardian_can_hold": true, "guardian_can_renew": true}
+func (h *HouseholdHandler) AddMember(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid household ID", http.StatusBadRequest)
+		return
+	}
+	var member models.HouseholdMember
+	if err := json.NewDecoder(r.Body).Decode(&member); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	household, err := h.householdService.AddMember(id, &member)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, household)
+}
+
+// UpdateMember handles PUT requests to change the guardian permissions for a dependent;
+// body: {"guardian_can_hold": true, "guardian_can_renew": false}
+func (h *HouseholdHandler) UpdateMember(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid household ID", http.StatusBadRequest)
+		return
+	}
+	memberID, err := strconv.Atoi(mux.Vars(r)["memberId"])
+	if err != nil {
+		http.Error(w, "Invalid member ID", http.StatusBadRequest)
+		return
+	}
+	var member models.HouseholdMember
+	if err := json.NewDecoder(r.Body).Decode(&member); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	member.MemberID = memberID
+	household, err := h.householdService.UpdateMemberPermissions(id, &member)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, household)
+}
+
+// RemoveMember handles DELETE requests to unlink a dependent from a household
+func (h *HouseholdHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid household ID", http.StatusBadRequest)
+		return
+	}
+	memberID, err := strconv.Atoi(mux.Vars(r)["memberId"])
+	if err != nil {
+		http.Error(w, "Invalid member ID", http.StatusBadRequest)
+		return
+	}
+	if err := h.householdService.RemoveMember(id, memberID); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetMyHousehold handles GET requests for the household of the logged-in member
+func (h *HouseholdHandler) GetMyHousehold(w http.ResponseWriter, r *http.Request) {
+	memberID, ok := currentMemberID(w, r)
+	if !ok {
+		return
+	}
+	household, err := h.householdService.GetMyHousehold(memberID)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, household)
+}
+
+// GetMyHouseholdFines handles GET requests from a primary account for the combined fines of the household
+func (h *HouseholdHandler) GetMyHouseholdFines(w http.ResponseWriter, r *http.Request) {
+	memberID, ok := currentMemberID(w, r)
+	if !ok {
+		return
+	}
+	fines, err := h.householdService.GetMyHouseholdFines(memberID)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, fines)
+}
+
+// GetDependentLoans handles GET requests from a primary account for the current loans of its dependents
+func (h *HouseholdHandler) GetDependentLoans(w http.ResponseWriter, r *http.Request) {
+	memberID, ok := currentMemberID(w, r)
+	if !ok {
+		return
+	}
+	loans, err := h.householdService.GetDependentLoans(memberID)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, loans)
+}
+
+// GiveConsent handles PUT requests from a guardian to allow a minor dependent to borrow
+func (h *HouseholdHandler) GiveConsent(w http.ResponseWriter, r *http.Request) {
+	guardianID, dependentID, ok := guardianAndDependentIDs(w, r)
+	if !ok {
+		return
+	}
+	dependent, err := h.householdService.GiveConsent(guardianID, dependentID)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, dependent)
+}
+
+// WithdrawConsent handles DELETE requests from a guardian to withdraw the consent for a minor dependent
+func (h *HouseholdHandler) WithdrawConsent(w http.ResponseWriter, r *http.Request) {
+	guardianID, dependentID, ok := guardianAndDependentIDs(w, r)
+	if !ok {
+		return
+	}
+	if err := h.householdService.WithdrawConsent(guardianID, dependentID); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PlaceHoldFor handles POST requests from a guardian to place a hold for a dependent;
+// body: {"book_id": 12, "any_edition": true}
+func (h *HouseholdHandler) PlaceHoldFor(w http.ResponseWriter, r *http.Request) {
+	guardianID, dependentID, ok := guardianAndDependentIDs(w, r)
+	if !ok {
+		return
+	}
+	var request struct {
+		BookID     int  `json:"book_id"`
+		AnyEdition bool `json:"any_edition"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	hold, err := h.householdService.PlaceHoldFor(guardianID, dependentID, request.BookID, request.AnyEdition)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeCreatedHold(w, hold)
+}
+
+// RenewFor handles POST requests from a guardian to renew a loan of a dependent
+func (h *HouseholdHandler) RenewFor(w http.ResponseWriter, r *http.Request) {
+	guardianID, dependentID, ok := guardianAndDependentIDs(w, r)
+	if !ok {
+		return
+	}
+	loanID, err := strconv.Atoi(mux.Vars(r)["loanId"])
+	if err != nil {
+		http.Error(w, "Invalid loan ID", http.StatusBadRequest)
+		return
+	}
+	loan, err := h.householdService.RenewFor(guardianID, dependentID, loanID)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, loan)
+}
+
+// guardianAndDependentIDs reads the logged-in guardian from the JWT subject and the dependent from the path,
+// writing an error response if either is missing
+func guardianAndDependentIDs(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	guardianID, ok := currentMemberID(w, r)
+	if !ok {
+		return 0, 0, false
+	}
+	dependentID, err := strconv.Atoi(mux.Vars(r)["memberId"])
+	if err != nil {
+		http.Error(w, "Invalid member ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	return guardianID, dependentID, true
+}
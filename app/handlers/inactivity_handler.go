@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/services"
+	"Restful-Perpustakaan-API/app/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// InactivityHandler handles HTTP requests for the inactive member report and its bulk actions
+type InactivityHandler struct {
+	inactivityService *services.InactivityService
+}
+
+// NewInactivityHandler creates a new instance of InactivityHandler
+func NewInactivityHandler(inactivityService *services.InactivityService) *InactivityHandler {
+	return &InactivityHandler{inactivityService: inactivityService}
+}
+
+// GetInactiveMembers handles GET requests for the members without login or circulation activity;
+// ?months=6 sets the inactivity period (default 12)
+func (h *InactivityHandler) GetInactiveMembers(w http.ResponseWriter, r *http.Request) {
+	var months int
+	if value := r.URL.Query().Get("months"); value != "" {
+		var err error
+		if months, err = strconv.Atoi(value); err != nil || months < 1 {
+			http.Error(w, "Invalid months value", http.StatusBadRequest)
+			return
+		}
+	}
+	report, err := h.inactivityService.GetInactiveMembers(months)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, report)
+}
+
+// ApplyAction handles POST requests to expire or email inactive members in bulk;
+// body: {"action": "expire" | "email", "months": 12, "member_ids": [1, 2], "subject": "...", "message": "..."}
+// (member_ids empty for the whole report; subject and message only for email)
+func (h *InactivityHandler) ApplyAction(w http.ResponseWriter, r *http.Request) {
+	var action models.InactiveMemberAction
+	if err := json.NewDecoder(r.Body).Decode(&action); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	result, err := h.inactivityService.ApplyAction(action)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, result)
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/utils"
 	"encoding/json"
 	"net/http"
 	"strconv"
@@ -54,7 +55,7 @@ func (lh *LoanHandlers) CreateLoan(w http.ResponseWriter, r *http.Request) {
 	}
 	err = lh.loanService.CreateLoan(&newLoan)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		utils.HandleError(w, err)
 		return
 	}
 	w.WriteHeader(http.StatusCreated)
@@ -113,6 +114,40 @@ func (lh *LoanHandlers) GetLoansByMemberID(w http.ResponseWriter, r *http.Reques
 	writeJSON(w, loans)
 }
 
+// RenewLoan handles POST requests from the desk to extend the due date of any loan
+func (lh *LoanHandlers) RenewLoan(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid loan ID", http.StatusBadRequest)
+		return
+	}
+	loan, err := lh.loanService.RenewLoan(id)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, loan)
+}
+
+// RenewMyLoan handles POST requests from the logged-in member to extend the due date of one of their own loans
+func (lh *LoanHandlers) RenewMyLoan(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid loan ID", http.StatusBadRequest)
+		return
+	}
+	memberID, ok := currentMemberID(w, r)
+	if !ok {
+		return
+	}
+	loan, err := lh.loanService.RenewMemberLoan(id, memberID)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, loan)
+}
+
 // GetLoansByBookID handles GET requests to retrieve loans by book ID
 func (lh *LoanHandlers) GetLoansByBookID(w http.ResponseWriter, r *http.Request) {
 	id, err := getIDFromParams(r)
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/services"
+	"Restful-Perpustakaan-API/app/utils"
+	"encoding/json"
+	"net/http"
+)
+
+// LocationHandler handles HTTP requests for shelf locations
+type LocationHandler struct {
+	locationService *services.LocationService
+}
+
+// NewLocationHandler creates a new instance of LocationHandler
+func NewLocationHandler(locationService *services.LocationService) *LocationHandler {
+	return &LocationHandler{locationService: locationService}
+}
+
+// GetAllLocations handles GET requests to retrieve all locations
+func (h *LocationHandler) GetAllLocations(w http.ResponseWriter, r *http.Request) {
+	locations, err := h.locationService.GetAllLocations()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, locations)
+}
+
+// GetLocationByID handles GET requests to retrieve a location by ID
+func (h *LocationHandler) GetLocationByID(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid location ID", http.StatusBadRequest)
+		return
+	}
+	location, err := h.locationService.GetLocationByID(id)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, location)
+}
+
+// CreateLocation handles POST requests to create a new location
+func (h *LocationHandler) CreateLocation(w http.ResponseWriter, r *http.Request) {
+	var location models.Location
+	if err := json.NewDecoder(r.Body).Decode(&location); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	location.ID = 0
+	if err := h.locationService.CreateLocation(&location); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, location)
+}
+
+// UpdateLocation handles PUT requests to update a location
+func (h *LocationHandler) UpdateLocation(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid location ID", http.StatusBadRequest)
+		return
+	}
+	var location models.Location
+	if err := json.NewDecoder(r.Body).Decode(&location); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	location.ID = id
+	if err := h.locationService.UpdateLocation(&location); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, location)
+}
+
+// DeleteLocation handles DELETE requests to delete an empty location
+func (h *LocationHandler) DeleteLocation(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid location ID", http.StatusBadRequest)
+		return
+	}
+	if err := h.locationService.DeleteLocation(id); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetShelfList handles GET requests for the shelf-reading list of a location and its sub-locations,
+// ordered by call number
+func (h *LocationHandler) GetShelfList(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid location ID", http.StatusBadRequest)
+		return
+	}
+	entries, err := h.locationService.GetShelfList(id)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, entries)
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"Restful-Perpustakaan-API/app/services"
+	"Restful-Perpustakaan-API/app/utils"
+	"encoding/json"
+	"net/http"
+)
+
+// MembershipHandler handles HTTP requests for membership status, renewals, the fine ledger and expiry
+type MembershipHandler struct {
+	membershipService *services.MembershipService
+}
+
+// NewMembershipHandler creates a new instance of MembershipHandler
+func NewMembershipHandler(membershipService *services.MembershipService) *MembershipHandler {
+	return &MembershipHandler{membershipService: membershipService}
+}
+
+// GetMembership handles GET requests to retrieve the membership status and period of a member
+func (h *MembershipHandler) GetMembership(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid member ID", http.StatusBadRequest)
+		return
+	}
+	member, err := h.membershipService.GetMembership(id)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, member)
+}
+
+// UpdateStatus handles PUT requests to change the membership status; body: {"status": "suspended", "note": "..."}
+func (h *MembershipHandler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid member ID", http.StatusBadRequest)
+		return
+	}
+	var request struct {
+		Status string `json:"status"`
+		Note   string `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	actor, ok := currentActor(w, r)
+	if !ok {
+		return
+	}
+	member, err := h.membershipService.UpdateStatus(id, request.Status, request.Note, actor)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, member)
+}
+
+// Renew handles POST requests to renew a membership; body: {"months": 12, "fee": 25000};
+// months and fee default to the configured period and renewal fee
+func (h *MembershipHandler) Renew(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid member ID", http.StatusBadRequest)
+		return
+	}
+	var request struct {
+		Months int      `json:"months"`
+		Fee    *float64 `json:"fee"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	actor, ok := currentActor(w, r)
+	if !ok {
+		return
+	}
+	renewal, err := h.membershipService.Renew(id, request.Months, request.Fee, actor)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, renewal)
+}
+
+// GetRenewals handles GET requests for the renewal history of a member
+func (h *MembershipHandler) GetRenewals(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid member ID", http.StatusBadRequest)
+		return
+	}
+	renewals, err := h.membershipService.GetRenewals(id)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, renewals)
+}
+
+// GetFineLedger handles GET requests for the charges recorded in a member's fine ledger
+func (h *MembershipHandler) GetFineLedger(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid member ID", http.StatusBadRequest)
+		return
+	}
+	entries, err := h.membershipService.GetFineLedger(id)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, entries)
+}
+
+// RunExpiry handles POST requests to expire lapsed memberships and send expiry reminders immediately
+func (h *MembershipHandler) RunExpiry(w http.ResponseWriter, r *http.Request) {
+	result, err := h.membershipService.RunExpiry()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, result)
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/services"
+	"Restful-Perpustakaan-API/app/utils"
+	"encoding/json"
+	"net/http"
+)
+
+// ProfileHandler handles self-service HTTP requests of the logged-in member for their profile, password and email
+type ProfileHandler struct {
+	profileService *services.ProfileService
+}
+
+// NewProfileHandler creates a new instance of ProfileHandler
+func NewProfileHandler(profileService *services.ProfileService) *ProfileHandler {
+	return &ProfileHandler{profileService: profileService}
+}
+
+// GetMyProfile handles GET requests for the profile of the logged-in member
+func (h *ProfileHandler) GetMyProfile(w http.ResponseWriter, r *http.Request) {
+	memberID, ok := currentMemberID(w, r)
+	if !ok {
+		return
+	}
+	member, err := h.profileService.GetProfile(memberID)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, member)
+}
+
+// UpdateMyProfile handles PATCH requests to update the profile; only name, phone_number, address and gender
+// are accepted, any other field is rejected
+func (h *ProfileHandler) UpdateMyProfile(w http.ResponseWriter, r *http.Request) {
+	memberID, ok := currentMemberID(w, r)
+	if !ok {
+		return
+	}
+	var update models.ProfileUpdate
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&update); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	member, err := h.profileService.UpdateProfile(memberID, &update)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, member)
+}
+
+// ChangeMyPassword handles PUT requests to change the password;
+// body: {"current_password": "...", "new_password": "..."}
+func (h *ProfileHandler) ChangeMyPassword(w http.ResponseWriter, r *http.Request) {
+	memberID, ok := currentMemberID(w, r)
+	if !ok {
+		return
+	}
+	var change models.PasswordChange
+	if err := json.NewDecoder(r.Body).Decode(&change); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.profileService.ChangePassword(memberID, &change); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RequestEmailChange handles POST requests to change the email address; a verification code is sent to the new
+// address. Body: {"email": "new@example.com", "password": "..."}
+func (h *ProfileHandler) RequestEmailChange(w http.ResponseWriter, r *http.Request) {
+	memberID, ok := currentMemberID(w, r)
+	if !ok {
+		return
+	}
+	var request struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	change, err := h.profileService.RequestEmailChange(memberID, request.Email, request.Password)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	writeJSON(w, change)
+}
+
+// VerifyEmailChange handles POST requests confirming the new email address; body: {"token": "..."}
+func (h *ProfileHandler) VerifyEmailChange(w http.ResponseWriter, r *http.Request) {
+	memberID, ok := currentMemberID(w, r)
+	if !ok {
+		return
+	}
+	var request struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	member, err := h.profileService.ConfirmEmailChange(memberID, request.Token)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, member)
+}
+
+// GetMyDashboard handles GET requests for the current loans, holds, fines and unread notifications of the
+// logged-in member
+func (h *ProfileHandler) GetMyDashboard(w http.ResponseWriter, r *http.Request) {
+	memberID, ok := currentMemberID(w, r)
+	if !ok {
+		return
+	}
+	dashboard, err := h.profileService.GetDashboard(memberID)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, dashboard)
+}
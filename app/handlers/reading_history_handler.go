@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"Restful-Perpustakaan-API/app/middleware"
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/services"
+	"Restful-Perpustakaan-API/app/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// ReadingHistoryHandler handles HTTP requests for the logged-in member's reading history and privacy settings
+type ReadingHistoryHandler struct {
+	readingHistoryService *services.ReadingHistoryService
+}
+
+// NewReadingHistoryHandler creates a new instance of ReadingHistoryHandler
+func NewReadingHistoryHandler(readingHistoryService *services.ReadingHistoryService) *ReadingHistoryHandler {
+	return &ReadingHistoryHandler{readingHistoryService: readingHistoryService}
+}
+
+// GetMyReadingHistory handles GET requests for the reading history of the logged-in member
+func (h *ReadingHistoryHandler) GetMyReadingHistory(w http.ResponseWriter, r *http.Request) {
+	memberID, ok := currentMemberID(w, r)
+	if !ok {
+		return
+	}
+	history, err := h.readingHistoryService.GetReadingHistory(memberID)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, history)
+}
+
+// GetMyPrivacySettings handles GET requests for the privacy settings of the logged-in member
+func (h *ReadingHistoryHandler) GetMyPrivacySettings(w http.ResponseWriter, r *http.Request) {
+	memberID, ok := currentMemberID(w, r)
+	if !ok {
+		return
+	}
+	settings, err := h.readingHistoryService.GetPrivacySettings(memberID)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, settings)
+}
+
+// UpdateMyPrivacySettings handles PUT requests to opt in to or out of the reading history;
+// body: {"reading_history_enabled": false}
+func (h *ReadingHistoryHandler) UpdateMyPrivacySettings(w http.ResponseWriter, r *http.Request) {
+	memberID, ok := currentMemberID(w, r)
+	if !ok {
+		return
+	}
+	var settings models.PrivacySettings
+	if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.readingHistoryService.UpdatePrivacySettings(memberID, &settings); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, settings)
+}
+
+// currentMemberID reads the member ID from the JWT subject, writing a 401 response if it is missing
+func currentMemberID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	memberID, err := strconv.Atoi(middleware.ClaimsSubject(r))
+	if err != nil {
+		http.Error(w, "Member token required", http.StatusUnauthorized)
+		return 0, false
+	}
+	return memberID, true
+}
+
+// currentActor reads the JWT subject recorded as the actor of an admin action, writing a 401 response if it is missing
+func currentActor(w http.ResponseWriter, r *http.Request) (string, bool) {
+	actor := middleware.ClaimsSubject(r)
+	if actor == "" {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return "", false
+	}
+	return actor, true
+}
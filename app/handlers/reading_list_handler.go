@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"Restful-Perpustakaan-API/app/middleware"
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/services"
+	"Restful-Perpustakaan-API/app/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// ReadingListHandler handles HTTP requests for members' reading lists and wishlists
+type ReadingListHandler struct {
+	readingListService *services.ReadingListService
+}
+
+// NewReadingListHandler creates a new instance of ReadingListHandler
+func NewReadingListHandler(readingListService *services.ReadingListService) *ReadingListHandler {
+	return &ReadingListHandler{readingListService: readingListService}
+}
+
+// GetLists handles GET requests for a member's reading lists; other members only see public lists
+func (h *ReadingListHandler) GetLists(w http.ResponseWriter, r *http.Request) {
+	memberID, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid member ID", http.StatusBadRequest)
+		return
+	}
+	lists, err := h.readingListService.GetLists(memberID, viewerMemberID(r))
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, lists)
+}
+
+// GetPublicLists handles GET requests for all public reading lists
+func (h *ReadingListHandler) GetPublicLists(w http.ResponseWriter, r *http.Request) {
+	lists, err := h.readingListService.GetPublicLists()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, lists)
+}
+
+// GetList handles GET requests for a single reading list with its items
+func (h *ReadingListHandler) GetList(w http.ResponseWriter, r *http.Request) {
+	memberID, listID, ok := parseListParams(w, r)
+	if !ok {
+		return
+	}
+	list, err := h.readingListService.GetList(memberID, listID, viewerMemberID(r))
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, list)
+}
+
+// GetSharedList handles GET requests for a reading list opened through its share link
+func (h *ReadingListHandler) GetSharedList(w http.ResponseWriter, r *http.Request) {
+	list, err := h.readingListService.GetSharedList(mux.Vars(r)["token"], viewerMemberID(r))
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, list)
+}
+
+// CreateList handles POST requests to create a reading list;
+// body: {"name": "Want to read", "description": "", "visibility": "private|shared|public"}
+func (h *ReadingListHandler) CreateList(w http.ResponseWriter, r *http.Request) {
+	memberID, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid member ID", http.StatusBadRequest)
+		return
+	}
+	var list models.ReadingList
+	if err := json.NewDecoder(r.Body).Decode(&list); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.readingListService.CreateList(memberID, viewerMemberID(r), &list); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, list)
+}
+
+// UpdateList handles PUT requests to update a reading list's name, description and visibility
+func (h *ReadingListHandler) UpdateList(w http.ResponseWriter, r *http.Request) {
+	memberID, listID, ok := parseListParams(w, r)
+	if !ok {
+		return
+	}
+	var update models.ReadingList
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	list, err := h.readingListService.UpdateList(memberID, listID, viewerMemberID(r), &update)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, list)
+}
+
+// DeleteList handles DELETE requests to remove a reading list
+func (h *ReadingListHandler) DeleteList(w http.ResponseWriter, r *http.Request) {
+	memberID, listID, ok := parseListParams(w, r)
+	if !ok {
+		return
+	}
+	if err := h.readingListService.DeleteList(memberID, listID, viewerMemberID(r)); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RegenerateShareLink handles POST requests to replace a reading list's share link
+func (h *ReadingListHandler) RegenerateShareLink(w http.ResponseWriter, r *http.Request) {
+	memberID, listID, ok := parseListParams(w, r)
+	if !ok {
+		return
+	}
+	list, err := h.readingListService.RegenerateShareLink(memberID, listID, viewerMemberID(r))
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, list)
+}
+
+// AddItem handles POST requests to add a book to a reading list; body: {"book_id": 12, "note": "chapter 1-3"}
+func (h *ReadingListHandler) AddItem(w http.ResponseWriter, r *http.Request) {
+	memberID, listID, ok := parseListParams(w, r)
+	if !ok {
+		return
+	}
+	var item models.ReadingListItem
+	if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.readingListService.AddItem(memberID, listID, viewerMemberID(r), &item); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, item)
+}
+
+// UpdateItem handles PUT requests to change the note of a reading list item; body: {"note": "..."}
+func (h *ReadingListHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	memberID, listID, ok := parseListParams(w, r)
+	if !ok {
+		return
+	}
+	itemID, err := strconv.Atoi(mux.Vars(r)["itemId"])
+	if err != nil {
+		http.Error(w, "Invalid item ID", http.StatusBadRequest)
+		return
+	}
+	var request struct {
+		Note string `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	item, err := h.readingListService.UpdateItemNote(memberID, listID, itemID, viewerMemberID(r), request.Note)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, item)
+}
+
+// RemoveItem handles DELETE requests to remove a book from a reading list
+func (h *ReadingListHandler) RemoveItem(w http.ResponseWriter, r *http.Request) {
+	memberID, listID, ok := parseListParams(w, r)
+	if !ok {
+		return
+	}
+	itemID, err := strconv.Atoi(mux.Vars(r)["itemId"])
+	if err != nil {
+		http.Error(w, "Invalid item ID", http.StatusBadRequest)
+		return
+	}
+	if err := h.readingListService.RemoveItem(memberID, listID, itemID, viewerMemberID(r)); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ReorderItems handles PUT requests to reorder a reading list; body: {"item_ids": [3, 1, 2]}
+func (h *ReadingListHandler) ReorderItems(w http.ResponseWriter, r *http.Request) {
+	memberID, listID, ok := parseListParams(w, r)
+	if !ok {
+		return
+	}
+	var request struct {
+		ItemIDs []int `json:"item_ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	list, err := h.readingListService.ReorderItems(memberID, listID, viewerMemberID(r), request.ItemIDs)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, list)
+}
+
+// PlaceHolds handles POST requests to place holds for the logged-in member on every book of a reading list
+func (h *ReadingListHandler) PlaceHolds(w http.ResponseWriter, r *http.Request) {
+	memberID, listID, ok := parseListParams(w, r)
+	if !ok {
+		return
+	}
+	result, err := h.readingListService.PlaceHolds(memberID, listID, viewerMemberID(r))
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, result)
+}
+
+// PlaceSharedHolds handles POST requests to place holds for the logged-in member on every book of a reading list
+// opened through its share link
+func (h *ReadingListHandler) PlaceSharedHolds(w http.ResponseWriter, r *http.Request) {
+	result, err := h.readingListService.PlaceSharedHolds(mux.Vars(r)["token"], viewerMemberID(r))
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, result)
+}
+
+// parseListParams reads the {id} and {listId} path parameters, writing a 400 response if either is invalid
+func parseListParams(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	memberID, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid member ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	listID, err := strconv.Atoi(mux.Vars(r)["listId"])
+	if err != nil {
+		http.Error(w, "Invalid list ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	return memberID, listID, true
+}
+
+// viewerMemberID returns the member ID from the JWT subject, or 0 for anonymous requests
+func viewerMemberID(r *http.Request) int {
+	memberID, err := strconv.Atoi(middleware.ClaimsSubject(r))
+	if err != nil {
+		return 0
+	}
+	return memberID
+}
@@ -1,41 +1,50 @@
 package handlers
 
 import (
-	"encoding/json"
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/services"
+	"Restful-Perpustakaan-API/app/utils"
 	"net/http"
-	"strconv"
-
-	"Restful-Perpustakaan-API/database" // Ganti dengan path yang sesuai
-	"github.com/gorilla/mux"
 )
 
-// GetRecommendations mengambil rekomendasi buku umum dari database.
-func GetRecommendations(w http.ResponseWriter, r *http.Request) {
-	recommendations, err := database.GetRecommendations()
+// RecommendationHandler handles HTTP requests for book recommendations
+type RecommendationHandler struct {
+	recommendationService *services.RecommendationService
+}
+
+// NewRecommendationHandler creates a new instance of RecommendationHandler
+func NewRecommendationHandler(recommendationService *services.RecommendationService) *RecommendationHandler {
+	return &RecommendationHandler{recommendationService: recommendationService}
+}
+
+// GetRecommendations mengambil rekomendasi buku umum.
+func (h *RecommendationHandler) GetRecommendations(w http.ResponseWriter, r *http.Request) {
+	recommendations, err := h.recommendationService.GetRecommendations()
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(recommendations)
+	if recommendations == nil {
+		recommendations = []models.Book{}
+	}
+	writeJSON(w, recommendations)
 }
 
-// GetPersonalizedRecommendations mengambil rekomendasi buku yang dipersonalisasi berdasarkan ID anggota.
-func GetPersonalizedRecommendations(w http.ResponseWriter, r *http.Request) {
-	params := mux.Vars(r)
-	memberID, err := strconv.Atoi(params["memberId"])
-	if err != nil {
-		http.Error(w, "Invalid member ID", http.StatusBadRequest)
+// GetMyRecommendations mengambil rekomendasi buku yang dipersonalisasi untuk anggota yang sedang login.
+// Rekomendasi diturunkan dari riwayat baca, sehingga hanya pemiliknya yang boleh melihatnya.
+func (h *RecommendationHandler) GetMyRecommendations(w http.ResponseWriter, r *http.Request) {
+	memberID, ok := currentMemberID(w, r)
+	if !ok {
 		return
 	}
 
-	recommendations, err := database.GetPersonalizedRecommendations(memberID)
+	recommendations, err := h.recommendationService.GetPersonalizedRecommendations(memberID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		utils.HandleError(w, err)
 		return
 	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(recommendations)
+	if recommendations == nil {
+		recommendations = []models.Book{}
+	}
+	writeJSON(w, recommendations)
 }
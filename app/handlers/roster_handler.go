@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/services"
+	"Restful-Perpustakaan-API/app/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RosterHandler handles HTTP requests for importing school and university rosters as members
+type RosterHandler struct {
+	rosterService *services.RosterService
+}
+
+// NewRosterHandler creates a new instance of RosterHandler
+func NewRosterHandler(rosterService *services.RosterService) *RosterHandler {
+	return &RosterHandler{rosterService: rosterService}
+}
+
+// ImportMembers handles POST requests to import a roster from a CSV or JSONL body. Members are matched by
+// student_id, then by email. Query parameters: roster (name of the roster, required), format (csv|jsonl),
+// mapping ("NIM:student_id,Nama:name"), membership_type, membership_end (YYYY-MM-DD), dry_run, full_roster
+// (deactivate members of this roster missing from the file) and send_welcome (email a password setup link).
+func (h *RosterHandler) ImportMembers(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	actor, ok := currentActor(w, r)
+	if !ok {
+		return
+	}
+	opts := models.RosterImportOptions{
+		Format:         query.Get("format"),
+		Roster:         query.Get("roster"),
+		MembershipType: query.Get("membership_type"),
+		ImportedBy:     actor,
+	}
+	if opts.Format == "" {
+		opts.Format = formatFromContentType(r.Header.Get("Content-Type"))
+	}
+	if value := query.Get("membership_end"); value != "" {
+		end, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			http.Error(w, "Invalid membership_end, expected YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		opts.MembershipEnd = &end
+	}
+	for name, target := range map[string]*bool{
+		"dry_run":      &opts.DryRun,
+		"full_roster":  &opts.FullRoster,
+		"send_welcome": &opts.SendWelcome,
+	} {
+		if value := query.Get(name); value != "" {
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				http.Error(w, "Invalid "+name+" value", http.StatusBadRequest)
+				return
+			}
+			*target = parsed
+		}
+	}
+	mapping, err := parseColumnMapping(query.Get("mapping"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	opts.Mapping = mapping
+
+	result, err := h.rosterService.ImportRoster(http.MaxBytesReader(w, r.Body, maxImportSize), opts)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case result.Failed > 0:
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	case !result.DryRun && result.Created > 0:
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(result)
+}
+
+// SetupPassword handles POST requests from the welcome email link to set the first password of an imported
+// member; body: {"token": "...", "password": "..."}
+func (h *RosterHandler) SetupPassword(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Token    string `json:"token"`
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.rosterService.SetupPassword(request.Token, request.Password); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/services"
+	"Restful-Perpustakaan-API/app/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// defaultPredictedIssues adalah jumlah nomor terbitan yang diprediksi jika ?count= tidak diisi
+const defaultPredictedIssues = 12
+
+// SerialHandler handles HTTP requests for serial subscriptions, their issues, claims and routing lists
+type SerialHandler struct {
+	serialService *services.SerialService
+}
+
+// NewSerialHandler creates a new instance of SerialHandler
+func NewSerialHandler(serialService *services.SerialService) *SerialHandler {
+	return &SerialHandler{serialService: serialService}
+}
+
+// GetAllSerials handles GET requests to retrieve all serials
+func (h *SerialHandler) GetAllSerials(w http.ResponseWriter, r *http.Request) {
+	serials, err := h.serialService.GetAllSerials()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, serials)
+}
+
+// GetSerialByID handles GET requests to retrieve a serial by ID
+func (h *SerialHandler) GetSerialByID(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid serial ID", http.StatusBadRequest)
+		return
+	}
+	serial, err := h.serialService.GetSerialByID(id)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, serial)
+}
+
+// CreateSerial handles POST requests to subscribe to a serial;
+// body: {"title": "Tempo", "issn": "0126-4273", "publisher": "...", "frequency": "weekly", "first_issue_date": "2026-01-05T00:00:00Z", "claim_grace_days": 7, "vendor_id": 1}
+func (h *SerialHandler) CreateSerial(w http.ResponseWriter, r *http.Request) {
+	var serial models.Serial
+	if err := json.NewDecoder(r.Body).Decode(&serial); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	serial.ID = 0
+	if err := h.serialService.CreateSerial(&serial); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, serial)
+}
+
+// UpdateSerial handles PUT requests to update a serial subscription
+func (h *SerialHandler) UpdateSerial(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid serial ID", http.StatusBadRequest)
+		return
+	}
+	var serial models.Serial
+	if err := json.NewDecoder(r.Body).Decode(&serial); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	serial.ID = id
+	if err := h.serialService.UpdateSerial(&serial); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, serial)
+}
+
+// DeleteSerial handles DELETE requests to remove a serial without received issues
+func (h *SerialHandler) DeleteSerial(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid serial ID", http.StatusBadRequest)
+		return
+	}
+	if err := h.serialService.DeleteSerial(id); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetIssues handles GET requests to retrieve the issues of a serial
+func (h *SerialHandler) GetIssues(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid serial ID", http.StatusBadRequest)
+		return
+	}
+	issues, err := h.serialService.GetIssues(id)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, issues)
+}
+
+// PredictIssues handles POST requests to predict the next issues of a serial; optional ?count= (default 12)
+func (h *SerialHandler) PredictIssues(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid serial ID", http.StatusBadRequest)
+		return
+	}
+	count := defaultPredictedIssues
+	if value := r.URL.Query().Get("count"); value != "" {
+		if count, err = strconv.Atoi(value); err != nil {
+			http.Error(w, "Invalid count", http.StatusBadRequest)
+			return
+		}
+	}
+	issues, err := h.serialService.PredictIssues(id, count)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, issues)
+}
+
+// CheckInIssue handles POST requests to check in a received issue; body: {"barcode": "S000123", "location_id": 4}
+func (h *SerialHandler) CheckInIssue(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid issue ID", http.StatusBadRequest)
+		return
+	}
+	var req models.SerialCheckIn
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	issue, err := h.serialService.CheckInIssue(id, &req)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, issue)
+}
+
+// GetClaims handles GET requests for overdue issues that should be claimed from the vendor
+func (h *SerialHandler) GetClaims(w http.ResponseWriter, r *http.Request) {
+	claims, err := h.serialService.GetClaims()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, claims)
+}
+
+// ClaimIssue handles POST requests to record a claim for a missing issue
+func (h *SerialHandler) ClaimIssue(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid issue ID", http.StatusBadRequest)
+		return
+	}
+	issue, err := h.serialService.ClaimIssue(id)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, issue)
+}
+
+// GetRouting handles GET requests for the routing list of a serial
+func (h *SerialHandler) GetRouting(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid serial ID", http.StatusBadRequest)
+		return
+	}
+	entries, err := h.serialService.GetRouting(id)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, entries)
+}
+
+// SetRouting handles PUT requests to replace the routing list of a serial; body: {"member_ids": [12, 7, 31]}
+func (h *SerialHandler) SetRouting(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid serial ID", http.StatusBadRequest)
+		return
+	}
+	var request struct {
+		MemberIDs []int `json:"member_ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	entries, err := h.serialService.SetRouting(id, request.MemberIDs)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, entries)
+}
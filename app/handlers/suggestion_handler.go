@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/services"
+	"Restful-Perpustakaan-API/app/utils"
+	"encoding/json"
+	"net/http"
+)
+
+// SuggestionHandler handles HTTP requests for purchase suggestions and the acquisition workflow
+type SuggestionHandler struct {
+	suggestionService *services.SuggestionService
+}
+
+// NewSuggestionHandler creates a new instance of SuggestionHandler
+func NewSuggestionHandler(suggestionService *services.SuggestionService) *SuggestionHandler {
+	return &SuggestionHandler{suggestionService: suggestionService}
+}
+
+// GetSuggestions handles GET requests for purchase suggestions, most voted first; optional ?status=
+func (h *SuggestionHandler) GetSuggestions(w http.ResponseWriter, r *http.Request) {
+	suggestions, err := h.suggestionService.GetSuggestions(r.URL.Query().Get("status"), viewerMemberID(r))
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, suggestions)
+}
+
+// GetSuggestionByID handles GET requests for a single purchase suggestion
+func (h *SuggestionHandler) GetSuggestionByID(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid suggestion ID", http.StatusBadRequest)
+		return
+	}
+	suggestion, err := h.suggestionService.GetSuggestionByID(id, viewerMemberID(r))
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, suggestion)
+}
+
+// GetMySuggestions handles GET requests for the purchase suggestions of the logged-in member
+func (h *SuggestionHandler) GetMySuggestions(w http.ResponseWriter, r *http.Request) {
+	memberID, ok := currentMemberID(w, r)
+	if !ok {
+		return
+	}
+	suggestions, err := h.suggestionService.GetMySuggestions(memberID)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, suggestions)
+}
+
+// CreateSuggestion handles POST requests to suggest a book for purchase;
+// body: {"isbn": "9786020332956", "title": "Laut Bercerita", "author": "Leila S. Chudori", "justification": "..."}
+func (h *SuggestionHandler) CreateSuggestion(w http.ResponseWriter, r *http.Request) {
+	memberID, ok := currentMemberID(w, r)
+	if !ok {
+		return
+	}
+	var suggestion models.PurchaseSuggestion
+	if err := json.NewDecoder(r.Body).Decode(&suggestion); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.suggestionService.CreateSuggestion(memberID, &suggestion); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, suggestion)
+}
+
+// Vote handles POST requests to vote for a purchase suggestion as the logged-in member
+func (h *SuggestionHandler) Vote(w http.ResponseWriter, r *http.Request) {
+	memberID, ok := currentMemberID(w, r)
+	if !ok {
+		return
+	}
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid suggestion ID", http.StatusBadRequest)
+		return
+	}
+	suggestion, err := h.suggestionService.Vote(id, memberID)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, suggestion)
+}
+
+// Unvote handles DELETE requests to withdraw the logged-in member's vote
+func (h *SuggestionHandler) Unvote(w http.ResponseWriter, r *http.Request) {
+	memberID, ok := currentMemberID(w, r)
+	if !ok {
+		return
+	}
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid suggestion ID", http.StatusBadRequest)
+		return
+	}
+	if err := h.suggestionService.Unvote(id, memberID); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UpdateStatus handles PUT requests from librarians to move a suggestion through the workflow;
+// body: {"status": "under_review|ordered|received|rejected", "note": "", "book_id": 12}
+func (h *SuggestionHandler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid suggestion ID", http.StatusBadRequest)
+		return
+	}
+	var update models.SuggestionStatusUpdate
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	suggestion, err := h.suggestionService.UpdateStatus(id, &update)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, suggestion)
+}
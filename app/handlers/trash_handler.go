@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"Restful-Perpustakaan-API/app/services"
+	"Restful-Perpustakaan-API/app/utils"
+	"net/http"
+)
+
+// TrashHandler handles HTTP requests for soft-deleting, restoring and purging books and members
+type TrashHandler struct {
+	trashService  *services.TrashService
+	bookService   *services.BookService
+	memberService *services.MemberService
+}
+
+// NewTrashHandler creates a new instance of TrashHandler
+func NewTrashHandler(trashService *services.TrashService, bookService *services.BookService, memberService *services.MemberService) *TrashHandler {
+	return &TrashHandler{
+		trashService:  trashService,
+		bookService:   bookService,
+		memberService: memberService,
+	}
+}
+
+// DeleteBook handles DELETE requests to move a book to the trash
+func (h *TrashHandler) DeleteBook(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid book ID", http.StatusBadRequest)
+		return
+	}
+	actor, ok := currentActor(w, r)
+	if !ok {
+		return
+	}
+	if err := h.bookService.DeleteBook(id, actor); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteMember handles DELETE requests to move a member to the trash
+func (h *TrashHandler) DeleteMember(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid member ID", http.StatusBadRequest)
+		return
+	}
+	actor, ok := currentActor(w, r)
+	if !ok {
+		return
+	}
+	if err := h.memberService.DeleteMember(id, actor); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetTrash handles GET requests to list soft-deleted books and members
+func (h *TrashHandler) GetTrash(w http.ResponseWriter, r *http.Request) {
+	trash, err := h.trashService.GetTrash()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, trash)
+}
+
+// RestoreBook handles POST requests to restore a book from the trash
+func (h *TrashHandler) RestoreBook(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid book ID", http.StatusBadRequest)
+		return
+	}
+	actor, ok := currentActor(w, r)
+	if !ok {
+		return
+	}
+	if err := h.trashService.RestoreBook(id, actor); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RestoreMember handles POST requests to restore a member from the trash
+func (h *TrashHandler) RestoreMember(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid member ID", http.StatusBadRequest)
+		return
+	}
+	actor, ok := currentActor(w, r)
+	if !ok {
+		return
+	}
+	if err := h.trashService.RestoreMember(id, actor); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Purge handles POST requests to permanently delete records older than the retention period
+func (h *TrashHandler) Purge(w http.ResponseWriter, r *http.Request) {
+	result, err := h.trashService.Purge()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, result)
+}
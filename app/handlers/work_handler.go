@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"Restful-Perpustakaan-API/app/middleware"
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/services"
+	"Restful-Perpustakaan-API/app/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// WorkHandler handles HTTP requests for works, their editions and series
+type WorkHandler struct {
+	workService *services.WorkService
+}
+
+// NewWorkHandler creates a new instance of WorkHandler
+func NewWorkHandler(workService *services.WorkService) *WorkHandler {
+	return &WorkHandler{workService: workService}
+}
+
+// GetAllWorks handles GET requests to retrieve all works
+func (h *WorkHandler) GetAllWorks(w http.ResponseWriter, r *http.Request) {
+	works, err := h.workService.GetAllWorks()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, works)
+}
+
+// GetWorkByID handles GET requests to retrieve a work with its editions
+func (h *WorkHandler) GetWorkByID(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid work ID", http.StatusBadRequest)
+		return
+	}
+	work, err := h.workService.GetWorkByID(id)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, work)
+}
+
+// CreateWork handles POST requests to create a new work
+func (h *WorkHandler) CreateWork(w http.ResponseWriter, r *http.Request) {
+	var work models.Work
+	if err := json.NewDecoder(r.Body).Decode(&work); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	work.ID = 0
+	work.Editions = nil
+	if err := h.workService.CreateWork(&work); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, work)
+}
+
+// UpdateWork handles PUT requests to update a work
+func (h *WorkHandler) UpdateWork(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid work ID", http.StatusBadRequest)
+		return
+	}
+	var work models.Work
+	if err := json.NewDecoder(r.Body).Decode(&work); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	work.ID = id
+	work.Editions = nil
+	if err := h.workService.UpdateWork(&work); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, work)
+}
+
+// DeleteWork handles DELETE requests to delete a work; its editions are kept
+func (h *WorkHandler) DeleteWork(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid work ID", http.StatusBadRequest)
+		return
+	}
+	if err := h.workService.DeleteWork(id); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddEdition handles POST requests to link a book to a work; body: {"book_id": 12}
+func (h *WorkHandler) AddEdition(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid work ID", http.StatusBadRequest)
+		return
+	}
+	var request struct {
+		BookID int `json:"book_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	book, err := h.workService.AddEdition(id, request.BookID, middleware.ClaimsSubject(r))
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, book)
+}
+
+// RemoveEdition handles DELETE requests to unlink a book from a work
+func (h *WorkHandler) RemoveEdition(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid work ID", http.StatusBadRequest)
+		return
+	}
+	bookID, err := strconv.Atoi(mux.Vars(r)["bookId"])
+	if err != nil {
+		http.Error(w, "Invalid book ID", http.StatusBadRequest)
+		return
+	}
+	if err := h.workService.RemoveEdition(id, bookID, middleware.ClaimsSubject(r)); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetWorkRating handles GET requests for the average rating of a work across all of its editions
+func (h *WorkHandler) GetWorkRating(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid work ID", http.StatusBadRequest)
+		return
+	}
+	rating, err := h.workService.GetWorkRating(id)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, rating)
+}
+
+// GetAllSeries handles GET requests to retrieve all series
+func (h *WorkHandler) GetAllSeries(w http.ResponseWriter, r *http.Request) {
+	series, err := h.workService.GetAllSeries()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, series)
+}
+
+// GetSeriesByID handles GET requests to retrieve a series with its works in volume order
+func (h *WorkHandler) GetSeriesByID(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid series ID", http.StatusBadRequest)
+		return
+	}
+	series, err := h.workService.GetSeriesByID(id)
+	if err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, series)
+}
+
+// CreateSeries handles POST requests to create a new series
+func (h *WorkHandler) CreateSeries(w http.ResponseWriter, r *http.Request) {
+	var series models.Series
+	if err := json.NewDecoder(r.Body).Decode(&series); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	series.ID = 0
+	series.Works = nil
+	if err := h.workService.CreateSeries(&series); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, series)
+}
+
+// UpdateSeries handles PUT requests to update a series
+func (h *WorkHandler) UpdateSeries(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid series ID", http.StatusBadRequest)
+		return
+	}
+	var series models.Series
+	if err := json.NewDecoder(r.Body).Decode(&series); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	series.ID = id
+	series.Works = nil
+	if err := h.workService.UpdateSeries(&series); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	writeJSON(w, series)
+}
+
+// DeleteSeries handles DELETE requests to delete a series; its works are kept
+func (h *WorkHandler) DeleteSeries(w http.ResponseWriter, r *http.Request) {
+	id, err := getIDFromParams(r)
+	if err != nil {
+		http.Error(w, "Invalid series ID", http.StatusBadRequest)
+		return
+	}
+	if err := h.workService.DeleteSeries(id); err != nil {
+		utils.HandleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
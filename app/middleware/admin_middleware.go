@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+)
+
+// AdminMiddleware adalah middleware yang hanya meneruskan request dari pengguna dengan peran admin.
+// Harus dipasang setelah AuthMiddleware; isAdmin memeriksa subject token JWT.
+func AdminMiddleware(isAdmin func(subject string) (bool, error)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			subject := ClaimsSubject(r)
+			if subject == "" {
+				http.Error(w, "Authentication required", http.StatusUnauthorized)
+				return
+			}
+
+			admin, err := isAdmin(subject)
+			if err != nil {
+				http.Error(w, "Failed to check permissions", http.StatusInternalServerError)
+				return
+			}
+			if !admin {
+				http.Error(w, "Admin access required", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestAdminMiddleware(t *testing.T) {
+	isAdmin := func(subject string) (bool, error) {
+		switch subject {
+		case "1":
+			return true, nil
+		case "3":
+			return false, errors.New("database unavailable")
+		}
+		return false, nil
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	handler := AdminMiddleware(isAdmin)(next)
+
+	tests := []struct {
+		name    string
+		subject string
+		want    int
+	}{
+		{"admin", "1", http.StatusNoContent},
+		{"member", "2", http.StatusForbidden},
+		{"lookup error", "3", http.StatusInternalServerError},
+		{"no token", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/admin/trash/purge", nil)
+			if tt.subject != "" {
+				r = r.WithContext(context.WithValue(r.Context(), "claims", &jwt.StandardClaims{Subject: tt.subject}))
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
@@ -18,9 +18,7 @@ func AuthMiddleware(next http.Handler) http.Handler {
 		}
 
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-		token, err := jwt.ParseWithClaims(tokenString, &jwt.StandardClaims{}, func(token *jwt.Token) (interface{}, error) {
-			return []byte("your_secret_key"), nil // Ganti dengan kunci rahasia Anda
-		})
+		token, err := parseToken(tokenString)
 
 		if err != nil || !token.Valid {
 			http.Error(w, "Invalid token", http.StatusUnauthorized)
@@ -38,3 +36,43 @@ func AuthMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// OptionalAuthMiddleware menyimpan claims token JWT jika header Authorization ada dan valid,
+// tetapi tetap meneruskan request tanpa token (misalnya untuk konten publik)
+func OptionalAuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader := r.Header.Get("Authorization")
+		if authHeader == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token, err := parseToken(strings.TrimPrefix(authHeader, "Bearer "))
+		if err != nil || !token.Valid {
+			http.Error(w, "Invalid token", http.StatusUnauthorized)
+			return
+		}
+		if claims, ok := token.Claims.(*jwt.StandardClaims); ok {
+			r = r.WithContext(context.WithValue(r.Context(), "claims", claims))
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// parseToken mem-parse dan memverifikasi token JWT
+func parseToken(tokenString string) (*jwt.Token, error) {
+	return jwt.ParseWithClaims(tokenString, &jwt.StandardClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return []byte("your_secret_key"), nil // Ganti dengan kunci rahasia Anda
+	})
+}
+
+// ClaimsSubject mengembalikan subject token JWT yang disimpan AuthMiddleware, atau string kosong
+// jika request tidak melewati AuthMiddleware
+func ClaimsSubject(r *http.Request) string {
+	claims, ok := r.Context().Value("claims").(*jwt.StandardClaims)
+	if !ok {
+		return ""
+	}
+	return claims.Subject
+}
@@ -2,17 +2,22 @@ package models
 
 // BookModel represents a book in the library
 type BookModel struct {
-	ID            int     `json:"id"`
-	Title         string  `json:"title"`
-	Year          int     `json:"year"`
-	Rating        float64 `json:"rating"`
-	Author        string  `json:"author"`
-	Publisher     string  `json:"publisher"`
-	PublishedYear int     `json:"published_year"`
-	ISBN          string  `json:"isbn"`
-	Genre         string  `json:"genre"`
-	Description   string  `json:"description"`
-	CoverImage    string  `json:"cover_image"`
+	ID             int     `json:"id"`
+	Title          string  `json:"title"`
+	Year           int     `json:"year"`
+	Rating         float64 `json:"rating"`
+	Author         string  `json:"author"`
+	Publisher      string  `json:"publisher"`
+	PublishedYear  int     `json:"published_year"`
+	ISBN           string  `json:"isbn"`
+	Genre          string  `json:"genre"`
+	Description    string  `json:"description"`
+	CoverImage     string  `json:"cover_image"`
+	Classification string  `json:"classification"`
+	CallNumber     string  `json:"call_number"`
+	WorkID         *int    `json:"work_id,omitempty"`
+	Edition        string  `json:"edition"`
+	Language       string  `json:"language"`
 
 	// Add other fields as needed
 }
@@ -0,0 +1,91 @@
+package models
+
+import "time"
+
+// Status pesanan pembelian (purchase order)
+const (
+	OrderStatusOpen              = "open"
+	OrderStatusPartiallyReceived = "partially_received"
+	OrderStatusReceived          = "received"
+	OrderStatusCancelled         = "cancelled"
+)
+
+// Vendor represents a supplier the library buys books from
+type Vendor struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	ContactName string    `json:"contact_name"`
+	Email       string    `json:"email"`
+	Phone       string    `json:"phone"`
+	Address     string    `json:"address"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Fund represents a budget line for a fiscal year. Encumbered, Expended dan Remaining dihitung saat dibaca:
+// encumbered adalah nilai pesanan yang belum diterima, expended adalah nilai yang sudah diterima.
+type Fund struct {
+	ID         int     `json:"id"`
+	Code       string  `json:"code"` // e.g. "BUKU-2026"
+	Name       string  `json:"name"`
+	FiscalYear int     `json:"fiscal_year"`
+	Allocated  float64 `json:"allocated"`
+	Encumbered float64 `json:"encumbered"`
+	Expended   float64 `json:"expended"`
+	Remaining  float64 `json:"remaining"` // allocated - encumbered - expended
+}
+
+// PurchaseOrder represents an order placed with a vendor and charged to a fund
+type PurchaseOrder struct {
+	ID        int                 `json:"id"`
+	VendorID  int                 `json:"vendor_id"`
+	FundID    int                 `json:"fund_id"`
+	Status    string              `json:"status"`
+	Note      string              `json:"note"`
+	OrderedAt time.Time           `json:"ordered_at"`
+	Total     float64             `json:"total"` // jumlah quantity × unit_price seluruh baris
+	Lines     []PurchaseOrderLine `json:"lines"`
+}
+
+// PurchaseOrderLine is one ISBN ordered on a purchase order
+type PurchaseOrderLine struct {
+	ID               int     `json:"id"`
+	OrderID          int     `json:"order_id"`
+	ISBN             string  `json:"isbn"`
+	Title            string  `json:"title"`
+	Author           string  `json:"author"`
+	Quantity         int     `json:"quantity"`
+	QuantityReceived int     `json:"quantity_received"`
+	UnitPrice        float64 `json:"unit_price"`
+	BookID           *int    `json:"book_id,omitempty"` // buku di katalog, diisi saat eksemplar pertama diterima
+}
+
+// Outstanding mengembalikan jumlah eksemplar yang belum diterima
+func (l *PurchaseOrderLine) Outstanding() int {
+	return l.Quantity - l.QuantityReceived
+}
+
+// ReceiveLine is one line of a receiving request: the number of copies that arrived and their barcodes
+type ReceiveLine struct {
+	LineID     int      `json:"line_id"`
+	Quantity   int      `json:"quantity"`
+	Barcodes   []string `json:"barcodes"` // satu barcode per eksemplar yang diterima
+	LocationID *int     `json:"location_id"`
+	BookID     int      `json:"-"` // buku di katalog, ditentukan service sebelum penerimaan disimpan
+	NewBook    *Book    `json:"-"` // buku baru untuk baris yang belum ada di katalog; dibuat dalam transaksi penerimaan
+}
+
+// ReceiveRequest is the body of a (partial) receipt of a purchase order
+type ReceiveRequest struct {
+	Lines []ReceiveLine `json:"lines"`
+}
+
+// BudgetReport summarises the funds of one or more fiscal years
+type BudgetReport struct {
+	FromYear   int     `json:"from_year"`
+	ToYear     int     `json:"to_year"`
+	Funds      []Fund  `json:"funds"`
+	Allocated  float64 `json:"allocated"`
+	Encumbered float64 `json:"encumbered"`
+	Expended   float64 `json:"expended"`
+	Remaining  float64 `json:"remaining"`
+}
@@ -0,0 +1,60 @@
+package models
+
+// Format file yang didukung untuk impor dan ekspor katalog
+const (
+	BookFormatCSV     = "csv"
+	BookFormatJSONL   = "jsonl"
+	BookFormatMARC    = "marc"    // MARC 21 (ISO 2709)
+	BookFormatMARCXML = "marcxml" // MARC 21 slim XML
+)
+
+// Mode commit untuk impor katalog
+const (
+	ImportModeTransactional = "transactional" // semua baris disimpan atau tidak sama sekali
+	ImportModeBestEffort    = "best_effort"   // baris yang valid tetap disimpan
+)
+
+// BookImportOptions holds the options for a bulk catalog import
+type BookImportOptions struct {
+	Format  string            `json:"format"`  // "csv" atau "jsonl"
+	Mapping map[string]string `json:"mapping"` // kolom sumber -> field buku, e.g. "Judul" -> "title"
+	DryRun  bool              `json:"dry_run"`
+	Mode    string            `json:"mode"` // "transactional" atau "best_effort"
+}
+
+// BookImportRowError describes a validation or storage error for a single imported row
+type BookImportRowError struct {
+	Row     int    `json:"row"` // nomor baris data, dimulai dari 1
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// BookImportResult summarizes the outcome of a bulk catalog import
+type BookImportResult struct {
+	DryRun    bool                 `json:"dry_run"`
+	Mode      string               `json:"mode"`
+	TotalRows int                  `json:"total_rows"`
+	Valid     int                  `json:"valid"`
+	Imported  int                  `json:"imported"`
+	Failed    int                  `json:"failed"`
+	Errors    []BookImportRowError `json:"errors"`
+}
+
+// MarcImportRecord reports what happened to a single MARC record during import
+type MarcImportRecord struct {
+	Index    int      `json:"index"` // urutan record di file, dimulai dari 1
+	BookID   int      `json:"book_id,omitempty"`
+	Title    string   `json:"title"`
+	ISBN     string   `json:"isbn"`
+	Unmapped []string `json:"unmapped_fields"` // tag atau tag$subfield yang tidak dipetakan ke buku
+	Error    string   `json:"error,omitempty"`
+}
+
+// MarcImportResult summarizes the outcome of a MARC import
+type MarcImportResult struct {
+	DryRun       bool               `json:"dry_run"`
+	TotalRecords int                `json:"total_records"`
+	Imported     int                `json:"imported"`
+	Failed       int                `json:"failed"`
+	Records      []MarcImportRecord `json:"records"`
+}
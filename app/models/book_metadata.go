@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// BookMetadata represents bibliographic data returned by a metadata provider
+type BookMetadata struct {
+	ISBN        string   `json:"isbn"`
+	Title       string   `json:"title,omitempty"`
+	Authors     []string `json:"authors,omitempty"`
+	Publisher   string   `json:"publisher,omitempty"`
+	Year        int      `json:"year,omitempty"`
+	Description string   `json:"description,omitempty"`
+	CoverURL    string   `json:"cover_url,omitempty"`
+}
+
+// MetadataSource reports the outcome of querying a single metadata provider
+type MetadataSource struct {
+	Provider string `json:"provider"`
+	Found    bool   `json:"found"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// EnrichmentResult is the merged metadata for an ISBN with the provider of every field
+type EnrichmentResult struct {
+	ISBN          string            `json:"isbn"`
+	BookID        int               `json:"book_id,omitempty"`
+	Created       bool              `json:"created"`
+	DryRun        bool              `json:"dry_run"`
+	UpdatedFields []string          `json:"updated_fields"`
+	Metadata      BookMetadata      `json:"metadata"`
+	Provenance    map[string]string `json:"provenance"` // field buku -> nama provider
+	Sources       []MetadataSource  `json:"sources"`
+}
+
+// Status job enrichment
+const (
+	EnrichmentJobRunning   = "running"
+	EnrichmentJobCompleted = "completed"
+)
+
+// EnrichmentJob tracks a batch re-enrichment of the whole catalog
+type EnrichmentJob struct {
+	ID         int        `json:"id"`
+	Status     string     `json:"status"`
+	Overwrite  bool       `json:"overwrite"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Total      int        `json:"total"`
+	Processed  int        `json:"processed"`
+	Enriched   int        `json:"enriched"`
+	Skipped    int        `json:"skipped"`
+	Failed     int        `json:"failed"`
+	Errors     []string   `json:"errors"`
+}
@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// Aturan peminjaman cadangan mata kuliah (course reserve)
+const (
+	ReserveRuleTwoHour   = "two_hour"  // dikembalikan 2 jam setelah dipinjam
+	ReserveRuleOvernight = "overnight" // dikembalikan keesokan pagi
+)
+
+// Term represents an academic term (semester)
+type Term struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"` // e.g. "Ganjil 2026/2027"
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"` // inklusif; cadangan kedaluwarsa setelah hari ini berakhir
+}
+
+// Contains mengembalikan true jika waktu at berada di dalam semester
+func (t *Term) Contains(at time.Time) bool {
+	return !at.Before(t.StartDate) && at.Before(t.EndDate.AddDate(0, 0, 1))
+}
+
+// Course represents a course taught in a term
+type Course struct {
+	ID       int    `json:"id"`
+	TermID   int    `json:"term_id"`
+	Code     string `json:"code"` // e.g. "IF2110"
+	Name     string `json:"name"`
+	Lecturer string `json:"lecturer"`
+}
+
+// CourseReserve links a book (and optionally a specific copy) to a course with a short-loan rule
+type CourseReserve struct {
+	ID        int       `json:"id"`
+	CourseID  int       `json:"course_id"`
+	BookID    int       `json:"book_id"`
+	CopyID    *int      `json:"copy_id,omitempty"` // eksemplar yang diletakkan di rak cadangan
+	LoanRule  string    `json:"loan_rule"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"` // akhir semester, dihitung saat dibaca
+	Active    bool      `json:"active"`
+	Title     string    `json:"title,omitempty"`
+	Author    string    `json:"author,omitempty"`
+}
+
+// AppliesTo mengembalikan true jika cadangan berlaku untuk peminjaman eksemplar copyID: cadangan tanpa eksemplar
+// berlaku untuk semua eksemplar buku, selain itu hanya untuk eksemplar yang diletakkan di rak cadangan
+func (r *CourseReserve) AppliesTo(copyID *int) bool {
+	if r.CopyID == nil {
+		return true
+	}
+	return copyID != nil && *copyID == *r.CopyID
+}
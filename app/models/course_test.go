@@ -0,0 +1,27 @@
+package models
+
+import "testing"
+
+func TestCourseReserveAppliesTo(t *testing.T) {
+	copyID := func(id int) *int { return &id }
+	tests := []struct {
+		name    string
+		reserve *int
+		loan    *int
+		want    bool
+	}{
+		{"book reserve, loan without copy", nil, nil, true},
+		{"book reserve, any copy", nil, copyID(7), true},
+		{"copy reserve, same copy", copyID(7), copyID(7), true},
+		{"copy reserve, other copy", copyID(7), copyID(8), false},
+		{"copy reserve, loan without copy", copyID(7), nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &CourseReserve{CopyID: tt.reserve}
+			if got := r.AppliesTo(tt.loan); got != tt.want {
+				t.Errorf("AppliesTo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
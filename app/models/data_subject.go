@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// Status permintaan penghapusan data pribadi
+const (
+	ErasureStatusPending  = "pending"
+	ErasureStatusApproved = "approved" // disetujui pustakawan dan data pribadi sudah dianonimkan
+	ErasureStatusRejected = "rejected"
+)
+
+// ErasedValue menggantikan nilai data pribadi di riwayat versi setelah anggota dihapus
+const ErasedValue = "[erased]"
+
+// MemberPersonalFields lists the JSON fields of Member that hold personal data and are anonymized on erasure.
+// Membership type, registration date and fines are kept for financial records and statistics.
+var MemberPersonalFields = []string{
+	"name", "email", "phone_number", "address", "username", "gender", "birth_date", "guardian_consent_at",
+	"student_id",
+}
+
+// ErasureRequest is a member's request to erase their personal data, reviewed by a librarian
+type ErasureRequest struct {
+	ID          int        `json:"id"`
+	MemberID    int        `json:"member_id"`
+	Reason      string     `json:"reason,omitempty"`
+	Status      string     `json:"status"`
+	RequestedAt time.Time  `json:"requested_at"`
+	ReviewedBy  string     `json:"reviewed_by,omitempty"`
+	ReviewedAt  *time.Time `json:"reviewed_at,omitempty"`
+	ReviewNote  string     `json:"review_note,omitempty"`
+}
+
+// MemberExport is the personal data of a member, written as one JSON file per section in the export ZIP
+type MemberExport struct {
+	Profile       *Member         `json:"profile"`
+	Loans         ExportLoans     `json:"loans"`
+	History       *ReadingHistory `json:"history"`
+	Reviews       []Review        `json:"reviews"`
+	Notifications []Notification  `json:"notifications"`
+	Fines         ExportFines     `json:"fines"`
+}
+
+// ExportLoans holds the current physical and digital loans of a member; past loans are part of the reading history
+type ExportLoans struct {
+	Physical []Loan        `json:"physical"`
+	Digital  []DigitalLoan `json:"digital"`
+}
+
+// ExportFines holds the outstanding fines of a member with the charges and membership renewals behind them
+type ExportFines struct {
+	Outstanding float64             `json:"outstanding"`
+	Ledger      []FineLedgerEntry   `json:"ledger"`
+	Renewals    []MembershipRenewal `json:"renewals"`
+}
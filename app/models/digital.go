@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// Jenis sumber digital
+const (
+	DigitalTypeEbook     = "ebook"
+	DigitalTypeAudiobook = "audiobook"
+)
+
+// DigitalItem represents a licensed digital file (e-book or audiobook) attached to a book
+type DigitalItem struct {
+	ID           int       `json:"id"`
+	BookID       int       `json:"book_id"`
+	Type         string    `json:"type"`
+	Format       string    `json:"format"` // e.g. "pdf", "epub", "mp3"
+	ContentType  string    `json:"content_type"`
+	Size         int       `json:"size"`
+	FileKey      string    `json:"-"`
+	LicenseCount int       `json:"license_count"` // jumlah peminjaman bersamaan yang diizinkan lisensi
+	CreatedAt    time.Time `json:"created_at"`
+	Available    int       `json:"available"` // lisensi yang belum dipakai, dihitung saat dibaca
+}
+
+// DigitalLoan represents a digital loan; it ends on its own at ExpiresAt without a return step
+type DigitalLoan struct {
+	ID         int       `json:"id"`
+	ItemID     int       `json:"item_id"`
+	BookID     int       `json:"book_id"`
+	MemberID   int       `json:"member_id"`
+	BorrowedAt time.Time `json:"borrowed_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	// Download diisi saat peminjaman dibuat agar klien bisa langsung mengunduh
+	Download *DownloadLink `json:"download,omitempty"`
+}
+
+// Active mengembalikan true jika peminjaman digital belum kedaluwarsa pada waktu now
+func (l *DigitalLoan) Active(now time.Time) bool {
+	return now.Before(l.ExpiresAt)
+}
+
+// DownloadLink is a signed, time-limited URL for downloading the file of a digital loan
+type DownloadLink struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
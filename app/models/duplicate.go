@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// Alasan dua anggota dianggap kemungkinan orang yang sama
+const (
+	DuplicateReasonEmail     = "email"      // email sama setelah dinormalisasi
+	DuplicateReasonPhone     = "phone"      // nomor telepon sama setelah dinormalisasi
+	DuplicateReasonName      = "name"       // nama sangat mirip
+	DuplicateReasonBirthDate = "birth_date" // tanggal lahir sama
+)
+
+// DuplicateCandidate is a pair of members that probably belong to the same person, listed for a librarian to
+// review. Member is the older account and the suggested survivor of a merge.
+type DuplicateCandidate struct {
+	Member         Member   `json:"member"`
+	Duplicate      Member   `json:"duplicate"`
+	Score          float64  `json:"score"`           // 0-1, makin tinggi makin mungkin orang yang sama
+	NameSimilarity float64  `json:"name_similarity"` // 0-1
+	Reasons        []string `json:"reasons"`
+}
+
+// DuplicateDismissal marks a candidate pair as different people so it is no longer listed
+type DuplicateDismissal struct {
+	MemberID      int       `json:"member_id"`
+	OtherMemberID int       `json:"other_member_id"`
+	DismissedBy   string    `json:"dismissed_by"`
+	DismissedAt   time.Time `json:"dismissed_at"`
+}
+
+// MemberMerge records the merge of a duplicate account into the surviving account.
+// Moved counts the rows re-pointed to the survivor per table.
+type MemberMerge struct {
+	ID         int              `json:"id"`
+	SurvivorID int              `json:"survivor_id"`
+	MergedID   int              `json:"merged_id"`
+	MergedBy   string           `json:"merged_by"`
+	MergedAt   time.Time        `json:"merged_at"`
+	Moved      map[string]int64 `json:"moved,omitempty"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"Restful-Perpustakaan-API/app/utils"
+	"time"
+)
+
+// Jenis record yang memiliki riwayat versi
+const (
+	HistoryEntityBook   = "book"
+	HistoryEntityMember = "member"
+)
+
+// Jenis perubahan pada riwayat versi
+const (
+	HistoryActionBaseline = "baseline" // keadaan sebelum perubahan pertama yang tercatat
+	HistoryActionCreate   = "create"
+	HistoryActionUpdate   = "update"
+	HistoryActionDelete   = "delete"
+	HistoryActionRestore  = "restore"
+	HistoryActionErase    = "erase" // data pribadi anggota dihapus; nilai lama di riwayat ikut disamarkan
+)
+
+// RecordVersion is one entry in the change history of a book or member.
+// Snapshot holds the full record after the change, Changes the fields that differ from the previous state.
+type RecordVersion struct {
+	ID         int                    `json:"id"`
+	EntityType string                 `json:"entity_type"`
+	EntityID   int                    `json:"entity_id"`
+	Version    int                    `json:"version"`
+	Action     string                 `json:"action"`
+	ChangedBy  string                 `json:"changed_by"`
+	ChangedAt  time.Time              `json:"changed_at"`
+	Changes    []utils.FieldChange    `json:"changes"`
+	Snapshot   map[string]interface{} `json:"snapshot,omitempty"`
+}
+
+// VersionDiff is the difference between two versions of the same record
+type VersionDiff struct {
+	EntityType  string              `json:"entity_type"`
+	EntityID    int                 `json:"entity_id"`
+	FromVersion int                 `json:"from_version"`
+	ToVersion   int                 `json:"to_version"`
+	Changes     []utils.FieldChange `json:"changes"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Status reservasi
+const (
+	HoldStatusWaiting   = "waiting"
+	HoldStatusReady     = "ready"
+	HoldStatusFulfilled = "fulfilled"
+	HoldStatusCancelled = "cancelled"
+)
+
+// Hold represents a member's reservation for a specific edition or for any edition of a work
+type Hold struct {
+	ID        int       `json:"id"`
+	MemberID  int       `json:"member_id"`
+	BookID    *int      `json:"book_id,omitempty"` // diisi untuk reservasi edisi tertentu
+	WorkID    *int      `json:"work_id,omitempty"` // diisi untuk reservasi "edisi mana saja"
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	Position  int       `json:"position,omitempty"` // posisi dalam antrean, dihitung saat dibaca
+}
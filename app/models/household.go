@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// AdultAge adalah usia (tahun) saat anggota tidak lagi membutuhkan persetujuan wali
+const AdultAge = 18
+
+// IsMinor mengembalikan true jika anggota belum mencapai AdultAge pada waktu now.
+// Anggota tanpa tanggal lahir dianggap dewasa.
+func (m *Member) IsMinor(now time.Time) bool {
+	if m.BirthDate == nil {
+		return false
+	}
+	return now.Before(m.BirthDate.AddDate(AdultAge, 0, 0))
+}
+
+// Household groups a primary account with linked dependents, e.g. a parent and their children
+type Household struct {
+	ID              int               `json:"id"`
+	Name            string            `json:"name"`
+	PrimaryMemberID int               `json:"primary_member_id"` // wali yang mengelola akun tanggungan
+	CreatedAt       time.Time         `json:"created_at"`
+	Members         []HouseholdMember `json:"members"`
+}
+
+// HouseholdMember is one member of a household; the guardian permissions only apply to dependents
+type HouseholdMember struct {
+	MemberID          int        `json:"member_id"`
+	Name              string     `json:"name"`
+	Email             string     `json:"email"`
+	Primary           bool       `json:"primary"`
+	BirthDate         *time.Time `json:"birth_date,omitempty"`
+	Minor             bool       `json:"minor"`
+	GuardianConsentAt *time.Time `json:"guardian_consent_at,omitempty"`
+	GuardianCanHold   bool       `json:"guardian_can_hold"`  // wali boleh mereservasi buku atas nama tanggungan
+	GuardianCanRenew  bool       `json:"guardian_can_renew"` // wali boleh memperpanjang peminjaman tanggungan
+	JoinedAt          time.Time  `json:"joined_at"`
+}
+
+// MemberFine is the outstanding fine of one household member
+type MemberFine struct {
+	MemberID   int     `json:"member_id"`
+	Name       string  `json:"name"`
+	FineAmount float64 `json:"fine_amount"`
+}
+
+// HouseholdFines is the combined view of the outstanding fines in a household
+type HouseholdFines struct {
+	HouseholdID int          `json:"household_id"`
+	Total       float64      `json:"total"`
+	Members     []MemberFine `json:"members"`
+}
+
+// DependentLoans lists the current loans of one household member
+type DependentLoans struct {
+	MemberID int    `json:"member_id"`
+	Name     string `json:"name"`
+	Loans    []Loan `json:"loans"`
+}
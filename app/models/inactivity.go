@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// DefaultInactiveMonths is the inactivity period used when a report does not specify one
+const DefaultInactiveMonths = 12
+
+// Tindakan massal untuk anggota yang tidak aktif
+const (
+	InactiveActionExpire = "expire" // mengakhiri keanggotaan yang masih aktif atau ditangguhkan
+	InactiveActionEmail  = "email"  // mengirim email kepada anggota
+)
+
+// InactiveMemberReport lists the members with no login or circulation activity since Cutoff
+type InactiveMemberReport struct {
+	Months  int       `json:"months"`
+	Cutoff  time.Time `json:"cutoff"`
+	Total   int       `json:"total"`
+	Members []Member  `json:"members"` // anggota yang paling lama tidak aktif lebih dulu
+}
+
+// InactiveMemberAction is a bulk action on the members of an inactive member report
+type InactiveMemberAction struct {
+	Action    string `json:"action"`
+	Months    int    `json:"months"`
+	MemberIDs []int  `json:"member_ids"` // kosong berarti semua anggota dalam laporan
+	Subject   string `json:"subject"`    // hanya untuk email; kosong memakai subjek bawaan
+	Message   string `json:"message"`    // hanya untuk email; kosong memakai pesan bawaan
+}
+
+// InactiveMemberActionResult summarizes a bulk action on inactive members
+type InactiveMemberActionResult struct {
+	Action   string `json:"action"`
+	Affected []int  `json:"affected"`
+	Skipped  []int  `json:"skipped"` // tidak ada di laporan, atau status keanggotaannya tidak bisa diakhiri
+	Failed   []int  `json:"failed"`  // email gagal dikirim
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Status kartu anggota
+const (
+	CardStatusActive   = "active"
+	CardStatusReplaced = "replaced" // nomor lama tidak berlaku lagi setelah kartu diganti
+)
+
+// LibraryCard is a numbered library card issued to a member; a member has at most one active card
+type LibraryCard struct {
+	ID                int        `json:"id"`
+	MemberID          int        `json:"member_id"`
+	Number            string     `json:"number"` // angka dengan check digit Luhn di posisi terakhir
+	Status            string     `json:"status"`
+	IssuedAt          time.Time  `json:"issued_at"`
+	ReplacedAt        *time.Time `json:"replaced_at,omitempty"`
+	ReplacementReason string     `json:"replacement_reason,omitempty"` // e.g. "lost", "damaged"
+}
+
+// CardLookup is the result of scanning a library card at the desk or a kiosk
+type CardLookup struct {
+	Card   LibraryCard `json:"card"`
+	Member CardHolder  `json:"member"`
+}
+
+// CardHolder is the part of a member shown after a card scan; contact details and birth date are left out
+type CardHolder struct {
+	ID               int        `json:"id"`
+	Name             string     `json:"name"`
+	MembershipType   string     `json:"membership_type"`
+	MembershipStatus string     `json:"membership_status"`
+	MembershipEnd    *time.Time `json:"membership_end,omitempty"`
+	FineAmount       float64    `json:"fine_amount"`
+}
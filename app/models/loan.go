@@ -24,16 +24,19 @@ type Loan struct {
 	ID         int        `json:"id"`
 	MemberID   int        `json:"member_id"`
 	BookID     int        `json:"book_id"`
+	CopyID     *int       `json:"copy_id,omitempty"` // eksemplar yang dipinjam; kosong jika hanya judulnya yang dicatat
 	BorrowDate time.Time  `json:"borrow_date"`
 	DueDate    time.Time  `json:"due_date"`
 	ReturnDate *time.Time `json:"return_date,omitempty"` // Can be null if not returned
 	Returned   bool       `json:"returned"`
+
+	RenewalCount int `json:"renewal_count"` // berapa kali jatuh tempo sudah diperpanjang
 }
 
-// LoanHistory represents a loan history.
+// LoanHistory represents an archived (returned) loan.
 type LoanHistory struct {
 	ID         int        `json:"id"`
-	MemberID   int        `json:"member_id"`
+	MemberID   *int       `json:"member_id,omitempty"` // nil jika dianonimkan karena anggota menonaktifkan riwayat baca
 	BookID     int        `json:"book_id"`
 	BorrowDate time.Time  `json:"borrow_date"`
 	DueDate    time.Time  `json:"due_date"`
@@ -0,0 +1,60 @@
+package models
+
+// Jenis lokasi, dari yang paling luas ke yang paling sempit
+const (
+	LocationTypeBranch = "branch"
+	LocationTypeFloor  = "floor"
+	LocationTypeRoom   = "room"
+	LocationTypeShelf  = "shelf"
+)
+
+// LocationTypeLevels memberi urutan hierarki setiap jenis lokasi (branch → floor → room → shelf)
+var LocationTypeLevels = map[string]int{
+	LocationTypeBranch: 0,
+	LocationTypeFloor:  1,
+	LocationTypeRoom:   2,
+	LocationTypeShelf:  3,
+}
+
+// Location represents a physical place in the library where copies are shelved
+type Location struct {
+	ID              int    `json:"id"`
+	ParentID        *int   `json:"parent_id,omitempty"` // nil untuk cabang (branch)
+	Type            string `json:"type"`
+	Name            string `json:"name"`
+	Code            string `json:"code"`
+	CallNumberStart string `json:"call_number_start,omitempty"` // rentang nomor panggil rak, e.g. "800"
+	CallNumberEnd   string `json:"call_number_end,omitempty"`   // e.g. "899.9"
+}
+
+// Status eksemplar
+const (
+	CopyStatusAvailable = "available"
+	CopyStatusOnLoan    = "on_loan"
+	CopyStatusLost      = "lost"
+	CopyStatusWithdrawn = "withdrawn"
+)
+
+// BookCopy represents a single physical copy (item) of a book
+type BookCopy struct {
+	ID         int    `json:"id"`
+	BookID     int    `json:"book_id"`
+	Barcode    string `json:"barcode"`
+	LocationID *int   `json:"location_id,omitempty"`
+	Status     string `json:"status"`
+}
+
+// ShelfListEntry is one line of a shelf-reading list, ordered by call number
+type ShelfListEntry struct {
+	CopyID       int    `json:"copy_id"`
+	Barcode      string `json:"barcode"`
+	Status       string `json:"status"`
+	LocationID   int    `json:"location_id"`
+	LocationCode string `json:"location_code"`
+	BookID       int    `json:"book_id"`
+	Title        string `json:"title"`
+	Author       string `json:"author"`
+	CallNumber   string `json:"call_number"`
+	// OutOfRange menandai eksemplar yang nomor panggilnya di luar rentang rak (salah taruh)
+	OutOfRange bool `json:"out_of_range,omitempty"`
+}
@@ -2,19 +2,50 @@ package models
 
 import "time"
 
+// Peran pengguna
+const (
+	RoleMember = "member"
+	RoleAdmin  = "admin"
+)
+
 // Member represents a library member.
 type Member struct {
-	ID               int       `json:"id"`
-	Name             string    `json:"name"`
-	Email            string    `json:"email"`
-	Password         string    `json:"password"`
-	PhoneNumber      string    `json:"phone_number"`
-	Address          string    `json:"address"`
-	RegistrationDate time.Time `json:"registration_date"` // e.g., "2022-01-01T00:00:00Z"
-	MembershipType   string    `json:"membership_type"`   // e.g., "Regular", "Student", "Premium"
-	Username         string    `json:"username"`          // e.g., "johndoe"
-	Gender           string    `json:"gender"`            // e.g., "Male", "Female", "Other" or any
-	FineAmount       float64   `json:"fine_amount"`       // e.g., float64
+	ID               int        `json:"id"`
+	Name             string     `json:"name"`
+	Email            string     `json:"email"`
+	Password         string     `json:"password"`
+	PhoneNumber      string     `json:"phone_number"`
+	Address          string     `json:"address"`
+	RegistrationDate time.Time  `json:"registration_date"`    // e.g., "2022-01-01T00:00:00Z"
+	MembershipType   string     `json:"membership_type"`      // e.g., "Regular", "Student", "Premium"
+	Username         string     `json:"username"`             // e.g., "johndoe"
+	Gender           string     `json:"gender"`               // e.g., "Male", "Female", "Other" or any
+	FineAmount       float64    `json:"fine_amount"`          // e.g., float64
+	DeletedAt        *time.Time `json:"deleted_at,omitempty"` // diisi jika anggota ada di trash
+	DeletedBy        string     `json:"deleted_by,omitempty"`
+	ChangedBy        string     `json:"-"` // pelaku perubahan untuk riwayat versi, tidak disimpan di tabel members
+
+	// Status dan masa berlaku keanggotaan; MembershipEnd kosong berarti keanggotaan tidak kedaluwarsa
+	MembershipStatus string     `json:"membership_status"`
+	MembershipStart  *time.Time `json:"membership_start,omitempty"`
+	MembershipEnd    *time.Time `json:"membership_end,omitempty"`
+
+	// Tanggal lahir menentukan apakah anggota masih di bawah umur; anggota di bawah umur baru boleh meminjam
+	// setelah wali dalam satu rumah tangga memberi persetujuan
+	BirthDate         *time.Time `json:"birth_date,omitempty"`
+	GuardianConsentAt *time.Time `json:"guardian_consent_at,omitempty"`
+
+	ErasedAt *time.Time `json:"erased_at,omitempty"` // diisi setelah data pribadi anggota dihapus atas permintaannya
+
+	// Anggota dari daftar mahasiswa/siswa sekolah atau universitas; Roster adalah nama daftar impor terakhirnya
+	StudentID string `json:"student_id,omitempty"`
+	Roster    string `json:"roster,omitempty"`
+
+	// Login terakhir dan aktivitas terakhir (login, peminjaman, perpanjangan, pengembalian atau reservasi)
+	LastLoginAt    *time.Time `json:"last_login_at,omitempty"`
+	LastActivityAt *time.Time `json:"last_activity_at,omitempty"`
+
+	Role string `json:"role"` // RoleMember atau RoleAdmin; hanya admin yang boleh memakai endpoint /admin
 
 	// ... tambahkan field lain sesuai kebutuhan
 }
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Batas halaman pencarian anggota
+const (
+	DefaultMemberPageSize = 20
+	MaxMemberPageSize     = 100
+)
+
+// MemberSortFields lists the fields a member search can be sorted by; a leading "-" sorts descending
+var MemberSortFields = []string{"id", "name", "email", "registration_date", "membership_end", "fine_amount", "last_activity_at"}
+
+// MemberSearch holds the filters, sort order and page of a member search. Empty filters are ignored.
+type MemberSearch struct {
+	Query            string     `json:"q"` // sebagian nama, email, telepon, alamat, username atau NIM, tanpa membedakan huruf besar/kecil
+	MembershipType   string     `json:"membership_type"`
+	Status           string     `json:"status"`
+	Gender           string     `json:"gender"`
+	RegisteredAfter  *time.Time `json:"registered_after,omitempty"`  // terdaftar pada atau setelah tanggal ini
+	RegisteredBefore *time.Time `json:"registered_before,omitempty"` // terdaftar sebelum tanggal ini
+	HasFines         *bool      `json:"has_fines,omitempty"`
+	Sort             string     `json:"sort"` // e.g. "name" atau "-registration_date"
+	Page             int        `json:"page"`
+	PerPage          int        `json:"per_page"`
+}
+
+// MemberSearchResult is one page of a member search
+type MemberSearchResult struct {
+	Members    []Member `json:"members"`
+	Page       int      `json:"page"`
+	PerPage    int      `json:"per_page"`
+	Total      int      `json:"total"`
+	TotalPages int      `json:"total_pages"`
+}
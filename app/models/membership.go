@@ -0,0 +1,76 @@
+package models
+
+import "time"
+
+// Status keanggotaan
+const (
+	MembershipStatusPending   = "pending" // mendaftar sendiri dan menunggu verifikasi pustakawan
+	MembershipStatusActive    = "active"
+	MembershipStatusSuspended = "suspended"
+	MembershipStatusExpired   = "expired"
+	MembershipStatusClosed    = "closed"
+)
+
+// membershipTransitions berisi status berikutnya yang boleh dipilih pustakawan dari setiap status;
+// keanggotaan yang kedaluwarsa diaktifkan kembali lewat perpanjangan, bukan perubahan status
+var membershipTransitions = map[string][]string{
+	MembershipStatusPending:   {MembershipStatusActive, MembershipStatusClosed},
+	MembershipStatusActive:    {MembershipStatusSuspended, MembershipStatusClosed},
+	MembershipStatusSuspended: {MembershipStatusActive, MembershipStatusClosed},
+	MembershipStatusExpired:   {MembershipStatusClosed},
+}
+
+// IsMembershipStatus mengembalikan true jika status adalah salah satu status keanggotaan
+func IsMembershipStatus(status string) bool {
+	switch status {
+	case MembershipStatusPending, MembershipStatusActive, MembershipStatusSuspended, MembershipStatusExpired, MembershipStatusClosed:
+		return true
+	}
+	return false
+}
+
+// CanMoveMembershipTo mengembalikan true jika status keanggotaan anggota boleh diubah menjadi status
+func (m *Member) CanMoveMembershipTo(status string) bool {
+	for _, next := range membershipTransitions[m.MembershipStatus] {
+		if next == status {
+			return true
+		}
+	}
+	return false
+}
+
+// MembershipActive mengembalikan true jika anggota berstatus aktif dan masa berlakunya belum lewat pada waktu now.
+// Masa berlaku dicek langsung agar anggota tidak dapat meminjam sebelum job kedaluwarsa berjalan.
+func (m *Member) MembershipActive(now time.Time) bool {
+	if m.MembershipStatus != MembershipStatusActive {
+		return false
+	}
+	return m.MembershipEnd == nil || now.Before(*m.MembershipEnd)
+}
+
+// MembershipRenewal is one renewal of a membership, with the fee charged to the member's fines
+type MembershipRenewal struct {
+	ID          int        `json:"id"`
+	MemberID    int        `json:"member_id"`
+	PreviousEnd *time.Time `json:"previous_end,omitempty"`
+	NewEnd      time.Time  `json:"new_end"`
+	Months      int        `json:"months"`
+	Fee         float64    `json:"fee"`
+	RenewedBy   string     `json:"renewed_by,omitempty"`
+	RenewedAt   time.Time  `json:"renewed_at"`
+}
+
+// FineLedgerEntry is one charge added to a member's outstanding fines
+type FineLedgerEntry struct {
+	ID        int       `json:"id"`
+	MemberID  int       `json:"member_id"`
+	Amount    float64   `json:"amount"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// MembershipJobResult is the outcome of one run of the membership expiry job
+type MembershipJobResult struct {
+	Expired  int `json:"expired"`
+	Reminded int `json:"reminded"`
+}
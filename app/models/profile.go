@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// ProfileUpdate holds the profile fields a member may change themselves; nil fields are left unchanged.
+// Fines, membership and email are managed elsewhere and cannot be set through it.
+type ProfileUpdate struct {
+	Name        *string `json:"name,omitempty"`
+	PhoneNumber *string `json:"phone_number,omitempty"`
+	Address     *string `json:"address,omitempty"`
+	Gender      *string `json:"gender,omitempty"`
+}
+
+// PasswordChange is a request of a member to change their own password
+type PasswordChange struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+// EmailChange is a pending change of a member's email address, applied once the new address is verified
+type EmailChange struct {
+	MemberID    int       `json:"member_id"`
+	NewEmail    string    `json:"new_email"`
+	TokenHash   string    `json:"-"` // hanya hash token yang disimpan; token asli dikirim ke alamat baru
+	RequestedAt time.Time `json:"requested_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// MemberDashboard summarizes the current account state of the logged-in member
+type MemberDashboard struct {
+	Member              Member         `json:"member"`
+	Loans               []Loan         `json:"loans"` // peminjaman yang belum dikembalikan
+	OverdueLoans        int            `json:"overdue_loans"`
+	Holds               []Hold         `json:"holds"` // reservasi yang masih aktif
+	FineAmount          float64        `json:"fine_amount"`
+	Blocks              []MemberBlock  `json:"blocks"` // blokir yang sedang berlaku beserta alasannya
+	UnreadNotifications []Notification `json:"unread_notifications"`
+	PendingEmailChange  *EmailChange   `json:"pending_email_change,omitempty"`
+}
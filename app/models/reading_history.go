@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Jenis peminjaman dalam riwayat baca
+const (
+	ReadingSourcePhysical = "physical"
+	ReadingSourceDigital  = "digital"
+)
+
+// ReadingHistoryEntry is one book a member has read, from an archived loan or an expired digital loan
+type ReadingHistoryEntry struct {
+	BookID     int        `json:"book_id"`
+	Title      string     `json:"title"`
+	Author     string     `json:"author"`
+	Source     string     `json:"source"` // "physical" atau "digital"
+	BorrowedAt time.Time  `json:"borrowed_at"`
+	ReturnedAt *time.Time `json:"returned_at,omitempty"` // untuk peminjaman digital diisi waktu kedaluwarsa
+}
+
+// ReadingHistory is a member's reading history together with their privacy setting
+type ReadingHistory struct {
+	ReadingHistoryEnabled bool                  `json:"reading_history_enabled"`
+	Entries               []ReadingHistoryEntry `json:"entries"`
+}
+
+// PrivacySettings holds a member's privacy preferences
+type PrivacySettings struct {
+	// ReadingHistoryEnabled false berarti peminjaman yang sudah selesai dianonimkan dan tidak dipakai untuk rekomendasi
+	ReadingHistoryEnabled bool `json:"reading_history_enabled"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// Visibilitas daftar bacaan
+const (
+	ListVisibilityPrivate = "private" // hanya pemilik
+	ListVisibilityShared  = "shared"  // siapa saja yang memiliki tautan berbagi
+	ListVisibilityPublic  = "public"  // tampil di daftar publik, e.g. daftar bacaan kelas
+)
+
+// ReadingList represents a member's reading list or wishlist
+type ReadingList struct {
+	ID          int               `json:"id"`
+	MemberID    int               `json:"member_id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Visibility  string            `json:"visibility"`
+	ShareToken  string            `json:"-"`
+	ShareURL    string            `json:"share_url,omitempty"` // hanya ditampilkan kepada pemilik
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+	Items       []ReadingListItem `json:"items,omitempty"`
+}
+
+// ReadingListItem is a book on a reading list
+type ReadingListItem struct {
+	ID       int       `json:"id"`
+	ListID   int       `json:"list_id"`
+	BookID   int       `json:"book_id"`
+	Position int       `json:"position"`
+	Note     string    `json:"note"`
+	AddedAt  time.Time `json:"added_at"`
+	Title    string    `json:"title,omitempty"`
+	Author   string    `json:"author,omitempty"`
+}
+
+// ReadingListHoldSkip explains why no hold was placed for a list item
+type ReadingListHoldSkip struct {
+	BookID int    `json:"book_id"`
+	Reason string `json:"reason"`
+}
+
+// ReadingListHoldResult is the outcome of placing holds on every item of a reading list
+type ReadingListHoldResult struct {
+	Placed  []Hold                `json:"placed"`
+	Skipped []ReadingListHoldSkip `json:"skipped"`
+}
@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+// Hasil per baris pada impor daftar anggota
+const (
+	RosterActionCreate    = "create"
+	RosterActionUpdate    = "update"
+	RosterActionUnchanged = "unchanged"
+	RosterActionError     = "error"
+)
+
+// DefaultRosterMembershipType adalah jenis keanggotaan anggota impor jika tidak diisi di file maupun opsi
+const DefaultRosterMembershipType = "Student"
+
+// RosterImportOptions holds the options for importing a school or university roster
+type RosterImportOptions struct {
+	Format         string            `json:"format"`  // "csv" atau "jsonl"
+	Mapping        map[string]string `json:"mapping"` // kolom sumber -> field anggota, e.g. "NIM" -> "student_id"
+	Roster         string            `json:"roster"`  // nama daftar, e.g. "FT-2026-ganjil"; disimpan di anggota
+	MembershipType string            `json:"membership_type"`
+	MembershipEnd  *time.Time        `json:"membership_end,omitempty"` // kosong berarti masa berlaku default
+	DryRun         bool              `json:"dry_run"`
+	FullRoster     bool              `json:"full_roster"`  // anggota daftar ini yang tidak ada di file dinonaktifkan
+	SendWelcome    bool              `json:"send_welcome"` // kirim email sambutan dengan tautan pembuatan password
+	ImportedBy     string            `json:"-"`
+}
+
+// RosterImportRowError describes a validation error for a single roster row
+type RosterImportRowError struct {
+	Row     int    `json:"row"` // nomor baris data, dimulai dari 1
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// RosterImportRow reports what the import does, or would do in a dry run, with one roster row
+type RosterImportRow struct {
+	Row       int    `json:"row"`
+	Action    string `json:"action"`
+	MemberID  int    `json:"member_id,omitempty"` // kosong untuk anggota baru pada dry run
+	StudentID string `json:"student_id,omitempty"`
+	Email     string `json:"email,omitempty"`
+	Name      string `json:"name,omitempty"`
+}
+
+// RosterImportResult summarizes a roster import. Nothing is written when a row fails or in a dry run.
+type RosterImportResult struct {
+	DryRun      bool                   `json:"dry_run"`
+	FullRoster  bool                   `json:"full_roster"`
+	Roster      string                 `json:"roster"`
+	TotalRows   int                    `json:"total_rows"`
+	Created     int                    `json:"created"`
+	Updated     int                    `json:"updated"`
+	Unchanged   int                    `json:"unchanged"`
+	Deactivated int                    `json:"deactivated"`
+	Failed      int                    `json:"failed"`
+	WelcomeSent int                    `json:"welcome_sent"`
+	Rows        []RosterImportRow      `json:"rows"`
+	Deactivate  []Member               `json:"deactivate"` // anggota daftar ini yang tidak ada lagi di file
+	Errors      []RosterImportRowError `json:"errors"`
+}
+
+// PasswordSetup is a pending link for an imported member to choose their first password
+type PasswordSetup struct {
+	MemberID  int       `json:"member_id"`
+	TokenHash string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,125 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Pola terbit terbitan berseri (serial)
+const (
+	SerialFrequencyWeekly    = "weekly"
+	SerialFrequencyMonthly   = "monthly"
+	SerialFrequencyQuarterly = "quarterly"
+)
+
+// Status nomor terbitan
+const (
+	IssueStatusExpected = "expected"
+	IssueStatusReceived = "received"
+	IssueStatusClaimed  = "claimed" // belum datang dan sudah diklaim ke vendor
+)
+
+// Serial represents a subscribed magazine or journal that arrives issue by issue
+type Serial struct {
+	ID             int       `json:"id"`
+	BookID         int       `json:"book_id"` // record katalog tempat eksemplar setiap nomor dibuat
+	VendorID       *int      `json:"vendor_id,omitempty"`
+	Title          string    `json:"title"`
+	ISSN           string    `json:"issn"`
+	Publisher      string    `json:"publisher"`
+	Frequency      string    `json:"frequency"`
+	FirstIssueDate time.Time `json:"first_issue_date"` // tanggal terbit nomor 1; nomor berikutnya diprediksi dari pola terbit
+	ClaimGraceDays int       `json:"claim_grace_days"` // hari setelah tanggal terbit sebelum nomor dapat diklaim
+	Active         bool      `json:"active"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// IsSerialFrequency mengembalikan true jika frequency adalah pola terbit yang didukung
+func IsSerialFrequency(frequency string) bool {
+	switch frequency {
+	case SerialFrequencyWeekly, SerialFrequencyMonthly, SerialFrequencyQuarterly:
+		return true
+	}
+	return false
+}
+
+// ExpectedDate menghitung tanggal terbit nomor ke-number (mulai dari 1). Tanggal selalu dihitung dari nomor 1
+// agar terbitan bulanan tidak bergeser setelah bulan yang pendek; tanggal yang tidak ada di bulan tujuan
+// (e.g. 31 Februari) menjadi hari terakhir bulan itu.
+func (s *Serial) ExpectedDate(number int) time.Time {
+	switch s.Frequency {
+	case SerialFrequencyWeekly:
+		return s.FirstIssueDate.AddDate(0, 0, 7*(number-1))
+	case SerialFrequencyQuarterly:
+		return addMonthsClamped(s.FirstIssueDate, 3*(number-1))
+	default:
+		return addMonthsClamped(s.FirstIssueDate, number-1)
+	}
+}
+
+// ClaimableAt mengembalikan waktu nomor terbitan dapat diklaim ke vendor: masa tenggang setelah tanggal terbit,
+// atau setelah klaim terakhir jika sudah pernah diklaim
+func (s *Serial) ClaimableAt(issue *SerialIssue) time.Time {
+	grace := time.Duration(s.ClaimGraceDays) * 24 * time.Hour
+	claimableAt := issue.ExpectedDate.Add(grace)
+	if issue.ClaimedAt != nil && issue.ClaimedAt.Add(grace).After(claimableAt) {
+		claimableAt = issue.ClaimedAt.Add(grace)
+	}
+	return claimableAt
+}
+
+// addMonthsClamped menambahkan months bulan ke t tanpa meluap ke bulan berikutnya seperti time.AddDate
+func addMonthsClamped(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	first := time.Date(year, month+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	if last := first.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+// IssueLabel membuat label nomor terbitan, e.g. "No. 12 (2026-12)"
+func (s *Serial) IssueLabel(number int) string {
+	expected := s.ExpectedDate(number)
+	if s.Frequency == SerialFrequencyWeekly {
+		return fmt.Sprintf("No. %d (%s)", number, expected.Format("2006-01-02"))
+	}
+	return fmt.Sprintf("No. %d (%s)", number, expected.Format("2006-01"))
+}
+
+// SerialIssue is one predicted or received issue of a serial
+type SerialIssue struct {
+	ID           int        `json:"id"`
+	SerialID     int        `json:"serial_id"`
+	Number       int        `json:"number"`
+	Label        string     `json:"label"`
+	ExpectedDate time.Time  `json:"expected_date"`
+	Status       string     `json:"status"`
+	ReceivedAt   *time.Time `json:"received_at,omitempty"`
+	CopyID       *int       `json:"copy_id,omitempty"` // eksemplar yang dibuat saat check-in
+	ClaimedAt    *time.Time `json:"claimed_at,omitempty"`
+	ClaimCount   int        `json:"claim_count"`
+}
+
+// SerialCheckIn is the body of an issue check-in: the barcode and shelf of the new copy
+type SerialCheckIn struct {
+	Barcode    string `json:"barcode"`
+	LocationID *int   `json:"location_id"`
+}
+
+// SerialClaim is an issue that is overdue and can be claimed from the vendor
+type SerialClaim struct {
+	SerialIssue
+	Title    string `json:"title"`
+	ISSN     string `json:"issn"`
+	VendorID *int   `json:"vendor_id,omitempty"`
+	DaysLate int    `json:"days_late"`
+}
+
+// SerialRoutingEntry is one member on the routing list of a serial, in routing order
+type SerialRoutingEntry struct {
+	MemberID int    `json:"member_id"`
+	Position int    `json:"position"`
+	Name     string `json:"name,omitempty"`
+	Email    string `json:"email,omitempty"`
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestSerialExpectedDate(t *testing.T) {
+	tests := []struct {
+		name      string
+		frequency string
+		first     time.Time
+		number    int
+		want      time.Time
+	}{
+		{"monthly first issue", SerialFrequencyMonthly, date(2024, time.January, 31), 1, date(2024, time.January, 31)},
+		{"monthly into leap February", SerialFrequencyMonthly, date(2024, time.January, 31), 2, date(2024, time.February, 29)},
+		{"monthly into February", SerialFrequencyMonthly, date(2023, time.January, 31), 2, date(2023, time.February, 28)},
+		{"monthly back to a long month", SerialFrequencyMonthly, date(2024, time.January, 31), 3, date(2024, time.March, 31)},
+		{"monthly into a 30-day month", SerialFrequencyMonthly, date(2024, time.January, 31), 4, date(2024, time.April, 30)},
+		{"monthly across the year", SerialFrequencyMonthly, date(2024, time.October, 31), 5, date(2025, time.February, 28)},
+		{"monthly mid month", SerialFrequencyMonthly, date(2024, time.January, 15), 13, date(2025, time.January, 15)},
+		{"quarterly from month end", SerialFrequencyQuarterly, date(2024, time.November, 30), 2, date(2025, time.February, 28)},
+		{"quarterly into a 30-day month", SerialFrequencyQuarterly, date(2024, time.March, 31), 2, date(2024, time.June, 30)},
+		{"quarterly mid month", SerialFrequencyQuarterly, date(2024, time.January, 10), 5, date(2025, time.January, 10)},
+		{"weekly", SerialFrequencyWeekly, date(2024, time.February, 26), 2, date(2024, time.March, 4)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Serial{Frequency: tt.frequency, FirstIssueDate: tt.first}
+			if got := s.ExpectedDate(tt.number); !got.Equal(tt.want) {
+				t.Errorf("ExpectedDate(%d) = %s, want %s", tt.number, got.Format("2006-01-02"), tt.want.Format("2006-01-02"))
+			}
+		})
+	}
+}
+
+func TestSerialExpectedDateKeepsTimeOfDay(t *testing.T) {
+	first := time.Date(2024, time.January, 31, 9, 30, 0, 0, time.UTC)
+	s := &Serial{Frequency: SerialFrequencyMonthly, FirstIssueDate: first}
+	want := time.Date(2024, time.February, 29, 9, 30, 0, 0, time.UTC)
+	if got := s.ExpectedDate(2); !got.Equal(want) {
+		t.Errorf("ExpectedDate(2) = %s, want %s", got, want)
+	}
+}
+
+func TestSerialClaimableAt(t *testing.T) {
+	expected := date(2024, time.March, 1)
+	claimed := date(2024, time.March, 20)
+	tests := []struct {
+		name      string
+		graceDays int
+		claimedAt *time.Time
+		want      time.Time
+	}{
+		{"not claimed", 10, nil, date(2024, time.March, 11)},
+		{"no grace period", 0, nil, expected},
+		{"claimed before", 10, &claimed, date(2024, time.March, 30)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Serial{ClaimGraceDays: tt.graceDays}
+			issue := &SerialIssue{ExpectedDate: expected, ClaimedAt: tt.claimedAt}
+			if got := s.ClaimableAt(issue); !got.Equal(tt.want) {
+				t.Errorf("ClaimableAt() = %s, want %s", got.Format("2006-01-02"), tt.want.Format("2006-01-02"))
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+// Status usulan pengadaan buku
+const (
+	SuggestionStatusNew         = "new"
+	SuggestionStatusUnderReview = "under_review"
+	SuggestionStatusOrdered     = "ordered"
+	SuggestionStatusReceived    = "received"
+	SuggestionStatusRejected    = "rejected"
+)
+
+// suggestionTransitions berisi status berikutnya yang boleh dipilih pustakawan dari setiap status
+var suggestionTransitions = map[string][]string{
+	SuggestionStatusNew:         {SuggestionStatusUnderReview, SuggestionStatusRejected},
+	SuggestionStatusUnderReview: {SuggestionStatusOrdered, SuggestionStatusRejected},
+	SuggestionStatusOrdered:     {SuggestionStatusReceived, SuggestionStatusRejected},
+}
+
+// IsSuggestionStatus mengembalikan true jika status adalah salah satu status usulan pengadaan
+func IsSuggestionStatus(status string) bool {
+	switch status {
+	case SuggestionStatusNew, SuggestionStatusUnderReview, SuggestionStatusOrdered, SuggestionStatusReceived, SuggestionStatusRejected:
+		return true
+	}
+	return false
+}
+
+// PurchaseSuggestion represents a member's request for the library to buy a book it does not own
+type PurchaseSuggestion struct {
+	ID            int       `json:"id"`
+	RequesterID   int       `json:"requester_id,omitempty"` // 0 jika anggota pengusul sudah dihapus permanen
+	ISBN          string    `json:"isbn,omitempty"`
+	Title         string    `json:"title"`
+	Author        string    `json:"author,omitempty"`
+	Justification string    `json:"justification"`
+	Status        string    `json:"status"`
+	StatusNote    string    `json:"status_note,omitempty"` // catatan pustakawan, e.g. alasan penolakan
+	BookID        *int      `json:"book_id,omitempty"`     // buku di katalog setelah usulan diterima dan dikatalogkan
+	Votes         int       `json:"votes"`
+	Voted         bool      `json:"voted"` // true jika anggota yang sedang login sudah memberi suara
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// Open mengembalikan true selama usulan belum dipesan, diterima atau ditolak; hanya usulan terbuka yang dapat diberi suara
+func (s *PurchaseSuggestion) Open() bool {
+	return s.Status == SuggestionStatusNew || s.Status == SuggestionStatusUnderReview
+}
+
+// CanMoveTo mengembalikan true jika status usulan boleh diubah menjadi status
+func (s *PurchaseSuggestion) CanMoveTo(status string) bool {
+	for _, next := range suggestionTransitions[s.Status] {
+		if next == status {
+			return true
+		}
+	}
+	return false
+}
+
+// SuggestionStatusUpdate is the body of a librarian's status change of a purchase suggestion
+type SuggestionStatusUpdate struct {
+	Status string `json:"status"`
+	Note   string `json:"note"`
+	BookID *int   `json:"book_id"` // opsional saat "received"; jika kosong buku dicari berdasarkan ISBN
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// Trash lists the soft-deleted records that can still be restored
+type Trash struct {
+	Books   []Book   `json:"books"`
+	Members []Member `json:"members"`
+}
+
+// PurgeResult reports how many soft-deleted records were permanently removed
+type PurgeResult struct {
+	Before        time.Time `json:"before"`
+	BooksPurged   int       `json:"books_purged"`
+	MembersPurged int       `json:"members_purged"`
+}
@@ -0,0 +1,38 @@
+package models
+
+// Work groups the editions, printings and translations of the same intellectual work,
+// e.g. every edition of "Laskar Pelangi" regardless of publisher or language
+type Work struct {
+	ID           int    `json:"id"`
+	Title        string `json:"title"`
+	Author       string `json:"author"`
+	SeriesID     *int   `json:"series_id,omitempty"`
+	SeriesVolume string `json:"series_volume,omitempty"` // nomor jilid dalam seri, e.g. "1" atau "2.5"
+	Editions     []Book `json:"editions,omitempty"`
+}
+
+// Series represents a numbered series of works, e.g. the "Tetralogi Laskar Pelangi"
+type Series struct {
+	ID          int    `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Works       []Work `json:"works,omitempty"` // diurutkan berdasarkan nomor jilid
+}
+
+// EditionRating is the rating summary of a single edition of a work
+type EditionRating struct {
+	BookID        int     `json:"book_id"`
+	Title         string  `json:"title"`
+	Edition       string  `json:"edition"`
+	Language      string  `json:"language"`
+	AverageRating float64 `json:"average_rating"`
+	ReviewCount   int     `json:"review_count"`
+}
+
+// WorkRating aggregates the reviews of every edition of a work
+type WorkRating struct {
+	WorkID        int             `json:"work_id"`
+	AverageRating float64         `json:"average_rating"`
+	ReviewCount   int             `json:"review_count"`
+	Editions      []EditionRating `json:"editions"`
+}
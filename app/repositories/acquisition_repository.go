@@ -0,0 +1,469 @@
+package repositories
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq" // Import driver PostgreSQL
+)
+
+// Error yang dikembalikan AcquisitionRepository saat aturan anggaran atau penerimaan dilanggar
+var (
+	ErrInsufficientFunds = errors.New("fund does not have enough remaining budget for this order")
+	ErrOverReceipt       = errors.New("received quantity exceeds the outstanding quantity of the order line")
+	ErrDuplicateBarcode  = errors.New("a received barcode is already used by another copy")
+)
+
+// AcquisitionRepository provides methods for interacting with vendors, funds, purchase orders and receipts in the database
+type AcquisitionRepository interface {
+	GetAllVendors() ([]models.Vendor, error)
+	GetVendorByID(id int) (*models.Vendor, error)
+	CreateVendor(v *models.Vendor) error
+	UpdateVendor(v *models.Vendor) error
+	DeleteVendor(id int) error
+	GetFunds(fromYear, toYear int) ([]models.Fund, error)
+	GetFundByID(id int) (*models.Fund, error)
+	CreateFund(f *models.Fund) error
+	UpdateFund(f *models.Fund) error
+	DeleteFund(id int) error
+	CountOrders(vendorID, fundID int) (int, error)
+	GetOrders(status string) ([]models.PurchaseOrder, error)
+	GetOrderByID(id int) (*models.PurchaseOrder, error)
+	CreateOrder(o *models.PurchaseOrder) error
+	AddLine(o *models.PurchaseOrder, l *models.PurchaseOrderLine) error
+	UpdateOrderStatus(id int, status string) error
+	ReceiveLines(orderID int, lines []models.ReceiveLine, receivedAt time.Time) error
+}
+
+// NewAcquisitionRepository creates a new AcquisitionRepository instance
+func NewAcquisitionRepository(db *sql.DB) *acquisitionRepository {
+	return &acquisitionRepository{db: db}
+}
+
+type acquisitionRepository struct {
+	db *sql.DB
+}
+
+// fundQuery menghitung nilai yang terikat pesanan (encumbered) dan yang sudah dibelanjakan (expended) per anggaran
+const fundQuery = `
+        SELECT f.id, f.code, f.name, f.fiscal_year, f.allocated,
+               COALESCE((SELECT SUM((l.quantity - l.quantity_received) * l.unit_price)
+                         FROM purchase_order_lines l
+                         JOIN purchase_orders o ON o.id = l.order_id
+                         WHERE o.fund_id = f.id AND o.status IN ('open', 'partially_received')), 0),
+               COALESCE((SELECT SUM(r.amount)
+                         FROM purchase_order_receipts r
+                         JOIN purchase_order_lines l ON l.id = r.line_id
+                         JOIN purchase_orders o ON o.id = l.order_id
+                         WHERE o.fund_id = f.id), 0)
+        FROM funds f
+`
+
+// GetAllVendors retrieves all vendors from the database
+func (ar *acquisitionRepository) GetAllVendors() ([]models.Vendor, error) {
+	const query = `
+        SELECT id, name, contact_name, email, phone, address, created_at
+        FROM vendors
+        ORDER BY name, id
+    `
+
+	rows, err := ar.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var vendors []models.Vendor
+	for rows.Next() {
+		var v models.Vendor
+		if err := rows.Scan(&v.ID, &v.Name, &v.ContactName, &v.Email, &v.Phone, &v.Address, &v.CreatedAt); err != nil {
+			return nil, err
+		}
+		vendors = append(vendors, v)
+	}
+
+	return vendors, rows.Err()
+}
+
+// GetVendorByID retrieves a vendor by ID from the database
+func (ar *acquisitionRepository) GetVendorByID(id int) (*models.Vendor, error) {
+	const query = `
+        SELECT id, name, contact_name, email, phone, address, created_at
+        FROM vendors
+        WHERE id = $1
+    `
+
+	var v models.Vendor
+	err := ar.db.QueryRow(query, id).Scan(&v.ID, &v.Name, &v.ContactName, &v.Email, &v.Phone, &v.Address, &v.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("vendor not found")
+		}
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+// CreateVendor creates a new vendor in the database
+func (ar *acquisitionRepository) CreateVendor(v *models.Vendor) error {
+	const query = `
+        INSERT INTO vendors (name, contact_name, email, phone, address, created_at)
+        VALUES ($1, $2, $3, $4, $5, NOW())
+        RETURNING id, created_at
+    `
+
+	return ar.db.QueryRow(query, v.Name, v.ContactName, v.Email, v.Phone, v.Address).Scan(&v.ID, &v.CreatedAt)
+}
+
+// UpdateVendor updates a vendor in the database
+func (ar *acquisitionRepository) UpdateVendor(v *models.Vendor) error {
+	const query = `
+        UPDATE vendors
+        SET name = $1, contact_name = $2, email = $3, phone = $4, address = $5
+        WHERE id = $6
+    `
+
+	_, err := ar.db.Exec(query, v.Name, v.ContactName, v.Email, v.Phone, v.Address, v.ID)
+	return err
+}
+
+// DeleteVendor deletes a vendor from the database
+func (ar *acquisitionRepository) DeleteVendor(id int) error {
+	const query = "DELETE FROM vendors WHERE id = $1"
+
+	_, err := ar.db.Exec(query, id)
+	return err
+}
+
+// GetFunds retrieves the funds of the fiscal years fromYear through toYear; 0 means no bound
+func (ar *acquisitionRepository) GetFunds(fromYear, toYear int) ([]models.Fund, error) {
+	const query = fundQuery + `
+        WHERE ($1 = 0 OR f.fiscal_year >= $1) AND ($2 = 0 OR f.fiscal_year <= $2)
+        ORDER BY f.fiscal_year DESC, f.code
+    `
+
+	rows, err := ar.db.Query(query, fromYear, toYear)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var funds []models.Fund
+	for rows.Next() {
+		f, err := scanFund(rows)
+		if err != nil {
+			return nil, err
+		}
+		funds = append(funds, *f)
+	}
+
+	return funds, rows.Err()
+}
+
+// GetFundByID retrieves a fund by ID from the database
+func (ar *acquisitionRepository) GetFundByID(id int) (*models.Fund, error) {
+	f, err := scanFund(ar.db.QueryRow(fundQuery+"WHERE f.id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("fund not found")
+	}
+	return f, err
+}
+
+// CreateFund creates a new fund in the database
+func (ar *acquisitionRepository) CreateFund(f *models.Fund) error {
+	const query = `
+        INSERT INTO funds (code, name, fiscal_year, allocated)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id
+    `
+
+	if err := ar.db.QueryRow(query, f.Code, f.Name, f.FiscalYear, f.Allocated).Scan(&f.ID); err != nil {
+		return err
+	}
+	f.Encumbered, f.Expended, f.Remaining = 0, 0, f.Allocated
+	return nil
+}
+
+// UpdateFund updates the code, name, fiscal year and allocation of a fund
+func (ar *acquisitionRepository) UpdateFund(f *models.Fund) error {
+	const query = `
+        UPDATE funds
+        SET code = $1, name = $2, fiscal_year = $3, allocated = $4
+        WHERE id = $5
+    `
+
+	_, err := ar.db.Exec(query, f.Code, f.Name, f.FiscalYear, f.Allocated, f.ID)
+	return err
+}
+
+// DeleteFund deletes a fund from the database
+func (ar *acquisitionRepository) DeleteFund(id int) error {
+	const query = "DELETE FROM funds WHERE id = $1"
+
+	_, err := ar.db.Exec(query, id)
+	return err
+}
+
+// CountOrders counts the purchase orders placed with a vendor or charged to a fund; 0 means any
+func (ar *acquisitionRepository) CountOrders(vendorID, fundID int) (int, error) {
+	const query = `
+        SELECT COUNT(*) FROM purchase_orders
+        WHERE ($1 = 0 OR vendor_id = $1) AND ($2 = 0 OR fund_id = $2)
+    `
+
+	var count int
+	err := ar.db.QueryRow(query, vendorID, fundID).Scan(&count)
+	return count, err
+}
+
+// GetOrders retrieves purchase orders without their lines, optionally filtered by status, newest first
+func (ar *acquisitionRepository) GetOrders(status string) ([]models.PurchaseOrder, error) {
+	const query = `
+        SELECT o.id, o.vendor_id, o.fund_id, o.status, o.note, o.ordered_at,
+               COALESCE((SELECT SUM(l.quantity * l.unit_price) FROM purchase_order_lines l WHERE l.order_id = o.id), 0)
+        FROM purchase_orders o
+        WHERE $1::text = '' OR o.status = $1
+        ORDER BY o.ordered_at DESC, o.id DESC
+    `
+
+	rows, err := ar.db.Query(query, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []models.PurchaseOrder
+	for rows.Next() {
+		var o models.PurchaseOrder
+		if err := rows.Scan(&o.ID, &o.VendorID, &o.FundID, &o.Status, &o.Note, &o.OrderedAt, &o.Total); err != nil {
+			return nil, err
+		}
+		orders = append(orders, o)
+	}
+
+	return orders, rows.Err()
+}
+
+// GetOrderByID retrieves a purchase order with its lines from the database
+func (ar *acquisitionRepository) GetOrderByID(id int) (*models.PurchaseOrder, error) {
+	const orderQuery = `
+        SELECT id, vendor_id, fund_id, status, note, ordered_at
+        FROM purchase_orders
+        WHERE id = $1
+    `
+	const linesQuery = `
+        SELECT id, order_id, isbn, title, author, quantity, quantity_received, unit_price, book_id
+        FROM purchase_order_lines
+        WHERE order_id = $1
+        ORDER BY id
+    `
+
+	var o models.PurchaseOrder
+	err := ar.db.QueryRow(orderQuery, id).Scan(&o.ID, &o.VendorID, &o.FundID, &o.Status, &o.Note, &o.OrderedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("purchase order not found")
+		}
+		return nil, err
+	}
+
+	rows, err := ar.db.Query(linesQuery, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	o.Lines = []models.PurchaseOrderLine{}
+	for rows.Next() {
+		var l models.PurchaseOrderLine
+		if err := rows.Scan(&l.ID, &l.OrderID, &l.ISBN, &l.Title, &l.Author, &l.Quantity, &l.QuantityReceived, &l.UnitPrice, &l.BookID); err != nil {
+			return nil, err
+		}
+		o.Lines = append(o.Lines, l)
+		o.Total += float64(l.Quantity) * l.UnitPrice
+	}
+
+	return &o, rows.Err()
+}
+
+// CreateOrder creates a purchase order with its lines. The fund is locked while its remaining budget is checked,
+// so concurrent orders cannot overspend it.
+func (ar *acquisitionRepository) CreateOrder(o *models.PurchaseOrder) error {
+	const orderQuery = `
+        INSERT INTO purchase_orders (vendor_id, fund_id, status, note, ordered_at)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING id
+    `
+
+	tx, err := ar.db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := checkFundRemaining(tx, o.FundID, o.Total); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.QueryRow(orderQuery, o.VendorID, o.FundID, o.Status, o.Note, o.OrderedAt).Scan(&o.ID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	for i := range o.Lines {
+		o.Lines[i].OrderID = o.ID
+		if err := insertOrderLine(tx, &o.Lines[i]); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// AddLine adds a line to an existing purchase order after checking the remaining budget of its fund
+func (ar *acquisitionRepository) AddLine(o *models.PurchaseOrder, l *models.PurchaseOrderLine) error {
+	tx, err := ar.db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := checkFundRemaining(tx, o.FundID, float64(l.Quantity)*l.UnitPrice); err != nil {
+		tx.Rollback()
+		return err
+	}
+	l.OrderID = o.ID
+	if err := insertOrderLine(tx, l); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UpdateOrderStatus updates the status of a purchase order
+func (ar *acquisitionRepository) UpdateOrderStatus(id int, status string) error {
+	const query = "UPDATE purchase_orders SET status = $1 WHERE id = $2"
+
+	_, err := ar.db.Exec(query, status, id)
+	return err
+}
+
+// ReceiveLines records a (partial) receipt in one transaction: the NewBook of a line is catalogued, a copy is created
+// for every barcode, the received amount is booked as expenditure and the order becomes partially_received or
+// received. Lines sharing a NewBook get a single book. ErrDuplicateBarcode is returned if a barcode is already taken.
+func (ar *acquisitionRepository) ReceiveLines(orderID int, lines []models.ReceiveLine, receivedAt time.Time) error {
+	const lockQuery = `
+        SELECT quantity - quantity_received, unit_price
+        FROM purchase_order_lines
+        WHERE id = $1 AND order_id = $2
+        FOR UPDATE
+    `
+	const copyQuery = `
+        INSERT INTO book_copies (book_id, barcode, location_id, status)
+        VALUES ($1, $2, $3, $4)
+    `
+	const receiptQuery = `
+        INSERT INTO purchase_order_receipts (line_id, quantity, amount, received_at)
+        VALUES ($1, $2, $3, $4)
+    `
+	const lineQuery = "UPDATE purchase_order_lines SET quantity_received = quantity_received + $1, book_id = $2 WHERE id = $3"
+	const statusQuery = `
+        UPDATE purchase_orders o
+        SET status = CASE
+            WHEN EXISTS (SELECT 1 FROM purchase_order_lines l WHERE l.order_id = o.id AND l.quantity_received < l.quantity)
+            THEN 'partially_received' ELSE 'received' END
+        WHERE o.id = $1
+    `
+
+	tx, err := ar.db.Begin()
+	if err != nil {
+		return err
+	}
+	for i := range lines {
+		line := &lines[i]
+		var outstanding int
+		var unitPrice float64
+		if err := tx.QueryRow(lockQuery, line.LineID, orderID).Scan(&outstanding, &unitPrice); err != nil {
+			tx.Rollback()
+			if err == sql.ErrNoRows {
+				return errors.New("order line not found")
+			}
+			return err
+		}
+		if line.Quantity > outstanding {
+			tx.Rollback()
+			return ErrOverReceipt
+		}
+		if line.NewBook != nil {
+			if line.NewBook.ID == 0 {
+				if err := insertBook(tx, line.NewBook); err != nil {
+					tx.Rollback()
+					return err
+				}
+			}
+			line.BookID = line.NewBook.ID
+		}
+		for _, barcode := range line.Barcodes {
+			if _, err := tx.Exec(copyQuery, line.BookID, barcode, line.LocationID, models.CopyStatusAvailable); err != nil {
+				tx.Rollback()
+				if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+					return ErrDuplicateBarcode
+				}
+				return err
+			}
+		}
+		if _, err := tx.Exec(receiptQuery, line.LineID, line.Quantity, float64(line.Quantity)*unitPrice, receivedAt); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if _, err := tx.Exec(lineQuery, line.Quantity, line.BookID, line.LineID); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if _, err := tx.Exec(statusQuery, orderID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// checkFundRemaining locks a fund and returns ErrInsufficientFunds if amount exceeds its remaining budget
+func checkFundRemaining(tx *sql.Tx, fundID int, amount float64) error {
+	if _, err := tx.Exec("SELECT id FROM funds WHERE id = $1 FOR UPDATE", fundID); err != nil {
+		return err
+	}
+	f, err := scanFund(tx.QueryRow(fundQuery+"WHERE f.id = $1", fundID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errors.New("fund not found")
+		}
+		return err
+	}
+	if amount > f.Remaining {
+		return ErrInsufficientFunds
+	}
+	return nil
+}
+
+// insertOrderLine inserts a purchase order line inside a transaction
+func insertOrderLine(tx *sql.Tx, l *models.PurchaseOrderLine) error {
+	const query = `
+        INSERT INTO purchase_order_lines (order_id, isbn, title, author, quantity, quantity_received, unit_price)
+        VALUES ($1, $2, $3, $4, $5, 0, $6)
+        RETURNING id
+    `
+
+	return tx.QueryRow(query, l.OrderID, l.ISBN, l.Title, l.Author, l.Quantity, l.UnitPrice).Scan(&l.ID)
+}
+
+// scanFund scans a row of fundQuery and computes the remaining budget
+func scanFund(row interface{ Scan(...interface{}) error }) (*models.Fund, error) {
+	var f models.Fund
+	if err := row.Scan(&f.ID, &f.Code, &f.Name, &f.FiscalYear, &f.Allocated, &f.Encumbered, &f.Expended); err != nil {
+		return nil, err
+	}
+	f.Remaining = f.Allocated - f.Encumbered - f.Expended
+	return &f, nil
+}
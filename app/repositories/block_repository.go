@@ -0,0 +1,148 @@
+package repositories
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// BlockRepository provides methods for member borrowing blocks
+type BlockRepository interface {
+	GetBlocksByMemberID(memberID int) ([]models.MemberBlock, error)
+	GetActiveBlocks(now time.Time) ([]models.MemberBlock, error)
+	GetBlockByID(id int) (*models.MemberBlock, error)
+	CreateBlock(b *models.MemberBlock) error
+	LiftBlock(b *models.MemberBlock) error
+	CreateFineBlocks(threshold float64, memberID int, reason string, at time.Time) ([]int, error)
+	LiftFineBlocks(threshold float64, memberID int, note string, at time.Time) ([]int, error)
+}
+
+// NewBlockRepository creates a new BlockRepository instance
+func NewBlockRepository(db *sql.DB) *blockRepository {
+	return &blockRepository{db: db}
+}
+
+type blockRepository struct {
+	db *sql.DB
+}
+
+// GetBlocksByMemberID retrieves all blocks of a member, lifted ones included, most recent first
+func (br *blockRepository) GetBlocksByMemberID(memberID int) ([]models.MemberBlock, error) {
+	const query = `
+        SELECT id, member_id, type, reason, automatic, starts_at, ends_at, created_by, created_at, lifted_at,
+               COALESCE(lifted_by, ''), COALESCE(lift_note, '')
+        FROM member_blocks
+        WHERE member_id = $1
+        ORDER BY starts_at DESC, id DESC
+    `
+	return br.queryBlocks(query, memberID)
+}
+
+// GetActiveBlocks retrieves the blocks in force at the given time, oldest first
+func (br *blockRepository) GetActiveBlocks(now time.Time) ([]models.MemberBlock, error) {
+	const query = `
+        SELECT id, member_id, type, reason, automatic, starts_at, ends_at, created_by, created_at, lifted_at,
+               COALESCE(lifted_by, ''), COALESCE(lift_note, '')
+        FROM member_blocks
+        WHERE lifted_at IS NULL AND starts_at <= $1 AND (ends_at IS NULL OR ends_at > $1)
+        ORDER BY starts_at, id
+    `
+	return br.queryBlocks(query, now)
+}
+
+// GetBlockByID retrieves a block by its ID
+func (br *blockRepository) GetBlockByID(id int) (*models.MemberBlock, error) {
+	const query = `
+        SELECT id, member_id, type, reason, automatic, starts_at, ends_at, created_by, created_at, lifted_at,
+               COALESCE(lifted_by, ''), COALESCE(lift_note, '')
+        FROM member_blocks
+        WHERE id = $1
+    `
+
+	blocks, err := br.queryBlocks(query, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(blocks) == 0 {
+		return nil, errors.New("block not found")
+	}
+	return &blocks[0], nil
+}
+
+// CreateBlock inserts a new block
+func (br *blockRepository) CreateBlock(b *models.MemberBlock) error {
+	const query = `
+        INSERT INTO member_blocks (member_id, type, reason, automatic, starts_at, ends_at, created_by, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        RETURNING id
+    `
+	return br.db.QueryRow(query, b.MemberID, b.Type, b.Reason, b.Automatic, b.StartsAt, b.EndsAt, b.CreatedBy, b.CreatedAt).Scan(&b.ID)
+}
+
+// LiftBlock stores the lifting of a block that has not been lifted yet
+func (br *blockRepository) LiftBlock(b *models.MemberBlock) error {
+	const query = `
+        UPDATE member_blocks
+        SET lifted_at = $1, lifted_by = $2, lift_note = $3
+        WHERE id = $4 AND lifted_at IS NULL
+    `
+
+	result, err := br.db.Exec(query, b.LiftedAt, b.LiftedBy, b.LiftNote, b.ID)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return errors.New("block has already been lifted")
+	}
+	return nil
+}
+
+// CreateFineBlocks places an automatic fine block on every member whose fine balance reached the threshold and
+// who has no fine block yet, and returns their IDs. memberID limits the check to one member; 0 checks everyone.
+func (br *blockRepository) CreateFineBlocks(threshold float64, memberID int, reason string, at time.Time) ([]int, error) {
+	const query = `
+        INSERT INTO member_blocks (member_id, type, reason, automatic, starts_at, created_by, created_at)
+        SELECT m.id, 'fines', $3, TRUE, $4, 'system', $4
+        FROM members m
+        WHERE m.deleted_at IS NULL AND COALESCE(m.fine_amount, 0) >= $1 AND ($2 = 0 OR m.id = $2)
+          AND NOT EXISTS (SELECT 1 FROM member_blocks b
+                          WHERE b.member_id = m.id AND b.type = 'fines' AND b.automatic AND b.lifted_at IS NULL)
+        RETURNING member_id
+    `
+	return queryIDs(br.db, query, threshold, memberID, reason, at)
+}
+
+// LiftFineBlocks lifts the automatic fine blocks of members whose fine balance dropped below the threshold and
+// returns their IDs. memberID limits the check to one member; 0 checks everyone.
+func (br *blockRepository) LiftFineBlocks(threshold float64, memberID int, note string, at time.Time) ([]int, error) {
+	const query = `
+        UPDATE member_blocks b
+        SET lifted_at = $4, lifted_by = 'system', lift_note = $3
+        FROM members m
+        WHERE b.member_id = m.id AND b.type = 'fines' AND b.automatic AND b.lifted_at IS NULL
+          AND COALESCE(m.fine_amount, 0) < $1 AND ($2 = 0 OR m.id = $2)
+        RETURNING b.member_id
+    `
+	return queryIDs(br.db, query, threshold, memberID, note, at)
+}
+
+// queryBlocks runs a query returning member block rows
+func (br *blockRepository) queryBlocks(query string, arg interface{}) ([]models.MemberBlock, error) {
+	rows, err := br.db.Query(query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var blocks []models.MemberBlock
+	for rows.Next() {
+		var b models.MemberBlock
+		if err := rows.Scan(&b.ID, &b.MemberID, &b.Type, &b.Reason, &b.Automatic, &b.StartsAt, &b.EndsAt,
+			&b.CreatedBy, &b.CreatedAt, &b.LiftedAt, &b.LiftedBy, &b.LiftNote); err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, b)
+	}
+	return blocks, rows.Err()
+}
@@ -4,7 +4,9 @@ import (
 	"Restful-Perpustakaan-API/app/models"
 	"database/sql"
 	"errors"
-	_ "github.com/lib/pq" // Import driver PostgreSQL
+	"time"
+
+	"github.com/lib/pq" // Import driver PostgreSQL
 )
 
 // BookRepository provides methods for interacting with the book data in the database
@@ -13,10 +15,17 @@ type BookRepository interface {
 	GetBookByID(id int) (*models.Book, error)
 	CreateBook(b *models.Book) error
 	UpdateBook(b *models.Book) error
-	DeleteBook(id int) error
+	DeleteBook(id int, deletedBy string) error
+	GetDeletedBooks() ([]models.Book, error)
+	RestoreBook(id int, restoredBy string) error
+	PurgeDeletedBooks(before time.Time) (int, error)
 	GetRecommendations() ([]models.Book, error)
 	GetPersonalizedRecommendations(id int) ([]models.Book, error)
 	GetTotalBooks() (interface{}, interface{})
+	GetBookByISBN(isbn string) (*models.Book, error)
+	CreateBooks(books []models.Book) error
+	StreamBooks(fn func(b models.Book) error) error
+	RecordCreated(b *models.Book)
 }
 
 // NewBookRepository creates a new BookRepository instance
@@ -31,8 +40,9 @@ type bookRepository struct {
 // GetAllBooks retrieves all books from the database
 func (br *bookRepository) GetAllBooks() ([]models.Book, error) {
 	const query = `
-        SELECT id, title, author, publisher, published_year, isbn, genre, description, cover_image 
+        SELECT id, title, author, publisher, published_year, isbn, genre, description, cover_image, classification, call_number, work_id, edition, language 
         FROM books
+        WHERE deleted_at IS NULL
     `
 
 	rows, err := br.db.Query(query)
@@ -44,7 +54,7 @@ func (br *bookRepository) GetAllBooks() ([]models.Book, error) {
 	var books []models.Book
 	for rows.Next() {
 		var b models.Book
-		if err := rows.Scan(&b.ID, &b.Title, &b.Author, &b.Publisher, &b.PublishedYear, &b.ISBN, &b.Genre, &b.Description, &b.CoverImage); err != nil {
+		if err := rows.Scan(&b.ID, &b.Title, &b.Author, &b.Publisher, &b.PublishedYear, &b.ISBN, &b.Genre, &b.Description, &b.CoverImage, &b.Classification, &b.CallNumber, &b.WorkID, &b.Edition, &b.Language); err != nil {
 			return nil, err
 		}
 		books = append(books, b)
@@ -56,13 +66,13 @@ func (br *bookRepository) GetAllBooks() ([]models.Book, error) {
 // GetBookByID retrieves a book by ID from the database
 func (br *bookRepository) GetBookByID(id int) (*models.Book, error) {
 	const query = `
-        SELECT id, title, author, publisher, published_year, isbn, genre, description, cover_image 
+        SELECT id, title, author, publisher, published_year, isbn, genre, description, cover_image, classification, call_number, work_id, edition, language 
         FROM books 
-        WHERE id = $1
+        WHERE id = $1 AND deleted_at IS NULL
     `
 
 	var b models.Book
-	err := br.db.QueryRow(query, id).Scan(&b.ID, &b.Title, &b.Author, &b.Publisher, &b.PublishedYear, &b.ISBN, &b.Genre, &b.Description, &b.CoverImage)
+	err := br.db.QueryRow(query, id).Scan(&b.ID, &b.Title, &b.Author, &b.Publisher, &b.PublishedYear, &b.ISBN, &b.Genre, &b.Description, &b.CoverImage, &b.Classification, &b.CallNumber, &b.WorkID, &b.Edition, &b.Language)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, errors.New("book not found")
@@ -75,50 +85,308 @@ func (br *bookRepository) GetBookByID(id int) (*models.Book, error) {
 
 // CreateBook creates a new book in the database
 func (br *bookRepository) CreateBook(b *models.Book) error {
+	return insertBook(br.db, b)
+}
+
+// RecordCreated is called for a book that another repository inserted with insertBook inside its own transaction.
+// The book is already stored, so there is nothing left to do here; the decorators record and announce it.
+func (br *bookRepository) RecordCreated(b *models.Book) {}
+
+// rowQuerier is implemented by both *sql.DB and *sql.Tx
+type rowQuerier interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// insertBook inserts a book and fills in its ID
+func insertBook(q rowQuerier, b *models.Book) error {
 	const query = `
-        INSERT INTO books (title, author, publisher, published_year, isbn, genre, description, cover_image) 
-        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        INSERT INTO books (title, author, publisher, published_year, isbn, genre, description, cover_image, classification, call_number, work_id, edition, language) 
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
         RETURNING id 
     `
 
-	err := br.db.QueryRow(query, b.Title, b.Author, b.Publisher, b.PublishedYear, b.ISBN, b.Genre, b.Description, b.CoverImage).Scan(&b.ID)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return q.QueryRow(query, b.Title, b.Author, b.Publisher, b.PublishedYear, b.ISBN, b.Genre, b.Description, b.CoverImage, b.Classification, b.CallNumber, b.WorkID, b.Edition, b.Language).Scan(&b.ID)
 }
 
 // UpdateBook updates a book in the database
 func (br *bookRepository) UpdateBook(b *models.Book) error {
 	const query = `
         UPDATE books 
-        SET title = $1, author = $2, publisher = $3, published_year = $4, isbn = $5, genre = $6, description = $7, cover_image = $8
-        WHERE id = $9
+        SET title = $1, author = $2, publisher = $3, published_year = $4, isbn = $5, genre = $6, description = $7, cover_image = $8, classification = $9, call_number = $10, work_id = $11, edition = $12, language = $13
+        WHERE id = $14
     `
 
-	_, err := br.db.Exec(query, b.Title, b.Author, b.Publisher, b.PublishedYear, b.ISBN, b.Genre, b.Description, b.CoverImage, b.ID)
+	_, err := br.db.Exec(query, b.Title, b.Author, b.Publisher, b.PublishedYear, b.ISBN, b.Genre, b.Description, b.CoverImage, b.Classification, b.CallNumber, b.WorkID, b.Edition, b.Language, b.ID)
 	return err
 }
 
-// DeleteBook deletes a book from the database
-func (br *bookRepository) DeleteBook(id int) error {
-	const query = "DELETE FROM books WHERE id = $1"
+// DeleteBook moves a book to the trash; the row is kept so loans and reviews stay valid
+func (br *bookRepository) DeleteBook(id int, deletedBy string) error {
+	const query = "UPDATE books SET deleted_at = $1, deleted_by = $2 WHERE id = $3 AND deleted_at IS NULL"
 
-	_, err := br.db.Exec(query, id)
-	return err
+	result, err := br.db.Exec(query, time.Now(), deletedBy, id)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return errors.New("book not found")
+	}
+	return nil
 }
 
-// GetRecommendations retrieves book recommendations
+// GetDeletedBooks retrieves the books in the trash, most recently deleted first
+func (br *bookRepository) GetDeletedBooks() ([]models.Book, error) {
+	const query = `
+        SELECT id, title, author, publisher, published_year, isbn, genre, description, cover_image, classification, call_number, work_id, edition, language, deleted_at, deleted_by 
+        FROM books
+        WHERE deleted_at IS NOT NULL
+        ORDER BY deleted_at DESC
+    `
+
+	rows, err := br.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var books []models.Book
+	for rows.Next() {
+		var b models.Book
+		if err := rows.Scan(&b.ID, &b.Title, &b.Author, &b.Publisher, &b.PublishedYear, &b.ISBN, &b.Genre, &b.Description, &b.CoverImage, &b.Classification, &b.CallNumber, &b.WorkID, &b.Edition, &b.Language, &b.DeletedAt, &b.DeletedBy); err != nil {
+			return nil, err
+		}
+		books = append(books, b)
+	}
+
+	return books, rows.Err()
+}
+
+// ErrBookNotInTrash dikembalikan RestoreBook jika buku tidak ada di trash
+var ErrBookNotInTrash = errors.New("book not found in trash")
+
+// RestoreBook moves a book out of the trash; restoredBy is only used by the change history
+func (br *bookRepository) RestoreBook(id int, restoredBy string) error {
+	const query = "UPDATE books SET deleted_at = NULL, deleted_by = '' WHERE id = $1 AND deleted_at IS NOT NULL"
+
+	result, err := br.db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrBookNotInTrash
+	}
+	return nil
+}
+
+// PurgeDeletedBooks permanently deletes books that were moved to the trash before the given time,
+// together with their copies, holds, reviews, reading list entries and course reserves. Books still referenced by loans, digital items or serials are kept in the trash.
+func (br *bookRepository) PurgeDeletedBooks(before time.Time) (int, error) {
+	const selectQuery = `
+        SELECT id FROM books b
+        WHERE b.deleted_at < $1
+          AND NOT EXISTS (SELECT 1 FROM loans l WHERE l.book_id = b.id)
+          AND NOT EXISTS (SELECT 1 FROM digital_items d WHERE d.book_id = b.id)
+          AND NOT EXISTS (SELECT 1 FROM serials s WHERE s.book_id = b.id)
+    `
+
+	tx, err := br.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	ids, err := queryIDs(tx, selectQuery, before)
+	if err != nil || len(ids) == 0 {
+		tx.Rollback()
+		return 0, err
+	}
+	for _, query := range []string{
+		"DELETE FROM course_reserves WHERE book_id = ANY($1)",
+		"DELETE FROM book_copies WHERE book_id = ANY($1)",
+		"DELETE FROM holds WHERE book_id = ANY($1)",
+		"DELETE FROM reviews WHERE book_id = ANY($1)",
+		"DELETE FROM reading_list_items WHERE book_id = ANY($1)",
+		"UPDATE purchase_suggestions SET book_id = NULL WHERE book_id = ANY($1)",
+		"UPDATE purchase_order_lines SET book_id = NULL WHERE book_id = ANY($1)",
+		"DELETE FROM books WHERE id = ANY($1)",
+	} {
+		if _, err := tx.Exec(query, pq.Array(ids)); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+
+	return len(ids), tx.Commit()
+}
+
+// queryIDs runs a query returning a single integer column, on the database or inside a transaction
+func queryIDs(q interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}, query string, args ...interface{}) ([]int, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// GetRecommendations retrieves the most borrowed books; anonymized loan history still counts
 func (br *bookRepository) GetRecommendations() ([]models.Book, error) {
-	return nil, nil
+	const query = `
+        SELECT b.id, b.title, b.author, b.publisher, b.published_year, b.isbn, b.genre, b.description, b.cover_image, b.classification, b.call_number, b.work_id, b.edition, b.language
+        FROM books b
+        JOIN (
+            SELECT book_id FROM loan_history
+            UNION ALL
+            SELECT book_id FROM loans
+        ) l ON l.book_id = b.id
+        WHERE b.deleted_at IS NULL
+        GROUP BY b.id
+        ORDER BY COUNT(*) DESC, b.id
+        LIMIT 10
+    `
+
+	return br.queryBooks(query)
 }
 
-// GetPersonalizedRecommendations retrieves personalized book recommendations
+// GetPersonalizedRecommendations retrieves popular books sharing a genre or author with the member's loans,
+// excluding books the member has already borrowed
 func (br *bookRepository) GetPersonalizedRecommendations(id int) ([]models.Book, error) {
-	return nil, nil
+	const query = `
+        WITH borrowed AS (
+            SELECT book_id FROM loan_history WHERE member_id = $1
+            UNION
+            SELECT book_id FROM loans WHERE member_id = $1
+        ),
+        popularity AS (
+            SELECT book_id, COUNT(*) AS loans
+            FROM (SELECT book_id FROM loan_history UNION ALL SELECT book_id FROM loans) l
+            GROUP BY book_id
+        )
+        SELECT b.id, b.title, b.author, b.publisher, b.published_year, b.isbn, b.genre, b.description, b.cover_image, b.classification, b.call_number, b.work_id, b.edition, b.language
+        FROM books b
+        LEFT JOIN popularity p ON p.book_id = b.id
+        WHERE b.deleted_at IS NULL
+          AND b.id NOT IN (SELECT book_id FROM borrowed)
+          AND EXISTS (
+              SELECT 1 FROM books r
+              WHERE r.id IN (SELECT book_id FROM borrowed)
+                AND ((r.genre <> '' AND r.genre = b.genre) OR r.author = b.author)
+          )
+        ORDER BY COALESCE(p.loans, 0) DESC, b.id
+        LIMIT 10
+    `
+
+	return br.queryBooks(query, id)
+}
+
+// queryBooks runs a query selecting the standard book columns and scans the rows
+func (br *bookRepository) queryBooks(query string, args ...interface{}) ([]models.Book, error) {
+	rows, err := br.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var books []models.Book
+	for rows.Next() {
+		var b models.Book
+		if err := rows.Scan(&b.ID, &b.Title, &b.Author, &b.Publisher, &b.PublishedYear, &b.ISBN, &b.Genre, &b.Description, &b.CoverImage, &b.Classification, &b.CallNumber, &b.WorkID, &b.Edition, &b.Language); err != nil {
+			return nil, err
+		}
+		books = append(books, b)
+	}
+
+	return books, rows.Err()
 }
 
 func (br *bookRepository) GetTotalBooks() (interface{}, interface{}) {
 	return nil, nil
 }
+
+// GetBookByISBN retrieves a book by ISBN from the database; it returns sql.ErrNoRows if no book has the ISBN,
+// so callers can tell a missing book apart from a failed query
+func (br *bookRepository) GetBookByISBN(isbn string) (*models.Book, error) {
+	const query = `
+        SELECT id, title, author, publisher, published_year, isbn, genre, description, cover_image, classification, call_number, work_id, edition, language 
+        FROM books 
+        WHERE isbn = $1 AND deleted_at IS NULL
+    `
+
+	var b models.Book
+	err := br.db.QueryRow(query, isbn).Scan(&b.ID, &b.Title, &b.Author, &b.Publisher, &b.PublishedYear, &b.ISBN, &b.Genre, &b.Description, &b.CoverImage, &b.Classification, &b.CallNumber, &b.WorkID, &b.Edition, &b.Language)
+	if err != nil {
+		return nil, err
+	}
+
+	return &b, nil
+}
+
+// CreateBooks creates several books in a single transaction; either all of them are stored or none
+func (br *bookRepository) CreateBooks(books []models.Book) error {
+	const query = `
+        INSERT INTO books (title, author, publisher, published_year, isbn, genre, description, cover_image, classification, call_number, work_id, edition, language) 
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+        RETURNING id 
+    `
+
+	tx, err := br.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare(query)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+
+	for i := range books {
+		b := &books[i]
+		err := stmt.QueryRow(b.Title, b.Author, b.Publisher, b.PublishedYear, b.ISBN, b.Genre, b.Description, b.CoverImage, b.Classification, b.CallNumber, b.WorkID, b.Edition, b.Language).Scan(&b.ID)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// StreamBooks calls fn for every book in the catalog without loading the whole catalog into memory
+func (br *bookRepository) StreamBooks(fn func(b models.Book) error) error {
+	const query = `
+        SELECT id, title, author, publisher, published_year, isbn, genre, description, cover_image, classification, call_number, work_id, edition, language 
+        FROM books
+        WHERE deleted_at IS NULL
+        ORDER BY id
+    `
+
+	rows, err := br.db.Query(query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var b models.Book
+		if err := rows.Scan(&b.ID, &b.Title, &b.Author, &b.Publisher, &b.PublishedYear, &b.ISBN, &b.Genre, &b.Description, &b.CoverImage, &b.Classification, &b.CallNumber, &b.WorkID, &b.Edition, &b.Language); err != nil {
+			return err
+		}
+		if err := fn(b); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
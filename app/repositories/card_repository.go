@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"database/sql"
+	"errors"
+)
+
+// ErrCardAlreadyReplaced dikembalikan ReplaceCard jika kartu lama sudah tidak aktif
+var ErrCardAlreadyReplaced = errors.New("card has already been replaced")
+
+// ErrCardNotFound dikembalikan jika tidak ada kartu yang cocok dengan pencarian
+var ErrCardNotFound = errors.New("library card not found")
+
+// CardRepository provides methods for interacting with library cards in the database
+type CardRepository interface {
+	GetActiveCardByMemberID(memberID int) (*models.LibraryCard, error)
+	GetCardsByMemberID(memberID int) ([]models.LibraryCard, error)
+	GetCardByNumber(number string) (*models.LibraryCard, error)
+	CreateCard(c *models.LibraryCard) error
+	ReplaceCard(oldCardID int, reason string, c *models.LibraryCard) error
+}
+
+// NewCardRepository creates a new CardRepository instance
+func NewCardRepository(db *sql.DB) *cardRepository {
+	return &cardRepository{db: db}
+}
+
+type cardRepository struct {
+	db *sql.DB
+}
+
+// GetActiveCardByMemberID retrieves the active card of a member
+func (cr *cardRepository) GetActiveCardByMemberID(memberID int) (*models.LibraryCard, error) {
+	const query = `
+        SELECT id, member_id, number, status, issued_at, replaced_at, replacement_reason
+        FROM library_cards
+        WHERE member_id = $1 AND status = 'active'
+    `
+	return cr.queryCard(query, memberID)
+}
+
+// GetCardsByMemberID retrieves all cards issued to a member, most recent first
+func (cr *cardRepository) GetCardsByMemberID(memberID int) ([]models.LibraryCard, error) {
+	const query = `
+        SELECT id, member_id, number, status, issued_at, replaced_at, replacement_reason
+        FROM library_cards
+        WHERE member_id = $1
+        ORDER BY issued_at DESC, id DESC
+    `
+
+	rows, err := cr.db.Query(query, memberID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cards []models.LibraryCard
+	for rows.Next() {
+		var c models.LibraryCard
+		if err := rows.Scan(&c.ID, &c.MemberID, &c.Number, &c.Status, &c.IssuedAt, &c.ReplacedAt, &c.ReplacementReason); err != nil {
+			return nil, err
+		}
+		cards = append(cards, c)
+	}
+	return cards, rows.Err()
+}
+
+// GetCardByNumber retrieves a card, active or replaced, by its number
+func (cr *cardRepository) GetCardByNumber(number string) (*models.LibraryCard, error) {
+	const query = `
+        SELECT id, member_id, number, status, issued_at, replaced_at, replacement_reason
+        FROM library_cards
+        WHERE number = $1
+    `
+	return cr.queryCard(query, number)
+}
+
+// CreateCard issues a new active card
+func (cr *cardRepository) CreateCard(c *models.LibraryCard) error {
+	const query = `
+        INSERT INTO library_cards (member_id, number, status, issued_at)
+        VALUES ($1, $2, 'active', $3)
+        RETURNING id, status
+    `
+	return cr.db.QueryRow(query, c.MemberID, c.Number, c.IssuedAt).Scan(&c.ID, &c.Status)
+}
+
+// ReplaceCard invalidates the old card and issues the new one in one transaction
+func (cr *cardRepository) ReplaceCard(oldCardID int, reason string, c *models.LibraryCard) error {
+	const replaceQuery = `
+        UPDATE library_cards
+        SET status = 'replaced', replaced_at = $1, replacement_reason = $2
+        WHERE id = $3 AND status = 'active'
+    `
+	const insertQuery = `
+        INSERT INTO library_cards (member_id, number, status, issued_at)
+        VALUES ($1, $2, 'active', $3)
+        RETURNING id, status
+    `
+
+	tx, err := cr.db.Begin()
+	if err != nil {
+		return err
+	}
+	result, err := tx.Exec(replaceQuery, c.IssuedAt, reason, oldCardID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		tx.Rollback()
+		return ErrCardAlreadyReplaced
+	}
+	if err := tx.QueryRow(insertQuery, c.MemberID, c.Number, c.IssuedAt).Scan(&c.ID, &c.Status); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// queryCard runs a query returning a single card row
+func (cr *cardRepository) queryCard(query string, arg interface{}) (*models.LibraryCard, error) {
+	var c models.LibraryCard
+	err := cr.db.QueryRow(query, arg).Scan(&c.ID, &c.MemberID, &c.Number, &c.Status, &c.IssuedAt, &c.ReplacedAt, &c.ReplacementReason)
+	if err == sql.ErrNoRows {
+		return nil, ErrCardNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
@@ -0,0 +1,172 @@
+package repositories
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"database/sql"
+	"errors"
+
+	"github.com/lib/pq" // Import driver PostgreSQL
+)
+
+// ErrCopyOnLoan dikembalikan DeleteCopy jika eksemplar masih tercatat pada peminjaman
+var ErrCopyOnLoan = errors.New("copy is referenced by a loan")
+
+// CopyRepository provides methods for interacting with book copy (item) data in the database
+type CopyRepository interface {
+	GetCopiesByBookID(bookID int) ([]models.BookCopy, error)
+	GetCopyByID(id int) (*models.BookCopy, error)
+	CreateCopy(c *models.BookCopy) error
+	UpdateCopy(c *models.BookCopy) error
+	DeleteCopy(id int) error
+	CountCopiesAtLocations(locationIDs []int) (int, error)
+	GetShelfList(locationIDs []int) ([]models.ShelfListEntry, error)
+}
+
+// NewCopyRepository creates a new CopyRepository instance
+func NewCopyRepository(db *sql.DB) *copyRepository {
+	return &copyRepository{db: db}
+}
+
+type copyRepository struct {
+	db *sql.DB
+}
+
+// GetCopiesByBookID retrieves all copies of a book from the database
+func (cr *copyRepository) GetCopiesByBookID(bookID int) ([]models.BookCopy, error) {
+	const query = `
+        SELECT id, book_id, barcode, location_id, status
+        FROM book_copies
+        WHERE book_id = $1
+        ORDER BY id
+    `
+
+	rows, err := cr.db.Query(query, bookID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var copies []models.BookCopy
+	for rows.Next() {
+		var c models.BookCopy
+		if err := rows.Scan(&c.ID, &c.BookID, &c.Barcode, &c.LocationID, &c.Status); err != nil {
+			return nil, err
+		}
+		copies = append(copies, c)
+	}
+
+	return copies, rows.Err()
+}
+
+// GetCopyByID retrieves a copy by ID from the database
+func (cr *copyRepository) GetCopyByID(id int) (*models.BookCopy, error) {
+	const query = `
+        SELECT id, book_id, barcode, location_id, status
+        FROM book_copies
+        WHERE id = $1
+    `
+
+	var c models.BookCopy
+	err := cr.db.QueryRow(query, id).Scan(&c.ID, &c.BookID, &c.Barcode, &c.LocationID, &c.Status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("copy not found")
+		}
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// CreateCopy creates a new copy in the database
+func (cr *copyRepository) CreateCopy(c *models.BookCopy) error {
+	const query = `
+        INSERT INTO book_copies (book_id, barcode, location_id, status)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id
+    `
+
+	return cr.db.QueryRow(query, c.BookID, c.Barcode, c.LocationID, c.Status).Scan(&c.ID)
+}
+
+// UpdateCopy updates a copy in the database
+func (cr *copyRepository) UpdateCopy(c *models.BookCopy) error {
+	const query = `
+        UPDATE book_copies
+        SET barcode = $1, location_id = $2, status = $3
+        WHERE id = $4
+    `
+
+	_, err := cr.db.Exec(query, c.Barcode, c.LocationID, c.Status, c.ID)
+	return err
+}
+
+// DeleteCopy deletes a copy from the database; course reserves of the copy stay on the book and a serial issue stays received.
+// A copy that a loan still points at is not deleted and ErrCopyOnLoan is returned.
+func (cr *copyRepository) DeleteCopy(id int) error {
+	tx, err := cr.db.Begin()
+	if err != nil {
+		return err
+	}
+	// Mengunci baris eksemplar agar tidak dipinjam di antara pemeriksaan dan penghapusan
+	var onLoan bool
+	err = tx.QueryRow("SELECT EXISTS (SELECT 1 FROM loans WHERE copy_id = $1) FROM book_copies WHERE id = $1 FOR UPDATE", id).Scan(&onLoan)
+	if err != nil && err != sql.ErrNoRows {
+		tx.Rollback()
+		return err
+	}
+	if onLoan {
+		tx.Rollback()
+		return ErrCopyOnLoan
+	}
+	for _, query := range []string{
+		"UPDATE course_reserves SET copy_id = NULL WHERE copy_id = $1",
+		"UPDATE serial_issues SET copy_id = NULL WHERE copy_id = $1",
+		"DELETE FROM book_copies WHERE id = $1",
+	} {
+		if _, err := tx.Exec(query, id); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// CountCopiesAtLocations counts the copies shelved at any of the given locations
+func (cr *copyRepository) CountCopiesAtLocations(locationIDs []int) (int, error) {
+	const query = "SELECT COUNT(*) FROM book_copies WHERE location_id = ANY($1)"
+
+	var count int
+	err := cr.db.QueryRow(query, pq.Array(locationIDs)).Scan(&count)
+	return count, err
+}
+
+// GetShelfList retrieves the copies shelved at any of the given locations together with their book.
+// Rows are not sorted; call numbers need call-number-aware ordering which SQL cannot do.
+func (cr *copyRepository) GetShelfList(locationIDs []int) ([]models.ShelfListEntry, error) {
+	const query = `
+        SELECT c.id, c.barcode, c.status, l.id, l.code, b.id, b.title, b.author,
+               COALESCE(NULLIF(b.call_number, ''), b.classification)
+        FROM book_copies c
+        JOIN books b ON b.id = c.book_id
+        JOIN locations l ON l.id = c.location_id
+        WHERE c.location_id = ANY($1) AND b.deleted_at IS NULL
+    `
+
+	rows, err := cr.db.Query(query, pq.Array(locationIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.ShelfListEntry
+	for rows.Next() {
+		var e models.ShelfListEntry
+		if err := rows.Scan(&e.CopyID, &e.Barcode, &e.Status, &e.LocationID, &e.LocationCode, &e.BookID, &e.Title, &e.Author, &e.CallNumber); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
@@ -0,0 +1,273 @@
+package repositories
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"database/sql"
+	"errors"
+	"time"
+
+	_ "github.com/lib/pq" // Import driver PostgreSQL
+)
+
+// CourseRepository provides methods for interacting with terms, courses and course reserves in the database
+type CourseRepository interface {
+	GetAllTerms() ([]models.Term, error)
+	GetTermByID(id int) (*models.Term, error)
+	CreateTerm(t *models.Term) error
+	UpdateTerm(t *models.Term) error
+	DeleteTerm(id int) error
+	CountCoursesByTermID(termID int) (int, error)
+	GetAllCourses() ([]models.Course, error)
+	GetCourseByID(id int) (*models.Course, error)
+	CreateCourse(c *models.Course) error
+	UpdateCourse(c *models.Course) error
+	DeleteCourse(id int) error
+	GetReservesByCourseID(courseID int) ([]models.CourseReserve, error)
+	GetReserveByID(id int) (*models.CourseReserve, error)
+	GetActiveReservesByBookID(bookID int, now time.Time) ([]models.CourseReserve, error)
+	CreateReserve(r *models.CourseReserve) error
+	DeleteReserve(id int) error
+}
+
+// NewCourseRepository creates a new CourseRepository instance
+func NewCourseRepository(db *sql.DB) *courseRepository {
+	return &courseRepository{db: db}
+}
+
+type courseRepository struct {
+	db *sql.DB
+}
+
+// GetAllTerms retrieves all terms, most recent first
+func (cr *courseRepository) GetAllTerms() ([]models.Term, error) {
+	const query = "SELECT id, name, start_date, end_date FROM terms ORDER BY start_date DESC, id DESC"
+
+	rows, err := cr.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var terms []models.Term
+	for rows.Next() {
+		var t models.Term
+		if err := rows.Scan(&t.ID, &t.Name, &t.StartDate, &t.EndDate); err != nil {
+			return nil, err
+		}
+		terms = append(terms, t)
+	}
+
+	return terms, rows.Err()
+}
+
+// GetTermByID retrieves a term by ID from the database
+func (cr *courseRepository) GetTermByID(id int) (*models.Term, error) {
+	const query = "SELECT id, name, start_date, end_date FROM terms WHERE id = $1"
+
+	var t models.Term
+	err := cr.db.QueryRow(query, id).Scan(&t.ID, &t.Name, &t.StartDate, &t.EndDate)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("term not found")
+		}
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// CreateTerm creates a new term in the database
+func (cr *courseRepository) CreateTerm(t *models.Term) error {
+	const query = "INSERT INTO terms (name, start_date, end_date) VALUES ($1, $2, $3) RETURNING id"
+
+	return cr.db.QueryRow(query, t.Name, t.StartDate, t.EndDate).Scan(&t.ID)
+}
+
+// UpdateTerm updates a term in the database
+func (cr *courseRepository) UpdateTerm(t *models.Term) error {
+	const query = "UPDATE terms SET name = $1, start_date = $2, end_date = $3 WHERE id = $4"
+
+	_, err := cr.db.Exec(query, t.Name, t.StartDate, t.EndDate, t.ID)
+	return err
+}
+
+// DeleteTerm deletes a term from the database
+func (cr *courseRepository) DeleteTerm(id int) error {
+	const query = "DELETE FROM terms WHERE id = $1"
+
+	_, err := cr.db.Exec(query, id)
+	return err
+}
+
+// CountCoursesByTermID counts the courses taught in a term
+func (cr *courseRepository) CountCoursesByTermID(termID int) (int, error) {
+	const query = "SELECT COUNT(*) FROM courses WHERE term_id = $1"
+
+	var count int
+	err := cr.db.QueryRow(query, termID).Scan(&count)
+	return count, err
+}
+
+// GetAllCourses retrieves all courses ordered by code
+func (cr *courseRepository) GetAllCourses() ([]models.Course, error) {
+	const query = "SELECT id, term_id, code, name, lecturer FROM courses ORDER BY code, id"
+
+	rows, err := cr.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var courses []models.Course
+	for rows.Next() {
+		var c models.Course
+		if err := rows.Scan(&c.ID, &c.TermID, &c.Code, &c.Name, &c.Lecturer); err != nil {
+			return nil, err
+		}
+		courses = append(courses, c)
+	}
+
+	return courses, rows.Err()
+}
+
+// GetCourseByID retrieves a course by ID from the database
+func (cr *courseRepository) GetCourseByID(id int) (*models.Course, error) {
+	const query = "SELECT id, term_id, code, name, lecturer FROM courses WHERE id = $1"
+
+	var c models.Course
+	err := cr.db.QueryRow(query, id).Scan(&c.ID, &c.TermID, &c.Code, &c.Name, &c.Lecturer)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("course not found")
+		}
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// CreateCourse creates a new course in the database
+func (cr *courseRepository) CreateCourse(c *models.Course) error {
+	const query = "INSERT INTO courses (term_id, code, name, lecturer) VALUES ($1, $2, $3, $4) RETURNING id"
+
+	return cr.db.QueryRow(query, c.TermID, c.Code, c.Name, c.Lecturer).Scan(&c.ID)
+}
+
+// UpdateCourse updates a course in the database
+func (cr *courseRepository) UpdateCourse(c *models.Course) error {
+	const query = "UPDATE courses SET term_id = $1, code = $2, name = $3, lecturer = $4 WHERE id = $5"
+
+	_, err := cr.db.Exec(query, c.TermID, c.Code, c.Name, c.Lecturer, c.ID)
+	return err
+}
+
+// DeleteCourse deletes a course and its reserves from the database
+func (cr *courseRepository) DeleteCourse(id int) error {
+	tx, err := cr.db.Begin()
+	if err != nil {
+		return err
+	}
+	for _, query := range []string{
+		"DELETE FROM course_reserves WHERE course_id = $1",
+		"DELETE FROM courses WHERE id = $1",
+	} {
+		if _, err := tx.Exec(query, id); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetReservesByCourseID retrieves the reserves of a course, including expired ones
+func (cr *courseRepository) GetReservesByCourseID(courseID int) ([]models.CourseReserve, error) {
+	const query = `
+        SELECT r.id, r.course_id, r.book_id, r.copy_id, r.loan_rule, r.created_at, t.start_date, t.end_date, b.title, b.author
+        FROM course_reserves r
+        JOIN courses c ON c.id = r.course_id
+        JOIN terms t ON t.id = c.term_id
+        JOIN books b ON b.id = r.book_id
+        WHERE r.course_id = $1 AND b.deleted_at IS NULL
+        ORDER BY b.title, r.id
+    `
+
+	return cr.queryReserves(query, courseID)
+}
+
+// GetReserveByID retrieves a course reserve by ID from the database
+func (cr *courseRepository) GetReserveByID(id int) (*models.CourseReserve, error) {
+	const query = `
+        SELECT r.id, r.course_id, r.book_id, r.copy_id, r.loan_rule, r.created_at, t.start_date, t.end_date, b.title, b.author
+        FROM course_reserves r
+        JOIN courses c ON c.id = r.course_id
+        JOIN terms t ON t.id = c.term_id
+        JOIN books b ON b.id = r.book_id
+        WHERE r.id = $1
+    `
+
+	reserves, err := cr.queryReserves(query, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(reserves) == 0 {
+		return nil, errors.New("course reserve not found")
+	}
+	return &reserves[0], nil
+}
+
+// GetActiveReservesByBookID retrieves the reserves of a book whose term is running at now
+func (cr *courseRepository) GetActiveReservesByBookID(bookID int, now time.Time) ([]models.CourseReserve, error) {
+	const query = `
+        SELECT r.id, r.course_id, r.book_id, r.copy_id, r.loan_rule, r.created_at, t.start_date, t.end_date, b.title, b.author
+        FROM course_reserves r
+        JOIN courses c ON c.id = r.course_id
+        JOIN terms t ON t.id = c.term_id
+        JOIN books b ON b.id = r.book_id
+        WHERE r.book_id = $1 AND t.start_date <= $2 AND t.end_date + INTERVAL '1 day' > $2
+    `
+
+	return cr.queryReserves(query, bookID, now)
+}
+
+// CreateReserve creates a new course reserve in the database
+func (cr *courseRepository) CreateReserve(r *models.CourseReserve) error {
+	const query = `
+        INSERT INTO course_reserves (course_id, book_id, copy_id, loan_rule, created_at)
+        VALUES ($1, $2, $3, $4, NOW())
+        RETURNING id, created_at
+    `
+
+	return cr.db.QueryRow(query, r.CourseID, r.BookID, r.CopyID, r.LoanRule).Scan(&r.ID, &r.CreatedAt)
+}
+
+// DeleteReserve deletes a course reserve from the database
+func (cr *courseRepository) DeleteReserve(id int) error {
+	const query = "DELETE FROM course_reserves WHERE id = $1"
+
+	_, err := cr.db.Exec(query, id)
+	return err
+}
+
+// queryReserves runs a reserve query and derives ExpiresAt and Active from the term dates
+func (cr *courseRepository) queryReserves(query string, args ...interface{}) ([]models.CourseReserve, error) {
+	rows, err := cr.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	var reserves []models.CourseReserve
+	for rows.Next() {
+		var r models.CourseReserve
+		var term models.Term
+		if err := rows.Scan(&r.ID, &r.CourseID, &r.BookID, &r.CopyID, &r.LoanRule, &r.CreatedAt, &term.StartDate, &term.EndDate, &r.Title, &r.Author); err != nil {
+			return nil, err
+		}
+		r.ExpiresAt = term.EndDate.AddDate(0, 0, 1)
+		r.Active = term.Contains(now)
+		reserves = append(reserves, r)
+	}
+
+	return reserves, rows.Err()
+}
@@ -0,0 +1,326 @@
+package repositories
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/utils"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ErrErasureNotPending dikembalikan jika permintaan penghapusan sudah ditinjau
+var ErrErasureNotPending = errors.New("erasure request has already been reviewed")
+
+// DataSubjectRepository provides methods for personal data erasure requests and the erasure itself
+type DataSubjectRepository interface {
+	CreateErasureRequest(req *models.ErasureRequest) error
+	GetErasureRequests(status string) ([]models.ErasureRequest, error)
+	GetErasureRequestsByMemberID(memberID int) ([]models.ErasureRequest, error)
+	GetErasureRequestByID(id int) (*models.ErasureRequest, error)
+	RejectErasureRequest(req *models.ErasureRequest) error
+	EraseMember(req *models.ErasureRequest, before, anonymized *models.Member) error
+}
+
+// NewDataSubjectRepository creates a new DataSubjectRepository instance
+func NewDataSubjectRepository(db *sql.DB) *dataSubjectRepository {
+	return &dataSubjectRepository{db: db}
+}
+
+type dataSubjectRepository struct {
+	db *sql.DB
+}
+
+// CreateErasureRequest inserts a new pending erasure request
+func (dr *dataSubjectRepository) CreateErasureRequest(req *models.ErasureRequest) error {
+	const query = `
+        INSERT INTO erasure_requests (member_id, reason, status, requested_at)
+        VALUES ($1, $2, 'pending', $3)
+        RETURNING id, status
+    `
+	return dr.db.QueryRow(query, req.MemberID, req.Reason, req.RequestedAt).Scan(&req.ID, &req.Status)
+}
+
+// GetErasureRequests retrieves erasure requests, oldest first; an empty status returns all of them
+func (dr *dataSubjectRepository) GetErasureRequests(status string) ([]models.ErasureRequest, error) {
+	const query = `
+        SELECT id, member_id, COALESCE(reason, ''), status, requested_at, COALESCE(reviewed_by, ''), reviewed_at,
+               COALESCE(review_note, '')
+        FROM erasure_requests
+        WHERE $1 = '' OR status = $1
+        ORDER BY requested_at, id
+    `
+	return dr.queryRequests(query, status)
+}
+
+// GetErasureRequestsByMemberID retrieves the erasure requests of a member, most recent first
+func (dr *dataSubjectRepository) GetErasureRequestsByMemberID(memberID int) ([]models.ErasureRequest, error) {
+	const query = `
+        SELECT id, member_id, COALESCE(reason, ''), status, requested_at, COALESCE(reviewed_by, ''), reviewed_at,
+               COALESCE(review_note, '')
+        FROM erasure_requests
+        WHERE member_id = $1
+        ORDER BY requested_at DESC, id DESC
+    `
+	return dr.queryRequests(query, memberID)
+}
+
+// GetErasureRequestByID retrieves an erasure request by its ID
+func (dr *dataSubjectRepository) GetErasureRequestByID(id int) (*models.ErasureRequest, error) {
+	const query = `
+        SELECT id, member_id, COALESCE(reason, ''), status, requested_at, COALESCE(reviewed_by, ''), reviewed_at,
+               COALESCE(review_note, '')
+        FROM erasure_requests
+        WHERE id = $1
+    `
+
+	var req models.ErasureRequest
+	err := dr.db.QueryRow(query, id).Scan(&req.ID, &req.MemberID, &req.Reason, &req.Status, &req.RequestedAt,
+		&req.ReviewedBy, &req.ReviewedAt, &req.ReviewNote)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("erasure request not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// RejectErasureRequest stores the rejection of a pending request
+func (dr *dataSubjectRepository) RejectErasureRequest(req *models.ErasureRequest) error {
+	const query = `
+        UPDATE erasure_requests
+        SET status = 'rejected', reviewed_by = $1, reviewed_at = $2, review_note = $3
+        WHERE id = $4 AND status = 'pending'
+    `
+
+	result, err := dr.db.Exec(query, req.ReviewedBy, req.ReviewedAt, req.ReviewNote, req.ID)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrErasureNotPending
+	}
+	return nil
+}
+
+// EraseMember approves an erasure request and anonymizes the member in one transaction: personal fields are
+// overwritten, personal content (holds, notifications, reading lists, cards, ...) is deleted, loan history and
+// suggestions are detached, review texts are cleared while ratings stay for statistics, and personal values in
+// the member's change history are masked. Fines, the fine ledger and membership renewals are retained as
+// financial records. The erasure itself is recorded in the change history, without the old personal values.
+func (dr *dataSubjectRepository) EraseMember(req *models.ErasureRequest, before, anonymized *models.Member) error {
+	const requestQuery = `
+        UPDATE erasure_requests
+        SET status = 'approved', reviewed_by = $1, reviewed_at = $2, review_note = $3
+        WHERE id = $4 AND status = 'pending'
+    `
+	const memberQuery = `
+        UPDATE members
+        SET name = $1, email = $2, password = NULL, phone_number = NULL, address = NULL, username = NULL,
+            gender = NULL, birth_date = NULL, guardian_consent_at = NULL, student_id = NULL, membership_status = $3,
+            reading_history_enabled = FALSE, erased_at = $4
+        WHERE id = $5 AND erased_at IS NULL
+    `
+
+	audit, err := erasureVersion(before, anonymized, req.ReviewedBy, *req.ReviewedAt)
+	if err != nil {
+		return err
+	}
+
+	tx, err := dr.db.Begin()
+	if err != nil {
+		return err
+	}
+	result, err := tx.Exec(requestQuery, req.ReviewedBy, req.ReviewedAt, req.ReviewNote, req.ID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		tx.Rollback()
+		return ErrErasureNotPending
+	}
+	result, err = tx.Exec(memberQuery, anonymized.Name, anonymized.Email, anonymized.MembershipStatus, anonymized.ErasedAt, anonymized.ID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		tx.Rollback()
+		return errors.New("member not found or already erased")
+	}
+
+	for _, query := range []string{
+		"DELETE FROM holds WHERE member_id = $1",
+		"DELETE FROM notifications WHERE user_id = $1",
+		"UPDATE reviews SET comment = '' WHERE user_id = $1",
+		"DELETE FROM reading_list_items WHERE list_id IN (SELECT id FROM reading_lists WHERE member_id = $1)",
+		"DELETE FROM reading_lists WHERE member_id = $1",
+		"UPDATE loan_history SET member_id = NULL WHERE member_id = $1",
+		"UPDATE digital_loans SET member_id = NULL WHERE member_id = $1",
+		"DELETE FROM suggestion_votes WHERE member_id = $1",
+		"DELETE FROM serial_routing WHERE member_id = $1",
+		"UPDATE purchase_suggestions SET requester_id = NULL WHERE requester_id = $1",
+		"DELETE FROM library_cards WHERE member_id = $1",
+		"DELETE FROM email_changes WHERE member_id = $1",
+		"DELETE FROM password_setups WHERE member_id = $1",
+		"DELETE FROM member_blocks WHERE member_id = $1",
+		"UPDATE members SET guardian_consent_at = NULL WHERE id IN (SELECT member_id FROM household_members WHERE household_id IN (SELECT id FROM households WHERE primary_member_id = $1))",
+		"DELETE FROM household_members WHERE member_id = $1 OR household_id IN (SELECT id FROM households WHERE primary_member_id = $1)",
+		"DELETE FROM households WHERE primary_member_id = $1",
+	} {
+		if _, err := tx.Exec(query, anonymized.ID); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := maskMemberHistory(tx, anonymized.ID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := insertVersion(tx.QueryRow, audit); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// erasureVersion builds the change history entry of an erasure; old personal values are replaced by
+// models.ErasedValue so the audit trail shows what was erased but not the data itself
+func erasureVersion(before, after *models.Member, erasedBy string, at time.Time) (*models.RecordVersion, error) {
+	beforeSnapshot, err := utils.Snapshot(before, memberSnapshotOmit...)
+	if err != nil {
+		return nil, err
+	}
+	afterSnapshot, err := utils.Snapshot(after, memberSnapshotOmit...)
+	if err != nil {
+		return nil, err
+	}
+
+	personal := personalFieldSet()
+	changes := utils.DiffSnapshots(beforeSnapshot, afterSnapshot)
+	for i := range changes {
+		if personal[changes[i].Field] && changes[i].OldValue != nil {
+			changes[i].OldValue = models.ErasedValue
+		}
+	}
+
+	return &models.RecordVersion{
+		EntityType: models.HistoryEntityMember,
+		EntityID:   before.ID,
+		Action:     models.HistoryActionErase,
+		ChangedBy:  erasedBy,
+		ChangedAt:  at,
+		Changes:    changes,
+		Snapshot:   afterSnapshot,
+	}, nil
+}
+
+// maskMemberHistory replaces the personal values in every stored version of a member with models.ErasedValue,
+// so the change history keeps which fields changed and when, but no longer who the member was
+func maskMemberHistory(tx *sql.Tx, memberID int) error {
+	const selectQuery = `
+        SELECT id, changes, snapshot
+        FROM record_versions
+        WHERE entity_type = $1 AND entity_id = $2
+    `
+
+	type storedVersion struct {
+		id       int
+		changes  []utils.FieldChange
+		snapshot map[string]interface{}
+	}
+
+	rows, err := tx.Query(selectQuery, models.HistoryEntityMember, memberID)
+	if err != nil {
+		return err
+	}
+	var versions []storedVersion
+	for rows.Next() {
+		var v storedVersion
+		var changes, snapshot []byte
+		if err := rows.Scan(&v.id, &changes, &snapshot); err != nil {
+			rows.Close()
+			return err
+		}
+		if err := json.Unmarshal(changes, &v.changes); err != nil {
+			rows.Close()
+			return err
+		}
+		if len(snapshot) > 0 {
+			if err := json.Unmarshal(snapshot, &v.snapshot); err != nil {
+				rows.Close()
+				return err
+			}
+		}
+		versions = append(versions, v)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	personal := personalFieldSet()
+	for _, v := range versions {
+		for i := range v.changes {
+			if !personal[v.changes[i].Field] {
+				continue
+			}
+			if v.changes[i].OldValue != nil {
+				v.changes[i].OldValue = models.ErasedValue
+			}
+			if v.changes[i].NewValue != nil {
+				v.changes[i].NewValue = models.ErasedValue
+			}
+		}
+		for field, value := range v.snapshot {
+			if personal[field] && value != nil {
+				v.snapshot[field] = models.ErasedValue
+			}
+		}
+
+		changes, err := json.Marshal(v.changes)
+		if err != nil {
+			return err
+		}
+		snapshot, err := json.Marshal(v.snapshot)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec("UPDATE record_versions SET changes = $1, snapshot = $2 WHERE id = $3", changes, snapshot, v.id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queryRequests runs a query returning erasure request rows
+func (dr *dataSubjectRepository) queryRequests(query string, arg interface{}) ([]models.ErasureRequest, error) {
+	rows, err := dr.db.Query(query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requests []models.ErasureRequest
+	for rows.Next() {
+		var req models.ErasureRequest
+		if err := rows.Scan(&req.ID, &req.MemberID, &req.Reason, &req.Status, &req.RequestedAt,
+			&req.ReviewedBy, &req.ReviewedAt, &req.ReviewNote); err != nil {
+			return nil, err
+		}
+		requests = append(requests, req)
+	}
+	return requests, rows.Err()
+}
+
+// personalFieldSet returns models.MemberPersonalFields as a set
+func personalFieldSet() map[string]bool {
+	personal := make(map[string]bool, len(models.MemberPersonalFields))
+	for _, field := range models.MemberPersonalFields {
+		personal[field] = true
+	}
+	return personal
+}
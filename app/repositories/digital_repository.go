@@ -0,0 +1,220 @@
+package repositories
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"database/sql"
+	"errors"
+	"time"
+
+	_ "github.com/lib/pq" // Import driver PostgreSQL
+)
+
+// ErrNoLicenseAvailable dikembalikan CreateLoan jika semua lisensi sumber digital sedang dipinjam
+var ErrNoLicenseAvailable = errors.New("no license available for this digital item")
+
+// DigitalRepository provides methods for interacting with digital items and digital loans in the database
+type DigitalRepository interface {
+	GetItemsByBookID(bookID int, now time.Time) ([]models.DigitalItem, error)
+	GetItemByID(id int, now time.Time) (*models.DigitalItem, error)
+	CreateItem(i *models.DigitalItem) error
+	DeleteItem(id int) error
+	GetLoanByID(id int) (*models.DigitalLoan, error)
+	GetActiveLoansByMemberID(memberID int, now time.Time) ([]models.DigitalLoan, error)
+	CountActiveLoansByMemberID(memberID int, now time.Time) (int, error)
+	CountActiveLoansByItemID(itemID int, now time.Time) (int, error)
+	CreateLoan(l *models.DigitalLoan) error
+}
+
+// NewDigitalRepository creates a new DigitalRepository instance
+func NewDigitalRepository(db *sql.DB) *digitalRepository {
+	return &digitalRepository{db: db}
+}
+
+type digitalRepository struct {
+	db *sql.DB
+}
+
+// GetItemsByBookID retrieves the digital items of a book with the number of licenses still available at now
+func (dr *digitalRepository) GetItemsByBookID(bookID int, now time.Time) ([]models.DigitalItem, error) {
+	const query = `
+        SELECT i.id, i.book_id, i.type, i.format, i.content_type, i.size, i.file_key, i.license_count, i.created_at,
+               i.license_count - (SELECT COUNT(*) FROM digital_loans l WHERE l.item_id = i.id AND l.expires_at > $2)
+        FROM digital_items i
+        WHERE i.book_id = $1
+        ORDER BY i.id
+    `
+
+	rows, err := dr.db.Query(query, bookID, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []models.DigitalItem
+	for rows.Next() {
+		var i models.DigitalItem
+		if err := rows.Scan(&i.ID, &i.BookID, &i.Type, &i.Format, &i.ContentType, &i.Size, &i.FileKey, &i.LicenseCount, &i.CreatedAt, &i.Available); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
+
+// GetItemByID retrieves a digital item by ID with the number of licenses still available at now
+func (dr *digitalRepository) GetItemByID(id int, now time.Time) (*models.DigitalItem, error) {
+	const query = `
+        SELECT i.id, i.book_id, i.type, i.format, i.content_type, i.size, i.file_key, i.license_count, i.created_at,
+               i.license_count - (SELECT COUNT(*) FROM digital_loans l WHERE l.item_id = i.id AND l.expires_at > $2)
+        FROM digital_items i
+        WHERE i.id = $1
+    `
+
+	var i models.DigitalItem
+	err := dr.db.QueryRow(query, id, now).Scan(&i.ID, &i.BookID, &i.Type, &i.Format, &i.ContentType, &i.Size, &i.FileKey, &i.LicenseCount, &i.CreatedAt, &i.Available)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("digital item not found")
+		}
+		return nil, err
+	}
+
+	return &i, nil
+}
+
+// CreateItem creates a new digital item in the database
+func (dr *digitalRepository) CreateItem(i *models.DigitalItem) error {
+	const query = `
+        INSERT INTO digital_items (book_id, type, format, content_type, size, file_key, license_count, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+        RETURNING id, created_at
+    `
+
+	return dr.db.QueryRow(query, i.BookID, i.Type, i.Format, i.ContentType, i.Size, i.FileKey, i.LicenseCount).Scan(&i.ID, &i.CreatedAt)
+}
+
+// DeleteItem deletes a digital item and its (expired) loans from the database
+func (dr *digitalRepository) DeleteItem(id int) error {
+	tx, err := dr.db.Begin()
+	if err != nil {
+		return err
+	}
+	for _, query := range []string{
+		"DELETE FROM digital_loans WHERE item_id = $1",
+		"DELETE FROM digital_items WHERE id = $1",
+	} {
+		if _, err := tx.Exec(query, id); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetLoanByID retrieves a digital loan by ID from the database
+func (dr *digitalRepository) GetLoanByID(id int) (*models.DigitalLoan, error) {
+	const query = `
+        SELECT l.id, l.item_id, i.book_id, COALESCE(l.member_id, 0), l.borrowed_at, l.expires_at
+        FROM digital_loans l
+        JOIN digital_items i ON i.id = l.item_id
+        WHERE l.id = $1
+    `
+
+	var l models.DigitalLoan
+	err := dr.db.QueryRow(query, id).Scan(&l.ID, &l.ItemID, &l.BookID, &l.MemberID, &l.BorrowedAt, &l.ExpiresAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("digital loan not found")
+		}
+		return nil, err
+	}
+
+	return &l, nil
+}
+
+// GetActiveLoansByMemberID retrieves a member's digital loans that have not expired at now
+func (dr *digitalRepository) GetActiveLoansByMemberID(memberID int, now time.Time) ([]models.DigitalLoan, error) {
+	const query = `
+        SELECT l.id, l.item_id, i.book_id, l.member_id, l.borrowed_at, l.expires_at
+        FROM digital_loans l
+        JOIN digital_items i ON i.id = l.item_id
+        WHERE l.member_id = $1 AND l.expires_at > $2
+        ORDER BY l.expires_at
+    `
+
+	rows, err := dr.db.Query(query, memberID, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var loans []models.DigitalLoan
+	for rows.Next() {
+		var l models.DigitalLoan
+		if err := rows.Scan(&l.ID, &l.ItemID, &l.BookID, &l.MemberID, &l.BorrowedAt, &l.ExpiresAt); err != nil {
+			return nil, err
+		}
+		loans = append(loans, l)
+	}
+
+	return loans, rows.Err()
+}
+
+// CountActiveLoansByMemberID counts a member's digital loans that have not expired at now
+func (dr *digitalRepository) CountActiveLoansByMemberID(memberID int, now time.Time) (int, error) {
+	const query = "SELECT COUNT(*) FROM digital_loans WHERE member_id = $1 AND expires_at > $2"
+
+	var count int
+	err := dr.db.QueryRow(query, memberID, now).Scan(&count)
+	return count, err
+}
+
+// CountActiveLoansByItemID counts the loans of a digital item that have not expired at now
+func (dr *digitalRepository) CountActiveLoansByItemID(itemID int, now time.Time) (int, error) {
+	const query = "SELECT COUNT(*) FROM digital_loans WHERE item_id = $1 AND expires_at > $2"
+
+	var count int
+	err := dr.db.QueryRow(query, itemID, now).Scan(&count)
+	return count, err
+}
+
+// CreateLoan creates a digital loan if the item still has a free license.
+// The item row is locked so concurrent borrowers cannot exceed the license count.
+func (dr *digitalRepository) CreateLoan(l *models.DigitalLoan) error {
+	const lockQuery = "SELECT license_count FROM digital_items WHERE id = $1 FOR UPDATE"
+	const countQuery = "SELECT COUNT(*) FROM digital_loans WHERE item_id = $1 AND expires_at > $2"
+	const insertQuery = `
+        INSERT INTO digital_loans (item_id, member_id, borrowed_at, expires_at)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id
+    `
+
+	tx, err := dr.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	var licenseCount, active int
+	if err := tx.QueryRow(lockQuery, l.ItemID).Scan(&licenseCount); err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return errors.New("digital item not found")
+		}
+		return err
+	}
+	if err := tx.QueryRow(countQuery, l.ItemID, l.BorrowedAt).Scan(&active); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if active >= licenseCount {
+		tx.Rollback()
+		return ErrNoLicenseAvailable
+	}
+
+	if err := tx.QueryRow(insertQuery, l.ItemID, l.MemberID, l.BorrowedAt, l.ExpiresAt).Scan(&l.ID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,228 @@
+package repositories
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"database/sql"
+	"errors"
+)
+
+// DuplicateRepository provides methods for finding and merging duplicate member accounts
+type DuplicateRepository interface {
+	GetMatchableMembers() ([]models.Member, error)
+	GetDismissals() ([]models.DuplicateDismissal, error)
+	DismissDuplicate(d *models.DuplicateDismissal) error
+	MergeMembers(merge *models.MemberMerge) error
+	GetMerges(memberID int) ([]models.MemberMerge, error)
+}
+
+// NewDuplicateRepository creates a new DuplicateRepository instance
+func NewDuplicateRepository(db *sql.DB) *duplicateRepository {
+	return &duplicateRepository{db: db}
+}
+
+type duplicateRepository struct {
+	db *sql.DB
+}
+
+// mergedTables lists the rows that a merge moves from the duplicate to the surviving account, keyed by table
+var mergedTables = []struct {
+	table string
+	query string
+}{
+	{"loans", "UPDATE loans SET member_id = $1 WHERE member_id = $2"},
+	{"loan_history", "UPDATE loan_history SET member_id = $1 WHERE member_id = $2"},
+	{"digital_loans", "UPDATE digital_loans SET member_id = $1 WHERE member_id = $2"},
+	{"holds", "UPDATE holds SET member_id = $1 WHERE member_id = $2"},
+	{"reviews", "UPDATE reviews SET user_id = $1 WHERE user_id = $2"},
+	{"notifications", "UPDATE notifications SET user_id = $1 WHERE user_id = $2"},
+	{"fine_ledger", "UPDATE fine_ledger SET member_id = $1 WHERE member_id = $2"},
+	{"membership_renewals", "UPDATE membership_renewals SET member_id = $1 WHERE member_id = $2"},
+	{"reading_lists", "UPDATE reading_lists SET member_id = $1 WHERE member_id = $2"},
+	{"suggestion_votes", "UPDATE suggestion_votes SET member_id = $1 WHERE member_id = $2"},
+	{"purchase_suggestions", "UPDATE purchase_suggestions SET requester_id = $1 WHERE requester_id = $2"},
+	{"serial_routing", "UPDATE serial_routing SET member_id = $1 WHERE member_id = $2"},
+	{"households", "UPDATE households SET primary_member_id = $1 WHERE primary_member_id = $2"},
+	{"household_members", "UPDATE household_members SET member_id = $1 WHERE member_id = $2"},
+	{"member_blocks", "UPDATE member_blocks SET member_id = $1 WHERE member_id = $2"},
+}
+
+// GetMatchableMembers retrieves the active, non-erased members with the fields used to find duplicates
+func (dr *duplicateRepository) GetMatchableMembers() ([]models.Member, error) {
+	const query = `
+        SELECT id, name, email, COALESCE(phone_number, ''), registration_date, membership_type, membership_status,
+               birth_date
+        FROM members
+        WHERE deleted_at IS NULL AND erased_at IS NULL
+        ORDER BY id
+    `
+
+	rows, err := dr.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []models.Member
+	for rows.Next() {
+		var m models.Member
+		if err := rows.Scan(&m.ID, &m.Name, &m.Email, &m.PhoneNumber, &m.RegistrationDate, &m.MembershipType,
+			&m.MembershipStatus, &m.BirthDate); err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+	return members, rows.Err()
+}
+
+// GetDismissals retrieves the pairs a librarian marked as different people
+func (dr *duplicateRepository) GetDismissals() ([]models.DuplicateDismissal, error) {
+	const query = `
+        SELECT member_id, other_member_id, dismissed_by, dismissed_at
+        FROM member_duplicate_dismissals
+    `
+
+	rows, err := dr.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var dismissals []models.DuplicateDismissal
+	for rows.Next() {
+		var d models.DuplicateDismissal
+		if err := rows.Scan(&d.MemberID, &d.OtherMemberID, &d.DismissedBy, &d.DismissedAt); err != nil {
+			return nil, err
+		}
+		dismissals = append(dismissals, d)
+	}
+	return dismissals, rows.Err()
+}
+
+// DismissDuplicate stores a pair as different people; the lower member ID is stored first so each pair is
+// kept once, and dismissing a pair again changes nothing
+func (dr *duplicateRepository) DismissDuplicate(d *models.DuplicateDismissal) error {
+	const query = `
+        INSERT INTO member_duplicate_dismissals (member_id, other_member_id, dismissed_by, dismissed_at)
+        VALUES (LEAST($1::int, $2::int), GREATEST($1::int, $2::int), $3, $4)
+        ON CONFLICT (member_id, other_member_id) DO NOTHING
+    `
+	_, err := dr.db.Exec(query, d.MemberID, d.OtherMemberID, d.DismissedBy, d.DismissedAt)
+	return err
+}
+
+// MergeMembers moves everything of the duplicate account to the survivor in one transaction: loans, loan history,
+// holds, reviews, notifications, the fine ledger and outstanding fines, renewals, reading lists, suggestions,
+// routing slips and household links. Holds, votes and routing slips the survivor already has for the same title
+// are dropped from the duplicate first, its active library cards are voided and the emptied duplicate is moved to
+// the trash, so a failed merge never leaves the duplicate half moved or outside the trash.
+func (dr *duplicateRepository) MergeMembers(merge *models.MemberMerge) error {
+	const survivorQuery = `
+        UPDATE members
+        SET fine_amount = fine_amount + (SELECT COALESCE(fine_amount, 0) FROM members WHERE id = $2)
+        WHERE id = $1 AND deleted_at IS NULL
+    `
+	const insertQuery = `
+        INSERT INTO member_merges (survivor_id, merged_id, merged_by, merged_at)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id
+    `
+
+	tx, err := dr.db.Begin()
+	if err != nil {
+		return err
+	}
+	result, err := tx.Exec(survivorQuery, merge.SurvivorID, merge.MergedID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		tx.Rollback()
+		return errors.New("member not found")
+	}
+	result, err = tx.Exec("UPDATE members SET fine_amount = 0 WHERE id = $1 AND deleted_at IS NULL", merge.MergedID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		tx.Rollback()
+		return errors.New("duplicate member not found")
+	}
+
+	for _, query := range []string{
+		`UPDATE holds SET status = 'cancelled'
+         WHERE member_id = $2 AND status IN ('waiting', 'ready')
+           AND EXISTS (SELECT 1 FROM holds s WHERE s.member_id = $1 AND s.status IN ('waiting', 'ready')
+                       AND s.book_id IS NOT DISTINCT FROM holds.book_id AND s.work_id IS NOT DISTINCT FROM holds.work_id)`,
+		"DELETE FROM suggestion_votes WHERE member_id = $2 AND suggestion_id IN (SELECT suggestion_id FROM suggestion_votes WHERE member_id = $1)",
+		"DELETE FROM serial_routing WHERE member_id = $2 AND serial_id IN (SELECT serial_id FROM serial_routing WHERE member_id = $1)",
+		`UPDATE member_blocks SET lifted_at = NOW(), lifted_by = 'system', lift_note = 'merged'
+         WHERE member_id = $2 AND automatic AND lifted_at IS NULL
+           AND EXISTS (SELECT 1 FROM member_blocks s WHERE s.member_id = $1 AND s.type = member_blocks.type
+                       AND s.automatic AND s.lifted_at IS NULL)`,
+	} {
+		if _, err := tx.Exec(query, merge.SurvivorID, merge.MergedID); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if _, err := tx.Exec("UPDATE library_cards SET status = 'replaced', replaced_at = $1, replacement_reason = 'merged' WHERE member_id = $2 AND status = 'active'",
+		merge.MergedAt, merge.MergedID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM email_changes WHERE member_id = $1", merge.MergedID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	merge.Moved = make(map[string]int64, len(mergedTables))
+	for _, moved := range mergedTables {
+		result, err := tx.Exec(moved.query, merge.SurvivorID, merge.MergedID)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		if affected, err := result.RowsAffected(); err == nil && affected > 0 {
+			merge.Moved[moved.table] = affected
+		}
+	}
+
+	if _, err := tx.Exec("UPDATE members SET deleted_at = $1, deleted_by = $2 WHERE id = $3 AND deleted_at IS NULL",
+		merge.MergedAt, merge.MergedBy, merge.MergedID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.QueryRow(insertQuery, merge.SurvivorID, merge.MergedID, merge.MergedBy, merge.MergedAt).Scan(&merge.ID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetMerges retrieves the merges in which a member was the survivor or the duplicate, most recent first
+func (dr *duplicateRepository) GetMerges(memberID int) ([]models.MemberMerge, error) {
+	const query = `
+        SELECT id, survivor_id, merged_id, merged_by, merged_at
+        FROM member_merges
+        WHERE survivor_id = $1 OR merged_id = $1
+        ORDER BY merged_at DESC, id DESC
+    `
+
+	rows, err := dr.db.Query(query, memberID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var merges []models.MemberMerge
+	for rows.Next() {
+		var m models.MemberMerge
+		if err := rows.Scan(&m.ID, &m.SurvivorID, &m.MergedID, &m.MergedBy, &m.MergedAt); err != nil {
+			return nil, err
+		}
+		merges = append(merges, m)
+	}
+	return merges, rows.Err()
+}
@@ -0,0 +1,273 @@
+package repositories
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrFileNotFound dikembalikan FileStorage jika objek tidak ada
+var ErrFileNotFound = errors.New("file not found")
+
+// FileStorage provides methods for storing binary files such as cover images
+type FileStorage interface {
+	Put(key string, data []byte, contentType string) error
+	Get(key string) (io.ReadCloser, error)
+	Delete(key string) error
+}
+
+// localFileStorage stores files in a directory on the local filesystem
+type localFileStorage struct {
+	baseDir string
+}
+
+// NewLocalFileStorage creates a new FileStorage backed by the local filesystem
+func NewLocalFileStorage(baseDir string) FileStorage {
+	return &localFileStorage{baseDir: baseDir}
+}
+
+// path mengubah key menjadi path file di dalam baseDir dan menolak path traversal
+func (ls *localFileStorage) path(key string) (string, error) {
+	cleaned := filepath.Clean("/" + key)
+	if cleaned == "/" {
+		return "", errors.New("invalid storage key")
+	}
+	return filepath.Join(ls.baseDir, cleaned), nil
+}
+
+// Put menyimpan file; penulisan dilakukan ke file sementara lalu di-rename agar atomik
+func (ls *localFileStorage) Put(key string, data []byte, contentType string) error {
+	path, err := ls.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Get membuka file untuk dibaca
+func (ls *localFileStorage) Get(key string) (io.ReadCloser, error) {
+	path, err := ls.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrFileNotFound
+	}
+	return f, err
+}
+
+// Delete menghapus file; file yang sudah tidak ada tidak dianggap error
+func (ls *localFileStorage) Delete(key string) error {
+	path, err := ls.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// S3Config holds the connection settings for an S3-compatible object store
+type S3Config struct {
+	Endpoint  string // e.g. "https://s3.amazonaws.com", "http://localhost:9000"
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+}
+
+// s3FileStorage stores files in an S3-compatible bucket using path-style URLs and AWS Signature V4
+type s3FileStorage struct {
+	cfg    S3Config
+	client *http.Client
+}
+
+// NewS3FileStorage creates a new FileStorage backed by an S3-compatible object store
+func NewS3FileStorage(cfg S3Config) FileStorage {
+	return &s3FileStorage{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Put mengunggah objek ke bucket
+func (ss *s3FileStorage) Put(key string, data []byte, contentType string) error {
+	req, err := ss.newRequest(http.MethodPut, key, data)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	ss.sign(req, data, time.Now())
+
+	resp, err := ss.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return s3Error(resp)
+	}
+	return nil
+}
+
+// Get mengunduh objek dari bucket; pemanggil wajib menutup ReadCloser
+func (ss *s3FileStorage) Get(key string) (io.ReadCloser, error) {
+	req, err := ss.newRequest(http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	ss.sign(req, nil, time.Now())
+
+	resp, err := ss.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, ErrFileNotFound
+	default:
+		defer resp.Body.Close()
+		return nil, s3Error(resp)
+	}
+}
+
+// Delete menghapus objek dari bucket
+func (ss *s3FileStorage) Delete(key string) error {
+	req, err := ss.newRequest(http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	ss.sign(req, nil, time.Now())
+
+	resp, err := ss.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return s3Error(resp)
+	}
+	return nil
+}
+
+// newRequest membuat request path-style ke {endpoint}/{bucket}/{key}
+func (ss *s3FileStorage) newRequest(method, key string, body []byte) (*http.Request, error) {
+	endpoint, err := url.Parse(strings.TrimRight(ss.cfg.Endpoint, "/"))
+	if err != nil {
+		return nil, err
+	}
+	endpoint.Path = "/" + ss.cfg.Bucket + "/" + strings.TrimLeft(key, "/")
+	endpoint.RawPath = s3EscapePath(endpoint.Path)
+
+	return http.NewRequest(method, endpoint.String(), bytes.NewReader(body))
+}
+
+// sign menambahkan header Authorization AWS Signature Version 4
+func (ss *s3FileStorage) sign(req *http.Request, body []byte, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	if req.Header.Get("Content-Type") != "" {
+		signedHeaders = []string{"content-type", "host", "x-amz-content-sha256", "x-amz-date"}
+	}
+	var canonicalHeaders strings.Builder
+	for _, header := range signedHeaders {
+		value := req.Header.Get(header)
+		if header == "host" {
+			value = req.URL.Host
+		}
+		canonicalHeaders.WriteString(header + ":" + strings.TrimSpace(value) + "\n")
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		strings.Join(signedHeaders, ";"),
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + ss.cfg.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+ss.cfg.SecretKey), date)
+	key = hmacSHA256(key, ss.cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		ss.cfg.AccessKey, scope, strings.Join(signedHeaders, ";"), signature))
+}
+
+// s3EscapePath meng-encode path sesuai aturan URI-encode S3: hanya karakter unreserved dan "/" yang dibiarkan
+func s3EscapePath(path string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// s3Error membuat error dari respons S3 yang gagal
+func s3Error(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("object storage request failed with status code %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
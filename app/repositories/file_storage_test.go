@@ -0,0 +1,205 @@
+package repositories
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	testS3Region    = "ap-southeast-3"
+	testS3Bucket    = "perpustakaan"
+	testS3AccessKey = "AKIDEXAMPLE"
+	testS3SecretKey = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+)
+
+// s3Stub meniru bucket S3 di memori dan, seperti S3, menolak request yang tanda tangan SigV4-nya tidak cocok
+type s3Stub struct {
+	t       *testing.T
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func newS3Stub(t *testing.T) *s3Stub {
+	return &s3Stub{t: t, objects: map[string][]byte{}, types: map[string]string{}}
+}
+
+func (s *s3Stub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if msg := s.verifySignature(r, body); msg != "" {
+		s.t.Logf("%s %s: %s", r.Method, r.URL.Path, msg)
+		http.Error(w, "<Error><Code>SignatureDoesNotMatch</Code></Error>", http.StatusForbidden)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		s.objects[r.URL.Path] = body
+		s.types[r.URL.Path] = r.Header.Get("Content-Type")
+	case http.MethodGet:
+		data, ok := s.objects[r.URL.Path]
+		if !ok {
+			http.Error(w, "<Error><Code>NoSuchKey</Code></Error>", http.StatusNotFound)
+			return
+		}
+		w.Write(data)
+	case http.MethodDelete:
+		delete(s.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// verifySignature menyusun ulang canonical request dari request yang diterima server dan membandingkan tanda tangannya
+func (s *s3Stub) verifySignature(r *http.Request, body []byte) string {
+	auth := strings.TrimPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ")
+	fields := map[string]string{}
+	for _, part := range strings.Split(auth, ", ") {
+		if name, value, ok := strings.Cut(part, "="); ok {
+			fields[name] = value
+		}
+	}
+
+	amzDate := r.Header.Get("X-Amz-Date")
+	date, err := time.Parse("20060102T150405Z", amzDate)
+	if err != nil {
+		return "invalid X-Amz-Date " + amzDate
+	}
+	scope := date.Format("20060102") + "/" + testS3Region + "/s3/aws4_request"
+	if fields["Credential"] != testS3AccessKey+"/"+scope {
+		return "unexpected credential " + fields["Credential"]
+	}
+	if payloadHash := sha256Hex(body); r.Header.Get("X-Amz-Content-Sha256") != payloadHash {
+		return "payload hash does not match the body"
+	}
+
+	signedHeaders := strings.Split(fields["SignedHeaders"], ";")
+	var canonicalHeaders strings.Builder
+	for _, header := range signedHeaders {
+		value := r.Header.Get(header)
+		if header == "host" {
+			value = r.Host
+		}
+		canonicalHeaders.WriteString(header + ":" + value + "\n")
+	}
+	canonicalRequest := strings.Join([]string{r.Method, r.URL.EscapedPath(), r.URL.RawQuery, canonicalHeaders.String(),
+		fields["SignedHeaders"], r.Header.Get("X-Amz-Content-Sha256")}, "\n")
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+testS3SecretKey), date.Format("20060102"))
+	for _, part := range []string{testS3Region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	want := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	if !hmac.Equal([]byte(fields["Signature"]), []byte(want)) {
+		return "signature mismatch"
+	}
+	return ""
+}
+
+func newTestS3Storage(endpoint, secretKey string) FileStorage {
+	return NewS3FileStorage(S3Config{Endpoint: endpoint, Region: testS3Region, Bucket: testS3Bucket, AccessKey: testS3AccessKey, SecretKey: secretKey})
+}
+
+func TestS3FileStorageAgainstStub(t *testing.T) {
+	stub := newS3Stub(t)
+	server := httptest.NewServer(stub)
+	defer server.Close()
+	storage := newTestS3Storage(server.URL+"/", testS3SecretKey)
+
+	// Spasi dan "+" harus di-encode sama oleh klien dan server agar tanda tangannya cocok
+	key := "covers/laskar pelangi+1.jpg"
+	data := []byte("sampul Laskar Pelangi")
+	if err := storage.Put(key, data, "image/jpeg"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	objectPath := "/" + testS3Bucket + "/" + key
+	if !bytes.Equal(stub.objects[objectPath], data) || stub.types[objectPath] != "image/jpeg" {
+		t.Fatalf("stored object = %q (%s), want %q (image/jpeg)", stub.objects[objectPath], stub.types[objectPath], data)
+	}
+
+	f, err := storage.Get(key)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	got, err := io.ReadAll(f)
+	f.Close()
+	if err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("Get() = %q, %v; want %q", got, err, data)
+	}
+
+	if err := storage.Delete(key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := storage.Get(key); !errors.Is(err, ErrFileNotFound) {
+		t.Errorf("Get() after Delete: err = %v, want ErrFileNotFound", err)
+	}
+}
+
+func TestS3FileStorageReportsRejectedSignatures(t *testing.T) {
+	server := httptest.NewServer(newS3Stub(t))
+	defer server.Close()
+	storage := newTestS3Storage(server.URL, "wrong-secret")
+
+	err := storage.Put("covers/a.jpg", []byte("sampul"), "image/jpeg")
+	if err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Put() err = %v, want a 403 error", err)
+	}
+}
+
+func TestS3Sign(t *testing.T) {
+	ss := newTestS3Storage("http://127.0.0.1:9000", testS3SecretKey).(*s3FileStorage)
+	body := []byte("sampul")
+	req, err := ss.newRequest(http.MethodPut, "covers/laskar pelangi+1.jpg", body)
+	if err != nil {
+		t.Fatalf("newRequest: %v", err)
+	}
+	req.Header.Set("Content-Type", "image/jpeg")
+	ss.sign(req, body, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	if got := req.URL.EscapedPath(); got != "/perpustakaan/covers/laskar%20pelangi%2B1.jpg" {
+		t.Errorf("path = %q", got)
+	}
+	// Nilai yang diharapkan dihitung terpisah dengan implementasi SigV4 lain
+	want := map[string]string{
+		"X-Amz-Date":           "20260102T030405Z",
+		"X-Amz-Content-Sha256": "64f7a4e0022b2839fe8b9f005c926cde97d67648228aaf5ccb53f8265864e411",
+		"Authorization": "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20260102/ap-southeast-3/s3/aws4_request, " +
+			"SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date, " +
+			"Signature=7a20c94bcca3768c233db7a6c519843f9bb156bda17eb4be5d9ae8289e4e5050",
+	}
+	for header, value := range want {
+		if got := req.Header.Get(header); got != value {
+			t.Errorf("%s = %q, want %q", header, got, value)
+		}
+	}
+}
+
+func TestS3EscapePath(t *testing.T) {
+	tests := map[string]string{
+		"/perpustakaan/covers/ab12.jpg":     "/perpustakaan/covers/ab12.jpg",
+		"/perpustakaan/a b+c=d&e.pdf":       "/perpustakaan/a%20b%2Bc%3Dd%26e.pdf",
+		"/perpustakaan/~user_1-2.txt":       "/perpustakaan/~user_1-2.txt",
+		"/perpustakaan/buku/Tetralogi Buru": "/perpustakaan/buku/Tetralogi%20Buru",
+		"/perpustakaan/é":                   "/perpustakaan/%C3%A9",
+	}
+	for path, want := range tests {
+		if got := s3EscapePath(path); got != want {
+			t.Errorf("s3EscapePath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
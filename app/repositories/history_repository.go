@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	_ "github.com/lib/pq" // Import driver PostgreSQL
+)
+
+// HistoryRepository provides methods for storing and reading the version history of records
+type HistoryRepository interface {
+	CreateVersion(v *models.RecordVersion) error
+	GetVersions(entityType string, entityID int) ([]models.RecordVersion, error)
+	GetVersion(entityType string, entityID, version int) (*models.RecordVersion, error)
+	GetLatestVersion(entityType string, entityID int) (*models.RecordVersion, error)
+}
+
+// NewHistoryRepository creates a new HistoryRepository instance
+func NewHistoryRepository(db *sql.DB) *historyRepository {
+	return &historyRepository{db: db}
+}
+
+type historyRepository struct {
+	db *sql.DB
+}
+
+// CreateVersion stores a new version; the version number is the next one for the record
+func (hr *historyRepository) CreateVersion(v *models.RecordVersion) error {
+	return insertVersion(hr.db.QueryRow, v)
+}
+
+// insertVersion stores a new version through queryRow, which is either db.QueryRow or tx.QueryRow
+func insertVersion(queryRow func(query string, args ...interface{}) *sql.Row, v *models.RecordVersion) error {
+	const query = `
+        INSERT INTO record_versions (entity_type, entity_id, version, action, changed_by, changed_at, changes, snapshot)
+        SELECT $1, $2, COALESCE(MAX(version), 0) + 1, $3, $4, $5, $6, $7
+        FROM record_versions
+        WHERE entity_type = $1 AND entity_id = $2
+        RETURNING id, version
+    `
+
+	changes, err := json.Marshal(v.Changes)
+	if err != nil {
+		return err
+	}
+	snapshot, err := json.Marshal(v.Snapshot)
+	if err != nil {
+		return err
+	}
+
+	return queryRow(query, v.EntityType, v.EntityID, v.Action, v.ChangedBy, v.ChangedAt, changes, snapshot).Scan(&v.ID, &v.Version)
+}
+
+// GetVersions retrieves the history of a record, newest first; snapshots are left out to keep the list small
+func (hr *historyRepository) GetVersions(entityType string, entityID int) ([]models.RecordVersion, error) {
+	const query = `
+        SELECT id, entity_type, entity_id, version, action, changed_by, changed_at, changes
+        FROM record_versions
+        WHERE entity_type = $1 AND entity_id = $2
+        ORDER BY version DESC
+    `
+
+	rows, err := hr.db.Query(query, entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []models.RecordVersion
+	for rows.Next() {
+		var v models.RecordVersion
+		var changes []byte
+		if err := rows.Scan(&v.ID, &v.EntityType, &v.EntityID, &v.Version, &v.Action, &v.ChangedBy, &v.ChangedAt, &changes); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(changes, &v.Changes); err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+
+	return versions, rows.Err()
+}
+
+// GetVersion retrieves a single version of a record including its snapshot
+func (hr *historyRepository) GetVersion(entityType string, entityID, version int) (*models.RecordVersion, error) {
+	const query = `
+        SELECT id, entity_type, entity_id, version, action, changed_by, changed_at, changes, snapshot
+        FROM record_versions
+        WHERE entity_type = $1 AND entity_id = $2 AND version = $3
+    `
+
+	v, err := hr.scanVersion(hr.db.QueryRow(query, entityType, entityID, version))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("version not found")
+	}
+	return v, err
+}
+
+// GetLatestVersion retrieves the newest version of a record, or nil if the record has no history yet
+func (hr *historyRepository) GetLatestVersion(entityType string, entityID int) (*models.RecordVersion, error) {
+	const query = `
+        SELECT id, entity_type, entity_id, version, action, changed_by, changed_at, changes, snapshot
+        FROM record_versions
+        WHERE entity_type = $1 AND entity_id = $2
+        ORDER BY version DESC
+        LIMIT 1
+    `
+
+	v, err := hr.scanVersion(hr.db.QueryRow(query, entityType, entityID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return v, err
+}
+
+// scanVersion reads a full version row
+func (hr *historyRepository) scanVersion(row *sql.Row) (*models.RecordVersion, error) {
+	var v models.RecordVersion
+	var changes, snapshot []byte
+	if err := row.Scan(&v.ID, &v.EntityType, &v.EntityID, &v.Version, &v.Action, &v.ChangedBy, &v.ChangedAt, &changes, &snapshot); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(changes, &v.Changes); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(snapshot, &v.Snapshot); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
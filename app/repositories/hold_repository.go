@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"database/sql"
+	"errors"
+
+	"github.com/lib/pq" // Import driver PostgreSQL
+)
+
+// HoldRepository provides methods for interacting with hold (reservation) data in the database
+type HoldRepository interface {
+	GetHoldByID(id int) (*models.Hold, error)
+	GetHoldsByMemberID(memberID int) ([]models.Hold, error)
+	GetActiveHolds(bookIDs []int, workID *int) ([]models.Hold, error)
+	CreateHold(h *models.Hold) error
+	UpdateHoldStatus(id int, status string) error
+}
+
+// NewHoldRepository creates a new HoldRepository instance
+func NewHoldRepository(db *sql.DB) *holdRepository {
+	return &holdRepository{db: db}
+}
+
+type holdRepository struct {
+	db *sql.DB
+}
+
+// GetHoldByID retrieves a hold by ID from the database
+func (hr *holdRepository) GetHoldByID(id int) (*models.Hold, error) {
+	const query = `
+        SELECT id, member_id, book_id, work_id, status, created_at
+        FROM holds
+        WHERE id = $1
+    `
+
+	var h models.Hold
+	err := hr.db.QueryRow(query, id).Scan(&h.ID, &h.MemberID, &h.BookID, &h.WorkID, &h.Status, &h.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("hold not found")
+		}
+		return nil, err
+	}
+
+	return &h, nil
+}
+
+// GetHoldsByMemberID retrieves all holds placed by a member, newest first
+func (hr *holdRepository) GetHoldsByMemberID(memberID int) ([]models.Hold, error) {
+	const query = `
+        SELECT id, member_id, book_id, work_id, status, created_at
+        FROM holds
+        WHERE member_id = $1
+        ORDER BY created_at DESC, id DESC
+    `
+
+	return hr.queryHolds(query, memberID)
+}
+
+// GetActiveHolds retrieves the waiting and ready holds on any of the given books or on the given work,
+// in queue order (oldest first)
+func (hr *holdRepository) GetActiveHolds(bookIDs []int, workID *int) ([]models.Hold, error) {
+	const query = `
+        SELECT id, member_id, book_id, work_id, status, created_at
+        FROM holds
+        WHERE status IN ('waiting', 'ready')
+          AND (book_id = ANY($1) OR work_id = $2)
+        ORDER BY created_at, id
+    `
+
+	return hr.queryHolds(query, pq.Array(bookIDs), workID)
+}
+
+// CreateHold creates a new hold in the database
+func (hr *holdRepository) CreateHold(h *models.Hold) error {
+	const query = `
+        INSERT INTO holds (member_id, book_id, work_id, status, created_at)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING id
+    `
+
+	return hr.db.QueryRow(query, h.MemberID, h.BookID, h.WorkID, h.Status, h.CreatedAt).Scan(&h.ID)
+}
+
+// UpdateHoldStatus updates the status of a hold
+func (hr *holdRepository) UpdateHoldStatus(id int, status string) error {
+	const query = "UPDATE holds SET status = $1 WHERE id = $2"
+
+	_, err := hr.db.Exec(query, status, id)
+	return err
+}
+
+// queryHolds runs a query returning hold rows
+func (hr *holdRepository) queryHolds(query string, args ...interface{}) ([]models.Hold, error) {
+	rows, err := hr.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var holds []models.Hold
+	for rows.Next() {
+		var h models.Hold
+		if err := rows.Scan(&h.ID, &h.MemberID, &h.BookID, &h.WorkID, &h.Status, &h.CreatedAt); err != nil {
+			return nil, err
+		}
+		holds = append(holds, h)
+	}
+
+	return holds, rows.Err()
+}
@@ -0,0 +1,267 @@
+package repositories
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// HouseholdRepository provides methods for interacting with households and their members in the database
+type HouseholdRepository interface {
+	GetHouseholds() ([]models.Household, error)
+	GetHouseholdByID(id int) (*models.Household, error)
+	GetHouseholdByMemberID(memberID int) (*models.Household, error)
+	CreateHousehold(h *models.Household) error
+	DeleteHousehold(id int) error
+	AddMember(householdID int, hm *models.HouseholdMember) error
+	UpdateMemberPermissions(householdID int, hm *models.HouseholdMember) error
+	RemoveMember(householdID, memberID int) error
+	SetConsent(memberID int, consentAt *time.Time, changedBy string) error
+	GetHouseholdFines(householdID int) ([]models.MemberFine, error)
+}
+
+// NewHouseholdRepository creates a new HouseholdRepository instance
+func NewHouseholdRepository(db *sql.DB) *householdRepository {
+	return &householdRepository{db: db}
+}
+
+type householdRepository struct {
+	db *sql.DB
+}
+
+// GetHouseholds retrieves all households without their members
+func (hr *householdRepository) GetHouseholds() ([]models.Household, error) {
+	const query = `
+        SELECT id, name, primary_member_id, created_at
+        FROM households
+        ORDER BY name, id
+    `
+
+	rows, err := hr.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var households []models.Household
+	for rows.Next() {
+		var h models.Household
+		if err := rows.Scan(&h.ID, &h.Name, &h.PrimaryMemberID, &h.CreatedAt); err != nil {
+			return nil, err
+		}
+		households = append(households, h)
+	}
+	return households, rows.Err()
+}
+
+// GetHouseholdByID retrieves a household with its primary account and dependents, primary account first
+func (hr *householdRepository) GetHouseholdByID(id int) (*models.Household, error) {
+	const query = `
+        SELECT id, name, primary_member_id, created_at
+        FROM households
+        WHERE id = $1
+    `
+
+	var h models.Household
+	err := hr.db.QueryRow(query, id).Scan(&h.ID, &h.Name, &h.PrimaryMemberID, &h.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("household not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := hr.getMembers(id)
+	if err != nil {
+		return nil, err
+	}
+	h.Members = members
+	return &h, nil
+}
+
+// GetHouseholdByMemberID retrieves the household a member belongs to, either as primary account or as dependent
+func (hr *householdRepository) GetHouseholdByMemberID(memberID int) (*models.Household, error) {
+	const query = `
+        SELECT id FROM households WHERE primary_member_id = $1
+        UNION
+        SELECT household_id FROM household_members WHERE member_id = $1
+    `
+
+	var id int
+	err := hr.db.QueryRow(query, memberID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("household not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return hr.GetHouseholdByID(id)
+}
+
+// CreateHousehold inserts a new household
+func (hr *householdRepository) CreateHousehold(h *models.Household) error {
+	const query = `
+        INSERT INTO households (name, primary_member_id, created_at)
+        VALUES ($1, $2, $3)
+        RETURNING id
+    `
+	return hr.db.QueryRow(query, h.Name, h.PrimaryMemberID, h.CreatedAt).Scan(&h.ID)
+}
+
+// DeleteHousehold deletes a household and unlinks its dependents; guardian consent given through the household is
+// withdrawn in the same transaction
+func (hr *householdRepository) DeleteHousehold(id int) error {
+	const consentQuery = `
+        UPDATE members SET guardian_consent_at = NULL
+        WHERE id IN (SELECT member_id FROM household_members WHERE household_id = $1)
+    `
+
+	tx, err := hr.db.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(consentQuery, id); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM household_members WHERE household_id = $1", id); err != nil {
+		tx.Rollback()
+		return err
+	}
+	result, err := tx.Exec("DELETE FROM households WHERE id = $1", id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		tx.Rollback()
+		return errors.New("household not found")
+	}
+	return tx.Commit()
+}
+
+// AddMember links a dependent to a household
+func (hr *householdRepository) AddMember(householdID int, hm *models.HouseholdMember) error {
+	const query = `
+        INSERT INTO household_members (household_id, member_id, guardian_can_hold, guardian_can_renew, joined_at)
+        VALUES ($1, $2, $3, $4, $5)
+    `
+	_, err := hr.db.Exec(query, householdID, hm.MemberID, hm.GuardianCanHold, hm.GuardianCanRenew, hm.JoinedAt)
+	return err
+}
+
+// UpdateMemberPermissions updates what the primary account may do on behalf of a dependent
+func (hr *householdRepository) UpdateMemberPermissions(householdID int, hm *models.HouseholdMember) error {
+	const query = `
+        UPDATE household_members
+        SET guardian_can_hold = $1, guardian_can_renew = $2
+        WHERE household_id = $3 AND member_id = $4
+    `
+
+	result, err := hr.db.Exec(query, hm.GuardianCanHold, hm.GuardianCanRenew, householdID, hm.MemberID)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return errors.New("member is not a dependent of this household")
+	}
+	return nil
+}
+
+// RemoveMember unlinks a dependent from a household and withdraws the guardian consent given through it
+func (hr *householdRepository) RemoveMember(householdID, memberID int) error {
+	tx, err := hr.db.Begin()
+	if err != nil {
+		return err
+	}
+	result, err := tx.Exec("DELETE FROM household_members WHERE household_id = $1 AND member_id = $2", householdID, memberID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		tx.Rollback()
+		return errors.New("member is not a dependent of this household")
+	}
+	if _, err := tx.Exec("UPDATE members SET guardian_consent_at = NULL WHERE id = $1", memberID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// SetConsent stores or, with a nil time, withdraws the guardian consent of a member; changedBy is the guardian
+// recorded in the member's change history
+func (hr *householdRepository) SetConsent(memberID int, consentAt *time.Time, changedBy string) error {
+	result, err := hr.db.Exec("UPDATE members SET guardian_consent_at = $1 WHERE id = $2 AND deleted_at IS NULL", consentAt, memberID)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return errors.New("member not found")
+	}
+	return nil
+}
+
+// GetHouseholdFines retrieves the outstanding fines of every member of a household
+func (hr *householdRepository) GetHouseholdFines(householdID int) ([]models.MemberFine, error) {
+	const query = `
+        SELECT m.id, m.name, COALESCE(m.fine_amount, 0)
+        FROM members m
+        WHERE m.id = (SELECT primary_member_id FROM households WHERE id = $1)
+           OR m.id IN (SELECT member_id FROM household_members WHERE household_id = $1)
+        ORDER BY m.name, m.id
+    `
+
+	rows, err := hr.db.Query(query, householdID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var fines []models.MemberFine
+	for rows.Next() {
+		var f models.MemberFine
+		if err := rows.Scan(&f.MemberID, &f.Name, &f.FineAmount); err != nil {
+			return nil, err
+		}
+		fines = append(fines, f)
+	}
+	return fines, rows.Err()
+}
+
+// getMembers retrieves the primary account and the dependents of a household
+func (hr *householdRepository) getMembers(householdID int) ([]models.HouseholdMember, error) {
+	const query = `
+        SELECT m.id, m.name, m.email, TRUE AS is_primary, m.birth_date, m.guardian_consent_at,
+               FALSE, FALSE, h.created_at
+        FROM households h
+        JOIN members m ON m.id = h.primary_member_id
+        WHERE h.id = $1
+        UNION ALL
+        SELECT m.id, m.name, m.email, FALSE AS is_primary, m.birth_date, m.guardian_consent_at,
+               hm.guardian_can_hold, hm.guardian_can_renew, hm.joined_at
+        FROM household_members hm
+        JOIN members m ON m.id = hm.member_id
+        WHERE hm.household_id = $1
+        ORDER BY is_primary DESC, name
+    `
+
+	rows, err := hr.db.Query(query, householdID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []models.HouseholdMember
+	for rows.Next() {
+		var hm models.HouseholdMember
+		if err := rows.Scan(&hm.MemberID, &hm.Name, &hm.Email, &hm.Primary, &hm.BirthDate, &hm.GuardianConsentAt,
+			&hm.GuardianCanHold, &hm.GuardianCanRenew, &hm.JoinedAt); err != nil {
+			return nil, err
+		}
+		members = append(members, hm)
+	}
+	return members, rows.Err()
+}
@@ -9,6 +9,15 @@ import (
 	_ "github.com/lib/pq" // Import driver PostgreSQL
 )
 
+// ErrCopyNotAvailable dikembalikan CreateLoan jika eksemplar yang dipinjam sedang dipinjam, hilang atau ditarik
+var ErrCopyNotAvailable = errors.New("copy is not available for loan")
+
+// releaseCopyQuery mengembalikan eksemplar peminjaman ke rak; eksemplar yang ditandai hilang atau ditarik tidak diubah
+const releaseCopyQuery = `
+	UPDATE book_copies SET status = 'available'
+	WHERE status = 'on_loan' AND id = (SELECT copy_id FROM loans WHERE id = $1)
+`
+
 // LoanRepository provides methods for interacting with loan data in the database.
 type LoanRepository struct {
 	db *sql.DB
@@ -22,7 +31,7 @@ func NewLoanRepository(db *sql.DB) *LoanRepository {
 // GetAllLoans mengambil semua peminjaman dari database
 func (lr *LoanRepository) GetAllLoans() ([]models.Loan, error) {
 	query := `
-		SELECT id, member_id, book_id, borrow_date, due_date, return_date
+		SELECT id, member_id, book_id, copy_id, borrow_date, due_date, return_date, renewal_count
 		FROM loans
 	`
 
@@ -35,7 +44,7 @@ func (lr *LoanRepository) GetAllLoans() ([]models.Loan, error) {
 	var loans []models.Loan
 	for rows.Next() {
 		var l models.Loan
-		err := rows.Scan(&l.ID, &l.MemberID, &l.BookID, &l.BorrowDate, &l.DueDate, &l.ReturnDate)
+		err := rows.Scan(&l.ID, &l.MemberID, &l.BookID, &l.CopyID, &l.BorrowDate, &l.DueDate, &l.ReturnDate, &l.RenewalCount)
 		if err != nil {
 			return nil, err
 		}
@@ -48,13 +57,13 @@ func (lr *LoanRepository) GetAllLoans() ([]models.Loan, error) {
 // GetLoanByID mengambil peminjaman berdasarkan ID dari database
 func (lr *LoanRepository) GetLoanByID(id int) (*models.Loan, error) {
 	query := `
-		SELECT id, member_id, book_id, borrow_date, due_date, return_date
+		SELECT id, member_id, book_id, copy_id, borrow_date, due_date, return_date, renewal_count
 		FROM loans
 		WHERE id = $1
 	`
 
 	var l models.Loan
-	err := lr.db.QueryRow(query, id).Scan(&l.ID, &l.MemberID, &l.BookID, &l.BorrowDate, &l.DueDate, &l.ReturnDate)
+	err := lr.db.QueryRow(query, id).Scan(&l.ID, &l.MemberID, &l.BookID, &l.CopyID, &l.BorrowDate, &l.DueDate, &l.ReturnDate, &l.RenewalCount)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, errors.New("loan not found")
@@ -65,23 +74,38 @@ func (lr *LoanRepository) GetLoanByID(id int) (*models.Loan, error) {
 	return &l, nil
 }
 
-// CreateLoan membuat peminjaman baru di database
+// CreateLoan membuat peminjaman baru di database. Eksemplar yang dipinjam ditandai on_loan dalam transaksi yang sama;
+// ErrCopyNotAvailable dikembalikan jika eksemplar tersebut tidak sedang tersedia.
 func (lr *LoanRepository) CreateLoan(l *models.Loan) error {
 	query := `
-		INSERT INTO loans (member_id, book_id, borrow_date, due_date) 
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO loans (member_id, book_id, copy_id, borrow_date, due_date)
+		VALUES ($1, $2, $3, $4, $5)
 		RETURNING id
 	`
 
-	err := lr.db.QueryRow(query, l.MemberID, l.BookID, time.Now(), l.DueDate).Scan(&l.ID)
+	tx, err := lr.db.Begin()
 	if err != nil {
 		return err
 	}
-
-	return nil
+	if l.CopyID != nil {
+		result, err := tx.Exec("UPDATE book_copies SET status = 'on_loan' WHERE id = $1 AND status = 'available'", *l.CopyID)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+			tx.Rollback()
+			return ErrCopyNotAvailable
+		}
+	}
+	if err := tx.QueryRow(query, l.MemberID, l.BookID, l.CopyID, time.Now(), l.DueDate).Scan(&l.ID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
 }
 
-// UpdateLoan memperbarui peminjaman di database
+// UpdateLoan memperbarui peminjaman di database; eksemplar peminjaman yang dikembalikan kembali tersedia
 func (lr *LoanRepository) UpdateLoan(l *models.Loan) error {
 	query := `
 		UPDATE loans
@@ -89,22 +113,76 @@ func (lr *LoanRepository) UpdateLoan(l *models.Loan) error {
 		WHERE id = $6
 	`
 
-	_, err := lr.db.Exec(query, l.MemberID, l.BookID, l.BorrowDate, l.DueDate, l.ReturnDate, l.ID)
-	return err
+	tx, err := lr.db.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(query, l.MemberID, l.BookID, l.BorrowDate, l.DueDate, l.ReturnDate, l.ID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if l.ReturnDate != nil {
+		if _, err := tx.Exec(releaseCopyQuery, l.ID); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
 }
 
-// DeleteLoan menghapus peminjaman dari database
+// DeleteLoan menghapus peminjaman dari database; eksemplar yang masih dipinjam kembali tersedia
 func (lr *LoanRepository) DeleteLoan(id int) error {
-	query := "DELETE FROM loans WHERE id = $1"
-	_, err := lr.db.Exec(query, id)
-	return err
+	tx, err := lr.db.Begin()
+	if err != nil {
+		return err
+	}
+	for _, query := range []string{releaseCopyQuery, "DELETE FROM loans WHERE id = $1"} {
+		if _, err := tx.Exec(query, id); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
 }
 
+// GetLoansByMemberID mengambil peminjaman anggota yang belum diarsipkan, jatuh tempo terdekat lebih dulu
 func (lr *LoanRepository) GetLoansByMemberID(id int) ([]models.Loan, error) {
-	// Implementasi metode GetLoansByMemberID
+	query := `
+		SELECT id, member_id, book_id, copy_id, borrow_date, due_date, return_date, renewal_count
+		FROM loans
+		WHERE member_id = $1
+		ORDER BY due_date, id
+	`
 
-	return nil, nil
+	rows, err := lr.db.Query(query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var loans []models.Loan
+	for rows.Next() {
+		var l models.Loan
+		if err := rows.Scan(&l.ID, &l.MemberID, &l.BookID, &l.CopyID, &l.BorrowDate, &l.DueDate, &l.ReturnDate, &l.RenewalCount); err != nil {
+			return nil, err
+		}
+		loans = append(loans, l)
+	}
+	return loans, rows.Err()
+}
+
+// RenewLoan memperpanjang jatuh tempo peminjaman yang belum dikembalikan dan menambah hitungan perpanjangannya
+func (lr *LoanRepository) RenewLoan(id int, dueDate time.Time) error {
+	query := "UPDATE loans SET due_date = $1, renewal_count = renewal_count + 1 WHERE id = $2 AND return_date IS NULL"
 
+	result, err := lr.db.Exec(query, dueDate, id)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return errors.New("loan not found or already returned")
+	}
+	return nil
 }
 
 func (lr *LoanRepository) GetLoansByBookID(id int) ([]models.Loan, error) {
@@ -127,4 +205,53 @@ func (lr *LoanRepository) GetOverdueLoans() (interface{}, interface{}) {
 
 }
 
+// CountActiveLoansByBookID menghitung peminjaman buku yang belum dikembalikan
+func (lr *LoanRepository) CountActiveLoansByBookID(bookID int) (int, error) {
+	query := "SELECT COUNT(*) FROM loans WHERE book_id = $1 AND return_date IS NULL"
+
+	var count int
+	err := lr.db.QueryRow(query, bookID).Scan(&count)
+	return count, err
+}
+
+// CountActiveLoansByMemberID menghitung peminjaman anggota yang belum dikembalikan
+func (lr *LoanRepository) CountActiveLoansByMemberID(memberID int) (int, error) {
+	query := "SELECT COUNT(*) FROM loans WHERE member_id = $1 AND return_date IS NULL"
+
+	var count int
+	err := lr.db.QueryRow(query, memberID).Scan(&count)
+	return count, err
+}
+
+// ArchiveLoan memindahkan peminjaman yang sudah dikembalikan ke loan_history. Untuk anggota yang
+// menonaktifkan riwayat baca, member_id tidak ikut disimpan sehingga hanya statistik buku yang tersisa.
+func (lr *LoanRepository) ArchiveLoan(id int) error {
+	insertQuery := `
+		INSERT INTO loan_history (member_id, book_id, borrow_date, due_date, return_date)
+		SELECT CASE WHEN m.reading_history_enabled THEN l.member_id END, l.book_id, l.borrow_date, l.due_date, l.return_date
+		FROM loans l
+		JOIN members m ON m.id = l.member_id
+		WHERE l.id = $1 AND l.return_date IS NOT NULL
+	`
+
+	tx, err := lr.db.Begin()
+	if err != nil {
+		return err
+	}
+	result, err := tx.Exec(insertQuery, id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		tx.Rollback()
+		return errors.New("returned loan not found")
+	}
+	if _, err := tx.Exec("DELETE FROM loans WHERE id = $1", id); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
 // ... (fungsi lain yang mungkin Anda butuhkan, seperti GetLoansByMemberID, GetLoansByBookID, dll.)
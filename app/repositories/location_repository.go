@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"database/sql"
+	"errors"
+
+	_ "github.com/lib/pq" // Import driver PostgreSQL
+)
+
+// LocationRepository provides methods for interacting with shelf location data in the database
+type LocationRepository interface {
+	GetAllLocations() ([]models.Location, error)
+	GetLocationByID(id int) (*models.Location, error)
+	CreateLocation(l *models.Location) error
+	UpdateLocation(l *models.Location) error
+	DeleteLocation(id int) error
+}
+
+// NewLocationRepository creates a new LocationRepository instance
+func NewLocationRepository(db *sql.DB) *locationRepository {
+	return &locationRepository{db: db}
+}
+
+type locationRepository struct {
+	db *sql.DB
+}
+
+// GetAllLocations retrieves all locations from the database
+func (lr *locationRepository) GetAllLocations() ([]models.Location, error) {
+	const query = `
+        SELECT id, parent_id, type, name, code, call_number_start, call_number_end
+        FROM locations
+        ORDER BY id
+    `
+
+	rows, err := lr.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var locations []models.Location
+	for rows.Next() {
+		var l models.Location
+		if err := rows.Scan(&l.ID, &l.ParentID, &l.Type, &l.Name, &l.Code, &l.CallNumberStart, &l.CallNumberEnd); err != nil {
+			return nil, err
+		}
+		locations = append(locations, l)
+	}
+
+	return locations, rows.Err()
+}
+
+// GetLocationByID retrieves a location by ID from the database
+func (lr *locationRepository) GetLocationByID(id int) (*models.Location, error) {
+	const query = `
+        SELECT id, parent_id, type, name, code, call_number_start, call_number_end
+        FROM locations
+        WHERE id = $1
+    `
+
+	var l models.Location
+	err := lr.db.QueryRow(query, id).Scan(&l.ID, &l.ParentID, &l.Type, &l.Name, &l.Code, &l.CallNumberStart, &l.CallNumberEnd)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("location not found")
+		}
+		return nil, err
+	}
+
+	return &l, nil
+}
+
+// CreateLocation creates a new location in the database
+func (lr *locationRepository) CreateLocation(l *models.Location) error {
+	const query = `
+        INSERT INTO locations (parent_id, type, name, code, call_number_start, call_number_end)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING id
+    `
+
+	return lr.db.QueryRow(query, l.ParentID, l.Type, l.Name, l.Code, l.CallNumberStart, l.CallNumberEnd).Scan(&l.ID)
+}
+
+// UpdateLocation updates a location in the database
+func (lr *locationRepository) UpdateLocation(l *models.Location) error {
+	const query = `
+        UPDATE locations
+        SET parent_id = $1, type = $2, name = $3, code = $4, call_number_start = $5, call_number_end = $6
+        WHERE id = $7
+    `
+
+	_, err := lr.db.Exec(query, l.ParentID, l.Type, l.Name, l.Code, l.CallNumberStart, l.CallNumberEnd, l.ID)
+	return err
+}
+
+// DeleteLocation deletes a location from the database
+func (lr *locationRepository) DeleteLocation(id int) error {
+	const query = "DELETE FROM locations WHERE id = $1"
+
+	_, err := lr.db.Exec(query, id)
+	return err
+}
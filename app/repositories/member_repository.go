@@ -4,9 +4,11 @@ import (
 	"Restful-Perpustakaan-API/app/models"
 	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
-	_ "github.com/lib/pq" // PostgreSQL driver
+	"github.com/lib/pq" // PostgreSQL driver
 )
 
 // MemberRepository provides methods for interacting with member data in the database.
@@ -16,23 +18,59 @@ type MemberRepository interface {
 	GetMemberByEmail(email string) (*models.Member, error)
 	CreateMember(m *models.Member) error
 	UpdateMember(m *models.Member) error
-	DeleteMember(id int) error
+	RevertMember(m *models.Member) error
+	DeleteMember(id int, deletedBy string) error
+	GetOutstandingFines(id int) (float64, error)
+	GetDeletedMembers() ([]models.Member, error)
+	RestoreMember(id int, restoredBy string) error
+	PurgeDeletedMembers(before time.Time) (int, error)
+	GetMembersByStudentIDsOrEmails(studentIDs, emails []string) ([]models.Member, error)
+	GetRosterMembers(roster string) ([]models.Member, error)
+	ImportMembers(creates, updates []models.Member, deactivate []int, changedBy string) error
+	SearchMembers(search models.MemberSearch) ([]models.Member, int, error)
+	RecordLogin(id int, at time.Time) error
+	RecordActivity(id int, at time.Time) error
+	GetInactiveMembers(before time.Time) ([]models.Member, error)
 }
 
+// memberSortColumns memetakan field urutan pencarian anggota ke kolom tabel members
+var memberSortColumns = map[string]string{
+	"id":                "id",
+	"name":              "LOWER(name)",
+	"email":             "LOWER(email)",
+	"registration_date": "registration_date",
+	"membership_end":    "membership_end",
+	"fine_amount":       "COALESCE(fine_amount, 0)",
+	"last_activity_at":  "last_activity_at",
+}
+
+// memberSearchFilter is the WHERE clause shared by the member search and its count
+const memberSearchFilter = `
+        WHERE deleted_at IS NULL
+          AND ($1 = '' OR name ILIKE $2 OR email ILIKE $2 OR phone_number ILIKE $2 OR address ILIKE $2
+               OR username ILIKE $2 OR student_id ILIKE $2)
+          AND ($3 = '' OR LOWER(membership_type) = LOWER($3))
+          AND ($4 = '' OR membership_status = $4)
+          AND ($5 = '' OR LOWER(gender) = LOWER($5))
+          AND ($6::timestamptz IS NULL OR registration_date >= $6)
+          AND ($7::timestamptz IS NULL OR registration_date < $7)
+          AND ($8::boolean IS NULL OR (COALESCE(fine_amount, 0) > 0) = $8)
+    `
+
 type memberRepository struct {
 	db *sql.DB
 }
 
 func (mr *memberRepository) GetAllMembers() ([]models.Member, error) {
 	var members []models.Member
-	rows, err := mr.db.Query("SELECT * FROM members")
+	rows, err := mr.db.Query("SELECT id, name, email, membership_type, membership_status, membership_start, membership_end, birth_date, guardian_consent_at, erased_at, COALESCE(student_id, ''), COALESCE(roster, ''), last_login_at, last_activity_at, COALESCE(role, 'member') FROM members WHERE deleted_at IS NULL")
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 	for rows.Next() {
 		var member models.Member
-		err = rows.Scan(&member.ID, &member.Name, &member.Email)
+		err = rows.Scan(&member.ID, &member.Name, &member.Email, &member.MembershipType, &member.MembershipStatus, &member.MembershipStart, &member.MembershipEnd, &member.BirthDate, &member.GuardianConsentAt, &member.ErasedAt, &member.StudentID, &member.Roster, &member.LastLoginAt, &member.LastActivityAt, &member.Role)
 		if err != nil {
 			return nil, err
 		}
@@ -43,7 +81,7 @@ func (mr *memberRepository) GetAllMembers() ([]models.Member, error) {
 
 func (mr *memberRepository) GetMemberByID(id int) (*models.Member, error) {
 	var member models.Member
-	err := mr.db.QueryRow("SELECT * FROM members WHERE id = $1", id).Scan(&member.ID, &member.Name, &member.Email)
+	err := mr.db.QueryRow("SELECT id, name, email, COALESCE(phone_number, ''), COALESCE(address, ''), COALESCE(gender, ''), COALESCE(username, ''), registration_date, membership_type, membership_status, membership_start, membership_end, birth_date, guardian_consent_at, erased_at, COALESCE(student_id, ''), COALESCE(roster, ''), last_login_at, last_activity_at, COALESCE(role, 'member') FROM members WHERE id = $1 AND deleted_at IS NULL", id).Scan(&member.ID, &member.Name, &member.Email, &member.PhoneNumber, &member.Address, &member.Gender, &member.Username, &member.RegistrationDate, &member.MembershipType, &member.MembershipStatus, &member.MembershipStart, &member.MembershipEnd, &member.BirthDate, &member.GuardianConsentAt, &member.ErasedAt, &member.StudentID, &member.Roster, &member.LastLoginAt, &member.LastActivityAt, &member.Role)
 	if err != nil {
 		return nil, err
 	}
@@ -52,28 +90,362 @@ func (mr *memberRepository) GetMemberByID(id int) (*models.Member, error) {
 
 func (mr *memberRepository) GetMemberByEmail(email string) (*models.Member, error) {
 	var member models.Member
-	err := mr.db.QueryRow("SELECT * FROM members WHERE email = $1", email).Scan(&member.ID, &member.Name, &member.Email)
+	err := mr.db.QueryRow("SELECT id, name, email, COALESCE(password, ''), membership_type, membership_status, membership_start, membership_end, birth_date, guardian_consent_at, erased_at, COALESCE(student_id, ''), COALESCE(roster, ''), last_login_at, last_activity_at, COALESCE(role, 'member') FROM members WHERE email = $1 AND deleted_at IS NULL", email).Scan(&member.ID, &member.Name, &member.Email, &member.Password, &member.MembershipType, &member.MembershipStatus, &member.MembershipStart, &member.MembershipEnd, &member.BirthDate, &member.GuardianConsentAt, &member.ErasedAt, &member.StudentID, &member.Roster, &member.LastLoginAt, &member.LastActivityAt, &member.Role)
 	if err != nil {
 		return nil, err
 	}
 	return &member, nil
 }
 
+// CreateMember inserts a member; an empty membership status is stored as active and an empty password hash as
+// no password
 func (mr *memberRepository) CreateMember(m *models.Member) error {
-	_, err := mr.db.Exec("INSERT INTO members (name, email) VALUES ($1, $2)", m.Name, m.Email)
-	return err
+	const query = `
+        INSERT INTO members (name, email, password, membership_type, membership_status, membership_start, membership_end, birth_date)
+        VALUES ($1, $2, NULLIF($3, ''), $4, COALESCE(NULLIF($5, ''), 'active'), $6, $7, $8)
+        RETURNING id, membership_status
+    `
+	return mr.db.QueryRow(query, m.Name, m.Email, m.Password, m.MembershipType, m.MembershipStatus, m.MembershipStart, m.MembershipEnd, m.BirthDate).Scan(&m.ID, &m.MembershipStatus)
 }
 
 func (mr *memberRepository) UpdateMember(m *models.Member) error {
-	_, err := mr.db.Exec("UPDATE members SET name = $1, email = $2 WHERE id = $3", m.Name, m.Email, m.ID)
+	_, err := mr.db.Exec("UPDATE members SET name = $1, email = $2, membership_type = $3, birth_date = $4 WHERE id = $5", m.Name, m.Email, m.MembershipType, m.BirthDate, m.ID)
+	return err
+}
+
+// RevertMember writes every stored field of a previous version back. The password, role, fines and login
+// times are not part of a version and are left as they are.
+func (mr *memberRepository) RevertMember(m *models.Member) error {
+	const query = `
+        UPDATE members
+        SET name = $1, email = $2, phone_number = NULLIF($3, ''), address = NULLIF($4, ''), gender = NULLIF($5, ''),
+            username = NULLIF($6, ''), membership_type = $7, membership_status = $8, membership_start = $9,
+            membership_end = $10, birth_date = $11, guardian_consent_at = $12, student_id = NULLIF($13, ''),
+            roster = NULLIF($14, '')
+        WHERE id = $15 AND deleted_at IS NULL
+    `
+
+	result, err := mr.db.Exec(query, m.Name, m.Email, m.PhoneNumber, m.Address, m.Gender, m.Username, m.MembershipType,
+		m.MembershipStatus, m.MembershipStart, m.MembershipEnd, m.BirthDate, m.GuardianConsentAt, m.StudentID, m.Roster, m.ID)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return errors.New("member not found")
+	}
+	return nil
+}
+
+// DeleteMember moves a member to the trash; the row is kept so loans and reviews stay valid
+func (mr *memberRepository) DeleteMember(id int, deletedBy string) error {
+	result, err := mr.db.Exec("UPDATE members SET deleted_at = $1, deleted_by = $2 WHERE id = $3 AND deleted_at IS NULL", time.Now(), deletedBy, id)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return errors.New("member not found")
+	}
+	return nil
+}
+
+// GetOutstandingFines retrieves the unpaid fine amount of a member
+func (mr *memberRepository) GetOutstandingFines(id int) (float64, error) {
+	var amount float64
+	err := mr.db.QueryRow("SELECT COALESCE(fine_amount, 0) FROM members WHERE id = $1", id).Scan(&amount)
+	if err == sql.ErrNoRows {
+		return 0, errors.New("member not found")
+	}
+	return amount, err
+}
+
+// GetDeletedMembers retrieves the members in the trash, most recently deleted first
+func (mr *memberRepository) GetDeletedMembers() ([]models.Member, error) {
+	rows, err := mr.db.Query("SELECT id, name, email, deleted_at, deleted_by FROM members WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []models.Member
+	for rows.Next() {
+		var member models.Member
+		if err := rows.Scan(&member.ID, &member.Name, &member.Email, &member.DeletedAt, &member.DeletedBy); err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+	return members, rows.Err()
+}
+
+// ErrMemberNotInTrash dikembalikan RestoreMember jika anggota tidak ada di trash
+var ErrMemberNotInTrash = errors.New("member not found in trash")
+
+// RestoreMember moves a member out of the trash; restoredBy is only used by the change history
+func (mr *memberRepository) RestoreMember(id int, restoredBy string) error {
+	result, err := mr.db.Exec("UPDATE members SET deleted_at = NULL, deleted_by = '' WHERE id = $1 AND deleted_at IS NOT NULL", id)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrMemberNotInTrash
+	}
+	return nil
+}
+
+// PurgeDeletedMembers permanently deletes members that were moved to the trash before the given time,
+// together with their holds, notifications, reviews, reading lists, library cards, household links, pending email changes and password setup links, erasure requests and duplicate reviews and merges; their archived loans, purchase suggestions, membership renewals and fine ledger entries are anonymized so loan statistics, acquisition records and financial records remain.
+// Members still referenced by active loans are kept in the trash.
+func (mr *memberRepository) PurgeDeletedMembers(before time.Time) (int, error) {
+	const selectQuery = `
+        SELECT id FROM members m
+        WHERE m.deleted_at < $1
+          AND NOT EXISTS (SELECT 1 FROM loans l WHERE l.member_id = m.id)
+    `
+
+	tx, err := mr.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	ids, err := queryIDs(tx, selectQuery, before)
+	if err != nil || len(ids) == 0 {
+		tx.Rollback()
+		return 0, err
+	}
+	for _, query := range []string{
+		"DELETE FROM holds WHERE member_id = ANY($1)",
+		"DELETE FROM notifications WHERE user_id = ANY($1)",
+		"DELETE FROM reviews WHERE user_id = ANY($1)",
+		"DELETE FROM reading_list_items WHERE list_id IN (SELECT id FROM reading_lists WHERE member_id = ANY($1))",
+		"DELETE FROM reading_lists WHERE member_id = ANY($1)",
+		"UPDATE loan_history SET member_id = NULL WHERE member_id = ANY($1)",
+		"UPDATE digital_loans SET member_id = NULL WHERE member_id = ANY($1)",
+		"DELETE FROM suggestion_votes WHERE member_id = ANY($1)",
+		"DELETE FROM serial_routing WHERE member_id = ANY($1)",
+		"UPDATE purchase_suggestions SET requester_id = NULL WHERE requester_id = ANY($1)",
+		"UPDATE membership_renewals SET member_id = NULL WHERE member_id = ANY($1)",
+		"UPDATE fine_ledger SET member_id = NULL WHERE member_id = ANY($1)",
+		"DELETE FROM library_cards WHERE member_id = ANY($1)",
+		"DELETE FROM email_changes WHERE member_id = ANY($1)",
+		"DELETE FROM password_setups WHERE member_id = ANY($1)",
+		"DELETE FROM erasure_requests WHERE member_id = ANY($1)",
+		"DELETE FROM member_duplicate_dismissals WHERE member_id = ANY($1) OR other_member_id = ANY($1)",
+		"DELETE FROM member_merges WHERE survivor_id = ANY($1) OR merged_id = ANY($1)",
+		"DELETE FROM member_blocks WHERE member_id = ANY($1)",
+		"UPDATE members SET guardian_consent_at = NULL WHERE id IN (SELECT member_id FROM household_members WHERE household_id IN (SELECT id FROM households WHERE primary_member_id = ANY($1)))",
+		"DELETE FROM household_members WHERE member_id = ANY($1) OR household_id IN (SELECT id FROM households WHERE primary_member_id = ANY($1))",
+		"DELETE FROM households WHERE primary_member_id = ANY($1)",
+		"DELETE FROM members WHERE id = ANY($1)",
+	} {
+		if _, err := tx.Exec(query, pq.Array(ids)); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+
+	return len(ids), tx.Commit()
+}
+
+// SearchMembers retrieves one page of the members matching the search, with the total number of matches.
+// The query matches partially and case-insensitively; search.Sort must be one of models.MemberSortFields,
+// optionally prefixed with "-", and Page and PerPage must be positive.
+func (mr *memberRepository) SearchMembers(search models.MemberSearch) ([]models.Member, int, error) {
+	const selectQuery = `
+        SELECT id, name, email, COALESCE(phone_number, ''), COALESCE(address, ''), COALESCE(gender, ''),
+               COALESCE(username, ''), registration_date, membership_type, membership_status, membership_start,
+               membership_end, birth_date, COALESCE(fine_amount, 0), COALESCE(student_id, ''), COALESCE(roster, ''),
+               last_login_at, last_activity_at
+        FROM members
+    `
+
+	column, ok := memberSortColumns[strings.TrimPrefix(search.Sort, "-")]
+	if !ok {
+		return nil, 0, fmt.Errorf("unknown sort field %q", search.Sort)
+	}
+	order := column
+	if strings.HasPrefix(search.Sort, "-") {
+		order += " DESC NULLS LAST"
+	}
+
+	args := []interface{}{
+		search.Query, likePattern(search.Query), search.MembershipType, search.Status, search.Gender,
+		search.RegisteredAfter, search.RegisteredBefore, search.HasFines,
+	}
+
+	var total int
+	if err := mr.db.QueryRow("SELECT COUNT(*) FROM members"+memberSearchFilter, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := selectQuery + memberSearchFilter + fmt.Sprintf(" ORDER BY %s, id LIMIT $9 OFFSET $10", order)
+	rows, err := mr.db.Query(query, append(args, search.PerPage, (search.Page-1)*search.PerPage)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var members []models.Member
+	for rows.Next() {
+		var m models.Member
+		if err := rows.Scan(&m.ID, &m.Name, &m.Email, &m.PhoneNumber, &m.Address, &m.Gender, &m.Username,
+			&m.RegistrationDate, &m.MembershipType, &m.MembershipStatus, &m.MembershipStart, &m.MembershipEnd,
+			&m.BirthDate, &m.FineAmount, &m.StudentID, &m.Roster, &m.LastLoginAt, &m.LastActivityAt); err != nil {
+			return nil, 0, err
+		}
+		members = append(members, m)
+	}
+	return members, total, rows.Err()
+}
+
+// RecordLogin stores a successful login as the member's last login and last activity
+func (mr *memberRepository) RecordLogin(id int, at time.Time) error {
+	_, err := mr.db.Exec("UPDATE members SET last_login_at = $1, last_activity_at = GREATEST(last_activity_at, $1) WHERE id = $2", at, id)
 	return err
 }
 
-func (mr *memberRepository) DeleteMember(id int) error {
-	_, err := mr.db.Exec("DELETE FROM members WHERE id = $1", id)
+// RecordActivity stores a circulation action (loan, renewal, return or hold) as the member's last activity
+func (mr *memberRepository) RecordActivity(id int, at time.Time) error {
+	_, err := mr.db.Exec("UPDATE members SET last_activity_at = GREATEST(last_activity_at, $1) WHERE id = $2", at, id)
 	return err
 }
 
+// GetInactiveMembers retrieves the members, erased and closed ones excluded, whose last activity is before the
+// given time, least recently active first. Members who were never active count from their registration date.
+func (mr *memberRepository) GetInactiveMembers(before time.Time) ([]models.Member, error) {
+	const query = `
+        SELECT id, name, email, COALESCE(phone_number, ''), registration_date, membership_type, membership_status,
+               membership_end, COALESCE(fine_amount, 0), last_login_at, last_activity_at
+        FROM members
+        WHERE deleted_at IS NULL AND erased_at IS NULL AND membership_status <> 'closed'
+          AND COALESCE(GREATEST(last_login_at, last_activity_at), registration_date) < $1
+        ORDER BY COALESCE(GREATEST(last_login_at, last_activity_at), registration_date), id
+    `
+
+	rows, err := mr.db.Query(query, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []models.Member
+	for rows.Next() {
+		var m models.Member
+		if err := rows.Scan(&m.ID, &m.Name, &m.Email, &m.PhoneNumber, &m.RegistrationDate, &m.MembershipType,
+			&m.MembershipStatus, &m.MembershipEnd, &m.FineAmount, &m.LastLoginAt, &m.LastActivityAt); err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+	return members, rows.Err()
+}
+
+// likePattern membuat pola ILIKE "mengandung" dari teks pencarian; karakter wildcard di teks di-escape
+func likePattern(text string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+	return "%" + replacer.Replace(text) + "%"
+}
+
+// GetMembersByStudentIDsOrEmails retrieves the members, erased ones excluded, that have one of the student IDs
+// or one of the emails; emails are compared case-insensitively and must be given in lower case
+func (mr *memberRepository) GetMembersByStudentIDsOrEmails(studentIDs, emails []string) ([]models.Member, error) {
+	const query = `
+        SELECT id, name, email, COALESCE(phone_number, ''), COALESCE(address, ''), COALESCE(gender, ''), birth_date,
+               COALESCE(student_id, ''), COALESCE(roster, ''), membership_type, membership_status, membership_start,
+               membership_end
+        FROM members
+        WHERE deleted_at IS NULL AND erased_at IS NULL
+          AND (student_id = ANY($1) OR LOWER(email) = ANY($2))
+    `
+	return mr.queryRosterMembers(query, pq.Array(studentIDs), pq.Array(emails))
+}
+
+// GetRosterMembers retrieves the active members last imported from a roster
+func (mr *memberRepository) GetRosterMembers(roster string) ([]models.Member, error) {
+	const query = `
+        SELECT id, name, email, COALESCE(phone_number, ''), COALESCE(address, ''), COALESCE(gender, ''), birth_date,
+               COALESCE(student_id, ''), COALESCE(roster, ''), membership_type, membership_status, membership_start,
+               membership_end
+        FROM members
+        WHERE deleted_at IS NULL AND erased_at IS NULL AND roster = $1 AND membership_status = 'active'
+        ORDER BY id
+    `
+	return mr.queryRosterMembers(query, roster)
+}
+
+// ImportMembers stores a roster import in one transaction: new members are inserted without a password, matched
+// members are overwritten with their roster data, and the members to deactivate get an expired membership
+// ending now. The IDs of the new members are filled in.
+func (mr *memberRepository) ImportMembers(creates, updates []models.Member, deactivate []int, changedBy string) error {
+	const insertQuery = `
+        INSERT INTO members (name, email, phone_number, address, gender, birth_date, student_id, roster, membership_type,
+                             membership_status, membership_start, membership_end)
+        VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''), $6, NULLIF($7, ''), $8, $9, $10, $11, $12)
+        RETURNING id
+    `
+	const updateQuery = `
+        UPDATE members
+        SET name = $1, email = $2, phone_number = NULLIF($3, ''), address = NULLIF($4, ''), gender = NULLIF($5, ''),
+            birth_date = $6, student_id = NULLIF($7, ''), roster = $8, membership_type = $9, membership_status = $10,
+            membership_start = $11, membership_end = $12
+        WHERE id = $13 AND deleted_at IS NULL
+    `
+	const deactivateQuery = `
+        UPDATE members
+        SET membership_status = 'expired', membership_end = $1
+        WHERE id = ANY($2) AND membership_status = 'active'
+    `
+
+	tx, err := mr.db.Begin()
+	if err != nil {
+		return err
+	}
+	for i := range creates {
+		m := &creates[i]
+		err := tx.QueryRow(insertQuery, m.Name, m.Email, m.PhoneNumber, m.Address, m.Gender, m.BirthDate, m.StudentID,
+			m.Roster, m.MembershipType, m.MembershipStatus, m.MembershipStart, m.MembershipEnd).Scan(&m.ID)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	for _, m := range updates {
+		_, err := tx.Exec(updateQuery, m.Name, m.Email, m.PhoneNumber, m.Address, m.Gender, m.BirthDate, m.StudentID,
+			m.Roster, m.MembershipType, m.MembershipStatus, m.MembershipStart, m.MembershipEnd, m.ID)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if len(deactivate) > 0 {
+		if _, err := tx.Exec(deactivateQuery, time.Now(), pq.Array(deactivate)); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// queryRosterMembers runs a query returning the member columns used by roster imports
+func (mr *memberRepository) queryRosterMembers(query string, args ...interface{}) ([]models.Member, error) {
+	rows, err := mr.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []models.Member
+	for rows.Next() {
+		var m models.Member
+		if err := rows.Scan(&m.ID, &m.Name, &m.Email, &m.PhoneNumber, &m.Address, &m.Gender, &m.BirthDate,
+			&m.StudentID, &m.Roster, &m.MembershipType, &m.MembershipStatus, &m.MembershipStart,
+			&m.MembershipEnd); err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+	return members, rows.Err()
+}
+
 // NewMemberRepository creates a new MemberRepository instance.
 func NewMemberRepository(db *sql.DB) MemberRepository {
 	return &memberRepository{db: db}
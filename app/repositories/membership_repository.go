@@ -0,0 +1,215 @@
+package repositories
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// MembershipRepository provides methods for membership status, renewals, the fine ledger and expiry in the database
+type MembershipRepository interface {
+	UpdateStatus(m *models.Member) error
+	Renew(r *models.MembershipRenewal) error
+	GetRenewals(memberID int) ([]models.MembershipRenewal, error)
+	GetFineLedger(memberID int) ([]models.FineLedgerEntry, error)
+	ExpireMemberships(now time.Time) ([]models.Member, error)
+	GetMembershipsToRemind(now, until time.Time) ([]models.Member, error)
+	MarkReminded(memberID int, end time.Time) error
+	ExpireMembers(ids []int, at time.Time) ([]models.Member, error)
+}
+
+// NewMembershipRepository creates a new MembershipRepository instance
+func NewMembershipRepository(db *sql.DB) *membershipRepository {
+	return &membershipRepository{db: db}
+}
+
+type membershipRepository struct {
+	db *sql.DB
+}
+
+// UpdateStatus stores the membership status and period of a member
+func (mr *membershipRepository) UpdateStatus(m *models.Member) error {
+	const query = `
+        UPDATE members
+        SET membership_status = $1, membership_start = $2, membership_end = $3
+        WHERE id = $4 AND deleted_at IS NULL
+    `
+
+	result, err := mr.db.Exec(query, m.MembershipStatus, m.MembershipStart, m.MembershipEnd, m.ID)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return errors.New("member not found")
+	}
+	return nil
+}
+
+// Renew records a renewal, extends and reactivates the membership and charges the renewal fee to the fine ledger
+// in one transaction
+func (mr *membershipRepository) Renew(r *models.MembershipRenewal) error {
+	const renewalQuery = `
+        INSERT INTO membership_renewals (member_id, previous_end, new_end, months, fee, renewed_by, renewed_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        RETURNING id
+    `
+	const memberQuery = `
+        UPDATE members
+        SET membership_status = 'active', membership_start = COALESCE(membership_start, $1), membership_end = $2,
+            expiry_reminded_for = NULL
+        WHERE id = $3 AND deleted_at IS NULL
+    `
+	const ledgerQuery = `
+        INSERT INTO fine_ledger (member_id, amount, reason, created_at)
+        VALUES ($1, $2, $3, $4)
+    `
+	const fineQuery = `
+        UPDATE members SET fine_amount = COALESCE(fine_amount, 0) + $1 WHERE id = $2
+    `
+
+	tx, err := mr.db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := tx.QueryRow(renewalQuery, r.MemberID, r.PreviousEnd, r.NewEnd, r.Months, r.Fee, r.RenewedBy, r.RenewedAt).Scan(&r.ID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	result, err := tx.Exec(memberQuery, r.RenewedAt, r.NewEnd, r.MemberID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		tx.Rollback()
+		return errors.New("member not found")
+	}
+	if r.Fee > 0 {
+		if _, err := tx.Exec(ledgerQuery, r.MemberID, r.Fee, "membership renewal fee", r.RenewedAt); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if _, err := tx.Exec(fineQuery, r.Fee, r.MemberID); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetRenewals retrieves the renewals of a member, most recent first
+func (mr *membershipRepository) GetRenewals(memberID int) ([]models.MembershipRenewal, error) {
+	const query = `
+        SELECT id, member_id, previous_end, new_end, months, fee, renewed_by, renewed_at
+        FROM membership_renewals
+        WHERE member_id = $1
+        ORDER BY renewed_at DESC, id DESC
+    `
+
+	rows, err := mr.db.Query(query, memberID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var renewals []models.MembershipRenewal
+	for rows.Next() {
+		var r models.MembershipRenewal
+		if err := rows.Scan(&r.ID, &r.MemberID, &r.PreviousEnd, &r.NewEnd, &r.Months, &r.Fee, &r.RenewedBy, &r.RenewedAt); err != nil {
+			return nil, err
+		}
+		renewals = append(renewals, r)
+	}
+	return renewals, rows.Err()
+}
+
+// GetFineLedger retrieves the charges recorded for a member, most recent first
+func (mr *membershipRepository) GetFineLedger(memberID int) ([]models.FineLedgerEntry, error) {
+	const query = `
+        SELECT id, member_id, amount, reason, created_at
+        FROM fine_ledger
+        WHERE member_id = $1
+        ORDER BY created_at DESC, id DESC
+    `
+
+	rows, err := mr.db.Query(query, memberID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.FineLedgerEntry
+	for rows.Next() {
+		var e models.FineLedgerEntry
+		if err := rows.Scan(&e.ID, &e.MemberID, &e.Amount, &e.Reason, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// ExpireMemberships marks active memberships whose end date has passed as expired and returns the affected members
+func (mr *membershipRepository) ExpireMemberships(now time.Time) ([]models.Member, error) {
+	const query = `
+        UPDATE members
+        SET membership_status = 'expired'
+        WHERE membership_status = 'active' AND membership_end <= $1 AND deleted_at IS NULL
+        RETURNING id, name, email, membership_type, membership_status, membership_start, membership_end
+    `
+	return mr.queryMembers(query, now)
+}
+
+// GetMembershipsToRemind retrieves active memberships ending between now and until
+// that have not yet been reminded about their current end date
+func (mr *membershipRepository) GetMembershipsToRemind(now, until time.Time) ([]models.Member, error) {
+	const query = `
+        SELECT id, name, email, membership_type, membership_status, membership_start, membership_end
+        FROM members
+        WHERE membership_status = 'active' AND membership_end > $1 AND membership_end <= $2
+          AND expiry_reminded_for IS DISTINCT FROM membership_end
+          AND deleted_at IS NULL
+        ORDER BY membership_end, id
+    `
+	return mr.queryMembers(query, now, until)
+}
+
+// MarkReminded records that the member has been reminded about the given end date, so the reminder is sent once
+func (mr *membershipRepository) MarkReminded(memberID int, end time.Time) error {
+	_, err := mr.db.Exec("UPDATE members SET expiry_reminded_for = $1 WHERE id = $2", end, memberID)
+	return err
+}
+
+// ExpireMembers ends the active or suspended memberships of the given members at the given time and returns the
+// affected members; members in any other status are left unchanged
+func (mr *membershipRepository) ExpireMembers(ids []int, at time.Time) ([]models.Member, error) {
+	const query = `
+        UPDATE members
+        SET membership_status = 'expired', membership_end = LEAST(COALESCE(membership_end, $2), $2)
+        WHERE id = ANY($1) AND membership_status IN ('active', 'suspended') AND deleted_at IS NULL
+        RETURNING id, name, email, membership_type, membership_status, membership_start, membership_end
+    `
+	return mr.queryMembers(query, pq.Array(ids), at)
+}
+
+// queryMembers runs a query returning member rows with their membership columns
+func (mr *membershipRepository) queryMembers(query string, args ...interface{}) ([]models.Member, error) {
+	rows, err := mr.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []models.Member
+	for rows.Next() {
+		var m models.Member
+		if err := rows.Scan(&m.ID, &m.Name, &m.Email, &m.MembershipType, &m.MembershipStatus, &m.MembershipStart, &m.MembershipEnd); err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+	return members, rows.Err()
+}
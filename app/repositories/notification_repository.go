@@ -100,4 +100,50 @@ func (nr *NotificationRepository) MarkNotificationAsRead(id int) error {
 	return err
 }
 
-// ... (fungsi lain yang mungkin Anda butuhkan, seperti GetNotificationsByUserID, dll.)
+// GetUnreadNotificationsByUserID mengambil notifikasi yang belum dibaca milik pengguna, terbaru lebih dulu
+func (nr *NotificationRepository) GetUnreadNotificationsByUserID(userID int) ([]models.Notification, error) {
+	query := "SELECT id, user_id, message, timestamp, is_read FROM notifications WHERE user_id = $1 AND is_read = false ORDER BY timestamp DESC, id DESC"
+
+	rows, err := nr.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notifications []models.Notification
+	for rows.Next() {
+		var n models.Notification
+		err := rows.Scan(&n.ID, &n.UserID, &n.Message, &n.Timestamp, &n.IsRead)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+
+	return notifications, rows.Err()
+}
+
+// GetNotificationsByUserID mengambil semua notifikasi milik pengguna, terbaru lebih dulu
+func (nr *NotificationRepository) GetNotificationsByUserID(userID int) ([]models.Notification, error) {
+	query := "SELECT id, user_id, message, timestamp, is_read FROM notifications WHERE user_id = $1 ORDER BY timestamp DESC, id DESC"
+
+	rows, err := nr.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notifications []models.Notification
+	for rows.Next() {
+		var n models.Notification
+		err := rows.Scan(&n.ID, &n.UserID, &n.Message, &n.Timestamp, &n.IsRead)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+
+	return notifications, rows.Err()
+}
+
+// ... (fungsi lain yang mungkin Anda butuhkan)
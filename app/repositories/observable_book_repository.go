@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/utils"
+)
+
+// BookCreatedListener is called after a new book has been stored
+type BookCreatedListener func(b *models.Book) error
+
+// ObservableBookRepository is a BookRepository that calls its listeners after every book it creates
+type ObservableBookRepository interface {
+	BookRepository
+	OnBookCreated(listener BookCreatedListener)
+}
+
+// NewObservableBookRepository wraps a BookRepository so that the listeners registered with OnBookCreated run
+// after every CreateBook, CreateBooks and RecordCreated, whichever service, import or handler created the book
+func NewObservableBookRepository(books BookRepository) ObservableBookRepository {
+	return &observableBookRepository{BookRepository: books}
+}
+
+type observableBookRepository struct {
+	BookRepository
+	listeners []BookCreatedListener
+}
+
+// OnBookCreated registers a listener; listeners are registered at startup, before the repository is used
+func (ob *observableBookRepository) OnBookCreated(listener BookCreatedListener) {
+	ob.listeners = append(ob.listeners, listener)
+}
+
+// CreateBook creates a book and notifies the listeners
+func (ob *observableBookRepository) CreateBook(b *models.Book) error {
+	if err := ob.BookRepository.CreateBook(b); err != nil {
+		return err
+	}
+	ob.created(b)
+	return nil
+}
+
+// CreateBooks creates several books and notifies the listeners of each
+func (ob *observableBookRepository) CreateBooks(books []models.Book) error {
+	if err := ob.BookRepository.CreateBooks(books); err != nil {
+		return err
+	}
+	for i := range books {
+		ob.created(&books[i])
+	}
+	return nil
+}
+
+// RecordCreated notifies the listeners of a book that was inserted inside another repository's transaction
+func (ob *observableBookRepository) RecordCreated(b *models.Book) {
+	ob.BookRepository.RecordCreated(b)
+	ob.created(b)
+}
+
+// created calls the listeners for a stored book. Failures are logged, not returned, because the book itself
+// has already been committed.
+func (ob *observableBookRepository) created(b *models.Book) {
+	for _, listener := range ob.listeners {
+		if err := listener(b); err != nil {
+			utils.GetLogger().WithError(err).WithField("book", b.ID).Warn("book created listener failed")
+		}
+	}
+}
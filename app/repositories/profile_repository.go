@@ -0,0 +1,195 @@
+package repositories
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"database/sql"
+	"errors"
+)
+
+// ProfileRepository provides methods for members maintaining their own profile, password and email address
+type ProfileRepository interface {
+	GetProfile(memberID int) (*models.Member, error)
+	UpdateProfile(memberID int, p *models.ProfileUpdate) error
+	GetPasswordHash(memberID int) (string, error)
+	UpdatePassword(memberID int, hash string) error
+	SaveEmailChange(c *models.EmailChange) error
+	GetEmailChange(memberID int) (*models.EmailChange, error)
+	ConfirmEmailChange(memberID int, newEmail string) error
+	SavePasswordSetup(p *models.PasswordSetup) error
+	GetPasswordSetup(tokenHash string) (*models.PasswordSetup, error)
+	CompletePasswordSetup(memberID int, hash string) error
+}
+
+// NewProfileRepository creates a new ProfileRepository instance
+func NewProfileRepository(db *sql.DB) *profileRepository {
+	return &profileRepository{db: db}
+}
+
+type profileRepository struct {
+	db *sql.DB
+}
+
+// GetProfile retrieves the full profile of a member
+func (pr *profileRepository) GetProfile(memberID int) (*models.Member, error) {
+	const query = `
+        SELECT id, name, email, COALESCE(phone_number, ''), COALESCE(address, ''), COALESCE(gender, ''),
+               COALESCE(username, ''), registration_date, membership_type, membership_status, membership_start,
+               membership_end, birth_date, COALESCE(fine_amount, 0), COALESCE(student_id, ''), COALESCE(roster, '')
+        FROM members
+        WHERE id = $1 AND deleted_at IS NULL
+    `
+
+	var m models.Member
+	err := pr.db.QueryRow(query, memberID).Scan(&m.ID, &m.Name, &m.Email, &m.PhoneNumber, &m.Address, &m.Gender,
+		&m.Username, &m.RegistrationDate, &m.MembershipType, &m.MembershipStatus, &m.MembershipStart,
+		&m.MembershipEnd, &m.BirthDate, &m.FineAmount, &m.StudentID, &m.Roster)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("member not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// UpdateProfile stores the self-service profile fields that are set
+func (pr *profileRepository) UpdateProfile(memberID int, p *models.ProfileUpdate) error {
+	const query = `
+        UPDATE members
+        SET name = COALESCE($1, name), phone_number = COALESCE($2, phone_number),
+            address = COALESCE($3, address), gender = COALESCE($4, gender)
+        WHERE id = $5 AND deleted_at IS NULL
+    `
+
+	result, err := pr.db.Exec(query, p.Name, p.PhoneNumber, p.Address, p.Gender, memberID)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return errors.New("member not found")
+	}
+	return nil
+}
+
+// GetPasswordHash retrieves the bcrypt hash of a member's password; members without a password get an empty hash
+func (pr *profileRepository) GetPasswordHash(memberID int) (string, error) {
+	var hash string
+	err := pr.db.QueryRow("SELECT COALESCE(password, '') FROM members WHERE id = $1 AND deleted_at IS NULL", memberID).Scan(&hash)
+	if err == sql.ErrNoRows {
+		return "", errors.New("member not found")
+	}
+	return hash, err
+}
+
+// UpdatePassword stores a new bcrypt password hash
+func (pr *profileRepository) UpdatePassword(memberID int, hash string) error {
+	_, err := pr.db.Exec("UPDATE members SET password = $1 WHERE id = $2 AND deleted_at IS NULL", hash, memberID)
+	return err
+}
+
+// SaveEmailChange stores a pending email change, replacing an earlier request of the member
+func (pr *profileRepository) SaveEmailChange(c *models.EmailChange) error {
+	const query = `
+        INSERT INTO email_changes (member_id, new_email, token_hash, requested_at, expires_at)
+        VALUES ($1, $2, $3, $4, $5)
+        ON CONFLICT (member_id) DO UPDATE
+        SET new_email = EXCLUDED.new_email, token_hash = EXCLUDED.token_hash,
+            requested_at = EXCLUDED.requested_at, expires_at = EXCLUDED.expires_at
+    `
+	_, err := pr.db.Exec(query, c.MemberID, c.NewEmail, c.TokenHash, c.RequestedAt, c.ExpiresAt)
+	return err
+}
+
+// GetEmailChange retrieves the pending email change of a member
+func (pr *profileRepository) GetEmailChange(memberID int) (*models.EmailChange, error) {
+	const query = `
+        SELECT member_id, new_email, token_hash, requested_at, expires_at
+        FROM email_changes
+        WHERE member_id = $1
+    `
+
+	var c models.EmailChange
+	err := pr.db.QueryRow(query, memberID).Scan(&c.MemberID, &c.NewEmail, &c.TokenHash, &c.RequestedAt, &c.ExpiresAt)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("no pending email change")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// ConfirmEmailChange applies a verified email change and removes the pending request in one transaction
+func (pr *profileRepository) ConfirmEmailChange(memberID int, newEmail string) error {
+	tx, err := pr.db.Begin()
+	if err != nil {
+		return err
+	}
+	result, err := tx.Exec("UPDATE members SET email = $1 WHERE id = $2 AND deleted_at IS NULL", newEmail, memberID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		tx.Rollback()
+		return errors.New("member not found")
+	}
+	if _, err := tx.Exec("DELETE FROM email_changes WHERE member_id = $1", memberID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// SavePasswordSetup stores a password setup link, replacing an earlier link of the member
+func (pr *profileRepository) SavePasswordSetup(p *models.PasswordSetup) error {
+	const query = `
+        INSERT INTO password_setups (member_id, token_hash, created_at, expires_at)
+        VALUES ($1, $2, $3, $4)
+        ON CONFLICT (member_id) DO UPDATE
+        SET token_hash = EXCLUDED.token_hash, created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at
+    `
+	_, err := pr.db.Exec(query, p.MemberID, p.TokenHash, p.CreatedAt, p.ExpiresAt)
+	return err
+}
+
+// GetPasswordSetup retrieves the password setup link with the given token hash
+func (pr *profileRepository) GetPasswordSetup(tokenHash string) (*models.PasswordSetup, error) {
+	const query = `
+        SELECT member_id, token_hash, created_at, expires_at
+        FROM password_setups
+        WHERE token_hash = $1
+    `
+
+	var p models.PasswordSetup
+	err := pr.db.QueryRow(query, tokenHash).Scan(&p.MemberID, &p.TokenHash, &p.CreatedAt, &p.ExpiresAt)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("password setup link not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// CompletePasswordSetup stores the first password of a member and removes the used link in one transaction
+func (pr *profileRepository) CompletePasswordSetup(memberID int, hash string) error {
+	tx, err := pr.db.Begin()
+	if err != nil {
+		return err
+	}
+	result, err := tx.Exec("UPDATE members SET password = $1 WHERE id = $2 AND deleted_at IS NULL", hash, memberID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		tx.Rollback()
+		return errors.New("member not found")
+	}
+	if _, err := tx.Exec("DELETE FROM password_setups WHERE member_id = $1", memberID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"database/sql"
+	"errors"
+	"time"
+
+	_ "github.com/lib/pq" // Import driver PostgreSQL
+)
+
+// ReadingHistoryRepository provides methods for members' reading history and its privacy setting
+type ReadingHistoryRepository interface {
+	GetReadingHistory(memberID int, now time.Time) ([]models.ReadingHistoryEntry, error)
+	GetPrivacySettings(memberID int) (*models.PrivacySettings, error)
+	UpdatePrivacySettings(memberID int, settings *models.PrivacySettings, now time.Time) error
+	AnonymizeExpiredDigitalLoans(now time.Time) (int, error)
+}
+
+// NewReadingHistoryRepository creates a new ReadingHistoryRepository instance
+func NewReadingHistoryRepository(db *sql.DB) *readingHistoryRepository {
+	return &readingHistoryRepository{db: db}
+}
+
+type readingHistoryRepository struct {
+	db *sql.DB
+}
+
+// GetReadingHistory retrieves a member's archived loans and expired digital loans, most recent first.
+// Anonymized rows no longer carry the member ID and are therefore never returned.
+func (rr *readingHistoryRepository) GetReadingHistory(memberID int, now time.Time) ([]models.ReadingHistoryEntry, error) {
+	const query = `
+        SELECT b.id, b.title, b.author, 'physical', h.borrow_date, h.return_date
+        FROM loan_history h
+        JOIN books b ON b.id = h.book_id
+        WHERE h.member_id = $1
+        UNION ALL
+        SELECT b.id, b.title, b.author, 'digital', l.borrowed_at, l.expires_at
+        FROM digital_loans l
+        JOIN digital_items i ON i.id = l.item_id
+        JOIN books b ON b.id = i.book_id
+        WHERE l.member_id = $1 AND l.expires_at <= $2
+        ORDER BY 5 DESC
+    `
+
+	rows, err := rr.db.Query(query, memberID, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.ReadingHistoryEntry
+	for rows.Next() {
+		var e models.ReadingHistoryEntry
+		if err := rows.Scan(&e.BookID, &e.Title, &e.Author, &e.Source, &e.BorrowedAt, &e.ReturnedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
+
+// GetPrivacySettings retrieves a member's privacy settings
+func (rr *readingHistoryRepository) GetPrivacySettings(memberID int) (*models.PrivacySettings, error) {
+	const query = "SELECT reading_history_enabled FROM members WHERE id = $1 AND deleted_at IS NULL"
+
+	var settings models.PrivacySettings
+	err := rr.db.QueryRow(query, memberID).Scan(&settings.ReadingHistoryEnabled)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("member not found")
+		}
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+// UpdatePrivacySettings stores a member's privacy settings. Opting out of the reading history also
+// anonymizes the member's archived loans and expired digital loans in the same transaction.
+func (rr *readingHistoryRepository) UpdatePrivacySettings(memberID int, settings *models.PrivacySettings, now time.Time) error {
+	tx, err := rr.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.Exec("UPDATE members SET reading_history_enabled = $1 WHERE id = $2 AND deleted_at IS NULL", settings.ReadingHistoryEnabled, memberID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		tx.Rollback()
+		return errors.New("member not found")
+	}
+
+	if !settings.ReadingHistoryEnabled {
+		if _, err := tx.Exec("UPDATE loan_history SET member_id = NULL WHERE member_id = $1", memberID); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if _, err := tx.Exec("UPDATE digital_loans SET member_id = NULL WHERE member_id = $1 AND expires_at <= $2", memberID, now); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// AnonymizeExpiredDigitalLoans removes the member from expired digital loans of members who opted out
+// of the reading history. Digital loans have no return step, so this runs periodically instead.
+func (rr *readingHistoryRepository) AnonymizeExpiredDigitalLoans(now time.Time) (int, error) {
+	const query = `
+        UPDATE digital_loans l
+        SET member_id = NULL
+        FROM members m
+        WHERE m.id = l.member_id AND m.reading_history_enabled = FALSE AND l.expires_at <= $1
+    `
+
+	result, err := rr.db.Exec(query, now)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	return int(affected), err
+}
@@ -0,0 +1,251 @@
+package repositories
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"database/sql"
+	"errors"
+
+	"github.com/lib/pq" // Import driver PostgreSQL
+)
+
+// ReadingListRepository provides methods for interacting with reading list data in the database
+type ReadingListRepository interface {
+	GetListsByMemberID(memberID int) ([]models.ReadingList, error)
+	GetPublicLists() ([]models.ReadingList, error)
+	GetListByID(id int) (*models.ReadingList, error)
+	GetListByShareToken(token string) (*models.ReadingList, error)
+	CreateList(l *models.ReadingList) error
+	UpdateList(l *models.ReadingList) error
+	DeleteList(id int) error
+	GetItems(listID int) ([]models.ReadingListItem, error)
+	GetItemByID(id int) (*models.ReadingListItem, error)
+	AddItem(item *models.ReadingListItem) error
+	UpdateItemNote(id int, note string) error
+	RemoveItem(id int) error
+	ReorderItems(listID int, itemIDs []int) error
+}
+
+// NewReadingListRepository creates a new ReadingListRepository instance
+func NewReadingListRepository(db *sql.DB) *readingListRepository {
+	return &readingListRepository{db: db}
+}
+
+type readingListRepository struct {
+	db *sql.DB
+}
+
+// GetListsByMemberID retrieves all reading lists of a member, newest first
+func (rr *readingListRepository) GetListsByMemberID(memberID int) ([]models.ReadingList, error) {
+	const query = `
+        SELECT id, member_id, name, description, visibility, share_token, created_at, updated_at
+        FROM reading_lists
+        WHERE member_id = $1
+        ORDER BY created_at DESC, id DESC
+    `
+
+	return rr.queryLists(query, memberID)
+}
+
+// GetPublicLists retrieves all public reading lists of members that are not in the trash
+func (rr *readingListRepository) GetPublicLists() ([]models.ReadingList, error) {
+	const query = `
+        SELECT l.id, l.member_id, l.name, l.description, l.visibility, l.share_token, l.created_at, l.updated_at
+        FROM reading_lists l
+        JOIN members m ON m.id = l.member_id
+        WHERE l.visibility = 'public' AND m.deleted_at IS NULL
+        ORDER BY l.updated_at DESC, l.id DESC
+    `
+
+	return rr.queryLists(query)
+}
+
+// GetListByID retrieves a reading list by ID from the database
+func (rr *readingListRepository) GetListByID(id int) (*models.ReadingList, error) {
+	const query = `
+        SELECT id, member_id, name, description, visibility, share_token, created_at, updated_at
+        FROM reading_lists
+        WHERE id = $1
+    `
+
+	return rr.queryList(query, id)
+}
+
+// GetListByShareToken retrieves a reading list by its share token
+func (rr *readingListRepository) GetListByShareToken(token string) (*models.ReadingList, error) {
+	const query = `
+        SELECT id, member_id, name, description, visibility, share_token, created_at, updated_at
+        FROM reading_lists
+        WHERE share_token = $1
+    `
+
+	return rr.queryList(query, token)
+}
+
+// CreateList creates a new reading list in the database
+func (rr *readingListRepository) CreateList(l *models.ReadingList) error {
+	const query = `
+        INSERT INTO reading_lists (member_id, name, description, visibility, share_token, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+        RETURNING id, created_at, updated_at
+    `
+
+	return rr.db.QueryRow(query, l.MemberID, l.Name, l.Description, l.Visibility, l.ShareToken).Scan(&l.ID, &l.CreatedAt, &l.UpdatedAt)
+}
+
+// UpdateList updates the name, description, visibility and share token of a reading list
+func (rr *readingListRepository) UpdateList(l *models.ReadingList) error {
+	const query = `
+        UPDATE reading_lists
+        SET name = $1, description = $2, visibility = $3, share_token = $4, updated_at = NOW()
+        WHERE id = $5
+        RETURNING updated_at
+    `
+
+	err := rr.db.QueryRow(query, l.Name, l.Description, l.Visibility, l.ShareToken, l.ID).Scan(&l.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return errors.New("reading list not found")
+	}
+	return err
+}
+
+// DeleteList deletes a reading list and its items from the database
+func (rr *readingListRepository) DeleteList(id int) error {
+	tx, err := rr.db.Begin()
+	if err != nil {
+		return err
+	}
+	for _, query := range []string{
+		"DELETE FROM reading_list_items WHERE list_id = $1",
+		"DELETE FROM reading_lists WHERE id = $1",
+	} {
+		if _, err := tx.Exec(query, id); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetItems retrieves the items of a reading list in list order; books in the trash are left out
+func (rr *readingListRepository) GetItems(listID int) ([]models.ReadingListItem, error) {
+	const query = `
+        SELECT i.id, i.list_id, i.book_id, i.position, i.note, i.added_at, b.title, b.author
+        FROM reading_list_items i
+        JOIN books b ON b.id = i.book_id
+        WHERE i.list_id = $1 AND b.deleted_at IS NULL
+        ORDER BY i.position, i.id
+    `
+
+	rows, err := rr.db.Query(query, listID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []models.ReadingListItem
+	for rows.Next() {
+		var i models.ReadingListItem
+		if err := rows.Scan(&i.ID, &i.ListID, &i.BookID, &i.Position, &i.Note, &i.AddedAt, &i.Title, &i.Author); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
+
+// GetItemByID retrieves a reading list item by ID from the database
+func (rr *readingListRepository) GetItemByID(id int) (*models.ReadingListItem, error) {
+	const query = `
+        SELECT id, list_id, book_id, position, note, added_at
+        FROM reading_list_items
+        WHERE id = $1
+    `
+
+	var i models.ReadingListItem
+	err := rr.db.QueryRow(query, id).Scan(&i.ID, &i.ListID, &i.BookID, &i.Position, &i.Note, &i.AddedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("reading list item not found")
+		}
+		return nil, err
+	}
+
+	return &i, nil
+}
+
+// AddItem appends a book to the end of a reading list
+func (rr *readingListRepository) AddItem(item *models.ReadingListItem) error {
+	const query = `
+        INSERT INTO reading_list_items (list_id, book_id, position, note, added_at)
+        SELECT $1, $2, COALESCE(MAX(position), 0) + 1, $3, NOW()
+        FROM reading_list_items
+        WHERE list_id = $1
+        RETURNING id, position, added_at
+    `
+
+	return rr.db.QueryRow(query, item.ListID, item.BookID, item.Note).Scan(&item.ID, &item.Position, &item.AddedAt)
+}
+
+// UpdateItemNote updates the note of a reading list item
+func (rr *readingListRepository) UpdateItemNote(id int, note string) error {
+	const query = "UPDATE reading_list_items SET note = $1 WHERE id = $2"
+
+	_, err := rr.db.Exec(query, note, id)
+	return err
+}
+
+// RemoveItem removes an item from a reading list
+func (rr *readingListRepository) RemoveItem(id int) error {
+	const query = "DELETE FROM reading_list_items WHERE id = $1"
+
+	_, err := rr.db.Exec(query, id)
+	return err
+}
+
+// ReorderItems sets the position of each item to its index in itemIDs (starting at 1)
+func (rr *readingListRepository) ReorderItems(listID int, itemIDs []int) error {
+	const query = `
+        UPDATE reading_list_items i
+        SET position = o.position
+        FROM UNNEST($2::int[]) WITH ORDINALITY AS o(id, position)
+        WHERE i.id = o.id AND i.list_id = $1
+    `
+
+	_, err := rr.db.Exec(query, listID, pq.Array(itemIDs))
+	return err
+}
+
+// queryList runs a query returning at most one reading list
+func (rr *readingListRepository) queryList(query string, args ...interface{}) (*models.ReadingList, error) {
+	var l models.ReadingList
+	err := rr.db.QueryRow(query, args...).Scan(&l.ID, &l.MemberID, &l.Name, &l.Description, &l.Visibility, &l.ShareToken, &l.CreatedAt, &l.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("reading list not found")
+		}
+		return nil, err
+	}
+
+	return &l, nil
+}
+
+// queryLists runs a query returning reading lists
+func (rr *readingListRepository) queryLists(query string, args ...interface{}) ([]models.ReadingList, error) {
+	rows, err := rr.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lists []models.ReadingList
+	for rows.Next() {
+		var l models.ReadingList
+		if err := rows.Scan(&l.ID, &l.MemberID, &l.Name, &l.Description, &l.Visibility, &l.ShareToken, &l.CreatedAt, &l.UpdatedAt); err != nil {
+			return nil, err
+		}
+		lists = append(lists, l)
+	}
+
+	return lists, rows.Err()
+}
@@ -118,3 +118,26 @@ func (rr *ReviewRepository) GetReviewsForBook(bookID int) ([]models.Review, erro
 
 	return reviews, nil
 }
+
+// GetReviewsByUserID mengambil semua ulasan yang ditulis pengguna tertentu
+func (rr *ReviewRepository) GetReviewsByUserID(userID int) ([]models.Review, error) {
+	query := "SELECT id, user_id, book_id, rating, comment, timestamp FROM reviews WHERE user_id = $1 ORDER BY timestamp DESC"
+
+	rows, err := rr.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reviews []models.Review
+	for rows.Next() {
+		var r models.Review
+		err := rows.Scan(&r.ID, &r.UserID, &r.BookID, &r.Rating, &r.Comment, &r.Timestamp)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, r)
+	}
+
+	return reviews, nil
+}
@@ -0,0 +1,353 @@
+package repositories
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq" // Import driver PostgreSQL
+)
+
+// ErrIssueAlreadyReceived dikembalikan CheckInIssue jika nomor terbitan sudah pernah di-check-in
+var ErrIssueAlreadyReceived = errors.New("issue has already been received")
+
+// SerialRepository provides methods for interacting with serials, their issues and routing lists in the database
+type SerialRepository interface {
+	GetAllSerials() ([]models.Serial, error)
+	GetSerialByID(id int) (*models.Serial, error)
+	CreateSerial(s *models.Serial) error
+	UpdateSerial(s *models.Serial) error
+	DeleteSerial(id int) error
+	GetIssuesBySerialID(serialID int) ([]models.SerialIssue, error)
+	GetIssueByID(id int) (*models.SerialIssue, error)
+	GetLastIssueNumber(serialID int) (int, error)
+	CountReceivedIssues(serialID int) (int, error)
+	CreateIssues(issues []models.SerialIssue) error
+	CheckInIssue(issue *models.SerialIssue, c *models.BookCopy, receivedAt time.Time) error
+	ClaimIssue(id int, claimedAt time.Time) error
+	GetClaimableIssues(now time.Time) ([]models.SerialClaim, error)
+	GetRouting(serialID int) ([]models.SerialRoutingEntry, error)
+	SetRouting(serialID int, memberIDs []int) error
+}
+
+// NewSerialRepository creates a new SerialRepository instance
+func NewSerialRepository(db *sql.DB) *serialRepository {
+	return &serialRepository{db: db}
+}
+
+type serialRepository struct {
+	db *sql.DB
+}
+
+// GetAllSerials retrieves all serials from the database
+func (sr *serialRepository) GetAllSerials() ([]models.Serial, error) {
+	const query = `
+        SELECT id, book_id, vendor_id, title, issn, publisher, frequency, first_issue_date, claim_grace_days, active, created_at
+        FROM serials
+        ORDER BY title, id
+    `
+
+	rows, err := sr.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var serials []models.Serial
+	for rows.Next() {
+		var s models.Serial
+		if err := rows.Scan(&s.ID, &s.BookID, &s.VendorID, &s.Title, &s.ISSN, &s.Publisher, &s.Frequency, &s.FirstIssueDate, &s.ClaimGraceDays, &s.Active, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		serials = append(serials, s)
+	}
+
+	return serials, rows.Err()
+}
+
+// GetSerialByID retrieves a serial by ID from the database
+func (sr *serialRepository) GetSerialByID(id int) (*models.Serial, error) {
+	const query = `
+        SELECT id, book_id, vendor_id, title, issn, publisher, frequency, first_issue_date, claim_grace_days, active, created_at
+        FROM serials
+        WHERE id = $1
+    `
+
+	var s models.Serial
+	err := sr.db.QueryRow(query, id).Scan(&s.ID, &s.BookID, &s.VendorID, &s.Title, &s.ISSN, &s.Publisher, &s.Frequency, &s.FirstIssueDate, &s.ClaimGraceDays, &s.Active, &s.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("serial not found")
+		}
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// CreateSerial creates a new serial in the database
+func (sr *serialRepository) CreateSerial(s *models.Serial) error {
+	const query = `
+        INSERT INTO serials (book_id, vendor_id, title, issn, publisher, frequency, first_issue_date, claim_grace_days, active, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())
+        RETURNING id, created_at
+    `
+
+	return sr.db.QueryRow(query, s.BookID, s.VendorID, s.Title, s.ISSN, s.Publisher, s.Frequency, s.FirstIssueDate, s.ClaimGraceDays, s.Active).Scan(&s.ID, &s.CreatedAt)
+}
+
+// UpdateSerial updates a serial in the database
+func (sr *serialRepository) UpdateSerial(s *models.Serial) error {
+	const query = `
+        UPDATE serials
+        SET vendor_id = $1, title = $2, issn = $3, publisher = $4, frequency = $5, first_issue_date = $6, claim_grace_days = $7, active = $8
+        WHERE id = $9
+    `
+
+	_, err := sr.db.Exec(query, s.VendorID, s.Title, s.ISSN, s.Publisher, s.Frequency, s.FirstIssueDate, s.ClaimGraceDays, s.Active, s.ID)
+	return err
+}
+
+// DeleteSerial deletes a serial with its predicted issues and routing list from the database
+func (sr *serialRepository) DeleteSerial(id int) error {
+	tx, err := sr.db.Begin()
+	if err != nil {
+		return err
+	}
+	for _, query := range []string{
+		"DELETE FROM serial_routing WHERE serial_id = $1",
+		"DELETE FROM serial_issues WHERE serial_id = $1",
+		"DELETE FROM serials WHERE id = $1",
+	} {
+		if _, err := tx.Exec(query, id); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetIssuesBySerialID retrieves the issues of a serial in issue order
+func (sr *serialRepository) GetIssuesBySerialID(serialID int) ([]models.SerialIssue, error) {
+	const query = `
+        SELECT id, serial_id, number, label, expected_date, status, received_at, copy_id, claimed_at, claim_count
+        FROM serial_issues
+        WHERE serial_id = $1
+        ORDER BY number
+    `
+
+	rows, err := sr.db.Query(query, serialID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var issues []models.SerialIssue
+	for rows.Next() {
+		var i models.SerialIssue
+		if err := rows.Scan(&i.ID, &i.SerialID, &i.Number, &i.Label, &i.ExpectedDate, &i.Status, &i.ReceivedAt, &i.CopyID, &i.ClaimedAt, &i.ClaimCount); err != nil {
+			return nil, err
+		}
+		issues = append(issues, i)
+	}
+
+	return issues, rows.Err()
+}
+
+// GetIssueByID retrieves a serial issue by ID from the database
+func (sr *serialRepository) GetIssueByID(id int) (*models.SerialIssue, error) {
+	const query = `
+        SELECT id, serial_id, number, label, expected_date, status, received_at, copy_id, claimed_at, claim_count
+        FROM serial_issues
+        WHERE id = $1
+    `
+
+	var i models.SerialIssue
+	err := sr.db.QueryRow(query, id).Scan(&i.ID, &i.SerialID, &i.Number, &i.Label, &i.ExpectedDate, &i.Status, &i.ReceivedAt, &i.CopyID, &i.ClaimedAt, &i.ClaimCount)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("issue not found")
+		}
+		return nil, err
+	}
+
+	return &i, nil
+}
+
+// GetLastIssueNumber retrieves the highest issue number of a serial, or 0 if no issue has been predicted yet
+func (sr *serialRepository) GetLastIssueNumber(serialID int) (int, error) {
+	const query = "SELECT COALESCE(MAX(number), 0) FROM serial_issues WHERE serial_id = $1"
+
+	var number int
+	err := sr.db.QueryRow(query, serialID).Scan(&number)
+	return number, err
+}
+
+// CountReceivedIssues counts the issues of a serial that have been checked in
+func (sr *serialRepository) CountReceivedIssues(serialID int) (int, error) {
+	const query = "SELECT COUNT(*) FROM serial_issues WHERE serial_id = $1 AND status = 'received'"
+
+	var count int
+	err := sr.db.QueryRow(query, serialID).Scan(&count)
+	return count, err
+}
+
+// CreateIssues inserts predicted issues in a single transaction
+func (sr *serialRepository) CreateIssues(issues []models.SerialIssue) error {
+	const query = `
+        INSERT INTO serial_issues (serial_id, number, label, expected_date, status, claim_count)
+        VALUES ($1, $2, $3, $4, $5, 0)
+        RETURNING id
+    `
+
+	tx, err := sr.db.Begin()
+	if err != nil {
+		return err
+	}
+	for i := range issues {
+		issue := &issues[i]
+		if err := tx.QueryRow(query, issue.SerialID, issue.Number, issue.Label, issue.ExpectedDate, issue.Status).Scan(&issue.ID); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// CheckInIssue marks an issue as received and creates its copy in one transaction
+func (sr *serialRepository) CheckInIssue(issue *models.SerialIssue, c *models.BookCopy, receivedAt time.Time) error {
+	const copyQuery = `
+        INSERT INTO book_copies (book_id, barcode, location_id, status)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id
+    `
+	const issueQuery = `
+        UPDATE serial_issues
+        SET status = 'received', received_at = $1, copy_id = $2
+        WHERE id = $3 AND status <> 'received'
+    `
+
+	tx, err := sr.db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := tx.QueryRow(copyQuery, c.BookID, c.Barcode, c.LocationID, c.Status).Scan(&c.ID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	result, err := tx.Exec(issueQuery, receivedAt, c.ID, issue.ID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		tx.Rollback()
+		return ErrIssueAlreadyReceived
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	issue.Status = models.IssueStatusReceived
+	issue.ReceivedAt = &receivedAt
+	issue.CopyID = &c.ID
+	return nil
+}
+
+// ClaimIssue records a claim to the vendor for an issue that has not arrived
+func (sr *serialRepository) ClaimIssue(id int, claimedAt time.Time) error {
+	const query = `
+        UPDATE serial_issues
+        SET status = 'claimed', claimed_at = $1, claim_count = claim_count + 1
+        WHERE id = $2 AND status <> 'received'
+    `
+
+	_, err := sr.db.Exec(query, claimedAt, id)
+	return err
+}
+
+// GetClaimableIssues retrieves the issues of active serials that are overdue by more than the serial's grace period
+// and have not been claimed within that period, most overdue first
+func (sr *serialRepository) GetClaimableIssues(now time.Time) ([]models.SerialClaim, error) {
+	const query = `
+        SELECT i.id, i.serial_id, i.number, i.label, i.expected_date, i.status, i.received_at, i.copy_id, i.claimed_at, i.claim_count,
+               s.title, s.issn, s.vendor_id
+        FROM serial_issues i
+        JOIN serials s ON s.id = i.serial_id
+        WHERE s.active
+          AND i.status <> 'received'
+          AND i.expected_date + s.claim_grace_days * INTERVAL '1 day' < $1
+          AND (i.claimed_at IS NULL OR i.claimed_at + s.claim_grace_days * INTERVAL '1 day' < $1)
+        ORDER BY i.expected_date, i.id
+    `
+
+	rows, err := sr.db.Query(query, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var claims []models.SerialClaim
+	for rows.Next() {
+		var c models.SerialClaim
+		if err := rows.Scan(&c.ID, &c.SerialID, &c.Number, &c.Label, &c.ExpectedDate, &c.Status, &c.ReceivedAt, &c.CopyID, &c.ClaimedAt, &c.ClaimCount, &c.Title, &c.ISSN, &c.VendorID); err != nil {
+			return nil, err
+		}
+		c.DaysLate = int(now.Sub(c.ExpectedDate).Hours() / 24)
+		claims = append(claims, c)
+	}
+
+	return claims, rows.Err()
+}
+
+// GetRouting retrieves the routing list of a serial in routing order; members in the trash are left out
+func (sr *serialRepository) GetRouting(serialID int) ([]models.SerialRoutingEntry, error) {
+	const query = `
+        SELECT r.member_id, r.position, m.name, m.email
+        FROM serial_routing r
+        JOIN members m ON m.id = r.member_id
+        WHERE r.serial_id = $1 AND m.deleted_at IS NULL
+        ORDER BY r.position
+    `
+
+	rows, err := sr.db.Query(query, serialID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.SerialRoutingEntry
+	for rows.Next() {
+		var e models.SerialRoutingEntry
+		if err := rows.Scan(&e.MemberID, &e.Position, &e.Name, &e.Email); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
+
+// SetRouting replaces the routing list of a serial; the position of each member is its index in memberIDs (starting at 1)
+func (sr *serialRepository) SetRouting(serialID int, memberIDs []int) error {
+	const insertQuery = `
+        INSERT INTO serial_routing (serial_id, member_id, position)
+        SELECT $1, o.member_id, o.position
+        FROM UNNEST($2::int[]) WITH ORDINALITY AS o(member_id, position)
+    `
+
+	tx, err := sr.db.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM serial_routing WHERE serial_id = $1", serialID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.Exec(insertQuery, serialID, pq.Array(memberIDs)); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,206 @@
+package repositories
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"database/sql"
+	"errors"
+)
+
+// ErrAlreadyVoted dikembalikan AddVote jika anggota sudah memberi suara pada usulan tersebut
+var ErrAlreadyVoted = errors.New("member has already voted for this suggestion")
+
+// SuggestionRepository provides methods for interacting with purchase suggestions and their votes in the database
+type SuggestionRepository interface {
+	GetSuggestions(status string, viewerID int) ([]models.PurchaseSuggestion, error)
+	GetSuggestionsByRequesterID(requesterID int) ([]models.PurchaseSuggestion, error)
+	GetSuggestionByID(id, viewerID int) (*models.PurchaseSuggestion, error)
+	GetOpenSuggestionByISBN(isbn string) (*models.PurchaseSuggestion, error)
+	GetUncataloguedSuggestionsByISBN(isbn string) ([]models.PurchaseSuggestion, error)
+	CreateSuggestion(s *models.PurchaseSuggestion) error
+	UpdateStatus(s *models.PurchaseSuggestion) error
+	AddVote(suggestionID, memberID int) error
+	RemoveVote(suggestionID, memberID int) error
+}
+
+// NewSuggestionRepository creates a new SuggestionRepository instance
+func NewSuggestionRepository(db *sql.DB) *suggestionRepository {
+	return &suggestionRepository{db: db}
+}
+
+type suggestionRepository struct {
+	db *sql.DB
+}
+
+// suggestionColumns adalah kolom yang dibaca querySuggestions; $1 selalu ID anggota yang sedang melihat (0 untuk anonim)
+const suggestionColumns = `
+        s.id, COALESCE(s.requester_id, 0), s.isbn, s.title, s.author, s.justification, s.status, s.status_note, s.book_id,
+        (SELECT COUNT(*) FROM suggestion_votes v WHERE v.suggestion_id = s.id) AS votes,
+        EXISTS (SELECT 1 FROM suggestion_votes v WHERE v.suggestion_id = s.id AND v.member_id = $1),
+        s.created_at, s.updated_at
+`
+
+// GetSuggestions retrieves purchase suggestions, optionally filtered by status, most voted first
+func (sr *suggestionRepository) GetSuggestions(status string, viewerID int) ([]models.PurchaseSuggestion, error) {
+	query := `
+        SELECT ` + suggestionColumns + `
+        FROM purchase_suggestions s
+        WHERE $2::text = '' OR s.status = $2
+        ORDER BY votes DESC, s.created_at, s.id
+    `
+
+	return sr.querySuggestions(query, viewerID, status)
+}
+
+// GetSuggestionsByRequesterID retrieves the purchase suggestions submitted by a member, newest first
+func (sr *suggestionRepository) GetSuggestionsByRequesterID(requesterID int) ([]models.PurchaseSuggestion, error) {
+	query := `
+        SELECT ` + suggestionColumns + `
+        FROM purchase_suggestions s
+        WHERE s.requester_id = $1
+        ORDER BY s.created_at DESC, s.id DESC
+    `
+
+	return sr.querySuggestions(query, requesterID)
+}
+
+// GetSuggestionByID retrieves a purchase suggestion by ID from the database
+func (sr *suggestionRepository) GetSuggestionByID(id, viewerID int) (*models.PurchaseSuggestion, error) {
+	query := `
+        SELECT ` + suggestionColumns + `
+        FROM purchase_suggestions s
+        WHERE s.id = $2
+    `
+
+	return sr.querySuggestion(query, viewerID, id)
+}
+
+// GetOpenSuggestionByISBN retrieves the suggestion for an ISBN that is still new or under review
+func (sr *suggestionRepository) GetOpenSuggestionByISBN(isbn string) (*models.PurchaseSuggestion, error) {
+	query := `
+        SELECT ` + suggestionColumns + `
+        FROM purchase_suggestions s
+        WHERE s.isbn = $2 AND s.status IN ('new', 'under_review')
+        ORDER BY s.id
+        LIMIT 1
+    `
+
+	return sr.querySuggestion(query, 0, isbn)
+}
+
+// GetUncataloguedSuggestionsByISBN retrieves the received suggestions for an ISBN that are not yet linked to a catalogued book
+func (sr *suggestionRepository) GetUncataloguedSuggestionsByISBN(isbn string) ([]models.PurchaseSuggestion, error) {
+	query := `
+        SELECT ` + suggestionColumns + `
+        FROM purchase_suggestions s
+        WHERE s.isbn = $2 AND s.status = 'received' AND s.book_id IS NULL
+        ORDER BY s.id
+    `
+
+	return sr.querySuggestions(query, 0, isbn)
+}
+
+// CreateSuggestion creates a new purchase suggestion; the requester's own vote is recorded with it
+func (sr *suggestionRepository) CreateSuggestion(s *models.PurchaseSuggestion) error {
+	const insertQuery = `
+        INSERT INTO purchase_suggestions (requester_id, isbn, title, author, justification, status, status_note, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, '', NOW(), NOW())
+        RETURNING id, created_at, updated_at
+    `
+	const voteQuery = "INSERT INTO suggestion_votes (suggestion_id, member_id, created_at) VALUES ($1, $2, NOW())"
+
+	tx, err := sr.db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := tx.QueryRow(insertQuery, s.RequesterID, s.ISBN, s.Title, s.Author, s.Justification, s.Status).Scan(&s.ID, &s.CreatedAt, &s.UpdatedAt); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.Exec(voteQuery, s.ID, s.RequesterID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	s.Votes, s.Voted = 1, true
+
+	return tx.Commit()
+}
+
+// UpdateStatus updates the status, status note and catalogued book of a purchase suggestion
+func (sr *suggestionRepository) UpdateStatus(s *models.PurchaseSuggestion) error {
+	const query = `
+        UPDATE purchase_suggestions
+        SET status = $1, status_note = $2, book_id = $3, updated_at = NOW()
+        WHERE id = $4
+        RETURNING updated_at
+    `
+
+	err := sr.db.QueryRow(query, s.Status, s.StatusNote, s.BookID, s.ID).Scan(&s.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return errors.New("suggestion not found")
+	}
+	return err
+}
+
+// AddVote records a member's vote for a purchase suggestion
+func (sr *suggestionRepository) AddVote(suggestionID, memberID int) error {
+	const query = `
+        INSERT INTO suggestion_votes (suggestion_id, member_id, created_at)
+        VALUES ($1, $2, NOW())
+        ON CONFLICT (suggestion_id, member_id) DO NOTHING
+    `
+
+	result, err := sr.db.Exec(query, suggestionID, memberID)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrAlreadyVoted
+	}
+	return nil
+}
+
+// RemoveVote withdraws a member's vote for a purchase suggestion
+func (sr *suggestionRepository) RemoveVote(suggestionID, memberID int) error {
+	const query = "DELETE FROM suggestion_votes WHERE suggestion_id = $1 AND member_id = $2"
+
+	result, err := sr.db.Exec(query, suggestionID, memberID)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return errors.New("vote not found")
+	}
+	return nil
+}
+
+// querySuggestion runs a query returning at most one purchase suggestion
+func (sr *suggestionRepository) querySuggestion(query string, args ...interface{}) (*models.PurchaseSuggestion, error) {
+	suggestions, err := sr.querySuggestions(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(suggestions) == 0 {
+		return nil, errors.New("suggestion not found")
+	}
+	return &suggestions[0], nil
+}
+
+// querySuggestions runs a query returning purchase suggestions
+func (sr *suggestionRepository) querySuggestions(query string, args ...interface{}) ([]models.PurchaseSuggestion, error) {
+	rows, err := sr.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var suggestions []models.PurchaseSuggestion
+	for rows.Next() {
+		var s models.PurchaseSuggestion
+		if err := rows.Scan(&s.ID, &s.RequesterID, &s.ISBN, &s.Title, &s.Author, &s.Justification, &s.Status, &s.StatusNote, &s.BookID, &s.Votes, &s.Voted, &s.CreatedAt, &s.UpdatedAt); err != nil {
+			return nil, err
+		}
+		suggestions = append(suggestions, s)
+	}
+
+	return suggestions, rows.Err()
+}
@@ -0,0 +1,348 @@
+package repositories
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/utils"
+	"strconv"
+	"time"
+)
+
+// memberSnapshotOmit lists member fields that must never be copied into the change history
+var memberSnapshotOmit = []string{"password"}
+
+// versionRecorder writes change history entries for one kind of record
+type versionRecorder struct {
+	history    HistoryRepository
+	entityType string
+}
+
+// record stores a new version of a record. before is the stored state prior to the change (nil if unknown),
+// after the state once the change is applied. When the record has no history yet, before is saved first as a
+// baseline version so the original data can still be restored. Failures are logged, not returned, because the
+// change itself has already been committed.
+func (vr versionRecorder) record(entityID int, action, changedBy string, before, after map[string]interface{}) {
+	logger := utils.GetLogger().WithField("entity_type", vr.entityType).WithField("entity_id", entityID)
+
+	previous := before
+	latest, err := vr.history.GetLatestVersion(vr.entityType, entityID)
+	if err != nil {
+		logger.WithError(err).Error("failed to read change history")
+		return
+	}
+	if latest != nil {
+		previous = latest.Snapshot
+	} else if before != nil {
+		baseline := &models.RecordVersion{
+			EntityType: vr.entityType,
+			EntityID:   entityID,
+			Action:     models.HistoryActionBaseline,
+			ChangedAt:  time.Now(),
+			Changes:    []utils.FieldChange{},
+			Snapshot:   before,
+		}
+		if err := vr.history.CreateVersion(baseline); err != nil {
+			logger.WithError(err).Error("failed to record baseline version")
+			return
+		}
+	}
+
+	changes := utils.DiffSnapshots(previous, after)
+	if action == models.HistoryActionUpdate && len(changes) == 0 {
+		return
+	}
+
+	version := &models.RecordVersion{
+		EntityType: vr.entityType,
+		EntityID:   entityID,
+		Action:     action,
+		ChangedBy:  changedBy,
+		ChangedAt:  time.Now(),
+		Changes:    changes,
+		Snapshot:   after,
+	}
+	if err := vr.history.CreateVersion(version); err != nil {
+		logger.WithError(err).Error("failed to record version")
+	}
+}
+
+// memberVersions records member versions. It is shared by every repository that writes to the members table,
+// so that profile, membership and household changes show up in the same history as the member record itself.
+type memberVersions struct {
+	members  MemberRepository
+	recorder versionRecorder
+}
+
+func newMemberVersions(members MemberRepository, history HistoryRepository) memberVersions {
+	return memberVersions{members: members, recorder: versionRecorder{history: history, entityType: models.HistoryEntityMember}}
+}
+
+// snapshot reads the stored state of a member, or nil if it cannot be read
+func (mv memberVersions) snapshot(id int) map[string]interface{} {
+	member, err := mv.members.GetMemberByID(id)
+	if err != nil {
+		return nil
+	}
+	snapshot, _ := utils.Snapshot(member, memberSnapshotOmit...)
+	return snapshot
+}
+
+// update runs write and records the fields of member id that it changed
+func (mv memberVersions) update(id int, changedBy string, write func() error) error {
+	before := mv.snapshot(id)
+	if err := write(); err != nil {
+		return err
+	}
+	mv.recorder.record(id, models.HistoryActionUpdate, changedBy, before, mv.snapshot(id))
+	return nil
+}
+
+// markDeleted returns a copy of snapshot with the soft-delete fields set
+func markDeleted(snapshot map[string]interface{}, deletedBy string) map[string]interface{} {
+	deleted := make(map[string]interface{}, len(snapshot)+2)
+	for field, value := range snapshot {
+		deleted[field] = value
+	}
+	deleted["deleted_at"] = time.Now().UTC().Format(time.RFC3339)
+	deleted["deleted_by"] = deletedBy
+	return deleted
+}
+
+// NewVersionedBookRepository wraps a BookRepository so that every create, update, delete and restore
+// is recorded in the change history. The actor is taken from Book.ChangedBy.
+func NewVersionedBookRepository(books BookRepository, history HistoryRepository) BookRepository {
+	return &versionedBookRepository{
+		BookRepository: books,
+		recorder:       versionRecorder{history: history, entityType: models.HistoryEntityBook},
+	}
+}
+
+type versionedBookRepository struct {
+	BookRepository
+	recorder versionRecorder
+}
+
+// snapshot reads the stored state of a book, or nil if it cannot be read
+func (vb *versionedBookRepository) snapshot(id int) map[string]interface{} {
+	book, err := vb.BookRepository.GetBookByID(id)
+	if err != nil {
+		return nil
+	}
+	snapshot, _ := utils.Snapshot(book)
+	return snapshot
+}
+
+// CreateBook creates a book and records its first version
+func (vb *versionedBookRepository) CreateBook(b *models.Book) error {
+	if err := vb.BookRepository.CreateBook(b); err != nil {
+		return err
+	}
+	after, _ := utils.Snapshot(b)
+	vb.recorder.record(b.ID, models.HistoryActionCreate, b.ChangedBy, nil, after)
+	return nil
+}
+
+// CreateBooks creates several books and records the first version of each
+func (vb *versionedBookRepository) CreateBooks(books []models.Book) error {
+	if err := vb.BookRepository.CreateBooks(books); err != nil {
+		return err
+	}
+	for i := range books {
+		after, _ := utils.Snapshot(books[i])
+		vb.recorder.record(books[i].ID, models.HistoryActionCreate, books[i].ChangedBy, nil, after)
+	}
+	return nil
+}
+
+// RecordCreated records the first version of a book that was inserted inside another repository's transaction
+func (vb *versionedBookRepository) RecordCreated(b *models.Book) {
+	vb.BookRepository.RecordCreated(b)
+	after, _ := utils.Snapshot(b)
+	vb.recorder.record(b.ID, models.HistoryActionCreate, b.ChangedBy, nil, after)
+}
+
+// UpdateBook updates a book and records the fields that changed
+func (vb *versionedBookRepository) UpdateBook(b *models.Book) error {
+	before := vb.snapshot(b.ID)
+	if err := vb.BookRepository.UpdateBook(b); err != nil {
+		return err
+	}
+	vb.recorder.record(b.ID, models.HistoryActionUpdate, b.ChangedBy, before, vb.snapshot(b.ID))
+	return nil
+}
+
+// DeleteBook moves a book to the trash and records the deletion
+func (vb *versionedBookRepository) DeleteBook(id int, deletedBy string) error {
+	before := vb.snapshot(id)
+	if err := vb.BookRepository.DeleteBook(id, deletedBy); err != nil {
+		return err
+	}
+	vb.recorder.record(id, models.HistoryActionDelete, deletedBy, before, markDeleted(before, deletedBy))
+	return nil
+}
+
+// RestoreBook moves a book out of the trash and records the restore
+func (vb *versionedBookRepository) RestoreBook(id int, restoredBy string) error {
+	if err := vb.BookRepository.RestoreBook(id, restoredBy); err != nil {
+		return err
+	}
+	vb.recorder.record(id, models.HistoryActionRestore, restoredBy, nil, vb.snapshot(id))
+	return nil
+}
+
+// NewVersionedMemberRepository wraps a MemberRepository so that every create, update, delete and restore
+// is recorded in the change history. The actor is taken from Member.ChangedBy; passwords are never recorded.
+func NewVersionedMemberRepository(members MemberRepository, history HistoryRepository) MemberRepository {
+	return &versionedMemberRepository{
+		MemberRepository: members,
+		memberVersions:   newMemberVersions(members, history),
+	}
+}
+
+type versionedMemberRepository struct {
+	MemberRepository
+	memberVersions
+}
+
+// CreateMember creates a member and records its first version
+func (vm *versionedMemberRepository) CreateMember(m *models.Member) error {
+	if err := vm.MemberRepository.CreateMember(m); err != nil {
+		return err
+	}
+	after, _ := utils.Snapshot(m, memberSnapshotOmit...)
+	vm.recorder.record(m.ID, models.HistoryActionCreate, m.ChangedBy, nil, after)
+	return nil
+}
+
+// UpdateMember updates a member and records the fields that changed
+func (vm *versionedMemberRepository) UpdateMember(m *models.Member) error {
+	return vm.update(m.ID, m.ChangedBy, func() error { return vm.MemberRepository.UpdateMember(m) })
+}
+
+// DeleteMember moves a member to the trash and records the deletion
+func (vm *versionedMemberRepository) DeleteMember(id int, deletedBy string) error {
+	before := vm.snapshot(id)
+	if err := vm.MemberRepository.DeleteMember(id, deletedBy); err != nil {
+		return err
+	}
+	vm.recorder.record(id, models.HistoryActionDelete, deletedBy, before, markDeleted(before, deletedBy))
+	return nil
+}
+
+// RevertMember writes a previous version back and records it as a new version
+func (vm *versionedMemberRepository) RevertMember(m *models.Member) error {
+	return vm.update(m.ID, m.ChangedBy, func() error { return vm.MemberRepository.RevertMember(m) })
+}
+
+// RestoreMember moves a member out of the trash and records the restore
+func (vm *versionedMemberRepository) RestoreMember(id int, restoredBy string) error {
+	if err := vm.MemberRepository.RestoreMember(id, restoredBy); err != nil {
+		return err
+	}
+	vm.recorder.record(id, models.HistoryActionRestore, restoredBy, nil, vm.snapshot(id))
+	return nil
+}
+
+// ImportMembers stores a roster import and records a version for every created, updated and deactivated member
+func (vm *versionedMemberRepository) ImportMembers(creates, updates []models.Member, deactivate []int, changedBy string) error {
+	before := make(map[int]map[string]interface{}, len(updates)+len(deactivate))
+	for _, m := range updates {
+		before[m.ID] = vm.snapshot(m.ID)
+	}
+	for _, id := range deactivate {
+		before[id] = vm.snapshot(id)
+	}
+	if err := vm.MemberRepository.ImportMembers(creates, updates, deactivate, changedBy); err != nil {
+		return err
+	}
+
+	for i := range creates {
+		after, _ := utils.Snapshot(creates[i], memberSnapshotOmit...)
+		vm.recorder.record(creates[i].ID, models.HistoryActionCreate, changedBy, nil, after)
+	}
+	for _, m := range updates {
+		vm.recorder.record(m.ID, models.HistoryActionUpdate, changedBy, before[m.ID], vm.snapshot(m.ID))
+	}
+	for _, id := range deactivate {
+		vm.recorder.record(id, models.HistoryActionUpdate, changedBy, before[id], vm.snapshot(id))
+	}
+	return nil
+}
+
+// NewVersionedProfileRepository wraps a ProfileRepository so that profile and email changes are recorded in the
+// member's change history. The member making the change is recorded as the actor.
+func NewVersionedProfileRepository(profiles ProfileRepository, members MemberRepository, history HistoryRepository) ProfileRepository {
+	return &versionedProfileRepository{ProfileRepository: profiles, memberVersions: newMemberVersions(members, history)}
+}
+
+type versionedProfileRepository struct {
+	ProfileRepository
+	memberVersions
+}
+
+// UpdateProfile updates the profile fields and records the fields that changed
+func (vp *versionedProfileRepository) UpdateProfile(memberID int, p *models.ProfileUpdate) error {
+	return vp.update(memberID, strconv.Itoa(memberID), func() error { return vp.ProfileRepository.UpdateProfile(memberID, p) })
+}
+
+// ConfirmEmailChange applies a verified email address and records the change
+func (vp *versionedProfileRepository) ConfirmEmailChange(memberID int, newEmail string) error {
+	return vp.update(memberID, strconv.Itoa(memberID), func() error { return vp.ProfileRepository.ConfirmEmailChange(memberID, newEmail) })
+}
+
+// NewVersionedMembershipRepository wraps a MembershipRepository so that status changes and renewals are recorded
+// in the member's change history. The actor is taken from Member.ChangedBy and MembershipRenewal.RenewedBy.
+func NewVersionedMembershipRepository(memberships MembershipRepository, members MemberRepository, history HistoryRepository) MembershipRepository {
+	return &versionedMembershipRepository{MembershipRepository: memberships, memberVersions: newMemberVersions(members, history)}
+}
+
+type versionedMembershipRepository struct {
+	MembershipRepository
+	memberVersions
+}
+
+// UpdateStatus stores the membership status and records the change
+func (vm *versionedMembershipRepository) UpdateStatus(m *models.Member) error {
+	return vm.update(m.ID, m.ChangedBy, func() error { return vm.MembershipRepository.UpdateStatus(m) })
+}
+
+// Renew stores a renewal and records the new end of the membership
+func (vm *versionedMembershipRepository) Renew(r *models.MembershipRenewal) error {
+	return vm.update(r.MemberID, r.RenewedBy, func() error { return vm.MembershipRepository.Renew(r) })
+}
+
+// NewVersionedHouseholdRepository wraps a HouseholdRepository so that guardian consent is recorded in the
+// dependent's change history
+func NewVersionedHouseholdRepository(households HouseholdRepository, members MemberRepository, history HistoryRepository) HouseholdRepository {
+	return &versionedHouseholdRepository{HouseholdRepository: households, memberVersions: newMemberVersions(members, history)}
+}
+
+type versionedHouseholdRepository struct {
+	HouseholdRepository
+	memberVersions
+}
+
+// SetConsent stores or clears the guardian consent and records the change
+func (vh *versionedHouseholdRepository) SetConsent(memberID int, consentAt *time.Time, changedBy string) error {
+	return vh.update(memberID, changedBy, func() error { return vh.HouseholdRepository.SetConsent(memberID, consentAt, changedBy) })
+}
+
+// NewVersionedDuplicateRepository wraps a DuplicateRepository so that the duplicate a merge moves to the trash
+// shows up as a delete in its change history
+func NewVersionedDuplicateRepository(duplicates DuplicateRepository, members MemberRepository, history HistoryRepository) DuplicateRepository {
+	return &versionedDuplicateRepository{DuplicateRepository: duplicates, memberVersions: newMemberVersions(members, history)}
+}
+
+type versionedDuplicateRepository struct {
+	DuplicateRepository
+	memberVersions
+}
+
+// MergeMembers merges the duplicate into the survivor and records the deletion of the duplicate
+func (vd *versionedDuplicateRepository) MergeMembers(merge *models.MemberMerge) error {
+	before := vd.snapshot(merge.MergedID)
+	if err := vd.DuplicateRepository.MergeMembers(merge); err != nil {
+		return err
+	}
+	vd.recorder.record(merge.MergedID, models.HistoryActionDelete, merge.MergedBy, before, markDeleted(before, merge.MergedBy))
+	return nil
+}
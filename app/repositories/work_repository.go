@@ -0,0 +1,284 @@
+package repositories
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"database/sql"
+	"errors"
+
+	_ "github.com/lib/pq" // Import driver PostgreSQL
+)
+
+// WorkRepository provides methods for interacting with work and series data in the database
+type WorkRepository interface {
+	GetAllWorks() ([]models.Work, error)
+	GetWorkByID(id int) (*models.Work, error)
+	CreateWork(w *models.Work) error
+	UpdateWork(w *models.Work) error
+	DeleteWork(id int) error
+	GetEditions(workID int) ([]models.Book, error)
+	GetEditionRatings(workID int) ([]models.EditionRating, error)
+
+	GetAllSeries() ([]models.Series, error)
+	GetSeriesByID(id int) (*models.Series, error)
+	CreateSeries(s *models.Series) error
+	UpdateSeries(s *models.Series) error
+	DeleteSeries(id int) error
+	GetWorksBySeriesID(seriesID int) ([]models.Work, error)
+}
+
+// NewWorkRepository creates a new WorkRepository instance
+func NewWorkRepository(db *sql.DB) *workRepository {
+	return &workRepository{db: db}
+}
+
+type workRepository struct {
+	db *sql.DB
+}
+
+// GetAllWorks retrieves all works from the database
+func (wr *workRepository) GetAllWorks() ([]models.Work, error) {
+	const query = `
+        SELECT id, title, author, series_id, series_volume
+        FROM works
+        ORDER BY title
+    `
+
+	return wr.queryWorks(query)
+}
+
+// GetWorkByID retrieves a work by ID from the database
+func (wr *workRepository) GetWorkByID(id int) (*models.Work, error) {
+	const query = `
+        SELECT id, title, author, series_id, series_volume
+        FROM works
+        WHERE id = $1
+    `
+
+	var w models.Work
+	err := wr.db.QueryRow(query, id).Scan(&w.ID, &w.Title, &w.Author, &w.SeriesID, &w.SeriesVolume)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("work not found")
+		}
+		return nil, err
+	}
+
+	return &w, nil
+}
+
+// CreateWork creates a new work in the database
+func (wr *workRepository) CreateWork(w *models.Work) error {
+	const query = `
+        INSERT INTO works (title, author, series_id, series_volume)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id
+    `
+
+	return wr.db.QueryRow(query, w.Title, w.Author, w.SeriesID, w.SeriesVolume).Scan(&w.ID)
+}
+
+// UpdateWork updates a work in the database
+func (wr *workRepository) UpdateWork(w *models.Work) error {
+	const query = `
+        UPDATE works
+        SET title = $1, author = $2, series_id = $3, series_volume = $4
+        WHERE id = $5
+    `
+
+	_, err := wr.db.Exec(query, w.Title, w.Author, w.SeriesID, w.SeriesVolume, w.ID)
+	return err
+}
+
+// DeleteWork deletes a work from the database; its editions are kept but no longer grouped
+func (wr *workRepository) DeleteWork(id int) error {
+	tx, err := wr.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("UPDATE books SET work_id = NULL WHERE work_id = $1", id); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM works WHERE id = $1", id); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetEditions retrieves the books that are editions of a work
+func (wr *workRepository) GetEditions(workID int) ([]models.Book, error) {
+	const query = `
+        SELECT id, title, author, publisher, published_year, isbn, genre, description, cover_image, classification, call_number, work_id, edition, language 
+        FROM books
+        WHERE work_id = $1 AND deleted_at IS NULL
+        ORDER BY published_year, id
+    `
+
+	rows, err := wr.db.Query(query, workID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var books []models.Book
+	for rows.Next() {
+		var b models.Book
+		if err := rows.Scan(&b.ID, &b.Title, &b.Author, &b.Publisher, &b.PublishedYear, &b.ISBN, &b.Genre, &b.Description, &b.CoverImage, &b.Classification, &b.CallNumber, &b.WorkID, &b.Edition, &b.Language); err != nil {
+			return nil, err
+		}
+		books = append(books, b)
+	}
+
+	return books, rows.Err()
+}
+
+// GetEditionRatings retrieves the review count and average rating of every edition of a work
+func (wr *workRepository) GetEditionRatings(workID int) ([]models.EditionRating, error) {
+	const query = `
+        SELECT b.id, b.title, b.edition, b.language, COALESCE(AVG(r.rating), 0), COUNT(r.id)
+        FROM books b
+        LEFT JOIN reviews r ON r.book_id = b.id
+        WHERE b.work_id = $1 AND b.deleted_at IS NULL
+        GROUP BY b.id, b.title, b.edition, b.language
+        ORDER BY b.id
+    `
+
+	rows, err := wr.db.Query(query, workID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ratings []models.EditionRating
+	for rows.Next() {
+		var e models.EditionRating
+		if err := rows.Scan(&e.BookID, &e.Title, &e.Edition, &e.Language, &e.AverageRating, &e.ReviewCount); err != nil {
+			return nil, err
+		}
+		ratings = append(ratings, e)
+	}
+
+	return ratings, rows.Err()
+}
+
+// GetAllSeries retrieves all series from the database
+func (wr *workRepository) GetAllSeries() ([]models.Series, error) {
+	const query = `
+        SELECT id, title, description
+        FROM series
+        ORDER BY title
+    `
+
+	rows, err := wr.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var series []models.Series
+	for rows.Next() {
+		var s models.Series
+		if err := rows.Scan(&s.ID, &s.Title, &s.Description); err != nil {
+			return nil, err
+		}
+		series = append(series, s)
+	}
+
+	return series, rows.Err()
+}
+
+// GetSeriesByID retrieves a series by ID from the database
+func (wr *workRepository) GetSeriesByID(id int) (*models.Series, error) {
+	const query = `
+        SELECT id, title, description
+        FROM series
+        WHERE id = $1
+    `
+
+	var s models.Series
+	err := wr.db.QueryRow(query, id).Scan(&s.ID, &s.Title, &s.Description)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("series not found")
+		}
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// CreateSeries creates a new series in the database
+func (wr *workRepository) CreateSeries(s *models.Series) error {
+	const query = `
+        INSERT INTO series (title, description)
+        VALUES ($1, $2)
+        RETURNING id
+    `
+
+	return wr.db.QueryRow(query, s.Title, s.Description).Scan(&s.ID)
+}
+
+// UpdateSeries updates a series in the database
+func (wr *workRepository) UpdateSeries(s *models.Series) error {
+	const query = `
+        UPDATE series
+        SET title = $1, description = $2
+        WHERE id = $3
+    `
+
+	_, err := wr.db.Exec(query, s.Title, s.Description, s.ID)
+	return err
+}
+
+// DeleteSeries deletes a series from the database; its works are kept but no longer numbered
+func (wr *workRepository) DeleteSeries(id int) error {
+	tx, err := wr.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("UPDATE works SET series_id = NULL, series_volume = '' WHERE series_id = $1", id); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM series WHERE id = $1", id); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetWorksBySeriesID retrieves the works in a series; volume ordering is left to the caller
+func (wr *workRepository) GetWorksBySeriesID(seriesID int) ([]models.Work, error) {
+	const query = `
+        SELECT id, title, author, series_id, series_volume
+        FROM works
+        WHERE series_id = $1
+    `
+
+	return wr.queryWorks(query, seriesID)
+}
+
+// queryWorks runs a query returning work rows
+func (wr *workRepository) queryWorks(query string, args ...interface{}) ([]models.Work, error) {
+	rows, err := wr.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var works []models.Work
+	for rows.Next() {
+		var w models.Work
+		if err := rows.Scan(&w.ID, &w.Title, &w.Author, &w.SeriesID, &w.SeriesVolume); err != nil {
+			return nil, err
+		}
+		works = append(works, w)
+	}
+
+	return works, rows.Err()
+}
@@ -0,0 +1,412 @@
+package services
+
+import (
+	"Restful-Perpustakaan-API/app/common"
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/repositories"
+	"Restful-Perpustakaan-API/app/utils"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// AcquisitionService provides methods for vendors, budget funds, purchase orders and receiving
+type AcquisitionService struct {
+	acquisitionRepository repositories.AcquisitionRepository
+	bookRepository        repositories.BookRepository
+	copyService           *CopyService
+}
+
+// NewAcquisitionService creates a new AcquisitionService instance
+func NewAcquisitionService(acquisitionRepository repositories.AcquisitionRepository, bookRepository repositories.BookRepository, copyService *CopyService) *AcquisitionService {
+	return &AcquisitionService{
+		acquisitionRepository: acquisitionRepository,
+		bookRepository:        bookRepository,
+		copyService:           copyService,
+	}
+}
+
+// GetAllVendors mengambil semua vendor
+func (as *AcquisitionService) GetAllVendors() ([]models.Vendor, error) {
+	vendors, err := as.acquisitionRepository.GetAllVendors()
+	if err != nil {
+		return nil, err
+	}
+	if vendors == nil {
+		vendors = []models.Vendor{}
+	}
+	return vendors, nil
+}
+
+// GetVendorByID mengambil vendor berdasarkan ID
+func (as *AcquisitionService) GetVendorByID(id int) (*models.Vendor, error) {
+	v, err := as.acquisitionRepository.GetVendorByID(id)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	return v, nil
+}
+
+// CreateVendor menambahkan vendor baru
+func (as *AcquisitionService) CreateVendor(v *models.Vendor) error {
+	if err := validateVendor(v); err != nil {
+		return err
+	}
+	return as.acquisitionRepository.CreateVendor(v)
+}
+
+// UpdateVendor memperbarui data vendor
+func (as *AcquisitionService) UpdateVendor(v *models.Vendor) error {
+	existing, err := as.GetVendorByID(v.ID)
+	if err != nil {
+		return err
+	}
+	if err := validateVendor(v); err != nil {
+		return err
+	}
+	v.CreatedAt = existing.CreatedAt
+	return as.acquisitionRepository.UpdateVendor(v)
+}
+
+// DeleteVendor menghapus vendor yang belum pernah menerima pesanan
+func (as *AcquisitionService) DeleteVendor(id int) error {
+	if _, err := as.GetVendorByID(id); err != nil {
+		return err
+	}
+	orders, err := as.acquisitionRepository.CountOrders(id, 0)
+	if err != nil {
+		return err
+	}
+	if orders > 0 {
+		return utils.NewAppError(http.StatusConflict, "vendor has purchase orders and cannot be deleted")
+	}
+	return as.acquisitionRepository.DeleteVendor(id)
+}
+
+// GetFunds mengambil anggaran beserta sisa anggarannya, opsional untuk satu tahun anggaran
+func (as *AcquisitionService) GetFunds(fiscalYear int) ([]models.Fund, error) {
+	funds, err := as.acquisitionRepository.GetFunds(fiscalYear, fiscalYear)
+	if err != nil {
+		return nil, err
+	}
+	if funds == nil {
+		funds = []models.Fund{}
+	}
+	return funds, nil
+}
+
+// GetFundByID mengambil anggaran berdasarkan ID
+func (as *AcquisitionService) GetFundByID(id int) (*models.Fund, error) {
+	f, err := as.acquisitionRepository.GetFundByID(id)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	return f, nil
+}
+
+// CreateFund menambahkan anggaran baru untuk sebuah tahun anggaran
+func (as *AcquisitionService) CreateFund(f *models.Fund) error {
+	if err := validateFund(f); err != nil {
+		return err
+	}
+	return as.acquisitionRepository.CreateFund(f)
+}
+
+// UpdateFund memperbarui anggaran; alokasi tidak boleh lebih kecil dari nilai yang sudah terikat pesanan dan dibelanjakan
+func (as *AcquisitionService) UpdateFund(f *models.Fund) (*models.Fund, error) {
+	existing, err := as.GetFundByID(f.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateFund(f); err != nil {
+		return nil, err
+	}
+	if committed := existing.Encumbered + existing.Expended; f.Allocated < committed {
+		return nil, utils.NewAppError(http.StatusConflict, fmt.Sprintf("allocation cannot be lower than the committed amount of %.2f", committed))
+	}
+	if err := as.acquisitionRepository.UpdateFund(f); err != nil {
+		return nil, err
+	}
+	return as.GetFundByID(f.ID)
+}
+
+// DeleteFund menghapus anggaran yang belum pernah dipakai untuk pesanan
+func (as *AcquisitionService) DeleteFund(id int) error {
+	if _, err := as.GetFundByID(id); err != nil {
+		return err
+	}
+	orders, err := as.acquisitionRepository.CountOrders(0, id)
+	if err != nil {
+		return err
+	}
+	if orders > 0 {
+		return utils.NewAppError(http.StatusConflict, "fund has purchase orders and cannot be deleted")
+	}
+	return as.acquisitionRepository.DeleteFund(id)
+}
+
+// GetOrders mengambil pesanan pembelian tanpa baris-barisnya, opsional difilter berdasarkan status
+func (as *AcquisitionService) GetOrders(status string) ([]models.PurchaseOrder, error) {
+	orders, err := as.acquisitionRepository.GetOrders(status)
+	if err != nil {
+		return nil, err
+	}
+	if orders == nil {
+		orders = []models.PurchaseOrder{}
+	}
+	return orders, nil
+}
+
+// GetOrderByID mengambil pesanan pembelian beserta baris-barisnya
+func (as *AcquisitionService) GetOrderByID(id int) (*models.PurchaseOrder, error) {
+	o, err := as.acquisitionRepository.GetOrderByID(id)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	return o, nil
+}
+
+// CreateOrder membuat pesanan pembelian; nilai pesanan langsung mengikat (encumber) sisa anggaran
+func (as *AcquisitionService) CreateOrder(o *models.PurchaseOrder) error {
+	if _, err := as.acquisitionRepository.GetVendorByID(o.VendorID); err != nil {
+		return utils.NewAppError(http.StatusBadRequest, err.Error())
+	}
+	if _, err := as.acquisitionRepository.GetFundByID(o.FundID); err != nil {
+		return utils.NewAppError(http.StatusBadRequest, err.Error())
+	}
+	if len(o.Lines) == 0 {
+		return utils.NewAppError(http.StatusBadRequest, "order must have at least one line")
+	}
+
+	o.Total = 0
+	for i := range o.Lines {
+		if err := validateOrderLine(&o.Lines[i]); err != nil {
+			return err
+		}
+		o.Total += float64(o.Lines[i].Quantity) * o.Lines[i].UnitPrice
+	}
+	o.Note = strings.TrimSpace(o.Note)
+	o.Status = models.OrderStatusOpen
+	o.OrderedAt = time.Now()
+
+	return fundError(as.acquisitionRepository.CreateOrder(o))
+}
+
+// AddLine menambahkan baris ke pesanan yang masih terbuka atau baru diterima sebagian
+func (as *AcquisitionService) AddLine(orderID int, l *models.PurchaseOrderLine) (*models.PurchaseOrder, error) {
+	o, err := as.GetOrderByID(orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !orderIsOpen(o) {
+		return nil, utils.NewAppError(http.StatusConflict, fmt.Sprintf("cannot add lines to a %s order", o.Status))
+	}
+	if err := validateOrderLine(l); err != nil {
+		return nil, err
+	}
+	if err := fundError(as.acquisitionRepository.AddLine(o, l)); err != nil {
+		return nil, err
+	}
+	return as.GetOrderByID(orderID)
+}
+
+// CancelOrder membatalkan pesanan; sisa yang belum diterima tidak lagi mengikat anggaran,
+// sedangkan yang sudah diterima tetap tercatat sebagai belanja
+func (as *AcquisitionService) CancelOrder(id int) (*models.PurchaseOrder, error) {
+	o, err := as.GetOrderByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !orderIsOpen(o) {
+		return nil, utils.NewAppError(http.StatusConflict, fmt.Sprintf("cannot cancel a %s order", o.Status))
+	}
+	if err := as.acquisitionRepository.UpdateOrderStatus(id, models.OrderStatusCancelled); err != nil {
+		return nil, err
+	}
+	o.Status = models.OrderStatusCancelled
+	return o, nil
+}
+
+// Receive mencatat penerimaan (sebagian) pesanan. Buku yang belum ada di katalog dibuat dari data baris pesanan,
+// lalu satu eksemplar dibuat untuk setiap barcode dan nilainya dicatat sebagai belanja.
+func (as *AcquisitionService) Receive(orderID int, req *models.ReceiveRequest) (*models.PurchaseOrder, error) {
+	o, err := as.GetOrderByID(orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !orderIsOpen(o) {
+		return nil, utils.NewAppError(http.StatusConflict, fmt.Sprintf("cannot receive a %s order", o.Status))
+	}
+	if len(req.Lines) == 0 {
+		return nil, utils.NewAppError(http.StatusBadRequest, "at least one line must be received")
+	}
+
+	lines := make(map[int]*models.PurchaseOrderLine, len(o.Lines))
+	for i := range o.Lines {
+		lines[o.Lines[i].ID] = &o.Lines[i]
+	}
+	seenLines := map[int]bool{}
+	seenBarcodes := map[string]bool{}
+	for i := range req.Lines {
+		received := &req.Lines[i]
+		line, ok := lines[received.LineID]
+		if !ok {
+			return nil, utils.NewAppError(http.StatusBadRequest, fmt.Sprintf("line %d does not belong to this order", received.LineID))
+		}
+		if seenLines[received.LineID] {
+			return nil, utils.NewAppError(http.StatusBadRequest, fmt.Sprintf("line %d is listed more than once", received.LineID))
+		}
+		seenLines[received.LineID] = true
+		if received.Quantity <= 0 || received.Quantity > line.Outstanding() {
+			return nil, utils.NewAppError(http.StatusBadRequest, fmt.Sprintf("quantity for line %d must be between 1 and %d", line.ID, line.Outstanding()))
+		}
+		if len(received.Barcodes) != received.Quantity {
+			return nil, utils.NewAppError(http.StatusBadRequest, fmt.Sprintf("line %d needs one barcode per received copy", line.ID))
+		}
+		for j, barcode := range received.Barcodes {
+			c := &models.BookCopy{Barcode: barcode, LocationID: received.LocationID, Status: models.CopyStatusAvailable}
+			if err := as.copyService.validateCopy(c); err != nil {
+				return nil, err
+			}
+			if seenBarcodes[c.Barcode] {
+				return nil, utils.NewAppError(http.StatusBadRequest, fmt.Sprintf("barcode %s is used more than once", c.Barcode))
+			}
+			seenBarcodes[c.Barcode] = true
+			received.Barcodes[j] = c.Barcode
+		}
+	}
+
+	newBooks := map[string]*models.Book{}
+	for i := range req.Lines {
+		if err := as.catalogueBook(lines[req.Lines[i].LineID], &req.Lines[i], newBooks); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := as.acquisitionRepository.ReceiveLines(orderID, req.Lines, time.Now()); err != nil {
+		if err == repositories.ErrOverReceipt || err == repositories.ErrDuplicateBarcode {
+			return nil, utils.NewAppError(http.StatusConflict, err.Error())
+		}
+		return nil, err
+	}
+	for _, book := range newBooks {
+		as.bookRepository.RecordCreated(book)
+	}
+	return as.GetOrderByID(orderID)
+}
+
+// GetBudgetReport menghasilkan laporan sisa anggaran untuk tahun anggaran fromYear sampai toYear
+func (as *AcquisitionService) GetBudgetReport(fromYear, toYear int) (*models.BudgetReport, error) {
+	if fromYear > toYear {
+		return nil, utils.NewAppError(http.StatusBadRequest, "from_year must not be after to_year")
+	}
+	return budgetReport(as.acquisitionRepository, fromYear, toYear)
+}
+
+// catalogueBook menentukan buku katalog untuk baris yang diterima: buku yang sudah terhubung, buku dengan ISBN yang
+// sama, atau buku baru dari data baris pesanan. Buku baru baru dibuat di dalam transaksi penerimaan dan dihubungkan ke
+// barisnya, sehingga penerimaan yang gagal tidak meninggalkan buku; baris dengan ISBN yang sama memakai satu buku baru.
+func (as *AcquisitionService) catalogueBook(line *models.PurchaseOrderLine, received *models.ReceiveLine, newBooks map[string]*models.Book) error {
+	if line.BookID != nil {
+		received.BookID = *line.BookID
+		return nil
+	}
+	book, err := as.bookRepository.GetBookByISBN(line.ISBN)
+	if err == nil {
+		received.BookID = book.ID
+		return nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	if newBooks[line.ISBN] == nil {
+		newBooks[line.ISBN] = &models.Book{Book: common.Book{Title: line.Title, Author: line.Author, ISBN: line.ISBN}}
+	}
+	received.NewBook = newBooks[line.ISBN]
+	return nil
+}
+
+// budgetReport menjumlahkan alokasi, nilai terikat, belanja dan sisa seluruh anggaran dalam rentang tahun anggaran
+func budgetReport(acquisitionRepository repositories.AcquisitionRepository, fromYear, toYear int) (*models.BudgetReport, error) {
+	funds, err := acquisitionRepository.GetFunds(fromYear, toYear)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.BudgetReport{FromYear: fromYear, ToYear: toYear, Funds: []models.Fund{}}
+	for _, f := range funds {
+		report.Funds = append(report.Funds, f)
+		report.Allocated += f.Allocated
+		report.Encumbered += f.Encumbered
+		report.Expended += f.Expended
+		report.Remaining += f.Remaining
+	}
+	return report, nil
+}
+
+// orderIsOpen mengembalikan true jika pesanan masih dapat diterima, ditambah barisnya, atau dibatalkan
+func orderIsOpen(o *models.PurchaseOrder) bool {
+	return o.Status == models.OrderStatusOpen || o.Status == models.OrderStatusPartiallyReceived
+}
+
+// fundError mengubah error anggaran dari repository menjadi AppError
+func fundError(err error) error {
+	if err == repositories.ErrInsufficientFunds {
+		return utils.NewAppError(http.StatusConflict, err.Error())
+	}
+	return err
+}
+
+// validateVendor memeriksa data vendor
+func validateVendor(v *models.Vendor) error {
+	v.Name = strings.TrimSpace(v.Name)
+	if v.Name == "" {
+		return utils.NewAppError(http.StatusBadRequest, "vendor name is required")
+	}
+	if v.Email != "" && !utils.ValidateEmail(v.Email) {
+		return utils.NewAppError(http.StatusBadRequest, "invalid vendor email")
+	}
+	return nil
+}
+
+// validateFund memeriksa kode, nama, tahun anggaran dan alokasi anggaran
+func validateFund(f *models.Fund) error {
+	f.Code = strings.TrimSpace(f.Code)
+	f.Name = strings.TrimSpace(f.Name)
+	if f.Code == "" || f.Name == "" {
+		return utils.NewAppError(http.StatusBadRequest, "fund code and name are required")
+	}
+	if f.FiscalYear < 1900 || f.FiscalYear > 9999 {
+		return utils.NewAppError(http.StatusBadRequest, "invalid fiscal year")
+	}
+	if f.Allocated < 0 {
+		return utils.NewAppError(http.StatusBadRequest, "allocated amount must not be negative")
+	}
+	return nil
+}
+
+// validateOrderLine memeriksa ISBN, judul, jumlah dan harga baris pesanan
+func validateOrderLine(l *models.PurchaseOrderLine) error {
+	if !utils.ValidateISBN(l.ISBN) {
+		return utils.NewAppError(http.StatusBadRequest, fmt.Sprintf("invalid ISBN %q", l.ISBN))
+	}
+	l.ISBN = utils.NormalizeISBN(l.ISBN)
+	l.Title = strings.TrimSpace(l.Title)
+	l.Author = strings.TrimSpace(l.Author)
+	if l.Title == "" {
+		return utils.NewAppError(http.StatusBadRequest, "line title is required")
+	}
+	if l.Quantity <= 0 {
+		return utils.NewAppError(http.StatusBadRequest, "line quantity must be positive")
+	}
+	if l.UnitPrice < 0 {
+		return utils.NewAppError(http.StatusBadRequest, "line unit price must not be negative")
+	}
+	l.QuantityReceived = 0
+	l.BookID = nil
+	return nil
+}
@@ -9,19 +9,21 @@ import (
 
 // AdminService provides methods for admin operations
 type AdminService struct {
-	userRepository   repositories.UserRepository
-	bookRepository   repositories.BookRepository
-	memberRepository repositories.MemberRepository
-	loanRepository   repositories.LoanRepository
+	userRepository        repositories.UserRepository
+	bookRepository        repositories.BookRepository
+	memberRepository      repositories.MemberRepository
+	loanRepository        repositories.LoanRepository
+	acquisitionRepository repositories.AcquisitionRepository
 }
 
 // NewAdminService creates a new AdminService instance
-func NewAdminService(userRepository repositories.UserRepository, bookRepository repositories.BookRepository, memberRepository repositories.MemberRepository, loanRepository database.LoanRepository) *AdminService {
+func NewAdminService(userRepository repositories.UserRepository, bookRepository repositories.BookRepository, memberRepository repositories.MemberRepository, loanRepository database.LoanRepository, acquisitionRepository repositories.AcquisitionRepository) *AdminService {
 	return &AdminService{
-		userRepository:   userRepository,
-		bookRepository:   bookRepository,
-		memberRepository: memberRepository,
-		loanRepository:   loanRepository,
+		userRepository:        userRepository,
+		bookRepository:        bookRepository,
+		memberRepository:      memberRepository,
+		loanRepository:        loanRepository,
+		acquisitionRepository: acquisitionRepository,
 	}
 }
 
@@ -71,6 +73,9 @@ func (as *AdminService) GenerateReports(reportType string, startDate, endDate ti
 	case "book_report":
 		// ... logika untuk menghasilkan laporan buku
 		// Anda bisa menggunakan as.bookRepository untuk mengambil data buku yang diperlukan
+	case "budget_report":
+		// Sisa anggaran pengadaan untuk tahun anggaran startDate sampai endDate
+		return budgetReport(as.acquisitionRepository, startDate.Year(), endDate.Year())
 	// ... tambahkan jenis laporan lain sesuai kebutuhan
 	default:
 		return nil, errors.New("invalid report type")
@@ -93,12 +98,9 @@ func (as *AdminService) UpdateBook(updatedBook *models.Book) error {
 	return as.bookRepository.UpdateBook(updatedBook)
 }
 
-// DeleteBook menghapus buku dari perpustakaan
-func (as *AdminService) DeleteBook(bookID int) error {
-	// Anda mungkin ingin menambahkan logika untuk menangani peminjaman yang terkait dengan buku ini sebelum menghapusnya
-	// ...
-
-	return as.bookRepository.DeleteBook(bookID)
+// DeleteBook memindahkan buku ke trash; buku yang masih dipinjam tidak dapat dihapus
+func (as *AdminService) DeleteBook(bookID int, deletedBy string) error {
+	return softDeleteBook(as.bookRepository, &as.loanRepository, bookID, deletedBy)
 }
 
 // GetMemberLoans mengambil riwayat peminjaman seorang anggota
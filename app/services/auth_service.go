@@ -3,6 +3,8 @@ package services
 import (
 	"Restful-Perpustakaan-API/app/models"
 	"Restful-Perpustakaan-API/app/repositories"
+	"Restful-Perpustakaan-API/app/utils"
+	"database/sql"
 	"errors"
 	"strconv"
 	"time"
@@ -44,6 +46,11 @@ func (as *AuthService) Login(credentials models.Credentials) (string, error) {
 		return "", errors.New("invalid email or password")
 	}
 
+	// Catat login terakhir untuk laporan anggota tidak aktif; kegagalan tidak menggagalkan login
+	if err := as.memberRepository.RecordLogin(member.ID, time.Now()); err != nil {
+		utils.GetLogger().WithError(err).WithField("member", member.ID).Warn("failed to record member login")
+	}
+
 	// Buat token JWT
 	expirationTime := time.Now().Add(24 * time.Hour)
 	claims := &jwt.StandardClaims{
@@ -60,6 +67,22 @@ func (as *AuthService) Login(credentials models.Credentials) (string, error) {
 	return tokenString, nil
 }
 
+// IsAdmin mengembalikan true jika subject token JWT adalah anggota dengan peran admin
+func (as *AuthService) IsAdmin(subject string) (bool, error) {
+	memberID, err := strconv.Atoi(subject)
+	if err != nil {
+		return false, nil
+	}
+	member, err := as.memberRepository.GetMemberByID(memberID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return member.Role == models.RoleAdmin, nil
+}
+
 // Register mendaftarkan anggota baru dan mengembalikan data anggota jika berhasil
 func (as *AuthService) Register(newMember *models.Member) error {
 	// Validasi data anggota baru
@@ -83,6 +106,12 @@ func (as *AuthService) Register(newMember *models.Member) error {
 	}
 	newMember.Password = string(hashedPassword)
 
+	// Pendaftaran mandiri selalu berperan anggota biasa dan menunggu verifikasi pustakawan sebelum dapat meminjam
+	newMember.Role = models.RoleMember
+	newMember.MembershipStatus = models.MembershipStatusPending
+	newMember.MembershipStart = nil
+	newMember.MembershipEnd = nil
+
 	// Simpan anggota baru ke database
 	err = as.memberRepository.CreateMember(newMember)
 	if err != nil {
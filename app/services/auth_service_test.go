@@ -0,0 +1,92 @@
+package services
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/repositories"
+	"database/sql"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// fakeMemberRepository menyimpan anggota di memori untuk menguji AuthService tanpa database
+type fakeMemberRepository struct {
+	repositories.MemberRepository
+	members map[string]*models.Member
+	logins  map[int]time.Time
+}
+
+func newFakeMemberRepository() *fakeMemberRepository {
+	return &fakeMemberRepository{members: map[string]*models.Member{}, logins: map[int]time.Time{}}
+}
+
+func (f *fakeMemberRepository) GetMemberByEmail(email string) (*models.Member, error) {
+	member, ok := f.members[email]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	found := *member
+	return &found, nil
+}
+
+func (f *fakeMemberRepository) CreateMember(m *models.Member) error {
+	m.ID = len(f.members) + 1
+	stored := *m
+	f.members[m.Email] = &stored
+	return nil
+}
+
+func (f *fakeMemberRepository) RecordLogin(id int, at time.Time) error {
+	f.logins[id] = at
+	return nil
+}
+
+func TestLogin(t *testing.T) {
+	secret := []byte("test-secret")
+	repo := newFakeMemberRepository()
+	auth := NewAuthService(repo, secret)
+
+	member := &models.Member{Name: "Ani", Email: "ani@example.com", Password: "rahasia123"}
+	if err := auth.Register(member); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		email    string
+		password string
+		wantErr  bool
+	}{
+		{"correct password", "ani@example.com", "rahasia123", false},
+		{"wrong password", "ani@example.com", "salah", true},
+		{"unknown email", "budi@example.com", "rahasia123", true},
+		{"missing password", "ani@example.com", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := auth.Login(models.Credentials{Email: tt.email, Password: tt.password})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Login succeeded, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Login: %v", err)
+			}
+
+			claims := &jwt.StandardClaims{}
+			if _, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) { return secret, nil }); err != nil {
+				t.Fatalf("token does not verify: %v", err)
+			}
+			if claims.Subject != strconv.Itoa(member.ID) {
+				t.Errorf("subject = %q, want %d", claims.Subject, member.ID)
+			}
+			if _, ok := repo.logins[member.ID]; !ok {
+				t.Errorf("login of member %d was not recorded", member.ID)
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/repositories"
+	"Restful-Perpustakaan-API/app/utils"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// BlockService provides methods for blocking members from borrowing. Staff place manual blocks with a reason;
+// fine blocks are placed automatically when a member's fine balance reaches the threshold and lifted again
+// once it drops below it.
+type BlockService struct {
+	blockRepository        repositories.BlockRepository
+	memberRepository       repositories.MemberRepository
+	notificationRepository *repositories.NotificationRepository
+	fineThreshold          float64 // saldo denda yang memblokir peminjaman; 0 menonaktifkan blokir otomatis
+}
+
+// NewBlockService creates a new BlockService instance
+func NewBlockService(blockRepository repositories.BlockRepository, memberRepository repositories.MemberRepository, notificationRepository *repositories.NotificationRepository, fineThreshold float64) *BlockService {
+	return &BlockService{
+		blockRepository:        blockRepository,
+		memberRepository:       memberRepository,
+		notificationRepository: notificationRepository,
+		fineThreshold:          fineThreshold,
+	}
+}
+
+// GetBlocks mengambil semua blokir anggota, termasuk yang sudah dicabut atau berakhir
+func (bs *BlockService) GetBlocks(memberID int) ([]models.MemberBlock, error) {
+	if _, err := bs.memberRepository.GetMemberByID(memberID); err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, "member not found")
+	}
+	blocks, err := bs.blockRepository.GetBlocksByMemberID(memberID)
+	if err != nil {
+		return nil, err
+	}
+	if blocks == nil {
+		blocks = []models.MemberBlock{}
+	}
+	return blocks, nil
+}
+
+// GetActiveBlocks mengambil semua blokir yang sedang berlaku
+func (bs *BlockService) GetActiveBlocks() ([]models.MemberBlock, error) {
+	blocks, err := bs.blockRepository.GetActiveBlocks(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if blocks == nil {
+		blocks = []models.MemberBlock{}
+	}
+	return blocks, nil
+}
+
+// GetMemberActiveBlocks mengambil blokir yang sedang berlaku untuk anggota tanpa mengubah apa pun; blokir denda
+// diperbarui oleh CheckBorrowing dan oleh job berkala
+func (bs *BlockService) GetMemberActiveBlocks(memberID int) ([]models.MemberBlock, error) {
+	blocks, err := bs.blockRepository.GetBlocksByMemberID(memberID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	active := []models.MemberBlock{}
+	for _, block := range blocks {
+		if block.ActiveAt(now) {
+			active = append(active, block)
+		}
+	}
+	return active, nil
+}
+
+// CreateBlock memblokir anggota secara manual dengan jenis dan alasan; tanpa starts_at blokir langsung berlaku
+// dan tanpa ends_at berlaku sampai dicabut. Anggota menerima notifikasi berisi alasannya.
+func (bs *BlockService) CreateBlock(block *models.MemberBlock) error {
+	if block.CreatedBy == "" {
+		return utils.NewAppError(http.StatusUnauthorized, "created_by is required")
+	}
+	if !containsString(models.ManualBlockTypes, block.Type) {
+		return utils.NewAppError(http.StatusBadRequest, "type must be one of "+strings.Join(models.ManualBlockTypes, ", "))
+	}
+	block.Reason = strings.TrimSpace(block.Reason)
+	if block.Reason == "" {
+		return utils.NewAppError(http.StatusBadRequest, "reason is required")
+	}
+	if _, err := bs.memberRepository.GetMemberByID(block.MemberID); err != nil {
+		return utils.NewAppError(http.StatusNotFound, "member not found")
+	}
+
+	now := time.Now()
+	if block.StartsAt.IsZero() {
+		block.StartsAt = now
+	}
+	if block.EndsAt != nil && !block.EndsAt.After(block.StartsAt) {
+		return utils.NewAppError(http.StatusBadRequest, "ends_at must be after starts_at")
+	}
+	block.Automatic = false
+	block.CreatedAt = now
+	if err := bs.blockRepository.CreateBlock(block); err != nil {
+		return err
+	}
+
+	message := "You cannot borrow, renew or place holds: " + block.Message()
+	if block.StartsAt.After(now) {
+		message = fmt.Sprintf("From %s you cannot borrow, renew or place holds: %s", block.StartsAt.Format("2006-01-02"), block.Message())
+	}
+	bs.notify(block.MemberID, message)
+	return nil
+}
+
+// LiftBlock mencabut blokir manual sebelum berakhir; blokir otomatis dicabut sendiri saat penyebabnya selesai
+func (bs *BlockService) LiftBlock(id int, liftedBy, note string) (*models.MemberBlock, error) {
+	if liftedBy == "" {
+		return nil, utils.NewAppError(http.StatusUnauthorized, "lifted_by is required")
+	}
+	block, err := bs.blockRepository.GetBlockByID(id)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	if block.LiftedAt != nil {
+		return nil, utils.NewAppError(http.StatusConflict, "block has already been lifted")
+	}
+	if block.Automatic {
+		return nil, utils.NewAppError(http.StatusConflict, "automatic blocks are lifted when their cause clears")
+	}
+
+	now := time.Now()
+	block.LiftedAt = &now
+	block.LiftedBy = liftedBy
+	block.LiftNote = strings.TrimSpace(note)
+	if err := bs.blockRepository.LiftBlock(block); err != nil {
+		return nil, utils.NewAppError(http.StatusConflict, err.Error())
+	}
+	if block.EndsAt == nil || block.EndsAt.After(now) {
+		bs.notify(block.MemberID, "A block on your account has been lifted: "+block.Reason)
+	}
+	return block, nil
+}
+
+// CheckBorrowing memastikan anggota tidak sedang diblokir sebelum meminjam, memperpanjang atau memesan buku;
+// semua alasan blokir yang berlaku dikembalikan dalam pesan error. Blokir denda anggota diperbarui dulu sehingga
+// anggota yang baru melunasi denda bisa langsung meminjam.
+func (bs *BlockService) CheckBorrowing(memberID int) error {
+	if _, _, err := bs.refreshFineBlocks(memberID); err != nil {
+		return err
+	}
+	blocks, err := bs.GetMemberActiveBlocks(memberID)
+	if err != nil {
+		return err
+	}
+	if len(blocks) == 0 {
+		return nil
+	}
+	reasons := make([]string, len(blocks))
+	for i := range blocks {
+		reasons[i] = blocks[i].Message()
+	}
+	return utils.NewAppError(http.StatusForbidden, "borrowing is blocked: "+strings.Join(reasons, "; "))
+}
+
+// RefreshFineBlocks memasang dan mencabut blokir denda otomatis untuk semua anggota
+func (bs *BlockService) RefreshFineBlocks() (*models.BlockJobResult, error) {
+	placed, lifted, err := bs.refreshFineBlocks(0)
+	if err != nil {
+		return nil, err
+	}
+	return &models.BlockJobResult{Placed: placed, Lifted: lifted}, nil
+}
+
+// StartFineBlockJob menjalankan RefreshFineBlocks secara berkala di latar belakang
+func (bs *BlockService) StartFineBlockJob(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			result, err := bs.RefreshFineBlocks()
+			if err != nil {
+				utils.GetLogger().WithError(err).Error("failed to refresh fine blocks")
+				continue
+			}
+			if result.Placed > 0 || result.Lifted > 0 {
+				utils.GetLogger().WithField("placed", result.Placed).WithField("lifted", result.Lifted).Info("fine blocks refreshed")
+			}
+		}
+	}()
+}
+
+// refreshFineBlocks memperbarui blokir denda otomatis untuk satu anggota (atau semua jika memberID 0) dan
+// memberi tahu anggota yang terdampak. Jika blokir denda dinonaktifkan, blokir yang masih ada dicabut.
+func (bs *BlockService) refreshFineBlocks(memberID int) (int, int, error) {
+	now := time.Now()
+	threshold := bs.fineThreshold
+	if threshold <= 0 {
+		threshold = math.MaxFloat64
+	}
+
+	lifted, err := bs.blockRepository.LiftFineBlocks(threshold, memberID, "fine balance is below the limit", now)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, id := range lifted {
+		bs.notify(id, "Your fines are below the limit again; you can borrow, renew and place holds.")
+	}
+	if bs.fineThreshold <= 0 {
+		return 0, len(lifted), nil
+	}
+
+	reason := fmt.Sprintf("outstanding fines of %.2f or more; pay your fines to borrow again", bs.fineThreshold)
+	placed, err := bs.blockRepository.CreateFineBlocks(bs.fineThreshold, memberID, reason, now)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, id := range placed {
+		bs.notify(id, "You cannot borrow, renew or place holds: "+reason)
+	}
+	return len(placed), len(lifted), nil
+}
+
+// notify mengirim notifikasi blokir kepada anggota; kegagalan hanya dicatat
+func (bs *BlockService) notify(memberID int, message string) {
+	n := &models.Notification{UserID: memberID, Message: message}
+	if err := bs.notificationRepository.CreateNotification(n); err != nil {
+		utils.GetLogger().WithError(err).WithField("member", memberID).Warn("failed to send block notification")
+	}
+}
@@ -0,0 +1,330 @@
+package services
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/utils"
+	"bufio"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// bookImportFields memetakan nama field impor ke setter pada models.Book
+var bookImportFields = map[string]func(b *models.Book, value string) error{
+	"title":          func(b *models.Book, value string) error { b.Title = value; return nil },
+	"author":         func(b *models.Book, value string) error { b.Author = value; return nil },
+	"publisher":      func(b *models.Book, value string) error { b.Publisher = value; return nil },
+	"isbn":           func(b *models.Book, value string) error { b.ISBN = value; return nil },
+	"genre":          func(b *models.Book, value string) error { b.Genre = value; return nil },
+	"description":    func(b *models.Book, value string) error { b.Description = value; return nil },
+	"cover_image":    func(b *models.Book, value string) error { b.CoverImage = value; return nil },
+	"classification": func(b *models.Book, value string) error { b.Classification = value; return nil },
+	"call_number":    func(b *models.Book, value string) error { b.CallNumber = value; return nil },
+	"edition":        func(b *models.Book, value string) error { b.Edition = value; return nil },
+	"language":       func(b *models.Book, value string) error { b.Language = value; return nil },
+	"published_year": func(b *models.Book, value string) error {
+		return parseImportYear(value, &b.PublishedYear)
+	},
+}
+
+// bookExportColumns adalah urutan kolom pada ekspor CSV. Selain id, setiap kolom adalah field impor, sehingga
+// file hasil ekspor dapat diimpor kembali; kolom id diabaikan saat impor.
+var bookExportColumns = []string{"id", "title", "author", "publisher", "published_year", "isbn", "genre", "description", "cover_image", "classification", "call_number", "edition", "language"}
+
+// importRow adalah satu baris data mentah dari file impor
+type importRow struct {
+	number int
+	values map[string]string
+	err    error
+}
+
+// ImportBooks mengimpor banyak buku sekaligus dari file CSV atau JSONL
+func (bs *BookService) ImportBooks(r io.Reader, opts models.BookImportOptions) (*models.BookImportResult, error) {
+	if opts.Mode == "" {
+		opts.Mode = models.ImportModeTransactional
+	}
+	if opts.Mode != models.ImportModeTransactional && opts.Mode != models.ImportModeBestEffort {
+		return nil, utils.NewAppError(http.StatusBadRequest, "invalid import mode")
+	}
+	for column, field := range opts.Mapping {
+		if _, ok := bookImportFields[field]; field != "" && !ok {
+			return nil, utils.NewAppError(http.StatusBadRequest, fmt.Sprintf("unknown book field %q mapped from column %q", field, column))
+		}
+	}
+
+	rows, err := readImportRows(r, opts.Format)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusBadRequest, err.Error())
+	}
+
+	result := &models.BookImportResult{
+		DryRun:    opts.DryRun,
+		Mode:      opts.Mode,
+		TotalRows: len(rows),
+		Errors:    []models.BookImportRowError{},
+	}
+
+	var validBooks []models.Book
+	var validRows []int
+	seenISBN := make(map[string]int)
+	for _, row := range rows {
+		if row.err != nil {
+			result.Errors = append(result.Errors, models.BookImportRowError{Row: row.number, Message: row.err.Error()})
+			result.Failed++
+			continue
+		}
+
+		book, rowErrors := buildImportedBook(row, opts.Mapping)
+		if book.ISBN != "" && len(rowErrors) == 0 {
+			if firstRow, ok := seenISBN[book.ISBN]; ok {
+				rowErrors = append(rowErrors, models.BookImportRowError{Row: row.number, Field: "isbn", Message: fmt.Sprintf("duplicate ISBN, already used in row %d", firstRow)})
+			} else if existing, err := bs.bookRepository.GetBookByISBN(book.ISBN); err == nil {
+				rowErrors = append(rowErrors, models.BookImportRowError{Row: row.number, Field: "isbn", Message: fmt.Sprintf("ISBN already exists in catalog (book %d)", existing.ID)})
+			} else if !errors.Is(err, sql.ErrNoRows) {
+				return nil, err
+			}
+			if _, ok := seenISBN[book.ISBN]; !ok {
+				seenISBN[book.ISBN] = row.number
+			}
+		}
+
+		if len(rowErrors) > 0 {
+			result.Errors = append(result.Errors, rowErrors...)
+			result.Failed++
+			continue
+		}
+		validBooks = append(validBooks, *book)
+		validRows = append(validRows, row.number)
+	}
+	result.Valid = len(validBooks)
+
+	if opts.DryRun || len(validBooks) == 0 {
+		return result, nil
+	}
+
+	switch opts.Mode {
+	case models.ImportModeTransactional:
+		// Satu baris gagal membatalkan seluruh impor
+		if result.Failed > 0 {
+			return result, nil
+		}
+		if err := bs.bookRepository.CreateBooks(validBooks); err != nil {
+			return nil, err
+		}
+		result.Imported = len(validBooks)
+	case models.ImportModeBestEffort:
+		for i := range validBooks {
+			if err := bs.bookRepository.CreateBook(&validBooks[i]); err != nil {
+				result.Errors = append(result.Errors, models.BookImportRowError{Row: validRows[i], Message: err.Error()})
+				result.Failed++
+				continue
+			}
+			result.Imported++
+		}
+	}
+
+	return result, nil
+}
+
+// ExportBooks menulis seluruh katalog ke w dalam format CSV atau JSONL secara streaming
+func (bs *BookService) ExportBooks(w io.Writer, format string) error {
+	switch format {
+	case models.BookFormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(bookExportColumns); err != nil {
+			return err
+		}
+		err := bs.bookRepository.StreamBooks(func(b models.Book) error {
+			return cw.Write([]string{
+				strconv.Itoa(b.ID),
+				b.Title,
+				b.Author,
+				b.Publisher,
+				strconv.Itoa(b.PublishedYear),
+				b.ISBN,
+				b.Genre,
+				b.Description,
+				b.CoverImage,
+				b.Classification,
+				b.CallNumber,
+				b.Edition,
+				b.Language,
+			})
+		})
+		if err != nil {
+			return err
+		}
+		cw.Flush()
+		return cw.Error()
+	case models.BookFormatJSONL:
+		enc := json.NewEncoder(w)
+		return bs.bookRepository.StreamBooks(func(b models.Book) error {
+			return enc.Encode(b)
+		})
+	default:
+		return errors.New("unsupported export format")
+	}
+}
+
+// readImportRows membaca seluruh baris dari file impor sesuai formatnya
+func readImportRows(r io.Reader, format string) ([]importRow, error) {
+	switch format {
+	case models.BookFormatCSV:
+		return readCSVRows(r)
+	case models.BookFormatJSONL:
+		return readJSONLRows(r)
+	default:
+		return nil, errors.New("unsupported import format")
+	}
+}
+
+// readCSVRows membaca file CSV dengan baris pertama sebagai header
+func readCSVRows(r io.Reader) ([]importRow, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, errors.New("CSV file is empty")
+		}
+		return nil, err
+	}
+
+	var rows []importRow
+	for number := 1; ; number++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		row := importRow{number: number}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, err
+			}
+			row.err = parseErr.Err
+			rows = append(rows, row)
+			continue
+		}
+		if len(record) != len(header) {
+			row.err = fmt.Errorf("expected %d columns, got %d", len(header), len(record))
+			rows = append(rows, row)
+			continue
+		}
+		row.values = make(map[string]string, len(header))
+		for i, column := range header {
+			row.values[column] = record[i]
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// readJSONLRows membaca file JSON Lines, satu objek JSON per baris
+func readJSONLRows(r io.Reader) ([]importRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var rows []importRow
+	number := 0
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		number++
+		row := importRow{number: number}
+
+		var object map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &object); err != nil {
+			row.err = fmt.Errorf("invalid JSON: %v", err)
+			rows = append(rows, row)
+			continue
+		}
+		row.values = make(map[string]string, len(object))
+		for key, value := range object {
+			switch v := value.(type) {
+			case nil:
+				row.values[key] = ""
+			case string:
+				row.values[key] = v
+			case float64:
+				row.values[key] = strconv.FormatFloat(v, 'f', -1, 64)
+			default:
+				row.values[key] = fmt.Sprint(v)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, scanner.Err()
+}
+
+// buildImportedBook membangun models.Book dari satu baris impor dan mengembalikan error validasinya
+func buildImportedBook(row importRow, mapping map[string]string) (*models.Book, []models.BookImportRowError) {
+	columns := make([]string, 0, len(row.values))
+	for column := range row.values {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	var book models.Book
+	var rowErrors []models.BookImportRowError
+	for _, column := range columns {
+		field := resolveImportField(column, mapping)
+		if field == "" {
+			continue
+		}
+		if err := bookImportFields[field](&book, strings.TrimSpace(row.values[column])); err != nil {
+			rowErrors = append(rowErrors, models.BookImportRowError{Row: row.number, Field: field, Message: err.Error()})
+		}
+	}
+
+	if book.Title == "" {
+		rowErrors = append(rowErrors, models.BookImportRowError{Row: row.number, Field: "title", Message: "title is required"})
+	}
+	if book.ISBN != "" {
+		if utils.ValidateISBN(book.ISBN) {
+			book.ISBN = utils.NormalizeISBN(book.ISBN)
+		} else {
+			rowErrors = append(rowErrors, models.BookImportRowError{Row: row.number, Field: "isbn", Message: "invalid ISBN"})
+		}
+	}
+
+	return &book, rowErrors
+}
+
+// resolveImportField menentukan field buku untuk sebuah kolom, memakai mapping jika ada
+func resolveImportField(column string, mapping map[string]string) string {
+	if field, ok := mapping[column]; ok {
+		return field
+	}
+	field := strings.ToLower(strings.TrimSpace(column))
+	if _, ok := bookImportFields[field]; ok {
+		return field
+	}
+	return ""
+}
+
+// parseImportYear mengurai tahun dari nilai teks; nilai kosong dibiarkan nol
+func parseImportYear(value string, year *int) error {
+	if value == "" {
+		return nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 || parsed > 9999 {
+		return fmt.Errorf("invalid year %q", value)
+	}
+	*year = parsed
+	return nil
+}
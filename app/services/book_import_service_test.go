@@ -0,0 +1,115 @@
+package services
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/repositories"
+	"bytes"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// fakeBookRepository menyimpan buku di memori untuk menguji impor dan ekspor katalog tanpa database
+type fakeBookRepository struct {
+	repositories.BookRepository
+	books     []models.Book
+	lookupErr error
+}
+
+func (f *fakeBookRepository) StreamBooks(fn func(b models.Book) error) error {
+	for _, b := range f.books {
+		if err := fn(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fakeBookRepository) GetBookByISBN(isbn string) (*models.Book, error) {
+	if f.lookupErr != nil {
+		return nil, f.lookupErr
+	}
+	for i := range f.books {
+		if f.books[i].ISBN == isbn {
+			found := f.books[i]
+			return &found, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func testBook(id int, title, isbn string) models.Book {
+	var b models.Book
+	b.ID = id
+	b.Title = title
+	b.Author = "Andrea Hirata"
+	b.Publisher = "Bentang Pustaka"
+	b.PublishedYear = 2005
+	b.ISBN = isbn
+	b.Genre = "Novel"
+	b.Description = "Kisah sepuluh anak, \"Laskar Pelangi\", di Belitung"
+	b.Classification = "899.2213"
+	b.CallNumber = "899.2213 HIR l"
+	b.Edition = "Cetakan ke-5"
+	b.Language = "ind"
+	return b
+}
+
+func TestExportedBooksReimport(t *testing.T) {
+	exported := []models.Book{testBook(1, "Laskar Pelangi", "9789793062792"), testBook(2, "Sang Pemimpi", "9789791227025")}
+
+	for _, format := range []string{models.BookFormatCSV, models.BookFormatJSONL} {
+		t.Run(format, func(t *testing.T) {
+			var file bytes.Buffer
+			if err := NewBookService(&fakeBookRepository{books: exported}, nil).ExportBooks(&file, format); err != nil {
+				t.Fatalf("ExportBooks: %v", err)
+			}
+
+			// Katalog tujuan masih kosong, jadi setiap baris hasil ekspor harus valid
+			result, err := NewBookService(&fakeBookRepository{}, nil).ImportBooks(&file, models.BookImportOptions{Format: format, DryRun: true})
+			if err != nil {
+				t.Fatalf("ImportBooks: %v", err)
+			}
+			if result.Valid != len(exported) || result.Failed != 0 {
+				t.Fatalf("valid = %d, failed = %d, errors = %+v; want %d valid rows", result.Valid, result.Failed, result.Errors, len(exported))
+			}
+		})
+	}
+}
+
+func TestExportColumnsAreImportFields(t *testing.T) {
+	for _, column := range bookExportColumns[1:] {
+		if resolveImportField(column, nil) != column {
+			t.Errorf("exported column %q is not an import field", column)
+		}
+	}
+}
+
+func TestImportBooksRejectsDuplicateISBNs(t *testing.T) {
+	catalog := &fakeBookRepository{books: []models.Book{testBook(7, "Laskar Pelangi", "9789793062792")}}
+	file := bytes.NewBufferString("title,isbn\nLaskar Pelangi,978-979-3062-79-2\nSang Pemimpi,9789791227025\nSang Pemimpi,9789791227025\nSang Pemimpi,978-979-1227-02-5\n")
+
+	result, err := NewBookService(catalog, nil).ImportBooks(file, models.BookImportOptions{Format: models.BookFormatCSV, DryRun: true})
+	if err != nil {
+		t.Fatalf("ImportBooks: %v", err)
+	}
+	want := []models.BookImportRowError{
+		{Row: 1, Field: "isbn", Message: "ISBN already exists in catalog (book 7)"},
+		{Row: 3, Field: "isbn", Message: "duplicate ISBN, already used in row 2"},
+		{Row: 4, Field: "isbn", Message: "duplicate ISBN, already used in row 2"},
+	}
+	if result.Valid != 1 || !reflect.DeepEqual(result.Errors, want) {
+		t.Errorf("valid = %d, errors = %+v; want 1 valid row and %+v", result.Valid, result.Errors, want)
+	}
+}
+
+func TestImportBooksReturnsLookupErrors(t *testing.T) {
+	lookupErr := errors.New("connection refused")
+	catalog := &fakeBookRepository{lookupErr: lookupErr}
+	file := bytes.NewBufferString("title,isbn\nLaskar Pelangi,9789793062792\n")
+
+	if _, err := NewBookService(catalog, nil).ImportBooks(file, models.BookImportOptions{Format: models.BookFormatCSV, DryRun: true}); !errors.Is(err, lookupErr) {
+		t.Errorf("ImportBooks() err = %v, want %v", err, lookupErr)
+	}
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/utils"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ImportMarc mengimpor record MARC 21 atau MARCXML sebagai buku baru
+func (bs *BookService) ImportMarc(r io.Reader, format string, dryRun bool) (*models.MarcImportResult, error) {
+	var records []utils.MarcRecord
+	var err error
+	switch format {
+	case models.BookFormatMARC:
+		records, err = utils.ReadMarc21(r)
+	case models.BookFormatMARCXML:
+		records, err = utils.ReadMarcXML(r)
+	default:
+		return nil, utils.NewAppError(http.StatusBadRequest, "unsupported MARC format")
+	}
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusBadRequest, err.Error())
+	}
+
+	result := &models.MarcImportResult{
+		DryRun:       dryRun,
+		TotalRecords: len(records),
+		Records:      make([]models.MarcImportRecord, 0, len(records)),
+	}
+	seenISBN := make(map[string]int)
+	for i, record := range records {
+		book, unmapped := utils.MarcToBook(record)
+		item := models.MarcImportRecord{
+			Index:    i + 1,
+			Title:    book.Title,
+			ISBN:     book.ISBN,
+			Unmapped: unmapped,
+		}
+		if item.Unmapped == nil {
+			item.Unmapped = []string{}
+		}
+
+		switch {
+		case book.Title == "":
+			item.Error = "record has no title (245$a)"
+		case book.ISBN != "" && !utils.ValidateISBN(book.ISBN):
+			item.Error = "invalid ISBN"
+		case book.ISBN != "":
+			if first, ok := seenISBN[book.ISBN]; ok {
+				item.Error = fmt.Sprintf("duplicate ISBN, already used in record %d", first)
+				break
+			}
+			seenISBN[book.ISBN] = item.Index
+			if existing, err := bs.bookRepository.GetBookByISBN(book.ISBN); err == nil {
+				item.Error = fmt.Sprintf("ISBN already exists in catalog (book %d)", existing.ID)
+			} else if !errors.Is(err, sql.ErrNoRows) {
+				return nil, err
+			}
+		}
+
+		if item.Error == "" && !dryRun {
+			b := models.Book{Book: book}
+			if err := bs.bookRepository.CreateBook(&b); err != nil {
+				item.Error = err.Error()
+			} else {
+				item.BookID = b.ID
+				result.Imported++
+			}
+		}
+		if item.Error != "" {
+			result.Failed++
+		}
+		result.Records = append(result.Records, item)
+	}
+
+	return result, nil
+}
+
+// ExportMarc menulis satu buku sebagai record MARC 21 atau MARCXML
+func (bs *BookService) ExportMarc(w io.Writer, format string, book *models.Book) error {
+	switch format {
+	case models.BookFormatMARC:
+		return utils.WriteMarc21(w, utils.BookToMarc(book.Book))
+	case models.BookFormatMARCXML:
+		mw := utils.NewMarcXMLWriter(w)
+		if err := mw.Write(utils.BookToMarc(book.Book)); err != nil {
+			return err
+		}
+		return mw.Close()
+	default:
+		return errors.New("unsupported MARC format")
+	}
+}
+
+// ExportCatalogMarc menulis seluruh katalog sebagai MARC 21 atau MARCXML secara streaming
+func (bs *BookService) ExportCatalogMarc(w io.Writer, format string) error {
+	switch format {
+	case models.BookFormatMARC:
+		return bs.bookRepository.StreamBooks(func(b models.Book) error {
+			return utils.WriteMarc21(w, utils.BookToMarc(b.Book))
+		})
+	case models.BookFormatMARCXML:
+		mw := utils.NewMarcXMLWriter(w)
+		err := bs.bookRepository.StreamBooks(func(b models.Book) error {
+			return mw.Write(utils.BookToMarc(b.Book))
+		})
+		if err != nil {
+			return err
+		}
+		return mw.Close()
+	default:
+		return errors.New("unsupported MARC format")
+	}
+}
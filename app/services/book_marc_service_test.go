@@ -0,0 +1,49 @@
+package services
+
+import (
+	"Restful-Perpustakaan-API/app/common"
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/utils"
+	"bytes"
+	"testing"
+)
+
+func TestImportMarcDryRunReportsDuplicateISBNs(t *testing.T) {
+	var file bytes.Buffer
+	for _, book := range []common.Book{
+		{Title: "Laskar Pelangi", ISBN: "9789793062792"},
+		{Title: "Sang Pemimpi", ISBN: "9789791227025"},
+		{Title: "Laskar Pelangi (cetak ulang)", ISBN: "978-979-3062-79-2"},
+		{Title: "Laskar Pelangi (edisi film)", ISBN: "9789793062792"},
+		{Title: "Edensor", ISBN: "9789791227780"},
+	} {
+		if err := utils.WriteMarc21(&file, utils.BookToMarc(book)); err != nil {
+			t.Fatalf("WriteMarc21: %v", err)
+		}
+	}
+	catalog := &fakeBookRepository{books: []models.Book{testBook(4, "Edensor", "9789791227780")}}
+
+	result, err := NewBookService(catalog, nil).ImportMarc(&file, models.BookFormatMARC, true)
+	if err != nil {
+		t.Fatalf("ImportMarc: %v", err)
+	}
+
+	want := []string{
+		"",
+		"",
+		"duplicate ISBN, already used in record 1",
+		"duplicate ISBN, already used in record 1",
+		"ISBN already exists in catalog (book 4)",
+	}
+	if len(result.Records) != len(want) {
+		t.Fatalf("got %d records, want %d", len(result.Records), len(want))
+	}
+	for i, record := range result.Records {
+		if record.Error != want[i] {
+			t.Errorf("record %d: error = %q, want %q", record.Index, record.Error, want[i])
+		}
+	}
+	if result.Failed != 3 || result.Imported != 0 {
+		t.Errorf("failed = %d, imported = %d; want 3 failed and nothing imported", result.Failed, result.Imported)
+	}
+}
@@ -3,16 +3,22 @@ package services
 import (
 	"Restful-Perpustakaan-API/app/models"
 	"Restful-Perpustakaan-API/app/repositories"
+	"Restful-Perpustakaan-API/app/utils"
+	"net/http"
 )
 
 // BookService provides methods for managing books
 type BookService struct {
 	bookRepository repositories.BookRepository
+	loanRepository *repositories.LoanRepository
 }
 
 // NewBookService creates a new BookService instance
-func NewBookService(bookRepository repositories.BookRepository) *BookService {
-	return &BookService{bookRepository: bookRepository}
+func NewBookService(bookRepository repositories.BookRepository, loanRepository *repositories.LoanRepository) *BookService {
+	return &BookService{
+		bookRepository: bookRepository,
+		loanRepository: loanRepository,
+	}
 }
 
 // GetAllBooks mengambil semua buku
@@ -31,15 +37,20 @@ func (bs *BookService) CreateBook(b *models.Book) error {
 	return bs.bookRepository.CreateBook(b)
 }
 
-// UpdateBook memperbarui buku
+// UpdateBook memperbarui buku; ChangedBy dicatat sebagai pelaku di riwayat versi buku
 func (bs *BookService) UpdateBook(b *models.Book) error {
-	// Anda dapat menambahkan logika validasi atau bisnis lainnya di sini sebelum memperbarui buku di database
+	if b.ChangedBy == "" {
+		return utils.NewAppError(http.StatusUnauthorized, "changed_by is required")
+	}
+	if _, err := bs.bookRepository.GetBookByID(b.ID); err != nil {
+		return utils.NewAppError(http.StatusNotFound, err.Error())
+	}
 	return bs.bookRepository.UpdateBook(b)
 }
 
-// DeleteBook menghapus buku
-func (bs *BookService) DeleteBook(id int) error {
-	return bs.bookRepository.DeleteBook(id)
+// DeleteBook memindahkan buku ke trash; buku yang masih dipinjam tidak dapat dihapus
+func (bs *BookService) DeleteBook(id int, deletedBy string) error {
+	return softDeleteBook(bs.bookRepository, bs.loanRepository, id, deletedBy)
 }
 
 // ... (fungsi lain yang mungkin Anda butuhkan, seperti SearchBooks, dll.)
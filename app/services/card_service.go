@@ -0,0 +1,210 @@
+package services
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/repositories"
+	"Restful-Perpustakaan-API/app/utils"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	cardNumberPrefix       = "29" // awalan nomor kartu anggota; eksemplar buku memakai barcode sendiri
+	cardNumberRandomDigits = 11   // prefix + 11 angka acak + check digit = 14 angka, ringkas di Code 128 set C
+	maxCardNumberAttempts  = 5
+)
+
+// Ukuran gambar kartu anggota
+const (
+	barcodeModuleWidth = 2  // piksel per modul Code 128
+	barcodeHeight      = 80 // tinggi batang dalam piksel
+	barcodeQuietZone   = 10 // modul
+	qrModuleSize       = 8  // piksel per modul QR
+	qrQuietZone        = 4  // modul
+)
+
+// CardService provides methods for issuing, replacing, rendering and scanning library cards
+type CardService struct {
+	cardRepository   repositories.CardRepository
+	memberRepository repositories.MemberRepository
+}
+
+// NewCardService creates a new CardService instance
+func NewCardService(cardRepository repositories.CardRepository, memberRepository repositories.MemberRepository) *CardService {
+	return &CardService{cardRepository: cardRepository, memberRepository: memberRepository}
+}
+
+// GetCard mengambil kartu aktif anggota
+func (cs *CardService) GetCard(memberID int) (*models.LibraryCard, error) {
+	if _, err := cs.memberRepository.GetMemberByID(memberID); err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, "member not found")
+	}
+	card, err := cs.cardRepository.GetActiveCardByMemberID(memberID)
+	if errors.Is(err, repositories.ErrCardNotFound) {
+		return nil, utils.NewAppError(http.StatusNotFound, "member has no active library card")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+// GetCards mengambil semua kartu yang pernah diterbitkan untuk anggota, termasuk yang sudah diganti
+func (cs *CardService) GetCards(memberID int) ([]models.LibraryCard, error) {
+	if _, err := cs.memberRepository.GetMemberByID(memberID); err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, "member not found")
+	}
+	cards, err := cs.cardRepository.GetCardsByMemberID(memberID)
+	if err != nil {
+		return nil, err
+	}
+	if cards == nil {
+		cards = []models.LibraryCard{}
+	}
+	return cards, nil
+}
+
+// IssueCard menerbitkan kartu pertama anggota; anggota yang sudah memiliki kartu aktif harus mengganti kartunya
+func (cs *CardService) IssueCard(memberID int) (*models.LibraryCard, error) {
+	if _, err := cs.memberRepository.GetMemberByID(memberID); err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, "member not found")
+	}
+	if _, err := cs.cardRepository.GetActiveCardByMemberID(memberID); err == nil {
+		return nil, utils.NewAppError(http.StatusConflict, "member already has an active library card; replace it instead")
+	} else if !errors.Is(err, repositories.ErrCardNotFound) {
+		return nil, err
+	}
+
+	number, err := cs.newCardNumber()
+	if err != nil {
+		return nil, err
+	}
+	card := &models.LibraryCard{MemberID: memberID, Number: number, IssuedAt: time.Now()}
+	if err := cs.cardRepository.CreateCard(card); err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+// ReplaceCard menonaktifkan kartu lama (e.g. hilang atau rusak) dan menerbitkan kartu dengan nomor baru
+func (cs *CardService) ReplaceCard(memberID int, reason string) (*models.LibraryCard, error) {
+	old, err := cs.GetCard(memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	number, err := cs.newCardNumber()
+	if err != nil {
+		return nil, err
+	}
+	card := &models.LibraryCard{MemberID: memberID, Number: number, IssuedAt: time.Now()}
+	if err := cs.cardRepository.ReplaceCard(old.ID, strings.TrimSpace(reason), card); err != nil {
+		if err == repositories.ErrCardAlreadyReplaced {
+			return nil, utils.NewAppError(http.StatusConflict, err.Error())
+		}
+		return nil, err
+	}
+	return card, nil
+}
+
+// LookupCard mencari anggota dari nomor kartu yang dipindai. Check digit diperiksa lebih dulu agar salah ketik
+// tidak dianggap kartu yang tidak dikenal; kartu yang sudah diganti ditolak.
+func (cs *CardService) LookupCard(number string) (*models.CardLookup, error) {
+	number = strings.TrimSpace(number)
+	if !utils.ValidateCardNumber(number) {
+		return nil, utils.NewAppError(http.StatusBadRequest, "invalid library card number")
+	}
+	card, err := cs.cardRepository.GetCardByNumber(number)
+	if errors.Is(err, repositories.ErrCardNotFound) {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+	if card.Status != models.CardStatusActive {
+		return nil, utils.NewAppError(http.StatusGone, "library card has been replaced")
+	}
+	member, err := cs.memberRepository.GetMemberByID(card.MemberID)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, "member not found")
+	}
+	holder := models.CardHolder{
+		ID:               member.ID,
+		Name:             member.Name,
+		MembershipType:   member.MembershipType,
+		MembershipStatus: member.MembershipStatus,
+		MembershipEnd:    member.MembershipEnd,
+		FineAmount:       member.FineAmount,
+	}
+	return &models.CardLookup{Card: *card, Member: holder}, nil
+}
+
+// RenderCard menggambar nomor kartu aktif anggota sebagai barcode Code 128 ("barcode") atau QR code ("qr")
+// dalam format "png" atau "svg", dan mengembalikan gambar beserta content type-nya
+func (cs *CardService) RenderCard(memberID int, symbology, format string) ([]byte, string, error) {
+	if format != "png" && format != "svg" {
+		return nil, "", utils.NewAppError(http.StatusBadRequest, "format must be json, png or svg")
+	}
+	card, err := cs.GetCard(memberID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var matrix [][]bool
+	moduleWidth, moduleHeight, quiet := qrModuleSize, qrModuleSize, qrQuietZone
+	switch symbology {
+	case "", "barcode":
+		bars, err := utils.Code128(card.Number)
+		if err != nil {
+			return nil, "", err
+		}
+		matrix = [][]bool{bars}
+		moduleWidth, moduleHeight, quiet = barcodeModuleWidth, barcodeHeight, barcodeQuietZone
+	case "qr":
+		if matrix, err = utils.QRCode(card.Number); err != nil {
+			return nil, "", err
+		}
+	default:
+		return nil, "", utils.NewAppError(http.StatusBadRequest, "type must be barcode or qr")
+	}
+
+	if format == "svg" {
+		return utils.RenderSVG(matrix, moduleWidth, moduleHeight, quiet), "image/svg+xml", nil
+	}
+	image, err := utils.RenderPNG(matrix, moduleWidth, moduleHeight, quiet)
+	if err != nil {
+		return nil, "", err
+	}
+	return image, "image/png", nil
+}
+
+// newCardNumber membuat nomor kartu acak yang belum pernah dipakai, termasuk oleh kartu yang sudah diganti
+func (cs *CardService) newCardNumber() (string, error) {
+	limit := big.NewInt(10)
+	for attempt := 0; attempt < maxCardNumberAttempts; attempt++ {
+		var b strings.Builder
+		b.WriteString(cardNumberPrefix)
+		for i := 0; i < cardNumberRandomDigits; i++ {
+			digit, err := rand.Int(rand.Reader, limit)
+			if err != nil {
+				return "", err
+			}
+			b.WriteByte(byte('0' + digit.Int64()))
+		}
+		b.WriteByte(utils.LuhnCheckDigit(b.String()))
+
+		// Hanya "tidak ditemukan" yang berarti nomor masih bebas; galat lain (e.g. koneksi) dikembalikan
+		_, err := cs.cardRepository.GetCardByNumber(b.String())
+		if errors.Is(err, repositories.ErrCardNotFound) {
+			return b.String(), nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return "", errors.New("failed to generate a unique library card number")
+}
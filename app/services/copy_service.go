@@ -0,0 +1,119 @@
+package services
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/repositories"
+	"Restful-Perpustakaan-API/app/utils"
+	"net/http"
+	"strings"
+)
+
+// copyStatuses adalah status eksemplar yang valid
+var copyStatuses = map[string]bool{
+	models.CopyStatusAvailable: true,
+	models.CopyStatusOnLoan:    true,
+	models.CopyStatusLost:      true,
+	models.CopyStatusWithdrawn: true,
+}
+
+// CopyService provides methods for managing the physical copies of books
+type CopyService struct {
+	copyRepository     repositories.CopyRepository
+	bookRepository     repositories.BookRepository
+	locationRepository repositories.LocationRepository
+}
+
+// NewCopyService creates a new CopyService instance
+func NewCopyService(copyRepository repositories.CopyRepository, bookRepository repositories.BookRepository, locationRepository repositories.LocationRepository) *CopyService {
+	return &CopyService{
+		copyRepository:     copyRepository,
+		bookRepository:     bookRepository,
+		locationRepository: locationRepository,
+	}
+}
+
+// GetCopiesByBookID mengambil semua eksemplar sebuah buku
+func (cs *CopyService) GetCopiesByBookID(bookID int) ([]models.BookCopy, error) {
+	if _, err := cs.bookRepository.GetBookByID(bookID); err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	copies, err := cs.copyRepository.GetCopiesByBookID(bookID)
+	if err != nil {
+		return nil, err
+	}
+	if copies == nil {
+		copies = []models.BookCopy{}
+	}
+	return copies, nil
+}
+
+// GetCopyByID mengambil eksemplar berdasarkan ID
+func (cs *CopyService) GetCopyByID(id int) (*models.BookCopy, error) {
+	c, err := cs.copyRepository.GetCopyByID(id)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	return c, nil
+}
+
+// CreateCopy menambahkan eksemplar baru untuk sebuah buku
+func (cs *CopyService) CreateCopy(c *models.BookCopy) error {
+	if _, err := cs.bookRepository.GetBookByID(c.BookID); err != nil {
+		return utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	if c.Status == "" {
+		c.Status = models.CopyStatusAvailable
+	}
+	if err := cs.validateCopy(c); err != nil {
+		return err
+	}
+	return cs.copyRepository.CreateCopy(c)
+}
+
+// UpdateCopy memperbarui barcode, lokasi, atau status eksemplar
+func (cs *CopyService) UpdateCopy(c *models.BookCopy) error {
+	existing, err := cs.GetCopyByID(c.ID)
+	if err != nil {
+		return err
+	}
+	// Eksemplar tidak dapat dipindah ke buku lain
+	c.BookID = existing.BookID
+	if c.Status == "" {
+		c.Status = existing.Status
+	}
+	if err := cs.validateCopy(c); err != nil {
+		return err
+	}
+	return cs.copyRepository.UpdateCopy(c)
+}
+
+// DeleteCopy menghapus eksemplar
+func (cs *CopyService) DeleteCopy(id int) error {
+	if _, err := cs.GetCopyByID(id); err != nil {
+		return err
+	}
+	if err := cs.copyRepository.DeleteCopy(id); err != nil {
+		if err == repositories.ErrCopyOnLoan {
+			return utils.NewAppError(http.StatusConflict, "copy is on loan and cannot be deleted")
+		}
+		return err
+	}
+	return nil
+}
+
+// validateCopy memeriksa barcode, status, dan lokasi eksemplar
+func (cs *CopyService) validateCopy(c *models.BookCopy) error {
+	c.Barcode = strings.TrimSpace(c.Barcode)
+	if c.Barcode == "" {
+		return utils.NewAppError(http.StatusBadRequest, "barcode is required")
+	}
+	if !copyStatuses[c.Status] {
+		return utils.NewAppError(http.StatusBadRequest, "invalid copy status")
+	}
+	if c.LocationID != nil {
+		if _, err := cs.locationRepository.GetLocationByID(*c.LocationID); err != nil {
+			return utils.NewAppError(http.StatusBadRequest, err.Error())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/repositories"
+	"Restful-Perpustakaan-API/app/utils"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// CourseService provides methods for managing terms, courses and course reserves
+type CourseService struct {
+	courseRepository repositories.CourseRepository
+	bookRepository   repositories.BookRepository
+	copyRepository   repositories.CopyRepository
+}
+
+// NewCourseService creates a new CourseService instance
+func NewCourseService(courseRepository repositories.CourseRepository, bookRepository repositories.BookRepository, copyRepository repositories.CopyRepository) *CourseService {
+	return &CourseService{
+		courseRepository: courseRepository,
+		bookRepository:   bookRepository,
+		copyRepository:   copyRepository,
+	}
+}
+
+// GetAllTerms mengambil semua semester
+func (cs *CourseService) GetAllTerms() ([]models.Term, error) {
+	return cs.courseRepository.GetAllTerms()
+}
+
+// GetTermByID mengambil semester berdasarkan ID
+func (cs *CourseService) GetTermByID(id int) (*models.Term, error) {
+	term, err := cs.courseRepository.GetTermByID(id)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	return term, nil
+}
+
+// CreateTerm membuat semester baru
+func (cs *CourseService) CreateTerm(t *models.Term) error {
+	if err := validateTerm(t); err != nil {
+		return err
+	}
+	return cs.courseRepository.CreateTerm(t)
+}
+
+// UpdateTerm memperbarui semester; mengubah tanggal akhir ikut mengubah masa berlaku cadangannya
+func (cs *CourseService) UpdateTerm(t *models.Term) error {
+	if _, err := cs.GetTermByID(t.ID); err != nil {
+		return err
+	}
+	if err := validateTerm(t); err != nil {
+		return err
+	}
+	return cs.courseRepository.UpdateTerm(t)
+}
+
+// DeleteTerm menghapus semester yang tidak memiliki mata kuliah
+func (cs *CourseService) DeleteTerm(id int) error {
+	if _, err := cs.GetTermByID(id); err != nil {
+		return err
+	}
+	count, err := cs.courseRepository.CountCoursesByTermID(id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return utils.NewAppError(http.StatusConflict, "term still has courses")
+	}
+	return cs.courseRepository.DeleteTerm(id)
+}
+
+// GetAllCourses mengambil semua mata kuliah
+func (cs *CourseService) GetAllCourses() ([]models.Course, error) {
+	return cs.courseRepository.GetAllCourses()
+}
+
+// GetCourseByID mengambil mata kuliah berdasarkan ID
+func (cs *CourseService) GetCourseByID(id int) (*models.Course, error) {
+	course, err := cs.courseRepository.GetCourseByID(id)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	return course, nil
+}
+
+// CreateCourse membuat mata kuliah baru
+func (cs *CourseService) CreateCourse(c *models.Course) error {
+	if err := cs.validateCourse(c); err != nil {
+		return err
+	}
+	return cs.courseRepository.CreateCourse(c)
+}
+
+// UpdateCourse memperbarui mata kuliah
+func (cs *CourseService) UpdateCourse(c *models.Course) error {
+	if _, err := cs.GetCourseByID(c.ID); err != nil {
+		return err
+	}
+	if err := cs.validateCourse(c); err != nil {
+		return err
+	}
+	return cs.courseRepository.UpdateCourse(c)
+}
+
+// DeleteCourse menghapus mata kuliah beserta cadangannya
+func (cs *CourseService) DeleteCourse(id int) error {
+	if _, err := cs.GetCourseByID(id); err != nil {
+		return err
+	}
+	return cs.courseRepository.DeleteCourse(id)
+}
+
+// GetReserves mengambil cadangan sebuah mata kuliah, termasuk yang sudah kedaluwarsa (active = false)
+func (cs *CourseService) GetReserves(courseID int) ([]models.CourseReserve, error) {
+	if _, err := cs.GetCourseByID(courseID); err != nil {
+		return nil, err
+	}
+	reserves, err := cs.courseRepository.GetReservesByCourseID(courseID)
+	if err != nil {
+		return nil, err
+	}
+	if reserves == nil {
+		reserves = []models.CourseReserve{}
+	}
+	return reserves, nil
+}
+
+// AddReserve menempatkan buku (dan opsional eksemplar tertentu) sebagai cadangan mata kuliah.
+// Cadangan berlaku sampai akhir semester mata kuliah tersebut.
+func (cs *CourseService) AddReserve(courseID int, r *models.CourseReserve) error {
+	course, err := cs.GetCourseByID(courseID)
+	if err != nil {
+		return err
+	}
+	term, err := cs.GetTermByID(course.TermID)
+	if err != nil {
+		return err
+	}
+	if !time.Now().Before(term.EndDate.AddDate(0, 0, 1)) {
+		return utils.NewAppError(http.StatusBadRequest, "term has already ended")
+	}
+
+	switch r.LoanRule {
+	case models.ReserveRuleTwoHour, models.ReserveRuleOvernight:
+	default:
+		return utils.NewAppError(http.StatusBadRequest, "loan_rule must be two_hour or overnight")
+	}
+	if _, err := cs.bookRepository.GetBookByID(r.BookID); err != nil {
+		return utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	if r.CopyID != nil {
+		bookCopy, err := cs.copyRepository.GetCopyByID(*r.CopyID)
+		if err != nil {
+			return utils.NewAppError(http.StatusNotFound, err.Error())
+		}
+		if bookCopy.BookID != r.BookID {
+			return utils.NewAppError(http.StatusBadRequest, "copy does not belong to the book")
+		}
+	}
+
+	existing, err := cs.courseRepository.GetReservesByCourseID(courseID)
+	if err != nil {
+		return err
+	}
+	for _, reserve := range existing {
+		if reserve.BookID == r.BookID {
+			return utils.NewAppError(http.StatusConflict, "book is already on reserve for this course")
+		}
+	}
+
+	r.CourseID = courseID
+	if err := cs.courseRepository.CreateReserve(r); err != nil {
+		return err
+	}
+	r.ExpiresAt = term.EndDate.AddDate(0, 0, 1)
+	r.Active = term.Contains(time.Now())
+	return nil
+}
+
+// DeleteReserve mengeluarkan buku dari cadangan mata kuliah
+func (cs *CourseService) DeleteReserve(id int) error {
+	if _, err := cs.courseRepository.GetReserveByID(id); err != nil {
+		return utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	return cs.courseRepository.DeleteReserve(id)
+}
+
+// validateCourse memeriksa kode, nama dan semester mata kuliah
+func (cs *CourseService) validateCourse(c *models.Course) error {
+	c.Code = strings.TrimSpace(c.Code)
+	c.Name = strings.TrimSpace(c.Name)
+	if c.Code == "" || c.Name == "" {
+		return utils.NewAppError(http.StatusBadRequest, "code and name are required")
+	}
+	if _, err := cs.courseRepository.GetTermByID(c.TermID); err != nil {
+		return utils.NewAppError(http.StatusBadRequest, "term_id does not refer to an existing term")
+	}
+	return nil
+}
+
+// validateTerm memeriksa nama dan rentang tanggal semester
+func validateTerm(t *models.Term) error {
+	t.Name = strings.TrimSpace(t.Name)
+	if t.Name == "" {
+		return utils.NewAppError(http.StatusBadRequest, "name is required")
+	}
+	if t.StartDate.IsZero() || t.EndDate.IsZero() {
+		return utils.NewAppError(http.StatusBadRequest, "start_date and end_date are required")
+	}
+	if t.EndDate.Before(t.StartDate) {
+		return utils.NewAppError(http.StatusBadRequest, "end_date must not be before start_date")
+	}
+	return nil
+}
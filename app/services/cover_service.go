@@ -0,0 +1,180 @@
+package services
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/repositories"
+	"Restful-Perpustakaan-API/app/utils"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// Ukuran sampul yang tersedia
+const (
+	CoverSizeOriginal = "original"
+	CoverSizeThumb    = "thumb"
+	CoverSizeMedium   = "medium"
+)
+
+// coverSizes adalah ukuran thumbnail yang dibuat otomatis (sisi terpanjang dalam piksel)
+var coverSizes = map[string]int{
+	CoverSizeThumb:  150,
+	CoverSizeMedium: 400,
+}
+
+// maxCoverDimension membatasi dimensi gambar agar tidak terjadi decompression bomb
+const maxCoverDimension = 6000
+
+// coverKeyPrefix adalah direktori penyimpanan sampul
+const coverKeyPrefix = "covers/"
+
+// CoverService provides methods for uploading and serving book cover images
+type CoverService struct {
+	bookRepository repositories.BookRepository
+	storage        repositories.FileStorage
+	maxSize        int
+}
+
+// NewCoverService creates a new CoverService instance
+func NewCoverService(bookRepository repositories.BookRepository, storage repositories.FileStorage, maxSize int) *CoverService {
+	return &CoverService{
+		bookRepository: bookRepository,
+		storage:        storage,
+		maxSize:        maxSize,
+	}
+}
+
+// MaxSize mengembalikan ukuran maksimum sampul yang boleh diunggah dalam byte
+func (cs *CoverService) MaxSize() int {
+	return cs.maxSize
+}
+
+// UploadCover memvalidasi dan menyimpan sampul buku beserta thumbnail-nya, lalu memperbarui CoverImage
+func (cs *CoverService) UploadCover(bookID int, r io.Reader) (*models.Book, error) {
+	book, err := cs.bookRepository.GetBookByID(bookID)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, int64(cs.maxSize)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, utils.NewAppError(http.StatusBadRequest, "cover image is empty")
+	}
+	if len(data) > cs.maxSize {
+		return nil, utils.NewAppError(http.StatusRequestEntityTooLarge, fmt.Sprintf("cover image exceeds %d bytes", cs.maxSize))
+	}
+
+	// Jenis file ditentukan dari isi, bukan dari header Content-Type klien
+	contentType := http.DetectContentType(data)
+	var ext string
+	switch contentType {
+	case "image/jpeg":
+		ext = ".jpg"
+	case "image/png":
+		ext = ".png"
+	default:
+		return nil, utils.NewAppError(http.StatusUnsupportedMediaType, "cover image must be JPEG or PNG")
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusBadRequest, "cover image cannot be decoded")
+	}
+	if config.Width > maxCoverDimension || config.Height > maxCoverDimension {
+		return nil, utils.NewAppError(http.StatusBadRequest, fmt.Sprintf("cover image dimensions exceed %dx%d", maxCoverDimension, maxCoverDimension))
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusBadRequest, "cover image cannot be decoded")
+	}
+
+	// Nama file berdasarkan hash isi sehingga unggahan ulang gambar yang sama tidak menduplikasi file
+	sum := sha256.Sum256(data)
+	key := coverKeyPrefix + hex.EncodeToString(sum[:]) + ext
+
+	if err := cs.storage.Put(key, data, contentType); err != nil {
+		return nil, err
+	}
+	for size, dimension := range coverSizes {
+		var buf bytes.Buffer
+		resized := utils.ResizeImage(img, dimension)
+		if ext == ".png" {
+			err = png.Encode(&buf, resized)
+		} else {
+			err = jpeg.Encode(&buf, resized, &jpeg.Options{Quality: 85})
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := cs.storage.Put(coverVariantKey(key, size), buf.Bytes(), contentType); err != nil {
+			return nil, err
+		}
+	}
+
+	// File sampul lama tidak dihapus karena nama berbasis hash bisa dipakai bersama oleh buku lain
+	book.CoverImage = key
+	if err := cs.bookRepository.UpdateBook(book); err != nil {
+		return nil, err
+	}
+
+	return book, nil
+}
+
+// GetCover membuka file sampul buku dengan ukuran tertentu; ETag diturunkan dari hash isi
+func (cs *CoverService) GetCover(bookID int, size string) (io.ReadCloser, string, string, error) {
+	if size == "" {
+		size = CoverSizeOriginal
+	}
+	if _, ok := coverSizes[size]; !ok && size != CoverSizeOriginal {
+		return nil, "", "", utils.NewAppError(http.StatusBadRequest, "invalid cover size")
+	}
+
+	book, err := cs.bookRepository.GetBookByID(bookID)
+	if err != nil {
+		return nil, "", "", utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	if !IsStoredCover(book.CoverImage) {
+		return nil, "", "", utils.NewAppError(http.StatusNotFound, "book has no uploaded cover")
+	}
+
+	key := book.CoverImage
+	if size != CoverSizeOriginal {
+		key = coverVariantKey(key, size)
+	}
+	f, err := cs.storage.Get(key)
+	if err != nil {
+		if err == repositories.ErrFileNotFound {
+			return nil, "", "", utils.NewAppError(http.StatusNotFound, "cover not found")
+		}
+		return nil, "", "", err
+	}
+
+	contentType := "image/jpeg"
+	if path.Ext(key) == ".png" {
+		contentType = "image/png"
+	}
+	etag := fmt.Sprintf("\"%s-%s\"", strings.TrimSuffix(path.Base(book.CoverImage), path.Ext(book.CoverImage)), size)
+	return f, contentType, etag, nil
+}
+
+// IsStoredCover mengembalikan true jika CoverImage menunjuk ke file yang diunggah, bukan URL eksternal
+func IsStoredCover(coverImage string) bool {
+	return strings.HasPrefix(coverImage, coverKeyPrefix)
+}
+
+// coverVariantKey menurunkan key thumbnail dari key sampul asli, e.g. covers/ab12.jpg -> covers/ab12_thumb.jpg
+func coverVariantKey(key, size string) string {
+	ext := path.Ext(key)
+	return strings.TrimSuffix(key, ext) + "_" + size + ext
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/repositories"
+	"Restful-Perpustakaan-API/app/utils"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func (f *fakeBookRepository) GetBookByID(id int) (*models.Book, error) {
+	for i := range f.books {
+		if f.books[i].ID == id {
+			found := f.books[i]
+			return &found, nil
+		}
+	}
+	return nil, errors.New("book not found")
+}
+
+func (f *fakeBookRepository) UpdateBook(b *models.Book) error {
+	for i := range f.books {
+		if f.books[i].ID == b.ID {
+			f.books[i] = *b
+			return nil
+		}
+	}
+	return errors.New("book not found")
+}
+
+// testCoverPNG membuat sampul PNG berukuran width x height
+func testCoverPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 120, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("encode PNG: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestUploadCoverStoresThumbnails(t *testing.T) {
+	catalog := &fakeBookRepository{books: []models.Book{testBook(1, "Laskar Pelangi", "9789793062792"), testBook(2, "Sang Pemimpi", "9789791227025")}}
+	storage := repositories.NewLocalFileStorage(t.TempDir())
+	service := NewCoverService(catalog, storage, 1<<20)
+
+	data := testCoverPNG(t, 600, 900)
+	book, err := service.UploadCover(1, bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("UploadCover: %v", err)
+	}
+	sum := sha256.Sum256(data)
+	wantKey := "covers/" + hex.EncodeToString(sum[:]) + ".png"
+	if book.CoverImage != wantKey {
+		t.Fatalf("CoverImage = %q, want %q", book.CoverImage, wantKey)
+	}
+	if stored, _ := catalog.GetBookByID(1); stored.CoverImage != wantKey {
+		t.Errorf("stored CoverImage = %q, want %q", stored.CoverImage, wantKey)
+	}
+
+	wantSizes := map[string]image.Point{CoverSizeOriginal: {600, 900}, CoverSizeMedium: {266, 400}, CoverSizeThumb: {100, 150}}
+	for size, want := range wantSizes {
+		f, contentType, etag, err := service.GetCover(1, size)
+		if err != nil {
+			t.Fatalf("GetCover(%s): %v", size, err)
+		}
+		config, err := png.DecodeConfig(f)
+		f.Close()
+		if err != nil {
+			t.Fatalf("decode %s cover: %v", size, err)
+		}
+		if config.Width != want.X || config.Height != want.Y {
+			t.Errorf("%s cover = %dx%d, want %dx%d", size, config.Width, config.Height, want.X, want.Y)
+		}
+		if contentType != "image/png" || etag != "\""+hex.EncodeToString(sum[:])+"-"+size+"\"" {
+			t.Errorf("%s cover: content type = %q, etag = %s", size, contentType, etag)
+		}
+	}
+
+	// Gambar yang sama untuk buku lain memakai file yang sama karena namanya berasal dari hash isi
+	other, err := service.UploadCover(2, bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("UploadCover: %v", err)
+	}
+	if other.CoverImage != wantKey {
+		t.Errorf("second CoverImage = %q, want %q", other.CoverImage, wantKey)
+	}
+}
+
+func TestUploadCoverRejectsInvalidImages(t *testing.T) {
+	catalog := &fakeBookRepository{books: []models.Book{testBook(1, "Laskar Pelangi", "9789793062792")}}
+	service := NewCoverService(catalog, repositories.NewLocalFileStorage(t.TempDir()), 4096)
+
+	tests := []struct {
+		name   string
+		bookID int
+		data   io.Reader
+		status int
+	}{
+		{"missing book", 9, bytes.NewReader(testCoverPNG(t, 10, 10)), http.StatusNotFound},
+		{"empty", 1, strings.NewReader(""), http.StatusBadRequest},
+		{"too large", 1, bytes.NewReader(make([]byte, 4097)), http.StatusRequestEntityTooLarge},
+		{"not an image", 1, strings.NewReader("%PDF-1.4 bukan gambar"), http.StatusUnsupportedMediaType},
+		{"truncated PNG", 1, bytes.NewReader(testCoverPNG(t, 10, 10)[:20]), http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.UploadCover(tt.bookID, tt.data)
+			var appErr *utils.AppError
+			if !errors.As(err, &appErr) || appErr.StatusCode != tt.status {
+				t.Errorf("UploadCover() err = %v, want status %d", err, tt.status)
+			}
+		})
+	}
+	if book, _ := catalog.GetBookByID(1); book.CoverImage != "" {
+		t.Errorf("CoverImage = %q after rejected uploads, want empty", book.CoverImage)
+	}
+}
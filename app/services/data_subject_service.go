@@ -0,0 +1,275 @@
+package services
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/repositories"
+	"Restful-Perpustakaan-API/app/utils"
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DataSubjectService provides the data subject rights of members under the PDP law: exporting their personal data
+// and erasing it after a librarian approves the request
+type DataSubjectService struct {
+	dataSubjectRepository  repositories.DataSubjectRepository
+	profileRepository      repositories.ProfileRepository
+	memberRepository       repositories.MemberRepository
+	membershipRepository   repositories.MembershipRepository
+	reviewRepository       *repositories.ReviewRepository
+	notificationRepository *repositories.NotificationRepository
+	loanService            *LoanService
+	digitalService         *DigitalService
+	readingHistoryService  *ReadingHistoryService
+}
+
+// NewDataSubjectService creates a new DataSubjectService instance
+func NewDataSubjectService(dataSubjectRepository repositories.DataSubjectRepository, profileRepository repositories.ProfileRepository, memberRepository repositories.MemberRepository, membershipRepository repositories.MembershipRepository, reviewRepository *repositories.ReviewRepository, notificationRepository *repositories.NotificationRepository, loanService *LoanService, digitalService *DigitalService, readingHistoryService *ReadingHistoryService) *DataSubjectService {
+	return &DataSubjectService{
+		dataSubjectRepository:  dataSubjectRepository,
+		profileRepository:      profileRepository,
+		memberRepository:       memberRepository,
+		membershipRepository:   membershipRepository,
+		reviewRepository:       reviewRepository,
+		notificationRepository: notificationRepository,
+		loanService:            loanService,
+		digitalService:         digitalService,
+		readingHistoryService:  readingHistoryService,
+	}
+}
+
+// ExportData mengumpulkan data pribadi anggota dan mengemasnya sebagai ZIP berisi satu file JSON per bagian
+func (ds *DataSubjectService) ExportData(memberID int) ([]byte, error) {
+	export, err := ds.collectExport(memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, file := range []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", export.Profile},
+		{"loans.json", export.Loans},
+		{"history.json", export.History},
+		{"reviews.json", export.Reviews},
+		{"notifications.json", export.Notifications},
+		{"fines.json", export.Fines},
+	} {
+		data, err := json.MarshalIndent(file.data, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		w, err := archive.Create(file.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// collectExport membaca semua bagian data pribadi anggota
+func (ds *DataSubjectService) collectExport(memberID int) (*models.MemberExport, error) {
+	profile, err := ds.profileRepository.GetProfile(memberID)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	export := &models.MemberExport{Profile: profile}
+
+	if export.Loans.Physical, err = ds.loanService.GetLoansByMemberID(memberID); err != nil {
+		return nil, err
+	}
+	if export.Loans.Digital, err = ds.digitalService.GetActiveLoansByMemberID(memberID); err != nil {
+		return nil, err
+	}
+	if export.History, err = ds.readingHistoryService.GetReadingHistory(memberID); err != nil {
+		return nil, err
+	}
+	if export.Reviews, err = ds.reviewRepository.GetReviewsByUserID(memberID); err != nil {
+		return nil, err
+	}
+	if export.Notifications, err = ds.notificationRepository.GetNotificationsByUserID(memberID); err != nil {
+		return nil, err
+	}
+	if export.Fines.Ledger, err = ds.membershipRepository.GetFineLedger(memberID); err != nil {
+		return nil, err
+	}
+	if export.Fines.Renewals, err = ds.membershipRepository.GetRenewals(memberID); err != nil {
+		return nil, err
+	}
+	export.Fines.Outstanding = profile.FineAmount
+
+	if export.Reviews == nil {
+		export.Reviews = []models.Review{}
+	}
+	if export.Notifications == nil {
+		export.Notifications = []models.Notification{}
+	}
+	if export.Fines.Ledger == nil {
+		export.Fines.Ledger = []models.FineLedgerEntry{}
+	}
+	if export.Fines.Renewals == nil {
+		export.Fines.Renewals = []models.MembershipRenewal{}
+	}
+	return export, nil
+}
+
+// RequestErasure mencatat permintaan anggota untuk menghapus data pribadinya; permintaan ditinjau pustakawan
+func (ds *DataSubjectService) RequestErasure(memberID int, reason string) (*models.ErasureRequest, error) {
+	if _, err := ds.memberRepository.GetMemberByID(memberID); err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, "member not found")
+	}
+	requests, err := ds.dataSubjectRepository.GetErasureRequestsByMemberID(memberID)
+	if err != nil {
+		return nil, err
+	}
+	for _, req := range requests {
+		if req.Status == models.ErasureStatusPending {
+			return nil, utils.NewAppError(http.StatusConflict, "an erasure request is already waiting for review")
+		}
+	}
+
+	req := &models.ErasureRequest{MemberID: memberID, Reason: strings.TrimSpace(reason), RequestedAt: time.Now()}
+	if err := ds.dataSubjectRepository.CreateErasureRequest(req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// GetMyErasureRequests mengambil permintaan penghapusan milik anggota
+func (ds *DataSubjectService) GetMyErasureRequests(memberID int) ([]models.ErasureRequest, error) {
+	requests, err := ds.dataSubjectRepository.GetErasureRequestsByMemberID(memberID)
+	if err != nil {
+		return nil, err
+	}
+	if requests == nil {
+		requests = []models.ErasureRequest{}
+	}
+	return requests, nil
+}
+
+// GetErasureRequests mengambil permintaan penghapusan untuk ditinjau pustakawan, opsional difilter status
+func (ds *DataSubjectService) GetErasureRequests(status string) ([]models.ErasureRequest, error) {
+	switch status {
+	case "", models.ErasureStatusPending, models.ErasureStatusApproved, models.ErasureStatusRejected:
+	default:
+		return nil, utils.NewAppError(http.StatusBadRequest, "status must be pending, approved or rejected")
+	}
+	requests, err := ds.dataSubjectRepository.GetErasureRequests(status)
+	if err != nil {
+		return nil, err
+	}
+	if requests == nil {
+		requests = []models.ErasureRequest{}
+	}
+	return requests, nil
+}
+
+// ApproveErasure menyetujui permintaan dan menganonimkan data pribadi anggota. Denda, buku denda dan riwayat
+// perpanjangan tetap disimpan sebagai catatan keuangan; anggota yang masih meminjam buku harus mengembalikannya dulu.
+func (ds *DataSubjectService) ApproveErasure(id int, reviewedBy, note string) (*models.ErasureRequest, error) {
+	if reviewedBy == "" {
+		return nil, utils.NewAppError(http.StatusUnauthorized, "reviewer is required")
+	}
+	req, err := ds.pendingRequest(id)
+	if err != nil {
+		return nil, err
+	}
+	before, err := ds.profileRepository.GetProfile(req.MemberID)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, "member not found")
+	}
+	loans, err := ds.loanService.GetLoansByMemberID(req.MemberID)
+	if err != nil {
+		return nil, err
+	}
+	for _, loan := range loans {
+		if loan.ReturnDate == nil {
+			return nil, utils.NewAppError(http.StatusConflict, "member still has books on loan; they must be returned before the data can be erased")
+		}
+	}
+
+	now := time.Now()
+	anonymized := *before
+	anonymized.Name = fmt.Sprintf("Erased member %d", before.ID)
+	anonymized.Email = fmt.Sprintf("erased-%d@erased.invalid", before.ID)
+	anonymized.Password = ""
+	anonymized.PhoneNumber = ""
+	anonymized.Address = ""
+	anonymized.Username = ""
+	anonymized.Gender = ""
+	anonymized.BirthDate = nil
+	anonymized.GuardianConsentAt = nil
+	anonymized.StudentID = ""
+	anonymized.MembershipStatus = models.MembershipStatusClosed
+	anonymized.ErasedAt = &now
+
+	req.ReviewedBy = reviewedBy
+	req.ReviewedAt = &now
+	req.ReviewNote = strings.TrimSpace(note)
+	if err := ds.dataSubjectRepository.EraseMember(req, before, &anonymized); err != nil {
+		if err == repositories.ErrErasureNotPending {
+			return nil, utils.NewAppError(http.StatusConflict, err.Error())
+		}
+		return nil, err
+	}
+	req.Status = models.ErasureStatusApproved
+	return req, nil
+}
+
+// RejectErasure menolak permintaan penghapusan, misalnya karena kewajiban penyimpanan data lain; note dikirim ke anggota
+func (ds *DataSubjectService) RejectErasure(id int, reviewedBy, note string) (*models.ErasureRequest, error) {
+	if reviewedBy == "" {
+		return nil, utils.NewAppError(http.StatusUnauthorized, "reviewer is required")
+	}
+	req, err := ds.pendingRequest(id)
+	if err != nil {
+		return nil, err
+	}
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return nil, utils.NewAppError(http.StatusBadRequest, "a note explaining the rejection is required")
+	}
+
+	now := time.Now()
+	req.ReviewedBy = reviewedBy
+	req.ReviewedAt = &now
+	req.ReviewNote = note
+	if err := ds.dataSubjectRepository.RejectErasureRequest(req); err != nil {
+		if err == repositories.ErrErasureNotPending {
+			return nil, utils.NewAppError(http.StatusConflict, err.Error())
+		}
+		return nil, err
+	}
+	req.Status = models.ErasureStatusRejected
+
+	message := "Your request to erase your personal data was rejected: " + note
+	if err := ds.notificationRepository.CreateNotification(&models.Notification{UserID: req.MemberID, Message: message}); err != nil {
+		utils.GetLogger().WithError(err).WithField("member", req.MemberID).Warn("failed to notify member of rejected erasure request")
+	}
+	return req, nil
+}
+
+// pendingRequest mengambil permintaan penghapusan yang belum ditinjau
+func (ds *DataSubjectService) pendingRequest(id int) (*models.ErasureRequest, error) {
+	req, err := ds.dataSubjectRepository.GetErasureRequestByID(id)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	if req.Status != models.ErasureStatusPending {
+		return nil, utils.NewAppError(http.StatusConflict, repositories.ErrErasureNotPending.Error())
+	}
+	return req, nil
+}
@@ -0,0 +1,309 @@
+package services
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/repositories"
+	"Restful-Perpustakaan-API/app/utils"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// digitalKeyPrefix adalah direktori penyimpanan file e-book dan audiobook
+const digitalKeyPrefix = "digital/"
+
+// DigitalConfig holds the lending rules for digital resources
+type DigitalConfig struct {
+	LoanPeriod time.Duration // lama peminjaman digital sebelum kedaluwarsa otomatis
+	LinkTTL    time.Duration // masa berlaku tautan unduhan
+	MaxSize    int           // ukuran file maksimum dalam byte
+	SigningKey []byte        // kunci HMAC untuk menandatangani tautan unduhan
+	MaxLoans   int           // batas peminjaman aktif anggota (fisik + digital); 0 berarti tanpa batas
+}
+
+// DigitalService provides methods for managing and lending e-books and audiobooks
+type DigitalService struct {
+	digitalRepository repositories.DigitalRepository
+	bookRepository    repositories.BookRepository
+	memberRepository  repositories.MemberRepository
+	loanRepository    *repositories.LoanRepository
+	storage           repositories.FileStorage
+	blockService      *BlockService
+	cfg               DigitalConfig
+}
+
+// NewDigitalService creates a new DigitalService instance
+func NewDigitalService(digitalRepository repositories.DigitalRepository, bookRepository repositories.BookRepository, memberRepository repositories.MemberRepository, loanRepository *repositories.LoanRepository, storage repositories.FileStorage, blockService *BlockService, cfg DigitalConfig) *DigitalService {
+	return &DigitalService{
+		digitalRepository: digitalRepository,
+		bookRepository:    bookRepository,
+		memberRepository:  memberRepository,
+		loanRepository:    loanRepository,
+		storage:           storage,
+		blockService:      blockService,
+		cfg:               cfg,
+	}
+}
+
+// MaxSize mengembalikan ukuran maksimum file digital yang boleh diunggah dalam byte
+func (ds *DigitalService) MaxSize() int {
+	return ds.cfg.MaxSize
+}
+
+// GetItemsByBookID mengambil sumber digital sebuah buku beserta jumlah lisensi yang tersedia
+func (ds *DigitalService) GetItemsByBookID(bookID int) ([]models.DigitalItem, error) {
+	if _, err := ds.bookRepository.GetBookByID(bookID); err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	items, err := ds.digitalRepository.GetItemsByBookID(bookID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if items == nil {
+		items = []models.DigitalItem{}
+	}
+	return items, nil
+}
+
+// UploadItem menyimpan file PDF, EPUB atau audio sebagai sumber digital buku dengan jumlah lisensi tertentu
+func (ds *DigitalService) UploadItem(bookID int, r io.Reader, licenseCount int) (*models.DigitalItem, error) {
+	if licenseCount < 1 {
+		return nil, utils.NewAppError(http.StatusBadRequest, "license_count must be at least 1")
+	}
+	if _, err := ds.bookRepository.GetBookByID(bookID); err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, int64(ds.cfg.MaxSize)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, utils.NewAppError(http.StatusBadRequest, "file is empty")
+	}
+	if len(data) > ds.cfg.MaxSize {
+		return nil, utils.NewAppError(http.StatusRequestEntityTooLarge, fmt.Sprintf("file exceeds %d bytes", ds.cfg.MaxSize))
+	}
+
+	// Jenis file ditentukan dari isi, bukan dari header Content-Type klien
+	itemType, format, contentType, ok := detectDigitalFormat(data)
+	if !ok {
+		return nil, utils.NewAppError(http.StatusUnsupportedMediaType, "file must be a PDF, EPUB, MP3, M4B, OGG or WAV")
+	}
+
+	sum := sha256.Sum256(data)
+	item := &models.DigitalItem{
+		BookID:       bookID,
+		Type:         itemType,
+		Format:       format,
+		ContentType:  contentType,
+		Size:         len(data),
+		FileKey:      digitalKeyPrefix + strconv.Itoa(bookID) + "/" + hex.EncodeToString(sum[:]) + "." + format,
+		LicenseCount: licenseCount,
+	}
+	if err := ds.storage.Put(item.FileKey, data, contentType); err != nil {
+		return nil, err
+	}
+	if err := ds.digitalRepository.CreateItem(item); err != nil {
+		return nil, err
+	}
+	item.Available = licenseCount
+
+	return item, nil
+}
+
+// DeleteItem menghapus sumber digital; ditolak selama masih ada peminjaman yang aktif
+func (ds *DigitalService) DeleteItem(id int) error {
+	now := time.Now()
+	item, err := ds.digitalRepository.GetItemByID(id, now)
+	if err != nil {
+		return utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	active, err := ds.digitalRepository.CountActiveLoansByItemID(id, now)
+	if err != nil {
+		return err
+	}
+	if active > 0 {
+		return utils.NewAppError(http.StatusConflict, fmt.Sprintf("digital item has %d active loan(s)", active))
+	}
+	if err := ds.digitalRepository.DeleteItem(id); err != nil {
+		return err
+	}
+
+	// File yang sama bisa diunggah ulang untuk buku yang sama, jadi hanya dihapus jika tidak dipakai item lain
+	others, err := ds.digitalRepository.GetItemsByBookID(item.BookID, now)
+	if err != nil {
+		return err
+	}
+	for _, other := range others {
+		if other.FileKey == item.FileKey {
+			return nil
+		}
+	}
+	if err := ds.storage.Delete(item.FileKey); err != nil {
+		utils.GetLogger().WithError(err).Warnf("failed to delete digital file %s", item.FileKey)
+	}
+	return nil
+}
+
+// Borrow meminjamkan sumber digital kepada anggota; peminjaman berakhir sendiri setelah LoanPeriod
+// dan dihitung dalam batas peminjaman anggota bersama peminjaman fisik
+func (ds *DigitalService) Borrow(itemID, memberID int) (*models.DigitalLoan, error) {
+	now := time.Now()
+	item, err := ds.digitalRepository.GetItemByID(itemID, now)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	member, err := ds.memberRepository.GetMemberByID(memberID)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, "member not found")
+	}
+	if err := checkMembershipActive(member); err != nil {
+		return nil, err
+	}
+	if err := checkGuardianConsent(member); err != nil {
+		return nil, err
+	}
+	if err := ds.blockService.CheckBorrowing(memberID); err != nil {
+		return nil, err
+	}
+
+	loans, err := ds.digitalRepository.GetActiveLoansByMemberID(memberID, now)
+	if err != nil {
+		return nil, err
+	}
+	for _, loan := range loans {
+		if loan.ItemID == itemID {
+			return nil, utils.NewAppError(http.StatusConflict, "member already has an active loan of this digital item")
+		}
+	}
+	if err := checkLoanLimit(ds.loanRepository, ds.digitalRepository, memberID, ds.cfg.MaxLoans); err != nil {
+		return nil, err
+	}
+
+	loan := &models.DigitalLoan{
+		ItemID:     itemID,
+		BookID:     item.BookID,
+		MemberID:   memberID,
+		BorrowedAt: now,
+		ExpiresAt:  now.Add(ds.cfg.LoanPeriod),
+	}
+	if err := ds.digitalRepository.CreateLoan(loan); err != nil {
+		if err == repositories.ErrNoLicenseAvailable {
+			return nil, utils.NewAppError(http.StatusConflict, err.Error())
+		}
+		return nil, err
+	}
+	loan.Download = ds.downloadLink(loan, now)
+	recordActivity(ds.memberRepository, memberID)
+
+	return loan, nil
+}
+
+// GetActiveLoansByMemberID mengambil peminjaman digital anggota yang belum kedaluwarsa
+func (ds *DigitalService) GetActiveLoansByMemberID(memberID int) ([]models.DigitalLoan, error) {
+	loans, err := ds.digitalRepository.GetActiveLoansByMemberID(memberID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if loans == nil {
+		loans = []models.DigitalLoan{}
+	}
+	return loans, nil
+}
+
+// CreateDownloadLink membuat tautan unduhan baru untuk peminjaman digital yang masih aktif milik anggota;
+// peminjaman anggota lain diperlakukan seperti tidak ada
+func (ds *DigitalService) CreateDownloadLink(loanID, memberID int) (*models.DownloadLink, error) {
+	loan, err := ds.digitalRepository.GetLoanByID(loanID)
+	if err != nil || loan.MemberID != memberID {
+		return nil, utils.NewAppError(http.StatusNotFound, "digital loan not found")
+	}
+	now := time.Now()
+	if !loan.Active(now) {
+		return nil, utils.NewAppError(http.StatusGone, "digital loan has expired")
+	}
+	return ds.downloadLink(loan, now), nil
+}
+
+// OpenDownload memverifikasi tautan unduhan dan membuka file peminjaman digital; pemanggil wajib menutup ReadCloser
+func (ds *DigitalService) OpenDownload(loanID int, query url.Values) (io.ReadCloser, *models.DigitalItem, error) {
+	now := time.Now()
+	if !utils.VerifySignedURL(ds.cfg.SigningKey, digitalDownloadPath(loanID), query, now) {
+		return nil, nil, utils.NewAppError(http.StatusForbidden, "download link is invalid or has expired")
+	}
+	loan, err := ds.activeLoan(loanID, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	item, err := ds.digitalRepository.GetItemByID(loan.ItemID, now)
+	if err != nil {
+		return nil, nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+
+	f, err := ds.storage.Get(item.FileKey)
+	if err != nil {
+		if err == repositories.ErrFileNotFound {
+			return nil, nil, utils.NewAppError(http.StatusNotFound, "digital file not found")
+		}
+		return nil, nil, err
+	}
+	return f, item, nil
+}
+
+// activeLoan mengambil peminjaman digital dan menolaknya dengan 410 jika sudah kedaluwarsa
+func (ds *DigitalService) activeLoan(loanID int, now time.Time) (*models.DigitalLoan, error) {
+	loan, err := ds.digitalRepository.GetLoanByID(loanID)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	if !loan.Active(now) {
+		return nil, utils.NewAppError(http.StatusGone, "digital loan has expired")
+	}
+	return loan, nil
+}
+
+// downloadLink menandatangani tautan unduhan; masa berlakunya tidak melewati akhir peminjaman
+func (ds *DigitalService) downloadLink(loan *models.DigitalLoan, now time.Time) *models.DownloadLink {
+	expires := now.Add(ds.cfg.LinkTTL)
+	if loan.ExpiresAt.Before(expires) {
+		expires = loan.ExpiresAt
+	}
+	return &models.DownloadLink{
+		URL:       utils.SignURL(ds.cfg.SigningKey, digitalDownloadPath(loan.ID), expires),
+		ExpiresAt: expires,
+	}
+}
+
+// digitalDownloadPath adalah path endpoint unduhan yang ditandatangani
+func digitalDownloadPath(loanID int) string {
+	return "/digital-loans/" + strconv.Itoa(loanID) + "/download"
+}
+
+// detectDigitalFormat menentukan jenis, format dan content type file dari isinya
+func detectDigitalFormat(data []byte) (itemType, format, contentType string, ok bool) {
+	switch http.DetectContentType(data) {
+	case "application/pdf":
+		return models.DigitalTypeEbook, "pdf", "application/pdf", true
+	case "application/zip":
+		// EPUB adalah arsip ZIP yang entri pertamanya file "mimetype" tanpa kompresi
+		if len(data) >= 58 && bytes.Equal(data[30:58], []byte("mimetypeapplication/epub+zip")) {
+			return models.DigitalTypeEbook, "epub", "application/epub+zip", true
+		}
+	case "audio/mpeg":
+		return models.DigitalTypeAudiobook, "mp3", "audio/mpeg", true
+	case "video/mp4":
+		return models.DigitalTypeAudiobook, "m4b", "audio/mp4", true
+	case "application/ogg":
+		return models.DigitalTypeAudiobook, "ogg", "audio/ogg", true
+	case "audio/wave":
+		return models.DigitalTypeAudiobook, "wav", "audio/wav", true
+	}
+	return "", "", "", false
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/repositories"
+	"Restful-Perpustakaan-API/app/utils"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Bobot skor kecocokan anggota ganda; skor dibatasi antara 0 dan 1
+const (
+	duplicateEmailWeight      = 0.5
+	duplicatePhoneWeight      = 0.3
+	duplicateBirthDateWeight  = 0.2
+	duplicateNameWeight       = 0.4  // dikalikan dengan kemiripan nama
+	duplicateBirthDatePenalty = 0.2  // tanggal lahir yang berbeda menandakan orang lain
+	duplicateNameThreshold    = 0.85 // kemiripan nama minimum agar nama dihitung sebagai alasan
+	defaultDuplicateMinScore  = 0.5
+)
+
+// DuplicateService finds member accounts that probably belong to the same person and merges them
+type DuplicateService struct {
+	duplicateRepository repositories.DuplicateRepository
+	memberRepository    repositories.MemberRepository
+	householdRepository repositories.HouseholdRepository
+}
+
+// NewDuplicateService creates a new DuplicateService instance
+func NewDuplicateService(duplicateRepository repositories.DuplicateRepository, memberRepository repositories.MemberRepository, householdRepository repositories.HouseholdRepository) *DuplicateService {
+	return &DuplicateService{
+		duplicateRepository: duplicateRepository,
+		memberRepository:    memberRepository,
+		householdRepository: householdRepository,
+	}
+}
+
+// FindDuplicates mencari pasangan anggota yang kemungkinan orang yang sama untuk ditinjau pustakawan, diurutkan dari
+// skor tertinggi. Anggota hanya dibandingkan jika email, nomor telepon, tanggal lahir atau salah satu kata namanya
+// sama, sehingga tidak semua pasangan perlu diperiksa. Pasangan yang sudah ditandai berbeda tidak ditampilkan.
+func (ds *DuplicateService) FindDuplicates(minScore float64) ([]models.DuplicateCandidate, error) {
+	if minScore < 0 || minScore > 1 {
+		return nil, utils.NewAppError(http.StatusBadRequest, "min_score must be between 0 and 1")
+	}
+	if minScore == 0 {
+		minScore = defaultDuplicateMinScore
+	}
+
+	members, err := ds.duplicateRepository.GetMatchableMembers()
+	if err != nil {
+		return nil, err
+	}
+	dismissals, err := ds.duplicateRepository.GetDismissals()
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[[2]int]bool, len(dismissals))
+	for _, d := range dismissals {
+		seen[duplicatePairKey(d.MemberID, d.OtherMemberID)] = true
+	}
+
+	blocks := make(map[string][]int)
+	for i, m := range members {
+		for _, key := range duplicateBlockKeys(m) {
+			blocks[key] = append(blocks[key], i)
+		}
+	}
+
+	candidates := []models.DuplicateCandidate{}
+	for _, block := range blocks {
+		for i := 0; i < len(block); i++ {
+			for j := i + 1; j < len(block); j++ {
+				a, b := members[block[i]], members[block[j]]
+				key := duplicatePairKey(a.ID, b.ID)
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+
+				candidate := scoreDuplicate(a, b)
+				if candidate.Score >= minScore {
+					candidates = append(candidates, candidate)
+				}
+			}
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		if candidates[i].Member.ID != candidates[j].Member.ID {
+			return candidates[i].Member.ID < candidates[j].Member.ID
+		}
+		return candidates[i].Duplicate.ID < candidates[j].Duplicate.ID
+	})
+	return candidates, nil
+}
+
+// DismissDuplicate menandai dua anggota sebagai orang yang berbeda sehingga tidak lagi muncul di daftar
+func (ds *DuplicateService) DismissDuplicate(memberID, otherMemberID int, dismissedBy string) (*models.DuplicateDismissal, error) {
+	if memberID == otherMemberID {
+		return nil, utils.NewAppError(http.StatusBadRequest, "a member cannot be a duplicate of itself")
+	}
+	for _, id := range []int{memberID, otherMemberID} {
+		if _, err := ds.memberRepository.GetMemberByID(id); err != nil {
+			return nil, utils.NewAppError(http.StatusNotFound, "member not found")
+		}
+	}
+
+	dismissal := &models.DuplicateDismissal{
+		MemberID:      memberID,
+		OtherMemberID: otherMemberID,
+		DismissedBy:   dismissedBy,
+		DismissedAt:   time.Now(),
+	}
+	if err := ds.duplicateRepository.DismissDuplicate(dismissal); err != nil {
+		return nil, err
+	}
+	return dismissal, nil
+}
+
+// MergeMembers menggabungkan akun ganda ke akun yang dipertahankan: pinjaman, reservasi, ulasan, notifikasi,
+// denda dan entri buku denda dipindahkan, lalu akun ganda dipindahkan ke trash. Data profil akun yang
+// dipertahankan tidak diubah; pustakawan memperbaikinya sendiri bila perlu.
+func (ds *DuplicateService) MergeMembers(survivorID, mergedID int, mergedBy string) (*models.MemberMerge, error) {
+	if mergedBy == "" {
+		return nil, utils.NewAppError(http.StatusUnauthorized, "merging member is required")
+	}
+	if survivorID == mergedID {
+		return nil, utils.NewAppError(http.StatusBadRequest, "a member cannot be merged into itself")
+	}
+	survivor, err := ds.memberRepository.GetMemberByID(survivorID)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, "member not found")
+	}
+	merged, err := ds.memberRepository.GetMemberByID(mergedID)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, "duplicate member not found")
+	}
+	if survivor.ErasedAt != nil || merged.ErasedAt != nil {
+		return nil, utils.NewAppError(http.StatusConflict, "the personal data of an erased member cannot be merged")
+	}
+	if _, err := ds.householdRepository.GetHouseholdByMemberID(survivorID); err == nil {
+		if _, err := ds.householdRepository.GetHouseholdByMemberID(mergedID); err == nil {
+			return nil, utils.NewAppError(http.StatusConflict, "both members belong to a household; remove one of them from their household first")
+		}
+	}
+
+	merge := &models.MemberMerge{
+		SurvivorID: survivorID,
+		MergedID:   mergedID,
+		MergedBy:   mergedBy,
+		MergedAt:   time.Now(),
+	}
+	if err := ds.duplicateRepository.MergeMembers(merge); err != nil {
+		if strings.HasSuffix(err.Error(), "not found") {
+			return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+		}
+		return nil, err
+	}
+	return merge, nil
+}
+
+// GetMerges mengambil riwayat penggabungan yang melibatkan anggota
+func (ds *DuplicateService) GetMerges(memberID int) ([]models.MemberMerge, error) {
+	merges, err := ds.duplicateRepository.GetMerges(memberID)
+	if err != nil {
+		return nil, err
+	}
+	if merges == nil {
+		merges = []models.MemberMerge{}
+	}
+	return merges, nil
+}
+
+// scoreDuplicate menghitung skor kecocokan dua anggota; anggota dengan ID terkecil (akun lama) menjadi Member
+func scoreDuplicate(a, b models.Member) models.DuplicateCandidate {
+	if b.ID < a.ID {
+		a, b = b, a
+	}
+	candidate := models.DuplicateCandidate{Member: a, Duplicate: b, Reasons: []string{}}
+
+	var score float64
+	if email := utils.NormalizeEmail(a.Email); email != "" && email == utils.NormalizeEmail(b.Email) {
+		score += duplicateEmailWeight
+		candidate.Reasons = append(candidate.Reasons, models.DuplicateReasonEmail)
+	}
+	if phone := utils.NormalizePhone(a.PhoneNumber); phone != "" && phone == utils.NormalizePhone(b.PhoneNumber) {
+		score += duplicatePhoneWeight
+		candidate.Reasons = append(candidate.Reasons, models.DuplicateReasonPhone)
+	}
+	candidate.NameSimilarity = math.Round(utils.NameSimilarity(a.Name, b.Name)*100) / 100
+	if candidate.NameSimilarity >= duplicateNameThreshold {
+		score += duplicateNameWeight * candidate.NameSimilarity
+		candidate.Reasons = append(candidate.Reasons, models.DuplicateReasonName)
+	}
+	if a.BirthDate != nil && b.BirthDate != nil {
+		if sameDate(*a.BirthDate, *b.BirthDate) {
+			score += duplicateBirthDateWeight
+			candidate.Reasons = append(candidate.Reasons, models.DuplicateReasonBirthDate)
+		} else {
+			score -= duplicateBirthDatePenalty
+		}
+	}
+
+	candidate.Score = math.Round(math.Max(0, math.Min(1, score))*100) / 100
+	return candidate
+}
+
+// duplicateBlockKeys mengembalikan kunci kelompok anggota; hanya anggota dengan kunci yang sama yang dibandingkan
+func duplicateBlockKeys(m models.Member) []string {
+	var keys []string
+	if email := utils.NormalizeEmail(m.Email); email != "" {
+		keys = append(keys, "email:"+email)
+	}
+	if phone := utils.NormalizePhone(m.PhoneNumber); phone != "" {
+		keys = append(keys, "phone:"+phone)
+	}
+	if m.BirthDate != nil {
+		keys = append(keys, "birth:"+m.BirthDate.Format("2006-01-02"))
+	}
+	for _, word := range strings.Fields(utils.NormalizeName(m.Name)) {
+		if len([]rune(word)) >= 3 {
+			keys = append(keys, "name:"+word)
+		}
+	}
+	return keys
+}
+
+// duplicatePairKey mengembalikan kunci pasangan anggota yang tidak bergantung pada urutan
+func duplicatePairKey(a, b int) [2]int {
+	if b < a {
+		a, b = b, a
+	}
+	return [2]int{a, b}
+}
+
+// sameDate membandingkan dua tanggal tanpa jam
+func sameDate(a, b time.Time) bool {
+	return a.Format("2006-01-02") == b.Format("2006-01-02")
+}
@@ -0,0 +1,298 @@
+package services
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/repositories"
+	"Restful-Perpustakaan-API/app/utils"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EnrichmentOptions controls how fetched metadata is applied to the catalog
+type EnrichmentOptions struct {
+	Overwrite bool // timpa field yang sudah terisi
+	Create    bool // buat buku baru jika ISBN belum ada di katalog
+	DryRun    bool // hanya tampilkan hasil tanpa menyimpan
+}
+
+// EnrichmentService provides methods for enriching book records from metadata providers
+type EnrichmentService struct {
+	bookRepository repositories.BookRepository
+	providers      []MetadataProvider // urutan menentukan prioritas saat penggabungan
+	timeout        time.Duration
+
+	mu        sync.Mutex
+	jobs      map[int]*models.EnrichmentJob
+	nextJobID int
+}
+
+// NewEnrichmentService creates a new EnrichmentService instance
+func NewEnrichmentService(bookRepository repositories.BookRepository, providers []MetadataProvider, timeout time.Duration) *EnrichmentService {
+	return &EnrichmentService{
+		bookRepository: bookRepository,
+		providers:      providers,
+		timeout:        timeout,
+		jobs:           make(map[int]*models.EnrichmentJob),
+		nextJobID:      1,
+	}
+}
+
+// FetchMetadata mengambil metadata dari semua provider lalu menggabungkannya per field.
+// Nilai dari provider dengan prioritas lebih tinggi dipakai lebih dulu.
+func (es *EnrichmentService) FetchMetadata(ctx context.Context, isbn string) (*models.BookMetadata, map[string]string, []models.MetadataSource) {
+	merged := &models.BookMetadata{ISBN: isbn}
+	provenance := make(map[string]string)
+	sources := make([]models.MetadataSource, len(es.providers))
+
+	// Provider dipanggil paralel; hasil digabung sesuai urutan prioritas
+	results := make([]*models.BookMetadata, len(es.providers))
+	var wg sync.WaitGroup
+	for i, provider := range es.providers {
+		wg.Add(1)
+		go func(i int, provider MetadataProvider) {
+			defer wg.Done()
+			providerCtx, cancel := context.WithTimeout(ctx, es.timeout)
+			defer cancel()
+
+			start := time.Now()
+			metadata, err := provider.LookupISBN(providerCtx, isbn)
+			source := models.MetadataSource{Provider: provider.Name(), Duration: time.Since(start).Round(time.Millisecond).String()}
+			switch {
+			case err == ErrMetadataNotFound:
+			case err != nil:
+				source.Error = err.Error()
+			default:
+				source.Found = true
+				results[i] = metadata
+			}
+			sources[i] = source
+		}(i, provider)
+	}
+	wg.Wait()
+
+	for i, metadata := range results {
+		if metadata == nil {
+			continue
+		}
+		name := es.providers[i].Name()
+		if merged.Title == "" && metadata.Title != "" {
+			merged.Title = metadata.Title
+			provenance["title"] = name
+		}
+		if len(merged.Authors) == 0 && len(metadata.Authors) > 0 {
+			merged.Authors = metadata.Authors
+			provenance["author"] = name
+		}
+		if merged.Publisher == "" && metadata.Publisher != "" {
+			merged.Publisher = metadata.Publisher
+			provenance["publisher"] = name
+		}
+		if merged.Year == 0 && metadata.Year != 0 {
+			merged.Year = metadata.Year
+			provenance["published_year"] = name
+		}
+		if merged.Description == "" && metadata.Description != "" {
+			merged.Description = metadata.Description
+			provenance["description"] = name
+		}
+		if merged.CoverURL == "" && metadata.CoverURL != "" {
+			merged.CoverURL = metadata.CoverURL
+			provenance["cover_image"] = name
+		}
+	}
+
+	return merged, provenance, sources
+}
+
+// EnrichISBN memperkaya buku dengan ISBN tertentu; buku baru dibuat jika opts.Create aktif
+func (es *EnrichmentService) EnrichISBN(ctx context.Context, isbn string, opts EnrichmentOptions) (*models.EnrichmentResult, error) {
+	if !utils.ValidateISBN(isbn) {
+		return nil, utils.NewAppError(http.StatusBadRequest, "invalid ISBN")
+	}
+	isbn = utils.NormalizeISBN(isbn)
+
+	created := false
+	book, err := es.bookRepository.GetBookByISBN(isbn)
+	if errors.Is(err, sql.ErrNoRows) {
+		book = nil
+		if opts.Create {
+			book = &models.Book{}
+			book.ISBN = isbn
+			created = true
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	return es.enrichBook(ctx, isbn, book, created, opts)
+}
+
+// enrichBook mengambil metadata untuk isbn dan menerapkannya ke book; book nil berarti hanya pratinjau
+func (es *EnrichmentService) enrichBook(ctx context.Context, isbn string, book *models.Book, created bool, opts EnrichmentOptions) (*models.EnrichmentResult, error) {
+	metadata, provenance, sources := es.FetchMetadata(ctx, isbn)
+	result := &models.EnrichmentResult{
+		ISBN:          isbn,
+		Created:       created,
+		DryRun:        opts.DryRun,
+		UpdatedFields: []string{},
+		Metadata:      *metadata,
+		Provenance:    provenance,
+		Sources:       sources,
+	}
+	if len(provenance) == 0 {
+		return result, utils.NewAppError(http.StatusNotFound, "no provider has metadata for this ISBN")
+	}
+	if book == nil {
+		return result, nil
+	}
+
+	result.UpdatedFields = applyMetadata(book, metadata, opts.Overwrite)
+	for field := range provenance {
+		if !containsString(result.UpdatedFields, field) {
+			delete(result.Provenance, field)
+		}
+	}
+	result.BookID = book.ID
+	if opts.DryRun || len(result.UpdatedFields) == 0 {
+		return result, nil
+	}
+
+	var err error
+	if created {
+		err = es.bookRepository.CreateBook(book)
+	} else {
+		err = es.bookRepository.UpdateBook(book)
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.BookID = book.ID
+
+	return result, nil
+}
+
+// StartReenrichment menjalankan job latar belakang yang memperkaya ulang seluruh katalog yang memiliki ISBN
+func (es *EnrichmentService) StartReenrichment(overwrite bool) (*models.EnrichmentJob, error) {
+	books, err := es.bookRepository.GetAllBooks()
+	if err != nil {
+		return nil, err
+	}
+
+	es.mu.Lock()
+	job := &models.EnrichmentJob{
+		ID:        es.nextJobID,
+		Status:    models.EnrichmentJobRunning,
+		Overwrite: overwrite,
+		StartedAt: time.Now(),
+		Total:     len(books),
+		Errors:    []string{},
+	}
+	es.nextJobID++
+	es.jobs[job.ID] = job
+	snapshot := *job
+	es.mu.Unlock()
+
+	go es.runReenrichment(job, books)
+
+	return &snapshot, nil
+}
+
+// runReenrichment memproses buku satu per satu agar tidak membebani provider eksternal
+func (es *EnrichmentService) runReenrichment(job *models.EnrichmentJob, books []models.Book) {
+	for _, book := range books {
+		var enriched, skipped bool
+		var errMessage string
+
+		if book.ISBN == "" || !utils.ValidateISBN(book.ISBN) {
+			skipped = true
+		} else {
+			result, err := es.enrichBook(context.Background(), utils.NormalizeISBN(book.ISBN), &book, false, EnrichmentOptions{Overwrite: job.Overwrite})
+			var appErr *utils.AppError
+			switch {
+			case errors.As(err, &appErr) && appErr.StatusCode == http.StatusNotFound:
+				skipped = true
+			case err != nil:
+				errMessage = fmt.Sprintf("book %d (%s): %v", book.ID, book.ISBN, err)
+			case len(result.UpdatedFields) > 0:
+				enriched = true
+			default:
+				skipped = true
+			}
+		}
+
+		es.mu.Lock()
+		job.Processed++
+		switch {
+		case errMessage != "":
+			job.Failed++
+			job.Errors = append(job.Errors, errMessage)
+		case enriched:
+			job.Enriched++
+		case skipped:
+			job.Skipped++
+		}
+		es.mu.Unlock()
+	}
+
+	es.mu.Lock()
+	finishedAt := time.Now()
+	job.FinishedAt = &finishedAt
+	job.Status = models.EnrichmentJobCompleted
+	es.mu.Unlock()
+}
+
+// GetJob mengambil status job re-enrichment
+func (es *EnrichmentService) GetJob(id int) (*models.EnrichmentJob, error) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	job, ok := es.jobs[id]
+	if !ok {
+		return nil, utils.NewAppError(http.StatusNotFound, "enrichment job not found")
+	}
+	snapshot := *job
+	snapshot.Errors = append([]string{}, job.Errors...)
+	return &snapshot, nil
+}
+
+// applyMetadata mengisi field buku dari metadata dan mengembalikan nama field yang berubah
+func applyMetadata(book *models.Book, metadata *models.BookMetadata, overwrite bool) []string {
+	updated := []string{}
+	set := func(field string, current *string, value string) {
+		if value != "" && *current != value && (overwrite || *current == "") {
+			*current = value
+			updated = append(updated, field)
+		}
+	}
+
+	set("title", &book.Title, metadata.Title)
+	set("author", &book.Author, strings.Join(metadata.Authors, "; "))
+	set("publisher", &book.Publisher, metadata.Publisher)
+	set("description", &book.Description, metadata.Description)
+	// Sampul yang sudah diunggah tidak pernah diganti dengan URL eksternal
+	if !IsStoredCover(book.CoverImage) {
+		set("cover_image", &book.CoverImage, metadata.CoverURL)
+	}
+	if metadata.Year != 0 && book.PublishedYear != metadata.Year && (overwrite || book.PublishedYear == 0) {
+		book.PublishedYear = metadata.Year
+		updated = append(updated, "published_year")
+	}
+
+	return updated
+}
+
+// containsString mengembalikan true jika value ada di values
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -1,45 +1,314 @@
 package services
 
 import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/utils"
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
-// ExternalService provides methods for interacting with external services
-type ExternalService struct{}
+// ErrMetadataNotFound dikembalikan provider jika ISBN tidak dikenal
+var ErrMetadataNotFound = errors.New("metadata not found")
 
-// NewExternalService creates a new ExternalService instance
-func NewExternalService() *ExternalService {
-	return &ExternalService{}
+// MetadataProvider provides bibliographic metadata for a book given its ISBN
+type MetadataProvider interface {
+	Name() string
+	LookupISBN(ctx context.Context, isbn string) (*models.BookMetadata, error)
 }
 
-// GetWeatherData mengambil data cuaca dari API OpenWeatherMap berdasarkan nama kota
-func (es *ExternalService) GetWeatherData(city string) (map[string]interface{}, error) {
-	apiKey := os.Getenv("OPENWEATHERMAP_API_KEY") // Ambil API key dari environment variable
-	if apiKey == "" {
-		return nil, errors.New("OPENWEATHERMAP_API_KEY environment variable not set")
+// openLibraryProvider fetches metadata from an Open Library-style JSON API
+// (GET {baseURL}/api/books?bibkeys=ISBN:{isbn}&format=json&jscmd=data)
+type openLibraryProvider struct {
+	baseURL string
+	client  *http.Client
+	retries int
+}
+
+// NewOpenLibraryProvider creates a MetadataProvider for an Open Library-compatible API.
+// transport may be nil to use http.DefaultTransport, or a FixtureTransport in tests.
+func NewOpenLibraryProvider(baseURL string, timeout time.Duration, retries int, transport http.RoundTripper) MetadataProvider {
+	return &openLibraryProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: timeout, Transport: transport},
+		retries: retries,
+	}
+}
+
+// Name mengembalikan nama provider untuk provenance
+func (op *openLibraryProvider) Name() string {
+	return "openlibrary"
+}
+
+// openLibraryBook adalah bagian respons Open Library yang dipakai
+type openLibraryBook struct {
+	Title    string `json:"title"`
+	Subtitle string `json:"subtitle"`
+	Authors  []struct {
+		Name string `json:"name"`
+	} `json:"authors"`
+	Publishers []struct {
+		Name string `json:"name"`
+	} `json:"publishers"`
+	PublishDate string          `json:"publish_date"`
+	Notes       json.RawMessage `json:"notes"`
+	Cover       struct {
+		Medium string `json:"medium"`
+		Large  string `json:"large"`
+	} `json:"cover"`
+}
+
+var yearRegex = regexp.MustCompile(`\d{4}`)
+
+// LookupISBN mengambil metadata buku dari API
+func (op *openLibraryProvider) LookupISBN(ctx context.Context, isbn string) (*models.BookMetadata, error) {
+	query := url.Values{}
+	query.Set("bibkeys", "ISBN:"+isbn)
+	query.Set("format", "json")
+	query.Set("jscmd", "data")
+	endpoint := op.baseURL + "/api/books?" + query.Encode()
+
+	body, err := getWithRetry(ctx, op.client, endpoint, op.retries)
+	if err != nil {
+		return nil, err
+	}
+
+	var response map[string]openLibraryBook
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("invalid response from %s: %v", op.Name(), err)
+	}
+	data, ok := response["ISBN:"+isbn]
+	if !ok {
+		return nil, ErrMetadataNotFound
+	}
+
+	metadata := &models.BookMetadata{
+		ISBN:  isbn,
+		Title: data.Title,
+	}
+	if data.Subtitle != "" {
+		metadata.Title += ": " + data.Subtitle
+	}
+	for _, author := range data.Authors {
+		metadata.Authors = append(metadata.Authors, author.Name)
+	}
+	if len(data.Publishers) > 0 {
+		metadata.Publisher = data.Publishers[0].Name
+	}
+	if year, err := strconv.Atoi(yearRegex.FindString(data.PublishDate)); err == nil {
+		metadata.Year = year
+	}
+	metadata.Description = decodeOpenLibraryText(data.Notes)
+	metadata.CoverURL = data.Cover.Large
+	if metadata.CoverURL == "" {
+		metadata.CoverURL = data.Cover.Medium
+	}
+
+	return metadata, nil
+}
+
+// decodeOpenLibraryText membaca field teks yang bisa berupa string atau {"type": ..., "value": ...}
+func decodeOpenLibraryText(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+	var typed struct {
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal(raw, &typed); err == nil {
+		return typed.Value
 	}
+	return ""
+}
+
+// getWithRetry melakukan GET dan mengulang saat terjadi error jaringan, 429, atau 5xx
+func getWithRetry(ctx context.Context, client *http.Client, endpoint string, retries int) ([]byte, error) {
+	backoff := 200 * time.Millisecond
+
+	var lastErr error
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "application/json")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		body, err := io.ReadAll(io.LimitReader(resp.Body, 2<<20))
+		resp.Body.Close()
+
+		switch {
+		case err != nil:
+			lastErr = err
+		case resp.StatusCode == http.StatusOK:
+			return body, nil
+		case resp.StatusCode == http.StatusNotFound:
+			return nil, ErrMetadataNotFound
+		case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+			lastErr = fmt.Errorf("API request failed with status code: %d", resp.StatusCode)
+		default:
+			return nil, fmt.Errorf("API request failed with status code: %d", resp.StatusCode)
+		}
+	}
+
+	return nil, lastErr
+}
+
+// mirrorFileProvider reads metadata from a local JSON file keyed by ISBN
+// ({"9789793062792": {"title": "...", "authors": ["..."], ...}, ...})
+type mirrorFileProvider struct {
+	path    string
+	mu      sync.Mutex
+	modTime time.Time
+	records map[string]models.BookMetadata
+}
+
+// NewMirrorFileProvider creates a MetadataProvider backed by a local mirror file.
+// The file is reloaded automatically when it changes on disk.
+func NewMirrorFileProvider(path string) MetadataProvider {
+	return &mirrorFileProvider{path: path}
+}
+
+// Name mengembalikan nama provider untuk provenance
+func (mp *mirrorFileProvider) Name() string {
+	return "mirror"
+}
 
-	url := fmt.Sprintf("http://api.openweathermap.org/data/2.5/weather?q=%s&appid=%s", city, apiKey)
+// LookupISBN mencari metadata di file mirror
+func (mp *mirrorFileProvider) LookupISBN(ctx context.Context, isbn string) (*models.BookMetadata, error) {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
 
-	resp, err := http.Get(url)
+	info, err := os.Stat(mp.path)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
+	if mp.records == nil || info.ModTime().After(mp.modTime) {
+		data, err := os.ReadFile(mp.path)
+		if err != nil {
+			return nil, err
+		}
+		records := make(map[string]models.BookMetadata)
+		if err := json.Unmarshal(data, &records); err != nil {
+			return nil, fmt.Errorf("invalid mirror file: %v", err)
+		}
+		// Kunci dinormalisasi agar ISBN dengan tanda hubung tetap cocok
+		mp.records = make(map[string]models.BookMetadata, len(records))
+		for key, record := range records {
+			mp.records[utils.NormalizeISBN(key)] = record
+		}
+		mp.modTime = info.ModTime()
+	}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API request failed with status code: %d", resp.StatusCode)
+	record, ok := mp.records[isbn]
+	if !ok {
+		return nil, ErrMetadataNotFound
 	}
+	record.ISBN = isbn
+	return &record, nil
+}
+
+// FixtureTransport is an http.RoundTripper that replays recorded responses from a directory,
+// so metadata providers can be tested without network access. When Record is set, missing
+// fixtures are fetched through Upstream and saved for later runs.
+type FixtureTransport struct {
+	Dir      string
+	Record   bool
+	Upstream http.RoundTripper
+}
 
-	var data map[string]interface{}
-	err = json.NewDecoder(resp.Body).Decode(&data)
+// RoundTrip mengembalikan respons rekaman untuk URL request
+func (ft *FixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	path := filepath.Join(ft.Dir, fixtureName(req.URL))
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && ft.Record {
+		upstream := ft.Upstream
+		if upstream == nil {
+			upstream = http.DefaultTransport
+		}
+		resp, err := upstream.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		data, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode == http.StatusOK {
+			if err := os.WriteFile(path, data, 0o644); err != nil {
+				return nil, err
+			}
+		}
+		return fixtureResponse(req, resp.StatusCode, data), nil
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return fixtureResponse(req, http.StatusNotFound, []byte("fixture not found")), nil
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	return data, nil
+	return fixtureResponse(req, http.StatusOK, data), nil
+}
+
+// fixtureName membuat nama file fixture dari path dan query URL, e.g. api_books_bibkeys_ISBN_978..._format_json_jscmd_data.json
+func fixtureName(u *url.URL) string {
+	query, err := url.QueryUnescape(u.RawQuery)
+	if err != nil {
+		query = u.RawQuery
+	}
+	name := strings.Trim(u.Path+"_"+query, "/_")
+	var b strings.Builder
+	for _, char := range name {
+		switch {
+		case char >= 'a' && char <= 'z', char >= 'A' && char <= 'Z', char >= '0' && char <= '9', char == '-':
+			b.WriteRune(char)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return b.String() + ".json"
+}
+
+// fixtureResponse membuat http.Response dari data fixture
+func fixtureResponse(req *http.Request, statusCode int, data []byte) *http.Response {
+	return &http.Response{
+		StatusCode:    statusCode,
+		Status:        fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(data)),
+		ContentLength: int64(len(data)),
+		Request:       req,
+	}
 }
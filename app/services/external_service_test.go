@@ -0,0 +1,86 @@
+package services
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// roundTripperFunc adapts a function to http.RoundTripper
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+const fixtureBaseURL = "https://openlibrary.org"
+
+func TestOpenLibraryProviderReplaysFixtures(t *testing.T) {
+	transport := &FixtureTransport{Dir: filepath.Join("testdata", "openlibrary")}
+	provider := NewOpenLibraryProvider(fixtureBaseURL, time.Second, 0, transport)
+
+	metadata, err := provider.LookupISBN(context.Background(), "9789793062792")
+	if err != nil {
+		t.Fatalf("LookupISBN: %v", err)
+	}
+	want := &models.BookMetadata{
+		ISBN:        "9789793062792",
+		Title:       "Laskar Pelangi: Sebuah Novel",
+		Authors:     []string{"Andrea Hirata"},
+		Publisher:   "Bentang Pustaka",
+		Year:        2005,
+		Description: "Novel pertama dari tetralogi Laskar Pelangi.",
+		CoverURL:    "https://covers.openlibrary.org/b/id/8231856-M.jpg",
+	}
+	if !reflect.DeepEqual(metadata, want) {
+		t.Errorf("LookupISBN() = %+v, want %+v", metadata, want)
+	}
+
+	if _, err := provider.LookupISBN(context.Background(), "9780000000002"); !errors.Is(err, ErrMetadataNotFound) {
+		t.Errorf("LookupISBN() of an ISBN without fixture: err = %v, want ErrMetadataNotFound", err)
+	}
+}
+
+func TestFixtureTransportRecordsMissingFixtures(t *testing.T) {
+	recorded, err := os.ReadFile(filepath.Join("testdata", "openlibrary", "api_books_bibkeys_ISBN_9789793062792_format_json_jscmd_data.json"))
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+
+	calls := 0
+	upstream := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(recorded)), Request: req}, nil
+	})
+	dir := t.TempDir()
+	provider := NewOpenLibraryProvider(fixtureBaseURL, time.Second, 0, &FixtureTransport{Dir: dir, Record: true, Upstream: upstream})
+
+	for i := 0; i < 2; i++ {
+		metadata, err := provider.LookupISBN(context.Background(), "9789793062792")
+		if err != nil {
+			t.Fatalf("LookupISBN: %v", err)
+		}
+		if metadata.Title != "Laskar Pelangi: Sebuah Novel" {
+			t.Errorf("Title = %q", metadata.Title)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("upstream called %d times, want 1", calls)
+	}
+
+	saved, err := os.ReadFile(filepath.Join(dir, "api_books_bibkeys_ISBN_9789793062792_format_json_jscmd_data.json"))
+	if err != nil {
+		t.Fatalf("fixture was not recorded: %v", err)
+	}
+	if !bytes.Equal(saved, recorded) {
+		t.Errorf("recorded fixture differs from the upstream response")
+	}
+}
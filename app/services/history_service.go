@@ -0,0 +1,128 @@
+package services
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/repositories"
+	"Restful-Perpustakaan-API/app/utils"
+	"encoding/json"
+	"net/http"
+)
+
+// HistoryService provides methods for reading, comparing and reverting record versions
+type HistoryService struct {
+	historyRepository repositories.HistoryRepository
+	bookRepository    repositories.BookRepository
+	memberRepository  repositories.MemberRepository
+}
+
+// NewHistoryService creates a new HistoryService instance
+func NewHistoryService(historyRepository repositories.HistoryRepository, bookRepository repositories.BookRepository, memberRepository repositories.MemberRepository) *HistoryService {
+	return &HistoryService{
+		historyRepository: historyRepository,
+		bookRepository:    bookRepository,
+		memberRepository:  memberRepository,
+	}
+}
+
+// GetHistory mengambil riwayat versi sebuah record, dari yang terbaru
+func (hs *HistoryService) GetHistory(entityType string, entityID int) ([]models.RecordVersion, error) {
+	versions, err := hs.historyRepository.GetVersions(entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+	if versions == nil {
+		versions = []models.RecordVersion{}
+	}
+	return versions, nil
+}
+
+// DiffVersions membandingkan dua versi record dan mengembalikan field yang berbeda
+func (hs *HistoryService) DiffVersions(entityType string, entityID, fromVersion, toVersion int) (*models.VersionDiff, error) {
+	from, err := hs.historyRepository.GetVersion(entityType, entityID, fromVersion)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	to, err := hs.historyRepository.GetVersion(entityType, entityID, toVersion)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+
+	return &models.VersionDiff{
+		EntityType:  entityType,
+		EntityID:    entityID,
+		FromVersion: fromVersion,
+		ToVersion:   toVersion,
+		Changes:     utils.DiffSnapshots(from.Snapshot, to.Snapshot),
+	}, nil
+}
+
+// RevertBook mengembalikan data buku ke versi tertentu; pengembalian dicatat sebagai versi baru
+func (hs *HistoryService) RevertBook(id, version int, changedBy string) (*models.Book, error) {
+	if changedBy == "" {
+		return nil, utils.NewAppError(http.StatusUnauthorized, "changed_by is required")
+	}
+	if _, err := hs.bookRepository.GetBookByID(id); err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+
+	var book models.Book
+	if err := hs.loadSnapshot(models.HistoryEntityBook, id, version, &book); err != nil {
+		return nil, err
+	}
+	book.ID = id
+	book.DeletedAt = nil
+	book.DeletedBy = ""
+	book.ChangedBy = changedBy
+	if err := hs.bookRepository.UpdateBook(&book); err != nil {
+		return nil, err
+	}
+
+	return hs.bookRepository.GetBookByID(id)
+}
+
+// RevertMember mengembalikan seluruh data anggota ke versi tertentu; password dan peran tidak ikut dikembalikan dan anggota yang
+// data pribadinya sudah dihapus tidak dapat dikembalikan
+func (hs *HistoryService) RevertMember(id, version int, changedBy string) (*models.Member, error) {
+	if changedBy == "" {
+		return nil, utils.NewAppError(http.StatusUnauthorized, "changed_by is required")
+	}
+	current, err := hs.memberRepository.GetMemberByID(id)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, "member not found")
+	}
+	if current.ErasedAt != nil {
+		return nil, utils.NewAppError(http.StatusConflict, "the personal data of this member has been erased and cannot be restored")
+	}
+
+	var member models.Member
+	if err := hs.loadSnapshot(models.HistoryEntityMember, id, version, &member); err != nil {
+		return nil, err
+	}
+	member.ID = id
+	member.DeletedAt = nil
+	member.DeletedBy = ""
+	member.ChangedBy = changedBy
+	if err := hs.memberRepository.RevertMember(&member); err != nil {
+		return nil, err
+	}
+
+	return hs.memberRepository.GetMemberByID(id)
+}
+
+// loadSnapshot membaca snapshot sebuah versi ke dalam target; versi yang mencatat penghapusan ditolak
+// karena record yang dihapus dipulihkan lewat trash
+func (hs *HistoryService) loadSnapshot(entityType string, entityID, version int, target interface{}) error {
+	v, err := hs.historyRepository.GetVersion(entityType, entityID, version)
+	if err != nil {
+		return utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	if v.Action == models.HistoryActionDelete {
+		return utils.NewAppError(http.StatusBadRequest, "cannot revert to a deletion; restore the record from the trash instead")
+	}
+
+	data, err := json.Marshal(v.Snapshot)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
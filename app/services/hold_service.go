@@ -0,0 +1,242 @@
+package services
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/repositories"
+	"Restful-Perpustakaan-API/app/utils"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// HoldService provides methods for placing and managing holds on editions and works
+type HoldService struct {
+	holdRepository         repositories.HoldRepository
+	bookRepository         repositories.BookRepository
+	workRepository         repositories.WorkRepository
+	memberRepository       repositories.MemberRepository
+	notificationRepository *repositories.NotificationRepository
+	blockService           *BlockService
+}
+
+// NewHoldService creates a new HoldService instance
+func NewHoldService(holdRepository repositories.HoldRepository, bookRepository repositories.BookRepository, workRepository repositories.WorkRepository, memberRepository repositories.MemberRepository, notificationRepository *repositories.NotificationRepository, blockService *BlockService) *HoldService {
+	return &HoldService{
+		holdRepository:         holdRepository,
+		bookRepository:         bookRepository,
+		workRepository:         workRepository,
+		memberRepository:       memberRepository,
+		notificationRepository: notificationRepository,
+		blockService:           blockService,
+	}
+}
+
+// PlaceHold membuat reservasi untuk sebuah buku. Jika anyEdition aktif, reservasi ditujukan ke karyanya
+// sehingga dapat dipenuhi oleh edisi mana saja.
+func (hs *HoldService) PlaceHold(memberID, bookID int, anyEdition bool) (*models.Hold, error) {
+	book, err := hs.bookRepository.GetBookByID(bookID)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+
+	hold := &models.Hold{MemberID: memberID}
+	if anyEdition {
+		if book.WorkID == nil {
+			return nil, utils.NewAppError(http.StatusBadRequest, "book is not linked to a work; place a hold on this edition instead")
+		}
+		hold.WorkID = book.WorkID
+	} else {
+		hold.BookID = &book.ID
+	}
+
+	return hold, hs.createHold(hold)
+}
+
+// PlaceWorkHold membuat reservasi "edisi mana saja" langsung pada sebuah karya
+func (hs *HoldService) PlaceWorkHold(memberID, workID int) (*models.Hold, error) {
+	if _, err := hs.workRepository.GetWorkByID(workID); err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+
+	hold := &models.Hold{MemberID: memberID, WorkID: &workID}
+	return hold, hs.createHold(hold)
+}
+
+// CancelHold membatalkan reservasi yang masih aktif milik anggota
+func (hs *HoldService) CancelHold(id, memberID int) error {
+	hold, err := hs.holdRepository.GetHoldByID(id)
+	if err != nil {
+		return utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	if hold.MemberID != memberID {
+		return utils.NewAppError(http.StatusForbidden, "holds can only be cancelled by the member who placed them")
+	}
+	if !isActiveHold(*hold) {
+		return utils.NewAppError(http.StatusConflict, "hold is no longer active")
+	}
+	return hs.holdRepository.UpdateHoldStatus(id, models.HoldStatusCancelled)
+}
+
+// GetHoldsByMemberID mengambil reservasi anggota beserta posisi antreannya
+func (hs *HoldService) GetHoldsByMemberID(memberID int) ([]models.Hold, error) {
+	holds, err := hs.holdRepository.GetHoldsByMemberID(memberID)
+	if err != nil {
+		return nil, err
+	}
+	if holds == nil {
+		holds = []models.Hold{}
+	}
+
+	for i := range holds {
+		if !isActiveHold(holds[i]) {
+			continue
+		}
+		queue, err := hs.queueFor(holds[i])
+		if err != nil {
+			return nil, err
+		}
+		for position, queued := range queue {
+			if queued.ID == holds[i].ID {
+				holds[i].Position = position + 1
+				break
+			}
+		}
+	}
+
+	return holds, nil
+}
+
+// GetHoldQueue mengambil antrean reservasi yang dapat dipenuhi oleh sebuah buku:
+// reservasi pada edisi ini dan reservasi "edisi mana saja" pada karyanya
+func (hs *HoldService) GetHoldQueue(bookID int) ([]models.Hold, error) {
+	book, err := hs.bookRepository.GetBookByID(bookID)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	queue, err := hs.holdRepository.GetActiveHolds([]int{book.ID}, book.WorkID)
+	if err != nil {
+		return nil, err
+	}
+	if queue == nil {
+		queue = []models.Hold{}
+	}
+	for i := range queue {
+		queue[i].Position = i + 1
+	}
+	return queue, nil
+}
+
+// BookReturned menyiapkan reservasi terdepan yang masih menunggu dalam antrean buku yang baru dikembalikan
+// dan memberi tahu anggotanya bahwa buku dapat diambil
+func (hs *HoldService) BookReturned(bookID int) error {
+	queue, err := hs.GetHoldQueue(bookID)
+	if err != nil {
+		return err
+	}
+	for _, hold := range queue {
+		if hold.Status != models.HoldStatusWaiting {
+			continue
+		}
+		if err := hs.holdRepository.UpdateHoldStatus(hold.ID, models.HoldStatusReady); err != nil {
+			return err
+		}
+		book, err := hs.bookRepository.GetBookByID(bookID)
+		if err != nil {
+			return err
+		}
+		hs.notify(hold.MemberID, fmt.Sprintf("%q is ready for you to pick up.", book.Title))
+		return nil
+	}
+	return nil
+}
+
+// BookBorrowed memenuhi reservasi aktif anggota yang dapat dipenuhi oleh buku yang baru dipinjamnya
+func (hs *HoldService) BookBorrowed(memberID, bookID int) error {
+	queue, err := hs.GetHoldQueue(bookID)
+	if err != nil {
+		return err
+	}
+	for _, hold := range queue {
+		if hold.MemberID == memberID {
+			return hs.holdRepository.UpdateHoldStatus(hold.ID, models.HoldStatusFulfilled)
+		}
+	}
+	return nil
+}
+
+// notify mengirim notifikasi reservasi kepada anggota; kegagalan hanya dicatat
+func (hs *HoldService) notify(memberID int, message string) {
+	n := &models.Notification{UserID: memberID, Message: message}
+	if err := hs.notificationRepository.CreateNotification(n); err != nil {
+		utils.GetLogger().WithError(err).WithField("member", memberID).Warn("failed to send hold notification")
+	}
+}
+
+// createHold memvalidasi anggota, menolak anggota yang diblokir dan mencegah reservasi ganda untuk target yang sama
+func (hs *HoldService) createHold(hold *models.Hold) error {
+	if _, err := hs.memberRepository.GetMemberByID(hold.MemberID); err != nil {
+		return utils.NewAppError(http.StatusBadRequest, "member not found")
+	}
+	if err := hs.blockService.CheckBorrowing(hold.MemberID); err != nil {
+		return err
+	}
+
+	existing, err := hs.holdRepository.GetHoldsByMemberID(hold.MemberID)
+	if err != nil {
+		return err
+	}
+	for _, h := range existing {
+		if isActiveHold(h) && sameIntPtr(h.BookID, hold.BookID) && sameIntPtr(h.WorkID, hold.WorkID) {
+			return utils.NewAppError(http.StatusConflict, "member already has an active hold for this title")
+		}
+	}
+
+	hold.Status = models.HoldStatusWaiting
+	hold.CreatedAt = time.Now()
+	if err := hs.holdRepository.CreateHold(hold); err != nil {
+		return err
+	}
+	recordActivity(hs.memberRepository, hold.MemberID)
+
+	queue, err := hs.queueFor(*hold)
+	if err != nil {
+		return err
+	}
+	hold.Position = len(queue)
+	return nil
+}
+
+// queueFor mengambil antrean yang bersaing dengan reservasi: untuk reservasi karya termasuk
+// reservasi pada setiap edisinya, untuk reservasi edisi termasuk reservasi pada karyanya
+func (hs *HoldService) queueFor(hold models.Hold) ([]models.Hold, error) {
+	if hold.WorkID != nil {
+		editions, err := hs.workRepository.GetEditions(*hold.WorkID)
+		if err != nil {
+			return nil, err
+		}
+		bookIDs := make([]int, 0, len(editions))
+		for _, edition := range editions {
+			bookIDs = append(bookIDs, edition.ID)
+		}
+		return hs.holdRepository.GetActiveHolds(bookIDs, hold.WorkID)
+	}
+
+	book, err := hs.bookRepository.GetBookByID(*hold.BookID)
+	if err != nil {
+		return nil, err
+	}
+	return hs.holdRepository.GetActiveHolds([]int{book.ID}, book.WorkID)
+}
+
+// isActiveHold mengembalikan true jika reservasi masih menunggu atau siap diambil
+func isActiveHold(hold models.Hold) bool {
+	return hold.Status == models.HoldStatusWaiting || hold.Status == models.HoldStatusReady
+}
+
+// sameIntPtr membandingkan dua *int berdasarkan nilainya
+func sameIntPtr(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
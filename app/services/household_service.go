@@ -0,0 +1,303 @@
+package services
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/repositories"
+	"Restful-Perpustakaan-API/app/utils"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HouseholdService provides methods for household accounts: grouping members under a primary account, guardian
+// consent for minors and acting on behalf of dependents
+type HouseholdService struct {
+	householdRepository repositories.HouseholdRepository
+	memberRepository    repositories.MemberRepository
+	loanService         *LoanService
+	holdService         *HoldService
+}
+
+// NewHouseholdService creates a new HouseholdService instance
+func NewHouseholdService(householdRepository repositories.HouseholdRepository, memberRepository repositories.MemberRepository, loanService *LoanService, holdService *HoldService) *HouseholdService {
+	return &HouseholdService{
+		householdRepository: householdRepository,
+		memberRepository:    memberRepository,
+		loanService:         loanService,
+		holdService:         holdService,
+	}
+}
+
+// GetHouseholds mengambil semua rumah tangga tanpa daftar anggotanya
+func (hs *HouseholdService) GetHouseholds() ([]models.Household, error) {
+	households, err := hs.householdRepository.GetHouseholds()
+	if err != nil {
+		return nil, err
+	}
+	if households == nil {
+		households = []models.Household{}
+	}
+	return households, nil
+}
+
+// GetHousehold mengambil rumah tangga beserta akun utama dan tanggungannya
+func (hs *HouseholdService) GetHousehold(id int) (*models.Household, error) {
+	household, err := hs.householdRepository.GetHouseholdByID(id)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	markMinors(household)
+	return household, nil
+}
+
+// CreateHousehold membuat rumah tangga baru. Akun utama harus anggota dewasa yang belum tergabung di rumah tangga lain.
+func (hs *HouseholdService) CreateHousehold(h *models.Household) error {
+	h.Name = strings.TrimSpace(h.Name)
+	if h.Name == "" {
+		return utils.NewAppError(http.StatusBadRequest, "name is required")
+	}
+	primary, err := hs.memberRepository.GetMemberByID(h.PrimaryMemberID)
+	if err != nil {
+		return utils.NewAppError(http.StatusNotFound, "primary member not found")
+	}
+	if primary.IsMinor(time.Now()) {
+		return utils.NewAppError(http.StatusBadRequest, "the primary account must belong to an adult")
+	}
+	if err := hs.checkNotInHousehold(primary.ID); err != nil {
+		return err
+	}
+
+	h.CreatedAt = time.Now()
+	if err := hs.householdRepository.CreateHousehold(h); err != nil {
+		return err
+	}
+	h.Members = []models.HouseholdMember{{
+		MemberID: primary.ID, Name: primary.Name, Email: primary.Email, Primary: true, BirthDate: primary.BirthDate, JoinedAt: h.CreatedAt,
+	}}
+	return nil
+}
+
+// DeleteHousehold menghapus rumah tangga; persetujuan wali untuk tanggungan ikut dicabut
+func (hs *HouseholdService) DeleteHousehold(id int) error {
+	if err := hs.householdRepository.DeleteHousehold(id); err != nil {
+		return utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	return nil
+}
+
+// AddMember menambahkan anggota sebagai tanggungan rumah tangga beserta izin wali untuknya
+func (hs *HouseholdService) AddMember(householdID int, hm *models.HouseholdMember) (*models.Household, error) {
+	household, err := hs.GetHousehold(householdID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := hs.memberRepository.GetMemberByID(hm.MemberID); err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, "member not found")
+	}
+	if hm.MemberID == household.PrimaryMemberID {
+		return nil, utils.NewAppError(http.StatusConflict, "member is the primary account of this household")
+	}
+	if err := hs.checkNotInHousehold(hm.MemberID); err != nil {
+		return nil, err
+	}
+
+	hm.JoinedAt = time.Now()
+	if err := hs.householdRepository.AddMember(householdID, hm); err != nil {
+		return nil, err
+	}
+	return hs.GetHousehold(householdID)
+}
+
+// UpdateMemberPermissions mengubah izin wali untuk mereservasi dan memperpanjang atas nama tanggungan
+func (hs *HouseholdService) UpdateMemberPermissions(householdID int, hm *models.HouseholdMember) (*models.Household, error) {
+	if err := hs.householdRepository.UpdateMemberPermissions(householdID, hm); err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	return hs.GetHousehold(householdID)
+}
+
+// RemoveMember mengeluarkan tanggungan dari rumah tangga; persetujuan wali untuknya ikut dicabut
+func (hs *HouseholdService) RemoveMember(householdID, memberID int) error {
+	if err := hs.householdRepository.RemoveMember(householdID, memberID); err != nil {
+		return utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	return nil
+}
+
+// GetHouseholdFines mengambil total denda seluruh anggota rumah tangga
+func (hs *HouseholdService) GetHouseholdFines(householdID int) (*models.HouseholdFines, error) {
+	if _, err := hs.householdRepository.GetHouseholdByID(householdID); err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	fines, err := hs.householdRepository.GetHouseholdFines(householdID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.HouseholdFines{HouseholdID: householdID, Members: []models.MemberFine{}}
+	for _, f := range fines {
+		result.Total += f.FineAmount
+		result.Members = append(result.Members, f)
+	}
+	return result, nil
+}
+
+// GetMyHousehold mengambil rumah tangga anggota yang sedang login, baik sebagai akun utama maupun tanggungan
+func (hs *HouseholdService) GetMyHousehold(memberID int) (*models.Household, error) {
+	household, err := hs.householdRepository.GetHouseholdByMemberID(memberID)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, "you do not belong to a household")
+	}
+	markMinors(household)
+	return household, nil
+}
+
+// GetMyHouseholdFines mengambil total denda rumah tangga; hanya akun utama yang dapat melihat denda tanggungan
+func (hs *HouseholdService) GetMyHouseholdFines(guardianID int) (*models.HouseholdFines, error) {
+	household, err := hs.guardianHousehold(guardianID)
+	if err != nil {
+		return nil, err
+	}
+	return hs.GetHouseholdFines(household.ID)
+}
+
+// GetDependentLoans mengambil peminjaman yang sedang berjalan milik setiap tanggungan
+func (hs *HouseholdService) GetDependentLoans(guardianID int) ([]models.DependentLoans, error) {
+	household, err := hs.guardianHousehold(guardianID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []models.DependentLoans{}
+	for _, hm := range household.Members {
+		if hm.Primary {
+			continue
+		}
+		loans, err := hs.loanService.GetLoansByMemberID(hm.MemberID)
+		if err != nil {
+			return nil, err
+		}
+		current := []models.Loan{}
+		for _, loan := range loans {
+			if loan.ReturnDate == nil {
+				current = append(current, loan)
+			}
+		}
+		result = append(result, models.DependentLoans{MemberID: hm.MemberID, Name: hm.Name, Loans: current})
+	}
+	return result, nil
+}
+
+// GiveConsent mencatat persetujuan wali agar tanggungan di bawah umur boleh meminjam
+func (hs *HouseholdService) GiveConsent(guardianID, dependentID int) (*models.HouseholdMember, error) {
+	dependent, err := hs.dependentOf(guardianID, dependentID)
+	if err != nil {
+		return nil, err
+	}
+	if !dependent.Minor {
+		return nil, utils.NewAppError(http.StatusConflict, fmt.Sprintf("guardian consent is only needed for members under %d", models.AdultAge))
+	}
+
+	now := time.Now()
+	if err := hs.householdRepository.SetConsent(dependentID, &now, strconv.Itoa(guardianID)); err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	dependent.GuardianConsentAt = &now
+	return dependent, nil
+}
+
+// WithdrawConsent mencabut persetujuan wali; tanggungan di bawah umur tidak dapat meminjam lagi
+func (hs *HouseholdService) WithdrawConsent(guardianID, dependentID int) error {
+	if _, err := hs.dependentOf(guardianID, dependentID); err != nil {
+		return err
+	}
+	if err := hs.householdRepository.SetConsent(dependentID, nil, strconv.Itoa(guardianID)); err != nil {
+		return utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	return nil
+}
+
+// PlaceHoldFor mereservasi buku atas nama tanggungan jika wali memiliki izin reservasi
+func (hs *HouseholdService) PlaceHoldFor(guardianID, dependentID, bookID int, anyEdition bool) (*models.Hold, error) {
+	dependent, err := hs.dependentOf(guardianID, dependentID)
+	if err != nil {
+		return nil, err
+	}
+	if !dependent.GuardianCanHold {
+		return nil, utils.NewAppError(http.StatusForbidden, "you are not allowed to place holds for this member")
+	}
+	return hs.holdService.PlaceHold(dependentID, bookID, anyEdition)
+}
+
+// RenewFor memperpanjang peminjaman tanggungan jika wali memiliki izin perpanjangan
+func (hs *HouseholdService) RenewFor(guardianID, dependentID, loanID int) (*models.Loan, error) {
+	dependent, err := hs.dependentOf(guardianID, dependentID)
+	if err != nil {
+		return nil, err
+	}
+	if !dependent.GuardianCanRenew {
+		return nil, utils.NewAppError(http.StatusForbidden, "you are not allowed to renew loans for this member")
+	}
+	loan, err := hs.loanService.GetLoanByID(loanID)
+	if err != nil || loan.MemberID != dependentID {
+		return nil, utils.NewAppError(http.StatusNotFound, "loan not found")
+	}
+	return hs.loanService.RenewLoan(loanID)
+}
+
+// guardianHousehold mengambil rumah tangga yang akun utamanya adalah guardianID
+func (hs *HouseholdService) guardianHousehold(guardianID int) (*models.Household, error) {
+	household, err := hs.GetMyHousehold(guardianID)
+	if err != nil {
+		return nil, err
+	}
+	if household.PrimaryMemberID != guardianID {
+		return nil, utils.NewAppError(http.StatusForbidden, "only the primary account of a household can act for its dependents")
+	}
+	return household, nil
+}
+
+// dependentOf mengambil tanggungan dependentID dari rumah tangga milik guardianID
+func (hs *HouseholdService) dependentOf(guardianID, dependentID int) (*models.HouseholdMember, error) {
+	household, err := hs.guardianHousehold(guardianID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range household.Members {
+		if hm := &household.Members[i]; !hm.Primary && hm.MemberID == dependentID {
+			return hm, nil
+		}
+	}
+	return nil, utils.NewAppError(http.StatusNotFound, "member is not a dependent of your household")
+}
+
+// checkNotInHousehold memastikan anggota belum tergabung di rumah tangga mana pun
+func (hs *HouseholdService) checkNotInHousehold(memberID int) error {
+	if household, err := hs.householdRepository.GetHouseholdByMemberID(memberID); err == nil {
+		return utils.NewAppError(http.StatusConflict, fmt.Sprintf("member already belongs to household %d", household.ID))
+	}
+	return nil
+}
+
+// markMinors menandai anggota rumah tangga yang masih di bawah umur
+func markMinors(household *models.Household) {
+	now := time.Now()
+	for i := range household.Members {
+		hm := &household.Members[i]
+		member := models.Member{BirthDate: hm.BirthDate}
+		hm.Minor = member.IsMinor(now)
+	}
+	if household.Members == nil {
+		household.Members = []models.HouseholdMember{}
+	}
+}
+
+// checkGuardianConsent memastikan anggota di bawah umur sudah mendapat persetujuan wali sebelum meminjam
+func checkGuardianConsent(member *models.Member) error {
+	if member.IsMinor(time.Now()) && member.GuardianConsentAt == nil {
+		return utils.NewAppError(http.StatusForbidden, fmt.Sprintf("members under %d need guardian consent to borrow", models.AdultAge))
+	}
+	return nil
+}
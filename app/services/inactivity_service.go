@@ -0,0 +1,159 @@
+package services
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/repositories"
+	"Restful-Perpustakaan-API/app/utils"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// InactivityService provides the report of members without login or circulation activity and the bulk
+// actions taken on them
+type InactivityService struct {
+	memberRepository       repositories.MemberRepository
+	membershipRepository   repositories.MembershipRepository
+	notificationRepository *repositories.NotificationRepository
+	mailer                 Mailer
+}
+
+// NewInactivityService creates a new InactivityService instance
+func NewInactivityService(memberRepository repositories.MemberRepository, membershipRepository repositories.MembershipRepository, notificationRepository *repositories.NotificationRepository, mailer Mailer) *InactivityService {
+	return &InactivityService{
+		memberRepository:       memberRepository,
+		membershipRepository:   membershipRepository,
+		notificationRepository: notificationRepository,
+		mailer:                 mailer,
+	}
+}
+
+// GetInactiveMembers mengambil anggota yang tidak login maupun meminjam, memperpanjang, mengembalikan atau
+// mereservasi selama months bulan terakhir; months 0 memakai models.DefaultInactiveMonths
+func (is *InactivityService) GetInactiveMembers(months int) (*models.InactiveMemberReport, error) {
+	if months < 0 {
+		return nil, utils.NewAppError(http.StatusBadRequest, "months must be positive")
+	}
+	if months == 0 {
+		months = models.DefaultInactiveMonths
+	}
+
+	cutoff := time.Now().AddDate(0, -months, 0)
+	members, err := is.memberRepository.GetInactiveMembers(cutoff)
+	if err != nil {
+		return nil, err
+	}
+	if members == nil {
+		members = []models.Member{}
+	}
+	return &models.InactiveMemberReport{Months: months, Cutoff: cutoff, Total: len(members), Members: members}, nil
+}
+
+// ApplyAction menjalankan tindakan massal pada anggota dalam laporan tidak aktif: mengakhiri keanggotaan atau
+// mengirim email. Anggota yang dipilih tetapi tidak ada di laporan dilewati, sehingga anggota yang baru aktif
+// kembali tidak ikut terkena.
+func (is *InactivityService) ApplyAction(action models.InactiveMemberAction) (*models.InactiveMemberActionResult, error) {
+	if action.Action != models.InactiveActionExpire && action.Action != models.InactiveActionEmail {
+		return nil, utils.NewAppError(http.StatusBadRequest, "action must be expire or email")
+	}
+	report, err := is.GetInactiveMembers(action.Months)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.InactiveMemberActionResult{Action: action.Action, Affected: []int{}, Skipped: []int{}, Failed: []int{}}
+	targets := report.Members
+	if len(action.MemberIDs) > 0 {
+		inactive := make(map[int]models.Member, len(report.Members))
+		for _, member := range report.Members {
+			inactive[member.ID] = member
+		}
+		targets = nil
+		for _, id := range action.MemberIDs {
+			member, ok := inactive[id]
+			if !ok {
+				result.Skipped = append(result.Skipped, id)
+				continue
+			}
+			targets = append(targets, member)
+		}
+	}
+	if len(targets) == 0 {
+		return result, nil
+	}
+
+	if action.Action == models.InactiveActionExpire {
+		return is.expire(targets, result)
+	}
+	return is.email(targets, action, report.Months, result), nil
+}
+
+// expire mengakhiri keanggotaan anggota sasaran yang masih aktif atau ditangguhkan dan memberi tahu mereka
+func (is *InactivityService) expire(targets []models.Member, result *models.InactiveMemberActionResult) (*models.InactiveMemberActionResult, error) {
+	ids := make([]int, len(targets))
+	for i, member := range targets {
+		ids[i] = member.ID
+	}
+	expired, err := is.membershipRepository.ExpireMembers(ids, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	done := make(map[int]bool, len(expired))
+	for _, member := range expired {
+		done[member.ID] = true
+		is.notify(member.ID, "Your library membership has expired because the account has been inactive. Please renew it to borrow again.")
+	}
+	for _, id := range ids {
+		if done[id] {
+			result.Affected = append(result.Affected, id)
+		} else {
+			result.Skipped = append(result.Skipped, id)
+		}
+	}
+	return result, nil
+}
+
+// email mengirim email kepada anggota sasaran; kegagalan per anggota dicatat di Failed tanpa menghentikan pengiriman
+func (is *InactivityService) email(targets []models.Member, action models.InactiveMemberAction, months int, result *models.InactiveMemberActionResult) *models.InactiveMemberActionResult {
+	subject := strings.TrimSpace(action.Subject)
+	if subject == "" {
+		subject = "We miss you at the library"
+	}
+	message := strings.TrimSpace(action.Message)
+	if message == "" {
+		message = fmt.Sprintf("We have not seen you at the library for more than %d months. Come and see what is new in the collection, or renew your membership to keep borrowing.", months)
+	}
+
+	for _, member := range targets {
+		if member.Email == "" {
+			result.Skipped = append(result.Skipped, member.ID)
+			continue
+		}
+		body := fmt.Sprintf("Hello %s,\n\n%s\n", member.Name, message)
+		if err := is.mailer.Send(member.Email, subject, body); err != nil {
+			utils.GetLogger().WithError(err).WithField("member", member.ID).Warn("failed to email inactive member")
+			result.Failed = append(result.Failed, member.ID)
+			continue
+		}
+		result.Affected = append(result.Affected, member.ID)
+	}
+	return result
+}
+
+// notify mengirim notifikasi kepada anggota; kegagalan hanya dicatat
+func (is *InactivityService) notify(memberID int, message string) {
+	n := &models.Notification{UserID: memberID, Message: message}
+	if err := is.notificationRepository.CreateNotification(n); err != nil {
+		utils.GetLogger().WithError(err).WithField("member", memberID).Warn("failed to send inactivity notification")
+	}
+}
+
+// recordActivity mencatat aktivitas sirkulasi anggota untuk laporan anggota tidak aktif; kegagalan hanya dicatat
+// agar tidak menggagalkan peminjaman yang sudah tersimpan
+func recordActivity(memberRepository repositories.MemberRepository, memberID int) {
+	if err := memberRepository.RecordActivity(memberID, time.Now()); err != nil {
+		utils.GetLogger().WithError(err).WithField("member", memberID).Warn("failed to record member activity")
+	}
+}
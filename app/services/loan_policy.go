@@ -0,0 +1,53 @@
+package services
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LoanPolicy holds the rules used to compute due dates and limit loans
+type LoanPolicy struct {
+	DefaultPeriod    time.Duration
+	Periods          map[string]time.Duration // lama pinjam per MembershipType (huruf kecil), e.g. "student"
+	OvernightDueHour int                      // jam pengembalian pinjaman cadangan "overnight" keesokan harinya
+	MaxLoans         int                      // batas peminjaman aktif (fisik + digital); 0 berarti tanpa batas
+	MaxRenewals      int                      // batas perpanjangan per peminjaman; 0 berarti tanpa batas
+}
+
+// ParseLoanPeriods mem-parse lama pinjam per jenis keanggotaan dalam hari, e.g. "Regular:14,Student:21"
+func ParseLoanPeriods(spec string) (map[string]time.Duration, error) {
+	periods := make(map[string]time.Duration)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, days, ok := strings.Cut(entry, ":")
+		n, err := strconv.Atoi(strings.TrimSpace(days))
+		if !ok || err != nil || n <= 0 || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid loan period %q, expected <membership type>:<days>", entry)
+		}
+		periods[strings.ToLower(strings.TrimSpace(name))] = time.Duration(n) * 24 * time.Hour
+	}
+	return periods, nil
+}
+
+// DueDate menghitung tanggal jatuh tempo biasa berdasarkan jenis keanggotaan
+func (p LoanPolicy) DueDate(membershipType string, now time.Time) time.Time {
+	if period, ok := p.Periods[strings.ToLower(membershipType)]; ok {
+		return now.Add(period)
+	}
+	return now.Add(p.DefaultPeriod)
+}
+
+// ReserveDueDate menghitung tanggal jatuh tempo pinjaman cadangan mata kuliah
+func (p LoanPolicy) ReserveDueDate(rule string, now time.Time) time.Time {
+	if rule == models.ReserveRuleOvernight {
+		tomorrow := now.AddDate(0, 0, 1)
+		return time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), p.OvernightDueHour, 0, 0, 0, now.Location())
+	}
+	return now.Add(2 * time.Hour)
+}
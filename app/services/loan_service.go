@@ -3,17 +3,36 @@ package services
 import (
 	"Restful-Perpustakaan-API/app/models"
 	"Restful-Perpustakaan-API/app/repositories"
+	"Restful-Perpustakaan-API/app/utils"
+	"fmt"
+	"net/http"
 	"time"
 )
 
 // LoanService provides methods for managing loans
 type LoanService struct {
-	loanRepository repositories.LoanRepository
+	loanRepository    repositories.LoanRepository
+	digitalRepository repositories.DigitalRepository
+	memberRepository  repositories.MemberRepository
+	courseRepository  repositories.CourseRepository
+	copyRepository    repositories.CopyRepository
+	holdService       *HoldService
+	blockService      *BlockService
+	policy            LoanPolicy
 }
 
 // NewLoanService creates a new LoanService instance
-func NewLoanService(loanRepository repositories.LoanRepository) *LoanService {
-	return &LoanService{loanRepository: loanRepository}
+func NewLoanService(loanRepository repositories.LoanRepository, digitalRepository repositories.DigitalRepository, memberRepository repositories.MemberRepository, courseRepository repositories.CourseRepository, copyRepository repositories.CopyRepository, holdService *HoldService, blockService *BlockService, policy LoanPolicy) *LoanService {
+	return &LoanService{
+		loanRepository:    loanRepository,
+		digitalRepository: digitalRepository,
+		memberRepository:  memberRepository,
+		courseRepository:  courseRepository,
+		copyRepository:    copyRepository,
+		holdService:       holdService,
+		blockService:      blockService,
+		policy:            policy,
+	}
 }
 
 // GetAllLoans mengambil semua peminjaman
@@ -26,15 +45,118 @@ func (ls *LoanService) GetLoanByID(id int) (*models.Loan, error) {
 	return ls.loanRepository.GetLoanByID(id)
 }
 
+// GetLoansByMemberID mengambil peminjaman anggota yang belum diarsipkan
+func (ls *LoanService) GetLoansByMemberID(memberID int) ([]models.Loan, error) {
+	loans, err := ls.loanRepository.GetLoansByMemberID(memberID)
+	if err != nil {
+		return nil, err
+	}
+	if loans == nil {
+		loans = []models.Loan{}
+	}
+	return loans, nil
+}
+
 // CreateLoan membuat peminjaman baru
 func (ls *LoanService) CreateLoan(l *models.Loan) error {
 	// Anda dapat menambahkan logika validasi atau bisnis lainnya di sini sebelum menyimpan peminjaman ke database
-	// Contoh: Memastikan buku tersedia, memeriksa batas peminjaman anggota, dll.
+	// Contoh: Memastikan buku tersedia, dll.
+	member, err := ls.memberRepository.GetMemberByID(l.MemberID)
+	if err != nil {
+		return utils.NewAppError(http.StatusNotFound, "member not found")
+	}
+	if err := checkMembershipActive(member); err != nil {
+		return err
+	}
+	if err := checkGuardianConsent(member); err != nil {
+		return err
+	}
+	if err := ls.blockService.CheckBorrowing(l.MemberID); err != nil {
+		return err
+	}
+	if err := checkLoanLimit(&ls.loanRepository, ls.digitalRepository, l.MemberID, ls.policy.MaxLoans); err != nil {
+		return err
+	}
+	if l.CopyID != nil {
+		bookCopy, err := ls.copyRepository.GetCopyByID(*l.CopyID)
+		if err != nil {
+			return utils.NewAppError(http.StatusNotFound, err.Error())
+		}
+		if bookCopy.BookID != l.BookID {
+			return utils.NewAppError(http.StatusBadRequest, "copy does not belong to the book")
+		}
+		if bookCopy.Status != models.CopyStatusAvailable {
+			return utils.NewAppError(http.StatusConflict, fmt.Sprintf("copy is not available (status %s)", bookCopy.Status))
+		}
+	}
 
 	// Set tanggal peminjaman saat ini
 	l.BorrowDate = time.Now()
 
-	return ls.loanRepository.CreateLoan(l)
+	dueDate, err := ls.dueDate(l)
+	if err != nil {
+		return err
+	}
+	l.DueDate = dueDate
+
+	if err := ls.loanRepository.CreateLoan(l); err != nil {
+		if err == repositories.ErrCopyNotAvailable {
+			return utils.NewAppError(http.StatusConflict, err.Error())
+		}
+		return err
+	}
+	recordActivity(ls.memberRepository, l.MemberID)
+
+	// Reservasi peminjam untuk buku ini terpenuhi; kegagalan hanya dicatat karena peminjaman sudah tersimpan
+	if err := ls.holdService.BookBorrowed(l.MemberID, l.BookID); err != nil {
+		utils.GetLogger().WithError(err).WithField("loan", l.ID).Warn("failed to fulfil hold for loan")
+	}
+	return nil
+}
+
+// dueDate menentukan jatuh tempo peminjaman. Eksemplar yang sedang menjadi cadangan mata kuliah selalu memakai
+// aturan cadangan (yang tersingkat jika ada beberapa); selain itu due_date dari klien dipakai, atau
+// dihitung dari jenis keanggotaan jika kosong.
+func (ls *LoanService) dueDate(l *models.Loan) (time.Time, error) {
+	reserves, err := ls.activeReserves(l, l.BorrowDate)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(reserves) > 0 {
+		var due time.Time
+		for _, reserve := range reserves {
+			reserveDue := ls.policy.ReserveDueDate(reserve.LoanRule, l.BorrowDate)
+			if due.IsZero() || reserveDue.Before(due) {
+				due = reserveDue
+			}
+		}
+		return due, nil
+	}
+
+	if !l.DueDate.IsZero() {
+		return l.DueDate, nil
+	}
+	member, err := ls.memberRepository.GetMemberByID(l.MemberID)
+	if err != nil {
+		return time.Time{}, utils.NewAppError(http.StatusNotFound, "member not found")
+	}
+	return ls.policy.DueDate(member.MembershipType, l.BorrowDate), nil
+}
+
+// activeReserves mengambil cadangan mata kuliah yang berlaku pada at untuk eksemplar yang dipinjam; cadangan
+// eksemplar tertentu tidak berlaku untuk eksemplar lain dari buku yang sama
+func (ls *LoanService) activeReserves(l *models.Loan, at time.Time) ([]models.CourseReserve, error) {
+	reserves, err := ls.courseRepository.GetActiveReservesByBookID(l.BookID, at)
+	if err != nil {
+		return nil, err
+	}
+	var applicable []models.CourseReserve
+	for i := range reserves {
+		if reserves[i].AppliesTo(l.CopyID) {
+			applicable = append(applicable, reserves[i])
+		}
+	}
+	return applicable, nil
 }
 
 // UpdateLoan memperbarui peminjaman
@@ -48,7 +170,94 @@ func (ls *LoanService) UpdateLoan(l *models.Loan) error {
 		l.ReturnDate = &now // Take the address of the time.Time value
 	}
 
-	return ls.loanRepository.UpdateLoan(l)
+	if err := ls.loanRepository.UpdateLoan(l); err != nil {
+		return err
+	}
+
+	// Peminjaman yang sudah kembali dipindahkan ke riwayat (dan dianonimkan jika anggota memilih keluar)
+	if l.Returned {
+		if l.MemberID != 0 {
+			recordActivity(ls.memberRepository, l.MemberID)
+		}
+		if err := ls.loanRepository.ArchiveLoan(l.ID); err != nil {
+			return err
+		}
+		// Buku yang kembali disiapkan untuk reservasi terdepan dalam antreannya
+		if err := ls.holdService.BookReturned(l.BookID); err != nil {
+			utils.GetLogger().WithError(err).WithField("loan", l.ID).Warn("failed to ready hold for returned book")
+		}
+	}
+	return nil
+}
+
+// RenewLoan memperpanjang jatuh tempo peminjaman dengan lama pinjam jenis keanggotaan, dihitung dari hari ini.
+// Perpanjangan ditolak untuk cadangan mata kuliah, buku yang sedang direservasi anggota lain, anggota yang tidak
+// boleh meminjam atau sedang diblokir, atau jika batas perpanjangan sudah tercapai.
+func (ls *LoanService) RenewLoan(id int) (*models.Loan, error) {
+	loan, err := ls.loanRepository.GetLoanByID(id)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	if loan.ReturnDate != nil {
+		return nil, utils.NewAppError(http.StatusConflict, "loan has already been returned")
+	}
+	if ls.policy.MaxRenewals > 0 && loan.RenewalCount >= ls.policy.MaxRenewals {
+		return nil, utils.NewAppError(http.StatusConflict, fmt.Sprintf("loan has reached the renewal limit of %d", ls.policy.MaxRenewals))
+	}
+
+	member, err := ls.memberRepository.GetMemberByID(loan.MemberID)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, "member not found")
+	}
+	if err := checkMembershipActive(member); err != nil {
+		return nil, err
+	}
+	if err := checkGuardianConsent(member); err != nil {
+		return nil, err
+	}
+	if err := ls.blockService.CheckBorrowing(loan.MemberID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	reserves, err := ls.activeReserves(loan, now)
+	if err != nil {
+		return nil, err
+	}
+	if len(reserves) > 0 {
+		return nil, utils.NewAppError(http.StatusConflict, "course reserve loans cannot be renewed")
+	}
+	queue, err := ls.holdService.GetHoldQueue(loan.BookID)
+	if err != nil {
+		return nil, err
+	}
+	for _, hold := range queue {
+		if hold.MemberID != loan.MemberID {
+			return nil, utils.NewAppError(http.StatusConflict, "book is on hold for another member and cannot be renewed")
+		}
+	}
+
+	dueDate := ls.policy.DueDate(member.MembershipType, now)
+	if !dueDate.After(loan.DueDate) {
+		return nil, utils.NewAppError(http.StatusConflict, "renewing now would not extend the due date")
+	}
+	if err := ls.loanRepository.RenewLoan(id, dueDate); err != nil {
+		return nil, utils.NewAppError(http.StatusConflict, err.Error())
+	}
+	loan.DueDate = dueDate
+	loan.RenewalCount++
+	recordActivity(ls.memberRepository, loan.MemberID)
+	return loan, nil
+}
+
+// RenewMemberLoan memperpanjang peminjaman atas permintaan anggota peminjamnya sendiri; peminjaman anggota lain
+// diperlakukan seperti tidak ada. Wali memperpanjang peminjaman tanggungan melalui HouseholdService.RenewFor.
+func (ls *LoanService) RenewMemberLoan(id, memberID int) (*models.Loan, error) {
+	loan, err := ls.loanRepository.GetLoanByID(id)
+	if err != nil || loan.MemberID != memberID {
+		return nil, utils.NewAppError(http.StatusNotFound, "loan not found")
+	}
+	return ls.RenewLoan(id)
 }
 
 // DeleteLoan menghapus peminjaman
@@ -56,4 +265,23 @@ func (ls *LoanService) DeleteLoan(id int) error {
 	return ls.loanRepository.DeleteLoan(id)
 }
 
-// ... (fungsi lain yang mungkin Anda butuhkan, seperti GetLoansByMemberID, GetOverdueLoans, dll.)
+// checkLoanLimit memastikan jumlah peminjaman fisik dan digital anggota yang masih aktif belum mencapai maxLoans
+func checkLoanLimit(loanRepository *repositories.LoanRepository, digitalRepository repositories.DigitalRepository, memberID, maxLoans int) error {
+	if maxLoans <= 0 {
+		return nil
+	}
+	physical, err := loanRepository.CountActiveLoansByMemberID(memberID)
+	if err != nil {
+		return err
+	}
+	digital, err := digitalRepository.CountActiveLoansByMemberID(memberID, time.Now())
+	if err != nil {
+		return err
+	}
+	if physical+digital >= maxLoans {
+		return utils.NewAppError(http.StatusConflict, fmt.Sprintf("member has reached the loan limit of %d", maxLoans))
+	}
+	return nil
+}
+
+// ... (fungsi lain yang mungkin Anda butuhkan, seperti GetOverdueLoans, dll.)
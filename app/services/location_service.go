@@ -0,0 +1,184 @@
+package services
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/repositories"
+	"Restful-Perpustakaan-API/app/utils"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// LocationService provides methods for managing the shelf location hierarchy
+type LocationService struct {
+	locationRepository repositories.LocationRepository
+	copyRepository     repositories.CopyRepository
+}
+
+// NewLocationService creates a new LocationService instance
+func NewLocationService(locationRepository repositories.LocationRepository, copyRepository repositories.CopyRepository) *LocationService {
+	return &LocationService{
+		locationRepository: locationRepository,
+		copyRepository:     copyRepository,
+	}
+}
+
+// GetAllLocations mengambil semua lokasi
+func (ls *LocationService) GetAllLocations() ([]models.Location, error) {
+	return ls.locationRepository.GetAllLocations()
+}
+
+// GetLocationByID mengambil lokasi berdasarkan ID
+func (ls *LocationService) GetLocationByID(id int) (*models.Location, error) {
+	location, err := ls.locationRepository.GetLocationByID(id)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	return location, nil
+}
+
+// CreateLocation membuat lokasi baru setelah memvalidasi posisinya di hierarki
+func (ls *LocationService) CreateLocation(l *models.Location) error {
+	if err := ls.validateLocation(l); err != nil {
+		return err
+	}
+	return ls.locationRepository.CreateLocation(l)
+}
+
+// UpdateLocation memperbarui lokasi; lokasi tidak boleh dipindah ke bawah dirinya sendiri
+func (ls *LocationService) UpdateLocation(l *models.Location) error {
+	if _, err := ls.GetLocationByID(l.ID); err != nil {
+		return err
+	}
+	if err := ls.validateLocation(l); err != nil {
+		return err
+	}
+	return ls.locationRepository.UpdateLocation(l)
+}
+
+// DeleteLocation menghapus lokasi yang tidak memiliki sub-lokasi maupun eksemplar
+func (ls *LocationService) DeleteLocation(id int) error {
+	locations, err := ls.locationRepository.GetAllLocations()
+	if err != nil {
+		return err
+	}
+	if _, ok := findLocation(locations, id); !ok {
+		return utils.NewAppError(http.StatusNotFound, "location not found")
+	}
+	if len(descendantLocationIDs(locations, id)) > 1 {
+		return utils.NewAppError(http.StatusConflict, "location still has child locations")
+	}
+	count, err := ls.copyRepository.CountCopiesAtLocations([]int{id})
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return utils.NewAppError(http.StatusConflict, "location still has copies shelved")
+	}
+	return ls.locationRepository.DeleteLocation(id)
+}
+
+// GetShelfList mengambil daftar baca rak untuk lokasi beserta seluruh sub-lokasinya,
+// diurutkan berdasarkan nomor panggil sehingga bisa dicocokkan langsung dengan urutan di rak
+func (ls *LocationService) GetShelfList(id int) ([]models.ShelfListEntry, error) {
+	locations, err := ls.locationRepository.GetAllLocations()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := findLocation(locations, id); !ok {
+		return nil, utils.NewAppError(http.StatusNotFound, "location not found")
+	}
+
+	entries, err := ls.copyRepository.GetShelfList(descendantLocationIDs(locations, id))
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []models.ShelfListEntry{}
+	}
+
+	for i := range entries {
+		shelf, _ := findLocation(locations, entries[i].LocationID)
+		if shelf.CallNumberStart != "" || shelf.CallNumberEnd != "" {
+			entries[i].OutOfRange = !utils.CallNumberInRange(entries[i].CallNumber, shelf.CallNumberStart, shelf.CallNumberEnd)
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if result := utils.CompareCallNumbers(entries[i].CallNumber, entries[j].CallNumber); result != 0 {
+			return result < 0
+		}
+		return entries[i].Barcode < entries[j].Barcode
+	})
+
+	return entries, nil
+}
+
+// validateLocation memastikan jenis lokasi valid dan induknya berada satu tingkat atau lebih di atasnya
+func (ls *LocationService) validateLocation(l *models.Location) error {
+	l.Name = strings.TrimSpace(l.Name)
+	l.Code = strings.TrimSpace(l.Code)
+	if l.Name == "" {
+		return utils.NewAppError(http.StatusBadRequest, "location name is required")
+	}
+	level, ok := models.LocationTypeLevels[l.Type]
+	if !ok {
+		return utils.NewAppError(http.StatusBadRequest, "location type must be branch, floor, room or shelf")
+	}
+	if l.CallNumberStart != "" && l.CallNumberEnd != "" && utils.CompareCallNumbers(l.CallNumberStart, l.CallNumberEnd) > 0 {
+		return utils.NewAppError(http.StatusBadRequest, "call_number_start must not be after call_number_end")
+	}
+
+	if l.ParentID == nil {
+		if l.Type != models.LocationTypeBranch {
+			return utils.NewAppError(http.StatusBadRequest, "only a branch can be a top-level location")
+		}
+		return nil
+	}
+
+	locations, err := ls.locationRepository.GetAllLocations()
+	if err != nil {
+		return err
+	}
+	parent, ok := findLocation(locations, *l.ParentID)
+	if !ok {
+		return utils.NewAppError(http.StatusBadRequest, "parent location not found")
+	}
+	if models.LocationTypeLevels[parent.Type] >= level {
+		return utils.NewAppError(http.StatusBadRequest, "a "+l.Type+" cannot be placed inside a "+parent.Type)
+	}
+	if l.ID != 0 {
+		for _, descendant := range descendantLocationIDs(locations, l.ID) {
+			if descendant == parent.ID {
+				return utils.NewAppError(http.StatusBadRequest, "a location cannot be moved inside itself")
+			}
+		}
+	}
+	return nil
+}
+
+// findLocation mencari lokasi berdasarkan ID
+func findLocation(locations []models.Location, id int) (models.Location, bool) {
+	for _, location := range locations {
+		if location.ID == id {
+			return location, true
+		}
+	}
+	return models.Location{}, false
+}
+
+// descendantLocationIDs mengembalikan id beserta ID semua sub-lokasinya
+func descendantLocationIDs(locations []models.Location, id int) []int {
+	children := make(map[int][]int)
+	for _, location := range locations {
+		if location.ParentID != nil {
+			children[*location.ParentID] = append(children[*location.ParentID], location.ID)
+		}
+	}
+
+	ids := []int{id}
+	for i := 0; i < len(ids); i++ {
+		ids = append(ids, children[ids[i]]...)
+	}
+	return ids
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"Restful-Perpustakaan-API/app/utils"
+	"errors"
+	"fmt"
+	"net/smtp"
+	"strings"
+)
+
+// Mailer sends plain text email to a single recipient
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+// smtpMailer sends email through an SMTP server with PLAIN authentication
+type smtpMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTPMailer creates a Mailer that sends through the SMTP server at host:port
+func NewSMTPMailer(host string, port int, user, password, from string) Mailer {
+	var auth smtp.Auth
+	if user != "" {
+		auth = smtp.PlainAuth("", user, password, host)
+	}
+	return &smtpMailer{addr: fmt.Sprintf("%s:%d", host, port), auth: auth, from: from}
+}
+
+// Send mengirim email teks biasa
+func (sm *smtpMailer) Send(to, subject, body string) error {
+	// Cegah header injection lewat alamat atau subjek
+	if strings.ContainsAny(to+subject, "\r\n") {
+		return errors.New("invalid email header")
+	}
+	msg := "From: " + sm.from + "\r\n" +
+		"To: " + to + "\r\n" +
+		"Subject: " + subject + "\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"\r\n" + body
+	return smtp.SendMail(sm.addr, sm.auth, sm.from, []string{to}, []byte(msg))
+}
+
+// logMailer writes email to the application log instead of sending it; used in development
+type logMailer struct{}
+
+// NewLogMailer creates a Mailer that only logs the messages
+func NewLogMailer() Mailer {
+	return logMailer{}
+}
+
+// Send mencatat email ke log
+func (logMailer) Send(to, subject, body string) error {
+	utils.GetLogger().WithField("to", to).WithField("subject", subject).Info(body)
+	return nil
+}
@@ -3,16 +3,22 @@ package services
 import (
 	"Restful-Perpustakaan-API/app/models"
 	"Restful-Perpustakaan-API/app/repositories"
+	"Restful-Perpustakaan-API/app/utils"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
 )
 
 // MemberService provides methods for managing members
 type MemberService struct {
 	memberRepository repositories.MemberRepository
+	membershipMonths int // masa berlaku keanggotaan baru dalam bulan; 0 berarti tidak kedaluwarsa
 }
 
 // NewMemberService creates a new MemberService instance
-func NewMemberService(memberRepository repositories.MemberRepository) *MemberService {
-	return &MemberService{memberRepository: memberRepository}
+func NewMemberService(memberRepository repositories.MemberRepository, membershipMonths int) *MemberService {
+	return &MemberService{memberRepository: memberRepository, membershipMonths: membershipMonths}
 }
 
 // GetAllMembers mengambil semua anggota
@@ -25,9 +31,24 @@ func (ms *MemberService) GetMemberByID(id int) (*models.Member, error) {
 	return ms.memberRepository.GetMemberByID(id)
 }
 
-// CreateMember membuat anggota baru
+// CreateMember membuat anggota baru; anggota yang didaftarkan pustakawan langsung aktif dengan masa berlaku default
 func (ms *MemberService) CreateMember(m *models.Member) error {
 	// Anda dapat menambahkan logika validasi atau bisnis lainnya di sini sebelum menyimpan anggota ke database
+	if m.MembershipStatus == "" {
+		m.MembershipStatus = models.MembershipStatusActive
+	}
+	if m.MembershipStatus != models.MembershipStatusActive && m.MembershipStatus != models.MembershipStatusPending {
+		return utils.NewAppError(http.StatusBadRequest, "new members must be pending or active")
+	}
+	// Password hanya diisi lewat pendaftaran atau tautan pengaturan password, tidak pernah dari data staf
+	m.Password = ""
+	if m.MembershipStatus == models.MembershipStatusActive && m.MembershipStart == nil {
+		now := time.Now()
+		m.MembershipStart = &now
+		if m.MembershipEnd == nil {
+			m.MembershipEnd = membershipEnd(now, ms.membershipMonths)
+		}
+	}
 	return ms.memberRepository.CreateMember(m)
 }
 
@@ -37,9 +58,19 @@ func (ms *MemberService) UpdateMember(m *models.Member) error {
 	return ms.memberRepository.UpdateMember(m)
 }
 
-// DeleteMember menghapus anggota
-func (ms *MemberService) DeleteMember(id int) error {
-	return ms.memberRepository.DeleteMember(id)
+// DeleteMember memindahkan anggota ke trash; anggota yang masih memiliki denda tidak dapat dihapus
+func (ms *MemberService) DeleteMember(id int, deletedBy string) error {
+	if _, err := ms.memberRepository.GetMemberByID(id); err != nil {
+		return utils.NewAppError(http.StatusNotFound, "member not found")
+	}
+	fines, err := ms.memberRepository.GetOutstandingFines(id)
+	if err != nil {
+		return err
+	}
+	if fines > 0 {
+		return utils.NewAppError(http.StatusConflict, fmt.Sprintf("member has outstanding fines of %.2f and cannot be deleted", fines))
+	}
+	return ms.memberRepository.DeleteMember(id, deletedBy)
 }
 
 // GetMemberByEmail mengambil anggota berdasarkan email
@@ -47,4 +78,47 @@ func (ms *MemberService) GetMemberByEmail(email string) (*models.Member, error)
 	return ms.memberRepository.GetMemberByEmail(email)
 }
 
-// ... (fungsi lain yang mungkin Anda butuhkan, seperti SearchMembers, dll.)
+// SearchMembers mencari anggota dengan gabungan filter, urutan dan halaman. Tanpa filter, semua anggota
+// dikembalikan per halaman, diurutkan berdasarkan nama.
+func (ms *MemberService) SearchMembers(search models.MemberSearch) (*models.MemberSearchResult, error) {
+	search.Query = strings.TrimSpace(search.Query)
+	if search.Sort == "" {
+		search.Sort = "name"
+	}
+	if !containsString(models.MemberSortFields, strings.TrimPrefix(search.Sort, "-")) {
+		return nil, utils.NewAppError(http.StatusBadRequest, "sort must be one of "+strings.Join(models.MemberSortFields, ", ")+", optionally prefixed with -")
+	}
+	switch search.Status {
+	case "", models.MembershipStatusPending, models.MembershipStatusActive, models.MembershipStatusSuspended,
+		models.MembershipStatusExpired, models.MembershipStatusClosed:
+	default:
+		return nil, utils.NewAppError(http.StatusBadRequest, "unknown membership status "+search.Status)
+	}
+	if search.RegisteredAfter != nil && search.RegisteredBefore != nil && !search.RegisteredBefore.After(*search.RegisteredAfter) {
+		return nil, utils.NewAppError(http.StatusBadRequest, "registered_before must be after registered_after")
+	}
+	if search.Page < 1 {
+		search.Page = 1
+	}
+	if search.PerPage < 1 {
+		search.PerPage = models.DefaultMemberPageSize
+	}
+	if search.PerPage > models.MaxMemberPageSize {
+		search.PerPage = models.MaxMemberPageSize
+	}
+
+	members, total, err := ms.memberRepository.SearchMembers(search)
+	if err != nil {
+		return nil, err
+	}
+	if members == nil {
+		members = []models.Member{}
+	}
+	return &models.MemberSearchResult{
+		Members:    members,
+		Page:       search.Page,
+		PerPage:    search.PerPage,
+		Total:      total,
+		TotalPages: (total + search.PerPage - 1) / search.PerPage,
+	}, nil
+}
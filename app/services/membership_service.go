@@ -0,0 +1,243 @@
+package services
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/repositories"
+	"Restful-Perpustakaan-API/app/utils"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// MembershipConfig holds the membership period, renewal fee and expiry reminder rules
+type MembershipConfig struct {
+	Months         int           // masa berlaku keanggotaan baru dan perpanjangan default, dalam bulan; 0 berarti tidak kedaluwarsa
+	RenewalFee     float64       // biaya perpanjangan default yang ditambahkan ke denda anggota
+	ReminderBefore time.Duration // pengingat dikirim sejauh ini sebelum keanggotaan berakhir
+}
+
+// MembershipService provides methods for the membership lifecycle: activation, suspension, renewal and expiry
+type MembershipService struct {
+	membershipRepository   repositories.MembershipRepository
+	memberRepository       repositories.MemberRepository
+	notificationRepository *repositories.NotificationRepository
+	cfg                    MembershipConfig
+}
+
+// NewMembershipService creates a new MembershipService instance
+func NewMembershipService(membershipRepository repositories.MembershipRepository, memberRepository repositories.MemberRepository, notificationRepository *repositories.NotificationRepository, cfg MembershipConfig) *MembershipService {
+	return &MembershipService{
+		membershipRepository:   membershipRepository,
+		memberRepository:       memberRepository,
+		notificationRepository: notificationRepository,
+		cfg:                    cfg,
+	}
+}
+
+// GetMembership mengambil status dan masa berlaku keanggotaan anggota
+func (ms *MembershipService) GetMembership(memberID int) (*models.Member, error) {
+	member, err := ms.memberRepository.GetMemberByID(memberID)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, "member not found")
+	}
+	return member, nil
+}
+
+// UpdateStatus mengubah status keanggotaan. Aktivasi anggota pending memulai masa berlaku keanggotaan;
+// note dikirim ke anggota bersama notifikasi perubahan status.
+func (ms *MembershipService) UpdateStatus(memberID int, status, note, changedBy string) (*models.Member, error) {
+	if changedBy == "" {
+		return nil, utils.NewAppError(http.StatusUnauthorized, "changed_by is required")
+	}
+	if !models.IsMembershipStatus(status) {
+		return nil, utils.NewAppError(http.StatusBadRequest, "status must be pending, active, suspended, expired or closed")
+	}
+	member, err := ms.GetMembership(memberID)
+	if err != nil {
+		return nil, err
+	}
+	if !member.CanMoveMembershipTo(status) {
+		if member.MembershipStatus == models.MembershipStatusExpired && status == models.MembershipStatusActive {
+			return nil, utils.NewAppError(http.StatusConflict, "membership has expired; renew it to reactivate")
+		}
+		return nil, utils.NewAppError(http.StatusConflict, fmt.Sprintf("membership cannot change from %s to %s", member.MembershipStatus, status))
+	}
+
+	if member.MembershipStatus == models.MembershipStatusPending && status == models.MembershipStatusActive {
+		now := time.Now()
+		member.MembershipStart = &now
+		member.MembershipEnd = membershipEnd(now, ms.cfg.Months)
+	}
+	member.MembershipStatus = status
+	member.ChangedBy = changedBy
+	if err := ms.membershipRepository.UpdateStatus(member); err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("Your library membership is now %s.", status)
+	if note != "" {
+		message += " Note from the librarian: " + note
+	}
+	ms.notify(member.ID, message)
+	return member, nil
+}
+
+// Renew memperpanjang keanggotaan aktif atau yang sudah kedaluwarsa sebanyak months bulan (0 berarti masa default).
+// Perpanjangan dihitung dari akhir masa berlaku jika belum lewat, selain itu dari hari ini. fee nil berarti biaya
+// default; biaya dicatat di buku denda anggota.
+func (ms *MembershipService) Renew(memberID, months int, fee *float64, renewedBy string) (*models.MembershipRenewal, error) {
+	if renewedBy == "" {
+		return nil, utils.NewAppError(http.StatusUnauthorized, "renewed_by is required")
+	}
+	if months == 0 {
+		months = ms.cfg.Months
+	}
+	if months <= 0 {
+		return nil, utils.NewAppError(http.StatusBadRequest, "months must be positive")
+	}
+	renewal := &models.MembershipRenewal{MemberID: memberID, Months: months, Fee: ms.cfg.RenewalFee, RenewedBy: renewedBy, RenewedAt: time.Now()}
+	if fee != nil {
+		renewal.Fee = *fee
+	}
+	if renewal.Fee < 0 {
+		return nil, utils.NewAppError(http.StatusBadRequest, "fee must not be negative")
+	}
+
+	member, err := ms.GetMembership(memberID)
+	if err != nil {
+		return nil, err
+	}
+	switch member.MembershipStatus {
+	case models.MembershipStatusActive, models.MembershipStatusExpired:
+	case models.MembershipStatusPending:
+		return nil, utils.NewAppError(http.StatusConflict, "membership must be activated before it can be renewed")
+	default:
+		return nil, utils.NewAppError(http.StatusConflict, fmt.Sprintf("membership is %s and cannot be renewed", member.MembershipStatus))
+	}
+
+	from := renewal.RenewedAt
+	if member.MembershipEnd != nil && member.MembershipEnd.After(from) {
+		from = *member.MembershipEnd
+	}
+	renewal.PreviousEnd = member.MembershipEnd
+	renewal.NewEnd = from.AddDate(0, months, 0)
+	if err := ms.membershipRepository.Renew(renewal); err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("Your library membership has been renewed until %s.", renewal.NewEnd.Format("2006-01-02"))
+	if renewal.Fee > 0 {
+		message += fmt.Sprintf(" A renewal fee of %.2f has been added to your fines.", renewal.Fee)
+	}
+	ms.notify(memberID, message)
+	return renewal, nil
+}
+
+// GetRenewals mengambil riwayat perpanjangan keanggotaan anggota
+func (ms *MembershipService) GetRenewals(memberID int) ([]models.MembershipRenewal, error) {
+	if _, err := ms.GetMembership(memberID); err != nil {
+		return nil, err
+	}
+	renewals, err := ms.membershipRepository.GetRenewals(memberID)
+	if err != nil {
+		return nil, err
+	}
+	if renewals == nil {
+		renewals = []models.MembershipRenewal{}
+	}
+	return renewals, nil
+}
+
+// GetFineLedger mengambil catatan biaya yang ditambahkan ke denda anggota
+func (ms *MembershipService) GetFineLedger(memberID int) ([]models.FineLedgerEntry, error) {
+	if _, err := ms.GetMembership(memberID); err != nil {
+		return nil, err
+	}
+	entries, err := ms.membershipRepository.GetFineLedger(memberID)
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []models.FineLedgerEntry{}
+	}
+	return entries, nil
+}
+
+// RunExpiry menandai keanggotaan yang sudah lewat masa berlakunya sebagai expired dan mengirim pengingat
+// kepada anggota yang keanggotaannya akan segera berakhir; setiap masa berlaku hanya diingatkan sekali
+func (ms *MembershipService) RunExpiry() (*models.MembershipJobResult, error) {
+	now := time.Now()
+	result := &models.MembershipJobResult{}
+
+	expired, err := ms.membershipRepository.ExpireMemberships(now)
+	if err != nil {
+		return nil, err
+	}
+	for _, member := range expired {
+		ms.notify(member.ID, "Your library membership has expired. Please renew it to continue borrowing.")
+	}
+	result.Expired = len(expired)
+
+	if ms.cfg.ReminderBefore <= 0 {
+		return result, nil
+	}
+	expiring, err := ms.membershipRepository.GetMembershipsToRemind(now, now.Add(ms.cfg.ReminderBefore))
+	if err != nil {
+		return nil, err
+	}
+	for _, member := range expiring {
+		ms.notify(member.ID, fmt.Sprintf("Your library membership expires on %s. Please renew it to keep borrowing.", member.MembershipEnd.Format("2006-01-02")))
+		if err := ms.membershipRepository.MarkReminded(member.ID, *member.MembershipEnd); err != nil {
+			return nil, err
+		}
+		result.Reminded++
+	}
+	return result, nil
+}
+
+// StartExpiryJob menjalankan RunExpiry secara berkala di latar belakang
+func (ms *MembershipService) StartExpiryJob(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			result, err := ms.RunExpiry()
+			if err != nil {
+				utils.GetLogger().WithError(err).Error("failed to process membership expiry")
+				continue
+			}
+			if result.Expired > 0 || result.Reminded > 0 {
+				utils.GetLogger().WithField("expired", result.Expired).WithField("reminded", result.Reminded).Info("memberships processed")
+			}
+		}
+	}()
+}
+
+// membershipEnd menghitung akhir masa berlaku keanggotaan yang dimulai pada start; nil jika keanggotaan tidak kedaluwarsa
+func membershipEnd(start time.Time, months int) *time.Time {
+	if months <= 0 {
+		return nil
+	}
+	end := start.AddDate(0, months, 0)
+	return &end
+}
+
+// notify mengirim notifikasi keanggotaan kepada anggota; kegagalan hanya dicatat
+func (ms *MembershipService) notify(memberID int, message string) {
+	n := &models.Notification{UserID: memberID, Message: message}
+	if err := ms.notificationRepository.CreateNotification(n); err != nil {
+		utils.GetLogger().WithError(err).WithField("member", memberID).Warn("failed to send membership notification")
+	}
+}
+
+// checkMembershipActive memastikan anggota boleh meminjam: hanya keanggotaan aktif yang belum lewat masa berlakunya
+func checkMembershipActive(member *models.Member) error {
+	if member.MembershipActive(time.Now()) {
+		return nil
+	}
+	if member.MembershipStatus == models.MembershipStatusActive || member.MembershipStatus == models.MembershipStatusExpired {
+		return utils.NewAppError(http.StatusForbidden, "membership has expired; renew it to borrow")
+	}
+	return utils.NewAppError(http.StatusForbidden, fmt.Sprintf("membership is %s; only active members can borrow", member.MembershipStatus))
+}
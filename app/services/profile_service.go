@@ -0,0 +1,262 @@
+package services
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/repositories"
+	"Restful-Perpustakaan-API/app/utils"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ProfileService provides self-service methods for the logged-in member: profile, password, email and dashboard
+type ProfileService struct {
+	profileRepository      repositories.ProfileRepository
+	memberRepository       repositories.MemberRepository
+	notificationRepository *repositories.NotificationRepository
+	loanService            *LoanService
+	holdService            *HoldService
+	blockService           *BlockService
+	mailer                 Mailer
+	emailTokenTTL          time.Duration // masa berlaku token verifikasi email baru
+}
+
+// NewProfileService creates a new ProfileService instance
+func NewProfileService(profileRepository repositories.ProfileRepository, memberRepository repositories.MemberRepository, notificationRepository *repositories.NotificationRepository, loanService *LoanService, holdService *HoldService, blockService *BlockService, mailer Mailer, emailTokenTTL time.Duration) *ProfileService {
+	return &ProfileService{
+		profileRepository:      profileRepository,
+		memberRepository:       memberRepository,
+		notificationRepository: notificationRepository,
+		loanService:            loanService,
+		holdService:            holdService,
+		blockService:           blockService,
+		mailer:                 mailer,
+		emailTokenTTL:          emailTokenTTL,
+	}
+}
+
+// GetProfile mengambil profil lengkap anggota yang sedang login
+func (ps *ProfileService) GetProfile(memberID int) (*models.Member, error) {
+	member, err := ps.profileRepository.GetProfile(memberID)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	return member, nil
+}
+
+// UpdateProfile memperbarui field profil yang boleh diubah anggota sendiri
+func (ps *ProfileService) UpdateProfile(memberID int, p *models.ProfileUpdate) (*models.Member, error) {
+	if p.Name != nil {
+		name := strings.TrimSpace(*p.Name)
+		if name == "" {
+			return nil, utils.NewAppError(http.StatusBadRequest, "name cannot be empty")
+		}
+		p.Name = &name
+	}
+	for field, value := range map[string]*string{"phone_number": p.PhoneNumber, "address": p.Address, "gender": p.Gender} {
+		if value != nil && !utils.ValidateStringLength(*value, 0, 255) {
+			return nil, utils.NewAppError(http.StatusBadRequest, fmt.Sprintf("%s must be at most 255 characters", field))
+		}
+	}
+
+	if err := ps.profileRepository.UpdateProfile(memberID, p); err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	return ps.GetProfile(memberID)
+}
+
+// ChangePassword mengganti password setelah password lama dicocokkan
+func (ps *ProfileService) ChangePassword(memberID int, change *models.PasswordChange) error {
+	hash, err := ps.profileRepository.GetPasswordHash(memberID)
+	if err != nil {
+		return utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	if err := ps.checkPassword(hash, change.CurrentPassword); err != nil {
+		return err
+	}
+	if !utils.ValidatePassword(change.NewPassword) {
+		return utils.NewAppError(http.StatusBadRequest, "new password must be at least 8 characters and contain upper case, lower case and a number")
+	}
+	if change.NewPassword == change.CurrentPassword {
+		return utils.NewAppError(http.StatusBadRequest, "new password must differ from the current password")
+	}
+
+	newHash, err := bcrypt.GenerateFromPassword([]byte(change.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	if err := ps.profileRepository.UpdatePassword(memberID, string(newHash)); err != nil {
+		return err
+	}
+	ps.notify(memberID, "Your password was changed. If this was not you, contact the library immediately.")
+	return nil
+}
+
+// RequestEmailChange memulai penggantian email: token verifikasi dikirim ke alamat baru dan email baru berlaku
+// setelah token dikonfirmasi. Password diminta agar sesi yang tertinggal tidak dapat mengambil alih akun.
+func (ps *ProfileService) RequestEmailChange(memberID int, newEmail, password string) (*models.EmailChange, error) {
+	newEmail = strings.TrimSpace(newEmail)
+	if !utils.ValidateEmail(newEmail) {
+		return nil, utils.NewAppError(http.StatusBadRequest, "invalid email address")
+	}
+	member, err := ps.GetProfile(memberID)
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(member.Email, newEmail) {
+		return nil, utils.NewAppError(http.StatusBadRequest, "new email is the same as the current email")
+	}
+	hash, err := ps.profileRepository.GetPasswordHash(memberID)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	if err := ps.checkPassword(hash, password); err != nil {
+		return nil, err
+	}
+	if existing, err := ps.memberRepository.GetMemberByEmail(newEmail); err == nil && existing != nil {
+		return nil, utils.NewAppError(http.StatusConflict, "email is already in use")
+	}
+
+	token, err := newVerificationToken()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	change := &models.EmailChange{
+		MemberID:    memberID,
+		NewEmail:    newEmail,
+		TokenHash:   hashVerificationToken(token),
+		RequestedAt: now,
+		ExpiresAt:   now.Add(ps.emailTokenTTL),
+	}
+	if err := ps.profileRepository.SaveEmailChange(change); err != nil {
+		return nil, err
+	}
+
+	body := fmt.Sprintf("Hello %s,\n\nUse this code to confirm your new library email address: %s\n\nThe code expires at %s. If you did not request this change, ignore this email.\n",
+		member.Name, token, change.ExpiresAt.Format(time.RFC1123))
+	if err := ps.mailer.Send(newEmail, "Confirm your new email address", body); err != nil {
+		utils.GetLogger().WithError(err).WithField("member", memberID).Error("failed to send email verification")
+		return nil, utils.NewAppError(http.StatusBadGateway, "failed to send the verification email")
+	}
+	ps.notify(memberID, fmt.Sprintf("A change of your email address to %s was requested. It takes effect once the new address is confirmed.", newEmail))
+	return change, nil
+}
+
+// ConfirmEmailChange menerapkan email baru jika token verifikasi cocok dan belum kedaluwarsa
+func (ps *ProfileService) ConfirmEmailChange(memberID int, token string) (*models.Member, error) {
+	change, err := ps.profileRepository.GetEmailChange(memberID)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	if subtle.ConstantTimeCompare([]byte(change.TokenHash), []byte(hashVerificationToken(strings.TrimSpace(token)))) != 1 {
+		return nil, utils.NewAppError(http.StatusBadRequest, "invalid verification code")
+	}
+	if time.Now().After(change.ExpiresAt) {
+		return nil, utils.NewAppError(http.StatusGone, "verification code has expired; request the email change again")
+	}
+	if existing, err := ps.memberRepository.GetMemberByEmail(change.NewEmail); err == nil && existing != nil {
+		return nil, utils.NewAppError(http.StatusConflict, "email is already in use")
+	}
+
+	if err := ps.profileRepository.ConfirmEmailChange(memberID, change.NewEmail); err != nil {
+		return nil, err
+	}
+	ps.notify(memberID, fmt.Sprintf("Your email address was changed to %s.", change.NewEmail))
+	return ps.GetProfile(memberID)
+}
+
+// GetDashboard mengambil ringkasan akun anggota: peminjaman berjalan, reservasi aktif, denda, blokir yang berlaku
+// dan notifikasi belum dibaca
+func (ps *ProfileService) GetDashboard(memberID int) (*models.MemberDashboard, error) {
+	member, err := ps.GetProfile(memberID)
+	if err != nil {
+		return nil, err
+	}
+	dashboard := &models.MemberDashboard{
+		Member:              *member,
+		Loans:               []models.Loan{},
+		Holds:               []models.Hold{},
+		FineAmount:          member.FineAmount,
+		UnreadNotifications: []models.Notification{},
+	}
+
+	loans, err := ps.loanService.GetLoansByMemberID(memberID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for _, loan := range loans {
+		if loan.ReturnDate != nil {
+			continue
+		}
+		dashboard.Loans = append(dashboard.Loans, loan)
+		if now.After(loan.DueDate) {
+			dashboard.OverdueLoans++
+		}
+	}
+
+	holds, err := ps.holdService.GetHoldsByMemberID(memberID)
+	if err != nil {
+		return nil, err
+	}
+	for _, hold := range holds {
+		if isActiveHold(hold) {
+			dashboard.Holds = append(dashboard.Holds, hold)
+		}
+	}
+
+	blocks, err := ps.blockService.GetMemberActiveBlocks(memberID)
+	if err != nil {
+		return nil, err
+	}
+	dashboard.Blocks = blocks
+
+	notifications, err := ps.notificationRepository.GetUnreadNotificationsByUserID(memberID)
+	if err != nil {
+		return nil, err
+	}
+	dashboard.UnreadNotifications = append(dashboard.UnreadNotifications, notifications...)
+
+	if change, err := ps.profileRepository.GetEmailChange(memberID); err == nil && now.Before(change.ExpiresAt) {
+		dashboard.PendingEmailChange = change
+	}
+	return dashboard, nil
+}
+
+// checkPassword mencocokkan password dengan hash yang tersimpan
+func (ps *ProfileService) checkPassword(hash, password string) error {
+	if hash == "" || bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) != nil {
+		return utils.NewAppError(http.StatusForbidden, "current password is incorrect")
+	}
+	return nil
+}
+
+// notify membuat notifikasi untuk anggota; kegagalan hanya dicatat di log
+func (ps *ProfileService) notify(memberID int, message string) {
+	if err := ps.notificationRepository.CreateNotification(&models.Notification{UserID: memberID, Message: message}); err != nil {
+		utils.GetLogger().WithError(err).WithField("member", memberID).Warn("failed to create profile notification")
+	}
+}
+
+// newVerificationToken membuat kode verifikasi acak
+func newVerificationToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// hashVerificationToken mengembalikan hash token yang disimpan di database
+func hashVerificationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
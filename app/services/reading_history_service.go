@@ -0,0 +1,85 @@
+package services
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/repositories"
+	"Restful-Perpustakaan-API/app/utils"
+	"net/http"
+	"time"
+)
+
+// ReadingHistoryService provides methods for members' reading history and privacy settings
+type ReadingHistoryService struct {
+	readingHistoryRepository repositories.ReadingHistoryRepository
+}
+
+// NewReadingHistoryService creates a new ReadingHistoryService instance
+func NewReadingHistoryService(readingHistoryRepository repositories.ReadingHistoryRepository) *ReadingHistoryService {
+	return &ReadingHistoryService{readingHistoryRepository: readingHistoryRepository}
+}
+
+// GetReadingHistory mengambil riwayat baca anggota; anggota yang memilih keluar hanya melihat riwayat yang belum dianonimkan
+func (rs *ReadingHistoryService) GetReadingHistory(memberID int) (*models.ReadingHistory, error) {
+	settings, err := rs.readingHistoryRepository.GetPrivacySettings(memberID)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	entries, err := rs.readingHistoryRepository.GetReadingHistory(memberID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []models.ReadingHistoryEntry{}
+	}
+
+	return &models.ReadingHistory{
+		ReadingHistoryEnabled: settings.ReadingHistoryEnabled,
+		Entries:               entries,
+	}, nil
+}
+
+// GetPrivacySettings mengambil pengaturan privasi anggota
+func (rs *ReadingHistoryService) GetPrivacySettings(memberID int) (*models.PrivacySettings, error) {
+	settings, err := rs.readingHistoryRepository.GetPrivacySettings(memberID)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	return settings, nil
+}
+
+// UpdatePrivacySettings menyimpan pengaturan privasi anggota; memilih keluar langsung menganonimkan riwayat yang ada
+func (rs *ReadingHistoryService) UpdatePrivacySettings(memberID int, settings *models.PrivacySettings) error {
+	if _, err := rs.readingHistoryRepository.GetPrivacySettings(memberID); err != nil {
+		return utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	return rs.readingHistoryRepository.UpdatePrivacySettings(memberID, settings, time.Now())
+}
+
+// IsReadingHistoryEnabled mengembalikan true jika riwayat baca anggota boleh disimpan dan dipakai
+func (rs *ReadingHistoryService) IsReadingHistoryEnabled(memberID int) (bool, error) {
+	settings, err := rs.readingHistoryRepository.GetPrivacySettings(memberID)
+	if err != nil {
+		return false, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	return settings.ReadingHistoryEnabled, nil
+}
+
+// StartAnonymizeJob menganonimkan peminjaman digital yang sudah kedaluwarsa secara berkala, karena
+// peminjaman digital tidak memiliki proses pengembalian yang bisa memicu anonimisasi
+func (rs *ReadingHistoryService) StartAnonymizeJob(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			count, err := rs.readingHistoryRepository.AnonymizeExpiredDigitalLoans(time.Now())
+			if err != nil {
+				utils.GetLogger().WithError(err).Error("failed to anonymize expired digital loans")
+				continue
+			}
+			if count > 0 {
+				utils.GetLogger().WithField("loans", count).Info("expired digital loans anonymized")
+			}
+		}
+	}()
+}
@@ -0,0 +1,403 @@
+package services
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/repositories"
+	"Restful-Perpustakaan-API/app/utils"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// readingListSharePath adalah prefiks tautan berbagi daftar bacaan
+const readingListSharePath = "/lists/shared/"
+
+// ReadingListService provides methods for managing members' reading lists and wishlists.
+// viewerID adalah ID anggota yang sedang login (dari token JWT), atau 0 untuk pengunjung anonim.
+type ReadingListService struct {
+	readingListRepository repositories.ReadingListRepository
+	bookRepository        repositories.BookRepository
+	memberRepository      repositories.MemberRepository
+	copyRepository        repositories.CopyRepository
+	loanRepository        *repositories.LoanRepository
+	holdService           *HoldService
+}
+
+// NewReadingListService creates a new ReadingListService instance
+func NewReadingListService(readingListRepository repositories.ReadingListRepository, bookRepository repositories.BookRepository, memberRepository repositories.MemberRepository, copyRepository repositories.CopyRepository, loanRepository *repositories.LoanRepository, holdService *HoldService) *ReadingListService {
+	return &ReadingListService{
+		readingListRepository: readingListRepository,
+		bookRepository:        bookRepository,
+		memberRepository:      memberRepository,
+		copyRepository:        copyRepository,
+		loanRepository:        loanRepository,
+		holdService:           holdService,
+	}
+}
+
+// GetLists mengambil daftar bacaan anggota; selain pemilik hanya melihat daftar publik
+func (rs *ReadingListService) GetLists(memberID, viewerID int) ([]models.ReadingList, error) {
+	if _, err := rs.memberRepository.GetMemberByID(memberID); err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, "member not found")
+	}
+	lists, err := rs.readingListRepository.GetListsByMemberID(memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := []models.ReadingList{}
+	for _, list := range lists {
+		if viewerID == memberID {
+			list.ShareURL = readingListSharePath + list.ShareToken
+		} else if list.Visibility != models.ListVisibilityPublic {
+			continue
+		}
+		visible = append(visible, list)
+	}
+	return visible, nil
+}
+
+// GetPublicLists mengambil semua daftar bacaan publik, e.g. daftar bacaan kelas
+func (rs *ReadingListService) GetPublicLists() ([]models.ReadingList, error) {
+	lists, err := rs.readingListRepository.GetPublicLists()
+	if err != nil {
+		return nil, err
+	}
+	if lists == nil {
+		lists = []models.ReadingList{}
+	}
+	return lists, nil
+}
+
+// GetList mengambil satu daftar bacaan beserta isinya
+func (rs *ReadingListService) GetList(memberID, listID, viewerID int) (*models.ReadingList, error) {
+	list, err := rs.findList(memberID, listID)
+	if err != nil {
+		return nil, err
+	}
+	if viewerID != memberID && list.Visibility != models.ListVisibilityPublic {
+		return nil, utils.NewAppError(http.StatusNotFound, "reading list not found")
+	}
+	return rs.withItems(list, viewerID)
+}
+
+// GetSharedList mengambil daftar bacaan melalui tautan berbagi; daftar privat tidak bisa dibuka lewat tautan
+func (rs *ReadingListService) GetSharedList(token string, viewerID int) (*models.ReadingList, error) {
+	list, err := rs.readingListRepository.GetListByShareToken(token)
+	if err != nil || (list.Visibility == models.ListVisibilityPrivate && viewerID != list.MemberID) {
+		return nil, utils.NewAppError(http.StatusNotFound, "reading list not found")
+	}
+	return rs.withItems(list, viewerID)
+}
+
+// CreateList membuat daftar bacaan baru milik anggota yang sedang login
+func (rs *ReadingListService) CreateList(memberID, viewerID int, list *models.ReadingList) error {
+	if err := requireListOwner(memberID, viewerID); err != nil {
+		return err
+	}
+	if list.Visibility == "" {
+		list.Visibility = models.ListVisibilityPrivate
+	}
+	if err := validateReadingList(list); err != nil {
+		return err
+	}
+
+	token, err := newShareToken()
+	if err != nil {
+		return err
+	}
+	list.MemberID = memberID
+	list.ShareToken = token
+	if err := rs.readingListRepository.CreateList(list); err != nil {
+		return err
+	}
+	list.ShareURL = readingListSharePath + list.ShareToken
+	return nil
+}
+
+// UpdateList memperbarui nama, deskripsi dan visibilitas daftar bacaan
+func (rs *ReadingListService) UpdateList(memberID, listID, viewerID int, update *models.ReadingList) (*models.ReadingList, error) {
+	list, err := rs.ownedList(memberID, listID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if update.Visibility == "" {
+		update.Visibility = list.Visibility
+	}
+	if err := validateReadingList(update); err != nil {
+		return nil, err
+	}
+
+	list.Name = update.Name
+	list.Description = update.Description
+	list.Visibility = update.Visibility
+	if err := rs.readingListRepository.UpdateList(list); err != nil {
+		return nil, err
+	}
+	return rs.withItems(list, viewerID)
+}
+
+// DeleteList menghapus daftar bacaan beserta isinya
+func (rs *ReadingListService) DeleteList(memberID, listID, viewerID int) error {
+	if _, err := rs.ownedList(memberID, listID, viewerID); err != nil {
+		return err
+	}
+	return rs.readingListRepository.DeleteList(listID)
+}
+
+// RegenerateShareLink membuat tautan berbagi baru; tautan lama tidak berlaku lagi
+func (rs *ReadingListService) RegenerateShareLink(memberID, listID, viewerID int) (*models.ReadingList, error) {
+	list, err := rs.ownedList(memberID, listID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	token, err := newShareToken()
+	if err != nil {
+		return nil, err
+	}
+	list.ShareToken = token
+	if err := rs.readingListRepository.UpdateList(list); err != nil {
+		return nil, err
+	}
+	list.ShareURL = readingListSharePath + list.ShareToken
+	return list, nil
+}
+
+// AddItem menambahkan buku ke akhir daftar bacaan
+func (rs *ReadingListService) AddItem(memberID, listID, viewerID int, item *models.ReadingListItem) error {
+	if _, err := rs.ownedList(memberID, listID, viewerID); err != nil {
+		return err
+	}
+	book, err := rs.bookRepository.GetBookByID(item.BookID)
+	if err != nil {
+		return utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+
+	items, err := rs.readingListRepository.GetItems(listID)
+	if err != nil {
+		return err
+	}
+	for _, existing := range items {
+		if existing.BookID == item.BookID {
+			return utils.NewAppError(http.StatusConflict, "book is already on this reading list")
+		}
+	}
+
+	item.ListID = listID
+	if err := rs.readingListRepository.AddItem(item); err != nil {
+		return err
+	}
+	item.Title = book.Title
+	item.Author = book.Author
+	return nil
+}
+
+// UpdateItemNote memperbarui catatan sebuah buku di daftar bacaan
+func (rs *ReadingListService) UpdateItemNote(memberID, listID, itemID, viewerID int, note string) (*models.ReadingListItem, error) {
+	item, err := rs.ownedItem(memberID, listID, itemID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := rs.readingListRepository.UpdateItemNote(itemID, note); err != nil {
+		return nil, err
+	}
+	item.Note = note
+	return item, nil
+}
+
+// RemoveItem menghapus buku dari daftar bacaan
+func (rs *ReadingListService) RemoveItem(memberID, listID, itemID, viewerID int) error {
+	if _, err := rs.ownedItem(memberID, listID, itemID, viewerID); err != nil {
+		return err
+	}
+	return rs.readingListRepository.RemoveItem(itemID)
+}
+
+// ReorderItems mengubah urutan daftar bacaan; itemIDs harus memuat setiap item tepat satu kali
+func (rs *ReadingListService) ReorderItems(memberID, listID, viewerID int, itemIDs []int) (*models.ReadingList, error) {
+	list, err := rs.ownedList(memberID, listID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := rs.readingListRepository.GetItems(listID)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := make(map[int]bool, len(items))
+	for _, item := range items {
+		remaining[item.ID] = true
+	}
+	for _, id := range itemIDs {
+		if !remaining[id] {
+			return nil, utils.NewAppError(http.StatusBadRequest, "item_ids must list every item of the reading list exactly once")
+		}
+		delete(remaining, id)
+	}
+	if len(remaining) > 0 {
+		return nil, utils.NewAppError(http.StatusBadRequest, "item_ids must list every item of the reading list exactly once")
+	}
+
+	if err := rs.readingListRepository.ReorderItems(listID, itemIDs); err != nil {
+		return nil, err
+	}
+	return rs.withItems(list, viewerID)
+}
+
+// PlaceHolds membuat reservasi atas nama anggota yang sedang login untuk setiap buku di daftar bacaan
+// yang dapat dilihatnya. Buku yang tidak bisa direservasi (e.g. sudah direservasi) dilewati beserta alasannya.
+func (rs *ReadingListService) PlaceHolds(memberID, listID, viewerID int) (*models.ReadingListHoldResult, error) {
+	if viewerID == 0 {
+		return nil, utils.NewAppError(http.StatusUnauthorized, "authentication required")
+	}
+	list, err := rs.GetList(memberID, listID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	return rs.placeHolds(list, viewerID)
+}
+
+// PlaceSharedHolds sama dengan PlaceHolds untuk daftar bacaan yang dibuka melalui tautan berbagi
+func (rs *ReadingListService) PlaceSharedHolds(token string, viewerID int) (*models.ReadingListHoldResult, error) {
+	if viewerID == 0 {
+		return nil, utils.NewAppError(http.StatusUnauthorized, "authentication required")
+	}
+	list, err := rs.GetSharedList(token, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	return rs.placeHolds(list, viewerID)
+}
+
+// placeHolds mereservasi setiap buku di daftar bacaan untuk viewerID. Buku yang masih memiliki eksemplar
+// tersedia tidak perlu direservasi dan ikut dilewati.
+func (rs *ReadingListService) placeHolds(list *models.ReadingList, viewerID int) (*models.ReadingListHoldResult, error) {
+	result := &models.ReadingListHoldResult{Placed: []models.Hold{}, Skipped: []models.ReadingListHoldSkip{}}
+	for _, item := range list.Items {
+		available, err := rs.hasAvailableCopy(item.BookID)
+		if err != nil {
+			return nil, err
+		}
+		if available {
+			result.Skipped = append(result.Skipped, models.ReadingListHoldSkip{BookID: item.BookID, Reason: "book is available to borrow"})
+			continue
+		}
+
+		hold, err := rs.holdService.PlaceHold(viewerID, item.BookID, false)
+		if err != nil {
+			var appErr *utils.AppError
+			if !errors.As(err, &appErr) {
+				return nil, err
+			}
+			result.Skipped = append(result.Skipped, models.ReadingListHoldSkip{BookID: item.BookID, Reason: appErr.Message})
+			continue
+		}
+		result.Placed = append(result.Placed, *hold)
+	}
+	return result, nil
+}
+
+// hasAvailableCopy memeriksa apakah buku masih memiliki eksemplar di rak, yaitu eksemplar yang bisa dipinjam
+// (tidak hilang atau ditarik) lebih banyak daripada peminjaman buku yang belum dikembalikan
+func (rs *ReadingListService) hasAvailableCopy(bookID int) (bool, error) {
+	copies, err := rs.copyRepository.GetCopiesByBookID(bookID)
+	if err != nil {
+		return false, err
+	}
+	loanable := 0
+	for _, c := range copies {
+		if c.Status == models.CopyStatusAvailable || c.Status == models.CopyStatusOnLoan {
+			loanable++
+		}
+	}
+	if loanable == 0 {
+		return false, nil
+	}
+	active, err := rs.loanRepository.CountActiveLoansByBookID(bookID)
+	if err != nil {
+		return false, err
+	}
+	return loanable > active, nil
+}
+
+// findList mengambil daftar bacaan dan memastikan daftar tersebut milik memberID
+func (rs *ReadingListService) findList(memberID, listID int) (*models.ReadingList, error) {
+	list, err := rs.readingListRepository.GetListByID(listID)
+	if err != nil || list.MemberID != memberID {
+		return nil, utils.NewAppError(http.StatusNotFound, "reading list not found")
+	}
+	return list, nil
+}
+
+// ownedList mengambil daftar bacaan yang boleh diubah oleh viewerID
+func (rs *ReadingListService) ownedList(memberID, listID, viewerID int) (*models.ReadingList, error) {
+	if err := requireListOwner(memberID, viewerID); err != nil {
+		return nil, err
+	}
+	return rs.findList(memberID, listID)
+}
+
+// ownedItem mengambil item daftar bacaan yang boleh diubah oleh viewerID
+func (rs *ReadingListService) ownedItem(memberID, listID, itemID, viewerID int) (*models.ReadingListItem, error) {
+	if _, err := rs.ownedList(memberID, listID, viewerID); err != nil {
+		return nil, err
+	}
+	item, err := rs.readingListRepository.GetItemByID(itemID)
+	if err != nil || item.ListID != listID {
+		return nil, utils.NewAppError(http.StatusNotFound, "reading list item not found")
+	}
+	return item, nil
+}
+
+// withItems melengkapi daftar bacaan dengan isinya; tautan berbagi hanya disertakan untuk pemilik
+func (rs *ReadingListService) withItems(list *models.ReadingList, viewerID int) (*models.ReadingList, error) {
+	items, err := rs.readingListRepository.GetItems(list.ID)
+	if err != nil {
+		return nil, err
+	}
+	if items == nil {
+		items = []models.ReadingListItem{}
+	}
+	list.Items = items
+	list.ShareURL = ""
+	if viewerID == list.MemberID {
+		list.ShareURL = readingListSharePath + list.ShareToken
+	}
+	return list, nil
+}
+
+// requireListOwner memastikan yang mengubah daftar bacaan adalah pemiliknya
+func requireListOwner(memberID, viewerID int) error {
+	if viewerID == 0 {
+		return utils.NewAppError(http.StatusUnauthorized, "authentication required")
+	}
+	if viewerID != memberID {
+		return utils.NewAppError(http.StatusForbidden, "reading lists can only be changed by their owner")
+	}
+	return nil
+}
+
+// validateReadingList memeriksa nama dan visibilitas daftar bacaan
+func validateReadingList(list *models.ReadingList) error {
+	list.Name = strings.TrimSpace(list.Name)
+	if list.Name == "" {
+		return utils.NewAppError(http.StatusBadRequest, "name is required")
+	}
+	switch list.Visibility {
+	case models.ListVisibilityPrivate, models.ListVisibilityShared, models.ListVisibilityPublic:
+		return nil
+	default:
+		return utils.NewAppError(http.StatusBadRequest, "visibility must be private, shared or public")
+	}
+}
+
+// newShareToken membuat token acak untuk tautan berbagi
+func newShareToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -7,12 +7,16 @@ import (
 
 // RecommendationService provides methods for getting book recommendations
 type RecommendationService struct {
-	bookRepository repositories.BookRepository
+	bookRepository        repositories.BookRepository
+	readingHistoryService *ReadingHistoryService
 }
 
 // NewRecommendationService creates a new RecommendationService instance
-func NewRecommendationService(bookRepository repositories.BookRepository) *RecommendationService {
-	return &RecommendationService{bookRepository: bookRepository}
+func NewRecommendationService(bookRepository repositories.BookRepository, readingHistoryService *ReadingHistoryService) *RecommendationService {
+	return &RecommendationService{
+		bookRepository:        bookRepository,
+		readingHistoryService: readingHistoryService,
+	}
 }
 
 // GetRecommendations mengambil rekomendasi buku umum
@@ -20,7 +24,15 @@ func (rs *RecommendationService) GetRecommendations() ([]models.Book, error) {
 	return rs.bookRepository.GetRecommendations()
 }
 
-// GetPersonalizedRecommendations mengambil rekomendasi buku yang dipersonalisasi berdasarkan ID anggota
+// GetPersonalizedRecommendations mengambil rekomendasi buku yang dipersonalisasi berdasarkan ID anggota.
+// Anggota yang menonaktifkan riwayat baca mendapat rekomendasi umum; riwayat peminjamannya tidak dipakai.
 func (rs *RecommendationService) GetPersonalizedRecommendations(memberID int) ([]models.Book, error) {
+	enabled, err := rs.readingHistoryService.IsReadingHistoryEnabled(memberID)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return rs.bookRepository.GetRecommendations()
+	}
 	return rs.bookRepository.GetPersonalizedRecommendations(memberID)
 }
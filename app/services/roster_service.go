@@ -0,0 +1,449 @@
+package services
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/repositories"
+	"Restful-Perpustakaan-API/app/utils"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// rosterImportFields memetakan nama field impor ke setter pada models.Member
+var rosterImportFields = map[string]func(m *models.Member, value string) error{
+	"student_id":      func(m *models.Member, value string) error { m.StudentID = value; return nil },
+	"name":            func(m *models.Member, value string) error { m.Name = value; return nil },
+	"email":           func(m *models.Member, value string) error { m.Email = strings.ToLower(value); return nil },
+	"phone_number":    func(m *models.Member, value string) error { m.PhoneNumber = value; return nil },
+	"address":         func(m *models.Member, value string) error { m.Address = value; return nil },
+	"gender":          func(m *models.Member, value string) error { m.Gender = value; return nil },
+	"membership_type": func(m *models.Member, value string) error { m.MembershipType = value; return nil },
+	"birth_date": func(m *models.Member, value string) error {
+		return parseRosterDate(value, &m.BirthDate)
+	},
+	"membership_end": func(m *models.Member, value string) error {
+		return parseRosterDate(value, &m.MembershipEnd)
+	},
+}
+
+// RosterService imports school and university rosters as members and lets imported members set their password
+type RosterService struct {
+	memberRepository  repositories.MemberRepository
+	profileRepository repositories.ProfileRepository
+	mailer            Mailer
+	membershipMonths  int
+	setupURL          string        // halaman pembuatan password; token ditambahkan sebagai ?token=
+	setupTTL          time.Duration // masa berlaku tautan pembuatan password
+}
+
+// NewRosterService creates a new RosterService instance
+func NewRosterService(memberRepository repositories.MemberRepository, profileRepository repositories.ProfileRepository, mailer Mailer, membershipMonths int, setupURL string, setupTTL time.Duration) *RosterService {
+	return &RosterService{
+		memberRepository:  memberRepository,
+		profileRepository: profileRepository,
+		mailer:            mailer,
+		membershipMonths:  membershipMonths,
+		setupURL:          setupURL,
+		setupTTL:          setupTTL,
+	}
+}
+
+// rosterEntry adalah satu baris daftar yang sudah diurai beserta anggota yang cocok
+type rosterEntry struct {
+	row      int
+	member   models.Member
+	existing *models.Member
+	errors   []models.RosterImportRowError
+}
+
+// ImportRoster mengimpor daftar mahasiswa/siswa: anggota dicocokkan berdasarkan NIM/NIS lalu email, anggota baru
+// dibuat dan anggota lama diperbarui dengan jenis dan masa berlaku keanggotaan dari daftar. Seluruh daftar disimpan
+// dalam satu transaksi dan tidak ada yang disimpan jika satu baris gagal, sehingga dry run menunjukkan persis apa
+// yang akan terjadi. Pada impor daftar lengkap, anggota daftar yang sama yang tidak ada di file dinonaktifkan.
+func (rs *RosterService) ImportRoster(r io.Reader, opts models.RosterImportOptions) (*models.RosterImportResult, error) {
+	opts.Roster = strings.TrimSpace(opts.Roster)
+	if opts.Roster == "" {
+		return nil, utils.NewAppError(http.StatusBadRequest, "roster name is required")
+	}
+	if opts.ImportedBy == "" {
+		return nil, utils.NewAppError(http.StatusUnauthorized, "importing user is required")
+	}
+	if opts.MembershipType == "" {
+		opts.MembershipType = models.DefaultRosterMembershipType
+	}
+	for column, field := range opts.Mapping {
+		if _, ok := rosterImportFields[field]; field != "" && !ok {
+			return nil, utils.NewAppError(http.StatusBadRequest, fmt.Sprintf("unknown member field %q mapped from column %q", field, column))
+		}
+	}
+
+	rows, err := readImportRows(r, opts.Format)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusBadRequest, err.Error())
+	}
+	// Daftar lengkap tanpa baris data akan menonaktifkan seluruh anggota daftar itu; hampir pasti file yang salah
+	if opts.FullRoster && len(rows) == 0 {
+		return nil, utils.NewAppError(http.StatusBadRequest, "full roster import has no data rows")
+	}
+
+	result := &models.RosterImportResult{
+		DryRun:     opts.DryRun,
+		FullRoster: opts.FullRoster,
+		Roster:     opts.Roster,
+		TotalRows:  len(rows),
+		Rows:       []models.RosterImportRow{},
+		Deactivate: []models.Member{},
+		Errors:     []models.RosterImportRowError{},
+	}
+
+	entries := make([]*rosterEntry, 0, len(rows))
+	seenStudentID := make(map[string]int)
+	seenEmail := make(map[string]int)
+	var studentIDs, emails []string
+	for _, row := range rows {
+		entry := &rosterEntry{row: row.number}
+		entries = append(entries, entry)
+		if row.err != nil {
+			entry.errors = append(entry.errors, models.RosterImportRowError{Row: row.number, Message: row.err.Error()})
+			continue
+		}
+
+		entry.member, entry.errors = buildRosterMember(row, opts.Mapping)
+		m := &entry.member
+		if m.StudentID != "" {
+			if firstRow, ok := seenStudentID[m.StudentID]; ok {
+				entry.errors = append(entry.errors, models.RosterImportRowError{Row: row.number, Field: "student_id", Message: fmt.Sprintf("duplicate student ID, already used in row %d", firstRow)})
+			} else {
+				seenStudentID[m.StudentID] = row.number
+				studentIDs = append(studentIDs, m.StudentID)
+			}
+		}
+		if m.Email != "" {
+			if firstRow, ok := seenEmail[m.Email]; ok {
+				entry.errors = append(entry.errors, models.RosterImportRowError{Row: row.number, Field: "email", Message: fmt.Sprintf("duplicate email, already used in row %d", firstRow)})
+			} else {
+				seenEmail[m.Email] = row.number
+				emails = append(emails, m.Email)
+			}
+		}
+	}
+
+	existing, err := rs.memberRepository.GetMembersByStudentIDsOrEmails(studentIDs, emails)
+	if err != nil {
+		return nil, err
+	}
+	byStudentID := make(map[string]*models.Member, len(existing))
+	byEmail := make(map[string]*models.Member, len(existing))
+	for i := range existing {
+		if existing[i].StudentID != "" {
+			byStudentID[existing[i].StudentID] = &existing[i]
+		}
+		byEmail[strings.ToLower(existing[i].Email)] = &existing[i]
+	}
+
+	now := time.Now()
+	defaultEnd := opts.MembershipEnd
+	if defaultEnd == nil {
+		defaultEnd = membershipEnd(now, rs.membershipMonths)
+	}
+
+	var creates, updates []models.Member
+	var createRows []int
+	matched := make(map[int]bool)
+	for _, entry := range entries {
+		if len(entry.errors) == 0 {
+			rs.matchRosterEntry(entry, byStudentID, byEmail, matched)
+		}
+		if len(entry.errors) > 0 {
+			result.Errors = append(result.Errors, entry.errors...)
+			result.Rows = append(result.Rows, models.RosterImportRow{Row: entry.row, Action: models.RosterActionError,
+				StudentID: entry.member.StudentID, Email: entry.member.Email, Name: entry.member.Name})
+			result.Failed++
+			continue
+		}
+
+		member := mergeRosterMember(entry.member, entry.existing, opts, defaultEnd, now)
+		row := models.RosterImportRow{Row: entry.row, StudentID: member.StudentID, Email: member.Email, Name: member.Name}
+		switch {
+		case entry.existing == nil:
+			row.Action = models.RosterActionCreate
+			creates = append(creates, member)
+			createRows = append(createRows, len(result.Rows))
+			result.Created++
+		case rosterMemberChanged(*entry.existing, member):
+			row.Action = models.RosterActionUpdate
+			row.MemberID = member.ID
+			updates = append(updates, member)
+			result.Updated++
+		default:
+			row.Action = models.RosterActionUnchanged
+			row.MemberID = member.ID
+			result.Unchanged++
+		}
+		result.Rows = append(result.Rows, row)
+	}
+
+	var deactivate []int
+	if opts.FullRoster {
+		members, err := rs.memberRepository.GetRosterMembers(opts.Roster)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range members {
+			if !matched[m.ID] {
+				deactivate = append(deactivate, m.ID)
+				result.Deactivate = append(result.Deactivate, m)
+			}
+		}
+		result.Deactivated = len(deactivate)
+	}
+
+	if opts.DryRun || result.Failed > 0 {
+		return result, nil
+	}
+	if len(creates) == 0 && len(updates) == 0 && len(deactivate) == 0 {
+		return result, nil
+	}
+	if err := rs.memberRepository.ImportMembers(creates, updates, deactivate, opts.ImportedBy); err != nil {
+		return nil, err
+	}
+	for i := range creates {
+		result.Rows[createRows[i]].MemberID = creates[i].ID
+	}
+
+	if opts.SendWelcome {
+		for i := range creates {
+			if err := rs.sendWelcome(&creates[i], now); err != nil {
+				utils.GetLogger().WithError(err).WithField("member", creates[i].ID).Error("failed to send roster welcome email")
+				continue
+			}
+			result.WelcomeSent++
+		}
+	}
+	return result, nil
+}
+
+// SetupPassword menyimpan password pertama anggota impor melalui tautan dari email sambutan
+func (rs *RosterService) SetupPassword(token, password string) error {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return utils.NewAppError(http.StatusBadRequest, "token is required")
+	}
+	if !utils.ValidatePassword(password) {
+		return utils.NewAppError(http.StatusBadRequest, "password does not meet the requirements")
+	}
+	setup, err := rs.profileRepository.GetPasswordSetup(hashVerificationToken(token))
+	if err != nil || time.Now().After(setup.ExpiresAt) {
+		return utils.NewAppError(http.StatusBadRequest, "the password setup link is invalid or has expired")
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	if err := rs.profileRepository.CompletePasswordSetup(setup.MemberID, string(hash)); err != nil {
+		return utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	return nil
+}
+
+// matchRosterEntry mencari anggota yang cocok dengan baris, pertama berdasarkan NIM/NIS lalu email.
+// Anggota baru wajib memiliki nama dan email; satu anggota tidak boleh cocok dengan dua baris.
+func (rs *RosterService) matchRosterEntry(entry *rosterEntry, byStudentID, byEmail map[string]*models.Member, matched map[int]bool) {
+	m := &entry.member
+	studentMatch := byStudentID[m.StudentID]
+	emailMatch := byEmail[m.Email]
+	if m.StudentID == "" {
+		studentMatch = nil
+	}
+	if m.Email == "" {
+		emailMatch = nil
+	}
+
+	switch {
+	case studentMatch != nil && emailMatch != nil && studentMatch.ID != emailMatch.ID:
+		entry.errors = append(entry.errors, models.RosterImportRowError{Row: entry.row, Field: "email",
+			Message: fmt.Sprintf("student ID belongs to member %d but email to member %d", studentMatch.ID, emailMatch.ID)})
+		return
+	case studentMatch != nil:
+		entry.existing = studentMatch
+	case emailMatch != nil:
+		entry.existing = emailMatch
+		if emailMatch.StudentID != "" && m.StudentID != "" {
+			entry.errors = append(entry.errors, models.RosterImportRowError{Row: entry.row, Field: "student_id",
+				Message: fmt.Sprintf("email belongs to member %d with student ID %s", emailMatch.ID, emailMatch.StudentID)})
+			return
+		}
+	}
+
+	if entry.existing == nil {
+		if m.Name == "" {
+			entry.errors = append(entry.errors, models.RosterImportRowError{Row: entry.row, Field: "name", Message: "name is required for new members"})
+		}
+		if m.Email == "" {
+			entry.errors = append(entry.errors, models.RosterImportRowError{Row: entry.row, Field: "email", Message: "email is required for new members"})
+		}
+		return
+	}
+	if matched[entry.existing.ID] {
+		entry.errors = append(entry.errors, models.RosterImportRowError{Row: entry.row,
+			Message: fmt.Sprintf("member %d is already matched by another row", entry.existing.ID)})
+		return
+	}
+	matched[entry.existing.ID] = true
+}
+
+// sendWelcome membuat tautan pembuatan password untuk anggota baru dan mengirimkannya lewat email
+func (rs *RosterService) sendWelcome(member *models.Member, now time.Time) error {
+	token, err := newVerificationToken()
+	if err != nil {
+		return err
+	}
+	setup := &models.PasswordSetup{
+		MemberID:  member.ID,
+		TokenHash: hashVerificationToken(token),
+		CreatedAt: now,
+		ExpiresAt: now.Add(rs.setupTTL),
+	}
+	if err := rs.profileRepository.SavePasswordSetup(setup); err != nil {
+		return err
+	}
+
+	link := rs.setupURL + "?token=" + token
+	body := fmt.Sprintf("Hello %s,\n\nWelcome to the library! Your membership is active until %s.\n\nChoose your password to sign in: %s\n\nThe link expires at %s.\n",
+		member.Name, formatMembershipEnd(member.MembershipEnd), link, setup.ExpiresAt.Format(time.RFC1123))
+	return rs.mailer.Send(member.Email, "Welcome to the library", body)
+}
+
+// buildRosterMember membangun data anggota dari satu baris daftar dan mengembalikan error validasinya
+func buildRosterMember(row importRow, mapping map[string]string) (models.Member, []models.RosterImportRowError) {
+	columns := make([]string, 0, len(row.values))
+	for column := range row.values {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	var member models.Member
+	var rowErrors []models.RosterImportRowError
+	for _, column := range columns {
+		field := resolveRosterField(column, mapping)
+		if field == "" {
+			continue
+		}
+		if err := rosterImportFields[field](&member, strings.TrimSpace(row.values[column])); err != nil {
+			rowErrors = append(rowErrors, models.RosterImportRowError{Row: row.number, Field: field, Message: err.Error()})
+		}
+	}
+
+	if member.StudentID == "" && member.Email == "" {
+		rowErrors = append(rowErrors, models.RosterImportRowError{Row: row.number, Message: "student_id or email is required"})
+	}
+	if member.Email != "" && !utils.ValidateEmail(member.Email) {
+		rowErrors = append(rowErrors, models.RosterImportRowError{Row: row.number, Field: "email", Message: "invalid email"})
+	}
+	return member, rowErrors
+}
+
+// mergeRosterMember menggabungkan data daftar dengan anggota yang sudah ada; kolom kosong di daftar tidak
+// menghapus data anggota. Keanggotaan selalu diaktifkan dengan jenis dan masa berlaku dari daftar.
+func mergeRosterMember(row models.Member, existing *models.Member, opts models.RosterImportOptions, defaultEnd *time.Time, now time.Time) models.Member {
+	var member models.Member
+	if existing != nil {
+		member = *existing
+	}
+	for _, field := range []struct {
+		target *string
+		value  string
+	}{
+		{&member.StudentID, row.StudentID},
+		{&member.Name, row.Name},
+		{&member.Email, row.Email},
+		{&member.PhoneNumber, row.PhoneNumber},
+		{&member.Address, row.Address},
+		{&member.Gender, row.Gender},
+	} {
+		if field.value != "" {
+			*field.target = field.value
+		}
+	}
+	if row.BirthDate != nil {
+		member.BirthDate = row.BirthDate
+	}
+
+	member.Roster = opts.Roster
+	member.MembershipType = opts.MembershipType
+	if row.MembershipType != "" {
+		member.MembershipType = row.MembershipType
+	}
+	member.MembershipEnd = defaultEnd
+	if row.MembershipEnd != nil {
+		member.MembershipEnd = row.MembershipEnd
+	}
+	if member.MembershipStatus != models.MembershipStatusActive || member.MembershipStart == nil {
+		member.MembershipStart = &now
+	}
+	member.MembershipStatus = models.MembershipStatusActive
+	member.ChangedBy = opts.ImportedBy
+	return member
+}
+
+// rosterMemberChanged memeriksa apakah impor mengubah data anggota
+func rosterMemberChanged(before, after models.Member) bool {
+	return before.StudentID != after.StudentID ||
+		before.Name != after.Name ||
+		!strings.EqualFold(before.Email, after.Email) ||
+		before.PhoneNumber != after.PhoneNumber ||
+		before.Address != after.Address ||
+		before.Gender != after.Gender ||
+		!sameOptionalDate(before.BirthDate, after.BirthDate) ||
+		before.Roster != after.Roster ||
+		before.MembershipType != after.MembershipType ||
+		before.MembershipStatus != after.MembershipStatus ||
+		!sameOptionalDate(before.MembershipEnd, after.MembershipEnd)
+}
+
+// resolveRosterField menentukan field anggota untuk sebuah kolom, memakai mapping jika ada
+func resolveRosterField(column string, mapping map[string]string) string {
+	if field, ok := mapping[column]; ok {
+		return field
+	}
+	field := strings.ToLower(strings.TrimSpace(column))
+	if _, ok := rosterImportFields[field]; ok {
+		return field
+	}
+	return ""
+}
+
+// parseRosterDate mengurai tanggal YYYY-MM-DD; nilai kosong dibiarkan nil
+func parseRosterDate(value string, date **time.Time) error {
+	if value == "" {
+		return nil
+	}
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return fmt.Errorf("invalid date %q, expected YYYY-MM-DD", value)
+	}
+	*date = &parsed
+	return nil
+}
+
+// sameOptionalDate membandingkan dua tanggal opsional tanpa jam
+func sameOptionalDate(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return sameDate(*a, *b)
+}
+
+// formatMembershipEnd menulis akhir masa keanggotaan untuk email
+func formatMembershipEnd(end *time.Time) string {
+	if end == nil {
+		return "further notice"
+	}
+	return end.Format("2 January 2006")
+}
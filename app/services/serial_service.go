@@ -0,0 +1,300 @@
+package services
+
+import (
+	"Restful-Perpustakaan-API/app/common"
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/repositories"
+	"Restful-Perpustakaan-API/app/utils"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxPredictedIssues membatasi jumlah nomor terbitan yang diprediksi sekaligus
+const maxPredictedIssues = 104
+
+// SerialService provides methods for serial subscriptions: issue prediction, check-in, claims and routing lists
+type SerialService struct {
+	serialRepository       repositories.SerialRepository
+	acquisitionRepository  repositories.AcquisitionRepository
+	memberRepository       repositories.MemberRepository
+	notificationRepository *repositories.NotificationRepository
+	bookService            *BookService
+	copyService            *CopyService
+}
+
+// NewSerialService creates a new SerialService instance
+func NewSerialService(serialRepository repositories.SerialRepository, acquisitionRepository repositories.AcquisitionRepository, memberRepository repositories.MemberRepository, notificationRepository *repositories.NotificationRepository, bookService *BookService, copyService *CopyService) *SerialService {
+	return &SerialService{
+		serialRepository:       serialRepository,
+		acquisitionRepository:  acquisitionRepository,
+		memberRepository:       memberRepository,
+		notificationRepository: notificationRepository,
+		bookService:            bookService,
+		copyService:            copyService,
+	}
+}
+
+// GetAllSerials mengambil semua terbitan berseri
+func (ss *SerialService) GetAllSerials() ([]models.Serial, error) {
+	serials, err := ss.serialRepository.GetAllSerials()
+	if err != nil {
+		return nil, err
+	}
+	if serials == nil {
+		serials = []models.Serial{}
+	}
+	return serials, nil
+}
+
+// GetSerialByID mengambil terbitan berseri berdasarkan ID
+func (ss *SerialService) GetSerialByID(id int) (*models.Serial, error) {
+	s, err := ss.serialRepository.GetSerialByID(id)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	return s, nil
+}
+
+// CreateSerial menambahkan langganan terbitan berseri. Jika book_id kosong, record katalognya dibuat otomatis.
+func (ss *SerialService) CreateSerial(s *models.Serial) error {
+	if err := ss.validateSerial(s); err != nil {
+		return err
+	}
+	s.Active = true
+
+	if s.BookID == 0 {
+		book := &models.Book{Book: common.Book{Title: s.Title, Publisher: s.Publisher}}
+		if err := ss.bookService.CreateBook(book); err != nil {
+			return err
+		}
+		s.BookID = book.ID
+	} else if _, err := ss.bookService.GetBookByID(s.BookID); err != nil {
+		return utils.NewAppError(http.StatusBadRequest, "book not found")
+	}
+
+	return ss.serialRepository.CreateSerial(s)
+}
+
+// UpdateSerial memperbarui langganan; perubahan pola terbit hanya berlaku untuk nomor yang diprediksi berikutnya
+func (ss *SerialService) UpdateSerial(s *models.Serial) error {
+	existing, err := ss.GetSerialByID(s.ID)
+	if err != nil {
+		return err
+	}
+	if err := ss.validateSerial(s); err != nil {
+		return err
+	}
+	// Record katalog tidak dapat diganti karena eksemplar nomor yang sudah diterima terhubung ke sana
+	s.BookID = existing.BookID
+	s.CreatedAt = existing.CreatedAt
+	return ss.serialRepository.UpdateSerial(s)
+}
+
+// DeleteSerial menghapus langganan yang belum memiliki nomor terbitan yang diterima
+func (ss *SerialService) DeleteSerial(id int) error {
+	if _, err := ss.GetSerialByID(id); err != nil {
+		return err
+	}
+	received, err := ss.serialRepository.CountReceivedIssues(id)
+	if err != nil {
+		return err
+	}
+	if received > 0 {
+		return utils.NewAppError(http.StatusConflict, "serial has received issues and cannot be deleted; deactivate it instead")
+	}
+	return ss.serialRepository.DeleteSerial(id)
+}
+
+// GetIssues mengambil semua nomor terbitan sebuah terbitan berseri
+func (ss *SerialService) GetIssues(serialID int) ([]models.SerialIssue, error) {
+	if _, err := ss.GetSerialByID(serialID); err != nil {
+		return nil, err
+	}
+	issues, err := ss.serialRepository.GetIssuesBySerialID(serialID)
+	if err != nil {
+		return nil, err
+	}
+	if issues == nil {
+		issues = []models.SerialIssue{}
+	}
+	return issues, nil
+}
+
+// PredictIssues membuat count nomor terbitan berikutnya beserta tanggal terbit yang diharapkan dari pola terbit
+func (ss *SerialService) PredictIssues(serialID, count int) ([]models.SerialIssue, error) {
+	serial, err := ss.GetSerialByID(serialID)
+	if err != nil {
+		return nil, err
+	}
+	if !serial.Active {
+		return nil, utils.NewAppError(http.StatusConflict, "serial subscription is not active")
+	}
+	if count <= 0 || count > maxPredictedIssues {
+		return nil, utils.NewAppError(http.StatusBadRequest, fmt.Sprintf("count must be between 1 and %d", maxPredictedIssues))
+	}
+
+	last, err := ss.serialRepository.GetLastIssueNumber(serialID)
+	if err != nil {
+		return nil, err
+	}
+	issues := make([]models.SerialIssue, 0, count)
+	for number := last + 1; number <= last+count; number++ {
+		issues = append(issues, models.SerialIssue{
+			SerialID:     serialID,
+			Number:       number,
+			Label:        serial.IssueLabel(number),
+			ExpectedDate: serial.ExpectedDate(number),
+			Status:       models.IssueStatusExpected,
+		})
+	}
+	if err := ss.serialRepository.CreateIssues(issues); err != nil {
+		return nil, err
+	}
+	return issues, nil
+}
+
+// CheckInIssue mencatat kedatangan nomor terbitan, membuat eksemplarnya, dan memberi tahu anggota di daftar edar
+func (ss *SerialService) CheckInIssue(issueID int, req *models.SerialCheckIn) (*models.SerialIssue, error) {
+	issue, err := ss.serialRepository.GetIssueByID(issueID)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	if issue.Status == models.IssueStatusReceived {
+		return nil, utils.NewAppError(http.StatusConflict, repositories.ErrIssueAlreadyReceived.Error())
+	}
+	serial, err := ss.GetSerialByID(issue.SerialID)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &models.BookCopy{BookID: serial.BookID, Barcode: req.Barcode, LocationID: req.LocationID, Status: models.CopyStatusAvailable}
+	if err := ss.copyService.validateCopy(c); err != nil {
+		return nil, err
+	}
+	if err := ss.serialRepository.CheckInIssue(issue, c, time.Now()); err != nil {
+		if err == repositories.ErrIssueAlreadyReceived {
+			return nil, utils.NewAppError(http.StatusConflict, err.Error())
+		}
+		return nil, err
+	}
+
+	ss.notifyRouting(serial, issue)
+	return issue, nil
+}
+
+// GetClaims mengambil nomor terbitan yang terlambat melewati masa tenggang dan perlu diklaim ke vendor
+func (ss *SerialService) GetClaims() ([]models.SerialClaim, error) {
+	claims, err := ss.serialRepository.GetClaimableIssues(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if claims == nil {
+		claims = []models.SerialClaim{}
+	}
+	return claims, nil
+}
+
+// ClaimIssue mencatat klaim ke vendor untuk nomor terbitan yang belum datang; seperti GetClaims, nomor baru dapat
+// diklaim setelah masa tenggang lewat sejak tanggal terbit maupun sejak klaim terakhir
+func (ss *SerialService) ClaimIssue(issueID int) (*models.SerialIssue, error) {
+	issue, err := ss.serialRepository.GetIssueByID(issueID)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	if issue.Status == models.IssueStatusReceived {
+		return nil, utils.NewAppError(http.StatusConflict, "issue has already been received")
+	}
+	serial, err := ss.GetSerialByID(issue.SerialID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if claimableAt := serial.ClaimableAt(issue); !now.After(claimableAt) {
+		return nil, utils.NewAppError(http.StatusConflict, "issue cannot be claimed before "+claimableAt.Format("2006-01-02"))
+	}
+	if err := ss.serialRepository.ClaimIssue(issueID, now); err != nil {
+		return nil, err
+	}
+	return ss.serialRepository.GetIssueByID(issueID)
+}
+
+// GetRouting mengambil daftar edar sebuah terbitan berseri
+func (ss *SerialService) GetRouting(serialID int) ([]models.SerialRoutingEntry, error) {
+	if _, err := ss.GetSerialByID(serialID); err != nil {
+		return nil, err
+	}
+	entries, err := ss.serialRepository.GetRouting(serialID)
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []models.SerialRoutingEntry{}
+	}
+	return entries, nil
+}
+
+// SetRouting mengganti daftar edar; urutan memberIDs adalah urutan edar
+func (ss *SerialService) SetRouting(serialID int, memberIDs []int) ([]models.SerialRoutingEntry, error) {
+	if _, err := ss.GetSerialByID(serialID); err != nil {
+		return nil, err
+	}
+	seen := map[int]bool{}
+	for _, memberID := range memberIDs {
+		if seen[memberID] {
+			return nil, utils.NewAppError(http.StatusBadRequest, fmt.Sprintf("member %d is listed more than once", memberID))
+		}
+		seen[memberID] = true
+		if _, err := ss.memberRepository.GetMemberByID(memberID); err != nil {
+			return nil, utils.NewAppError(http.StatusBadRequest, fmt.Sprintf("member %d not found", memberID))
+		}
+	}
+	if err := ss.serialRepository.SetRouting(serialID, memberIDs); err != nil {
+		return nil, err
+	}
+	return ss.GetRouting(serialID)
+}
+
+// notifyRouting memberi tahu setiap anggota di daftar edar bahwa nomor baru telah datang; kegagalan hanya dicatat
+func (ss *SerialService) notifyRouting(serial *models.Serial, issue *models.SerialIssue) {
+	entries, err := ss.serialRepository.GetRouting(serial.ID)
+	if err != nil {
+		utils.GetLogger().WithError(err).WithField("serial", serial.ID).Warn("failed to load serial routing list")
+		return
+	}
+	for _, entry := range entries {
+		n := &models.Notification{
+			UserID:  entry.MemberID,
+			Message: fmt.Sprintf("A new issue of %q has arrived: %s (routing position %d of %d).", serial.Title, issue.Label, entry.Position, len(entries)),
+		}
+		if err := ss.notificationRepository.CreateNotification(n); err != nil {
+			utils.GetLogger().WithError(err).WithField("serial", serial.ID).WithField("member", entry.MemberID).Warn("failed to notify routing list member")
+		}
+	}
+}
+
+// validateSerial memeriksa judul, pola terbit, tanggal terbit pertama, masa tenggang dan vendor langganan
+func (ss *SerialService) validateSerial(s *models.Serial) error {
+	s.Title = strings.TrimSpace(s.Title)
+	s.ISSN = strings.ToUpper(strings.TrimSpace(s.ISSN))
+	s.Publisher = strings.TrimSpace(s.Publisher)
+	if s.Title == "" {
+		return utils.NewAppError(http.StatusBadRequest, "title is required")
+	}
+	if !models.IsSerialFrequency(s.Frequency) {
+		return utils.NewAppError(http.StatusBadRequest, "frequency must be weekly, monthly or quarterly")
+	}
+	if s.FirstIssueDate.IsZero() {
+		return utils.NewAppError(http.StatusBadRequest, "first_issue_date is required")
+	}
+	if s.ClaimGraceDays < 0 {
+		return utils.NewAppError(http.StatusBadRequest, "claim_grace_days must not be negative")
+	}
+	if s.VendorID != nil {
+		if _, err := ss.acquisitionRepository.GetVendorByID(*s.VendorID); err != nil {
+			return utils.NewAppError(http.StatusBadRequest, err.Error())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+package services
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/repositories"
+	"Restful-Perpustakaan-API/app/utils"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// SuggestionService provides methods for members' purchase suggestions and the librarian's acquisition workflow
+type SuggestionService struct {
+	suggestionRepository   repositories.SuggestionRepository
+	bookRepository         repositories.BookRepository
+	notificationRepository *repositories.NotificationRepository
+	holdService            *HoldService
+}
+
+// NewSuggestionService creates a new SuggestionService instance
+func NewSuggestionService(suggestionRepository repositories.SuggestionRepository, bookRepository repositories.BookRepository, notificationRepository *repositories.NotificationRepository, holdService *HoldService) *SuggestionService {
+	return &SuggestionService{
+		suggestionRepository:   suggestionRepository,
+		bookRepository:         bookRepository,
+		notificationRepository: notificationRepository,
+		holdService:            holdService,
+	}
+}
+
+// GetSuggestions mengambil usulan pengadaan, opsional difilter berdasarkan status, urut dari suara terbanyak
+func (ss *SuggestionService) GetSuggestions(status string, viewerID int) ([]models.PurchaseSuggestion, error) {
+	if status != "" && !models.IsSuggestionStatus(status) {
+		return nil, utils.NewAppError(http.StatusBadRequest, fmt.Sprintf("unknown suggestion status %q", status))
+	}
+	suggestions, err := ss.suggestionRepository.GetSuggestions(status, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if suggestions == nil {
+		suggestions = []models.PurchaseSuggestion{}
+	}
+	return suggestions, nil
+}
+
+// GetSuggestionByID mengambil usulan pengadaan berdasarkan ID
+func (ss *SuggestionService) GetSuggestionByID(id, viewerID int) (*models.PurchaseSuggestion, error) {
+	suggestion, err := ss.suggestionRepository.GetSuggestionByID(id, viewerID)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	return suggestion, nil
+}
+
+// GetMySuggestions mengambil usulan pengadaan yang diajukan anggota
+func (ss *SuggestionService) GetMySuggestions(memberID int) ([]models.PurchaseSuggestion, error) {
+	suggestions, err := ss.suggestionRepository.GetSuggestionsByRequesterID(memberID)
+	if err != nil {
+		return nil, err
+	}
+	if suggestions == nil {
+		suggestions = []models.PurchaseSuggestion{}
+	}
+	return suggestions, nil
+}
+
+// CreateSuggestion mengajukan usulan pengadaan baru atas nama anggota. Buku yang sudah ada di katalog
+// atau yang sudah diusulkan anggota lain ditolak agar suara terkumpul pada satu usulan.
+func (ss *SuggestionService) CreateSuggestion(requesterID int, s *models.PurchaseSuggestion) error {
+	s.Title = strings.TrimSpace(s.Title)
+	s.Author = strings.TrimSpace(s.Author)
+	s.Justification = strings.TrimSpace(s.Justification)
+	if s.ISBN != "" {
+		if !utils.ValidateISBN(s.ISBN) {
+			return utils.NewAppError(http.StatusBadRequest, "invalid ISBN")
+		}
+		s.ISBN = utils.NormalizeISBN(s.ISBN)
+	}
+	if s.ISBN == "" && s.Title == "" {
+		return utils.NewAppError(http.StatusBadRequest, "isbn or title is required")
+	}
+	if s.Justification == "" {
+		return utils.NewAppError(http.StatusBadRequest, "justification is required")
+	}
+
+	if s.ISBN != "" {
+		if book, err := ss.bookRepository.GetBookByISBN(s.ISBN); err == nil {
+			return utils.NewAppError(http.StatusConflict, fmt.Sprintf("book is already in the catalogue (book %d)", book.ID))
+		}
+		if existing, err := ss.suggestionRepository.GetOpenSuggestionByISBN(s.ISBN); err == nil {
+			return utils.NewAppError(http.StatusConflict, fmt.Sprintf("this book has already been suggested (suggestion %d); vote for it instead", existing.ID))
+		}
+	}
+
+	s.RequesterID = requesterID
+	s.Status = models.SuggestionStatusNew
+	s.StatusNote = ""
+	s.BookID = nil
+	return ss.suggestionRepository.CreateSuggestion(s)
+}
+
+// Vote memberikan suara anggota pada usulan yang masih terbuka
+func (ss *SuggestionService) Vote(id, memberID int) (*models.PurchaseSuggestion, error) {
+	suggestion, err := ss.GetSuggestionByID(id, memberID)
+	if err != nil {
+		return nil, err
+	}
+	if !suggestion.Open() {
+		return nil, utils.NewAppError(http.StatusConflict, "voting is closed for this suggestion")
+	}
+	if err := ss.suggestionRepository.AddVote(id, memberID); err != nil {
+		if err == repositories.ErrAlreadyVoted {
+			return nil, utils.NewAppError(http.StatusConflict, err.Error())
+		}
+		return nil, err
+	}
+	return ss.GetSuggestionByID(id, memberID)
+}
+
+// Unvote menarik kembali suara anggota pada usulan yang masih terbuka
+func (ss *SuggestionService) Unvote(id, memberID int) error {
+	suggestion, err := ss.GetSuggestionByID(id, memberID)
+	if err != nil {
+		return err
+	}
+	if !suggestion.Open() {
+		return utils.NewAppError(http.StatusConflict, "voting is closed for this suggestion")
+	}
+	if err := ss.suggestionRepository.RemoveVote(id, memberID); err != nil {
+		return utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	return nil
+}
+
+// UpdateStatus memindahkan usulan ke status berikutnya (new → under_review → ordered → received, atau rejected)
+// dan memberi tahu pengusul. Usulan yang diterima dihubungkan ke buku di katalog (dari book_id atau ISBN);
+// jika bukunya sudah dikatalogkan, reservasi untuk pengusul langsung dibuat.
+func (ss *SuggestionService) UpdateStatus(id int, update *models.SuggestionStatusUpdate) (*models.PurchaseSuggestion, error) {
+	suggestion, err := ss.GetSuggestionByID(id, 0)
+	if err != nil {
+		return nil, err
+	}
+	if !models.IsSuggestionStatus(update.Status) {
+		return nil, utils.NewAppError(http.StatusBadRequest, fmt.Sprintf("unknown suggestion status %q", update.Status))
+	}
+	if !suggestion.CanMoveTo(update.Status) {
+		return nil, utils.NewAppError(http.StatusConflict, fmt.Sprintf("cannot change suggestion status from %s to %s", suggestion.Status, update.Status))
+	}
+	note := strings.TrimSpace(update.Note)
+	if update.Status == models.SuggestionStatusRejected && note == "" {
+		return nil, utils.NewAppError(http.StatusBadRequest, "note is required when rejecting a suggestion")
+	}
+	if update.BookID != nil && update.Status != models.SuggestionStatusReceived {
+		return nil, utils.NewAppError(http.StatusBadRequest, "book_id can only be set when the suggestion is received")
+	}
+
+	suggestion.Status = update.Status
+	suggestion.StatusNote = note
+	if update.Status == models.SuggestionStatusReceived {
+		suggestion.BookID, err = ss.catalogueBookID(suggestion, update.BookID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := ss.suggestionRepository.UpdateStatus(suggestion); err != nil {
+		return nil, err
+	}
+
+	ss.notify(suggestion, suggestionStatusMessage(suggestion))
+	if suggestion.BookID != nil {
+		ss.placeRequesterHold(suggestion)
+	}
+	return suggestion, nil
+}
+
+// BookCatalogued menghubungkan usulan yang sudah diterima tetapi belum dikatalogkan ke buku baru
+// dengan ISBN yang sama, lalu membuat reservasi untuk pengusulnya
+func (ss *SuggestionService) BookCatalogued(book *models.Book) error {
+	if book.ISBN == "" {
+		return nil
+	}
+	suggestions, err := ss.suggestionRepository.GetUncataloguedSuggestionsByISBN(utils.NormalizeISBN(book.ISBN))
+	if err != nil {
+		return err
+	}
+	for i := range suggestions {
+		suggestion := &suggestions[i]
+		suggestion.BookID = &book.ID
+		if err := ss.suggestionRepository.UpdateStatus(suggestion); err != nil {
+			return err
+		}
+		ss.placeRequesterHold(suggestion)
+	}
+	return nil
+}
+
+// catalogueBookID menentukan buku katalog untuk usulan yang diterima: book_id dari pustakawan jika ada,
+// selain itu buku dengan ISBN yang sama; nil jika bukunya belum dikatalogkan
+func (ss *SuggestionService) catalogueBookID(suggestion *models.PurchaseSuggestion, bookID *int) (*int, error) {
+	if bookID != nil {
+		if _, err := ss.bookRepository.GetBookByID(*bookID); err != nil {
+			return nil, utils.NewAppError(http.StatusBadRequest, "book not found")
+		}
+		return bookID, nil
+	}
+	if suggestion.ISBN == "" {
+		return nil, nil
+	}
+	book, err := ss.bookRepository.GetBookByISBN(suggestion.ISBN)
+	if err != nil {
+		return nil, nil
+	}
+	return &book.ID, nil
+}
+
+// placeRequesterHold membuat reservasi buku yang diusulkan untuk pengusulnya dan memberi tahu pengusul.
+// Kegagalan (e.g. pengusul sudah punya reservasi) hanya dicatat karena status usulan sudah tersimpan.
+func (ss *SuggestionService) placeRequesterHold(suggestion *models.PurchaseSuggestion) {
+	if suggestion.RequesterID == 0 {
+		return
+	}
+	hold, err := ss.holdService.PlaceHold(suggestion.RequesterID, *suggestion.BookID, false)
+	if err != nil {
+		utils.GetLogger().WithError(err).WithField("suggestion", suggestion.ID).Warn("failed to place hold for purchase suggestion")
+		return
+	}
+	ss.notify(suggestion, fmt.Sprintf("%q is now in the catalogue and a hold has been placed for you (queue position %d).", suggestionTitle(suggestion), hold.Position))
+}
+
+// notify mengirim notifikasi ke pengusul; kegagalan hanya dicatat
+func (ss *SuggestionService) notify(suggestion *models.PurchaseSuggestion, message string) {
+	if suggestion.RequesterID == 0 {
+		return
+	}
+	n := &models.Notification{UserID: suggestion.RequesterID, Message: message}
+	if err := ss.notificationRepository.CreateNotification(n); err != nil {
+		utils.GetLogger().WithError(err).WithField("suggestion", suggestion.ID).Warn("failed to notify purchase suggestion requester")
+	}
+}
+
+// suggestionStatusMessage menyusun pesan notifikasi perubahan status usulan
+func suggestionStatusMessage(suggestion *models.PurchaseSuggestion) string {
+	title := suggestionTitle(suggestion)
+	var message string
+	switch suggestion.Status {
+	case models.SuggestionStatusUnderReview:
+		message = fmt.Sprintf("Your purchase suggestion %q is being reviewed by a librarian.", title)
+	case models.SuggestionStatusOrdered:
+		message = fmt.Sprintf("Good news: %q has been ordered.", title)
+	case models.SuggestionStatusReceived:
+		message = fmt.Sprintf("%q has arrived at the library.", title)
+	case models.SuggestionStatusRejected:
+		message = fmt.Sprintf("Your purchase suggestion %q was not accepted.", title)
+	}
+	if suggestion.StatusNote != "" {
+		message += " Note: " + suggestion.StatusNote
+	}
+	return message
+}
+
+// suggestionTitle mengembalikan judul usulan, atau ISBN jika judulnya kosong
+func suggestionTitle(suggestion *models.PurchaseSuggestion) string {
+	if suggestion.Title != "" {
+		return suggestion.Title
+	}
+	return "ISBN " + suggestion.ISBN
+}
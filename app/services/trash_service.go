@@ -0,0 +1,118 @@
+package services
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/repositories"
+	"Restful-Perpustakaan-API/app/utils"
+	"net/http"
+	"time"
+)
+
+// TrashService provides methods for listing, restoring and purging soft-deleted records
+type TrashService struct {
+	bookRepository   repositories.BookRepository
+	memberRepository repositories.MemberRepository
+	retention        time.Duration
+}
+
+// NewTrashService creates a new TrashService instance
+func NewTrashService(bookRepository repositories.BookRepository, memberRepository repositories.MemberRepository, retention time.Duration) *TrashService {
+	return &TrashService{
+		bookRepository:   bookRepository,
+		memberRepository: memberRepository,
+		retention:        retention,
+	}
+}
+
+// GetTrash mengambil semua buku dan anggota yang dihapus sementara
+func (ts *TrashService) GetTrash() (*models.Trash, error) {
+	books, err := ts.bookRepository.GetDeletedBooks()
+	if err != nil {
+		return nil, err
+	}
+	members, err := ts.memberRepository.GetDeletedMembers()
+	if err != nil {
+		return nil, err
+	}
+
+	trash := &models.Trash{Books: books, Members: members}
+	if trash.Books == nil {
+		trash.Books = []models.Book{}
+	}
+	if trash.Members == nil {
+		trash.Members = []models.Member{}
+	}
+	return trash, nil
+}
+
+// RestoreBook mengembalikan buku dari trash
+func (ts *TrashService) RestoreBook(id int, restoredBy string) error {
+	if err := ts.bookRepository.RestoreBook(id, restoredBy); err != nil {
+		if err == repositories.ErrBookNotInTrash {
+			return utils.NewAppError(http.StatusNotFound, err.Error())
+		}
+		return err
+	}
+	return nil
+}
+
+// RestoreMember mengembalikan anggota dari trash
+func (ts *TrashService) RestoreMember(id int, restoredBy string) error {
+	if err := ts.memberRepository.RestoreMember(id, restoredBy); err != nil {
+		if err == repositories.ErrMemberNotInTrash {
+			return utils.NewAppError(http.StatusNotFound, err.Error())
+		}
+		return err
+	}
+	return nil
+}
+
+// Purge menghapus permanen record yang sudah berada di trash lebih lama dari masa retensi
+func (ts *TrashService) Purge() (*models.PurgeResult, error) {
+	result := &models.PurgeResult{Before: time.Now().Add(-ts.retention)}
+
+	var err error
+	result.BooksPurged, err = ts.bookRepository.PurgeDeletedBooks(result.Before)
+	if err != nil {
+		return nil, err
+	}
+	result.MembersPurged, err = ts.memberRepository.PurgeDeletedMembers(result.Before)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// StartPurgeJob menjalankan Purge secara berkala di latar belakang
+func (ts *TrashService) StartPurgeJob(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			result, err := ts.Purge()
+			if err != nil {
+				utils.GetLogger().WithError(err).Error("failed to purge trash")
+				continue
+			}
+			if result.BooksPurged > 0 || result.MembersPurged > 0 {
+				utils.GetLogger().WithField("books", result.BooksPurged).WithField("members", result.MembersPurged).Info("trash purged")
+			}
+		}
+	}()
+}
+
+// softDeleteBook memindahkan buku ke trash, kecuali masih ada peminjaman yang belum dikembalikan
+func softDeleteBook(bookRepository repositories.BookRepository, loanRepository *repositories.LoanRepository, id int, deletedBy string) error {
+	if _, err := bookRepository.GetBookByID(id); err != nil {
+		return utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	activeLoans, err := loanRepository.CountActiveLoansByBookID(id)
+	if err != nil {
+		return err
+	}
+	if activeLoans > 0 {
+		return utils.NewAppError(http.StatusConflict, "book has active loans and cannot be deleted")
+	}
+	return bookRepository.DeleteBook(id, deletedBy)
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"Restful-Perpustakaan-API/app/models"
+	"Restful-Perpustakaan-API/app/repositories"
+	"Restful-Perpustakaan-API/app/utils"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// WorkService provides methods for grouping editions into works and works into series
+type WorkService struct {
+	workRepository repositories.WorkRepository
+	bookRepository repositories.BookRepository
+}
+
+// NewWorkService creates a new WorkService instance
+func NewWorkService(workRepository repositories.WorkRepository, bookRepository repositories.BookRepository) *WorkService {
+	return &WorkService{
+		workRepository: workRepository,
+		bookRepository: bookRepository,
+	}
+}
+
+// GetAllWorks mengambil semua karya
+func (ws *WorkService) GetAllWorks() ([]models.Work, error) {
+	return ws.workRepository.GetAllWorks()
+}
+
+// GetWorkByID mengambil karya beserta seluruh edisinya
+func (ws *WorkService) GetWorkByID(id int) (*models.Work, error) {
+	work, err := ws.workRepository.GetWorkByID(id)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	work.Editions, err = ws.workRepository.GetEditions(id)
+	if err != nil {
+		return nil, err
+	}
+	return work, nil
+}
+
+// CreateWork membuat karya baru
+func (ws *WorkService) CreateWork(w *models.Work) error {
+	if err := ws.validateWork(w); err != nil {
+		return err
+	}
+	return ws.workRepository.CreateWork(w)
+}
+
+// UpdateWork memperbarui judul, pengarang, atau posisi karya dalam seri
+func (ws *WorkService) UpdateWork(w *models.Work) error {
+	if _, err := ws.workRepository.GetWorkByID(w.ID); err != nil {
+		return utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	if err := ws.validateWork(w); err != nil {
+		return err
+	}
+	return ws.workRepository.UpdateWork(w)
+}
+
+// DeleteWork menghapus karya; edisinya tetap ada di katalog
+func (ws *WorkService) DeleteWork(id int) error {
+	if _, err := ws.workRepository.GetWorkByID(id); err != nil {
+		return utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	return ws.workRepository.DeleteWork(id)
+}
+
+// AddEdition menautkan buku sebagai edisi dari sebuah karya
+func (ws *WorkService) AddEdition(workID, bookID int, changedBy string) (*models.Book, error) {
+	if _, err := ws.workRepository.GetWorkByID(workID); err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	book, err := ws.bookRepository.GetBookByID(bookID)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	if book.WorkID != nil && *book.WorkID != workID {
+		return nil, utils.NewAppError(http.StatusConflict, "book is already an edition of another work")
+	}
+	book.WorkID = &workID
+	book.ChangedBy = changedBy
+	if err := ws.bookRepository.UpdateBook(book); err != nil {
+		return nil, err
+	}
+	return book, nil
+}
+
+// RemoveEdition melepas buku dari karya
+func (ws *WorkService) RemoveEdition(workID, bookID int, changedBy string) error {
+	book, err := ws.bookRepository.GetBookByID(bookID)
+	if err != nil {
+		return utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	if book.WorkID == nil || *book.WorkID != workID {
+		return utils.NewAppError(http.StatusNotFound, "book is not an edition of this work")
+	}
+	book.WorkID = nil
+	book.ChangedBy = changedBy
+	return ws.bookRepository.UpdateBook(book)
+}
+
+// GetWorkRating menghitung rating rata-rata karya dari ulasan semua edisinya.
+// Rata-rata dibobot jumlah ulasan, sehingga edisi dengan banyak ulasan lebih berpengaruh.
+func (ws *WorkService) GetWorkRating(workID int) (*models.WorkRating, error) {
+	if _, err := ws.workRepository.GetWorkByID(workID); err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	editions, err := ws.workRepository.GetEditionRatings(workID)
+	if err != nil {
+		return nil, err
+	}
+
+	rating := &models.WorkRating{WorkID: workID, Editions: []models.EditionRating{}}
+	var total float64
+	for _, edition := range editions {
+		// Rata-rata karya dihitung dari rata-rata mentah; pembulatan hanya untuk tampilan
+		rating.ReviewCount += edition.ReviewCount
+		total += edition.AverageRating * float64(edition.ReviewCount)
+		edition.AverageRating = math.Round(edition.AverageRating*100) / 100
+		rating.Editions = append(rating.Editions, edition)
+	}
+	if rating.ReviewCount > 0 {
+		rating.AverageRating = math.Round(total/float64(rating.ReviewCount)*100) / 100
+	}
+
+	return rating, nil
+}
+
+// GetAllSeries mengambil semua seri
+func (ws *WorkService) GetAllSeries() ([]models.Series, error) {
+	return ws.workRepository.GetAllSeries()
+}
+
+// GetSeriesByID mengambil seri beserta karyanya, diurutkan berdasarkan nomor jilid
+func (ws *WorkService) GetSeriesByID(id int) (*models.Series, error) {
+	series, err := ws.workRepository.GetSeriesByID(id)
+	if err != nil {
+		return nil, utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	series.Works, err = ws.workRepository.GetWorksBySeriesID(id)
+	if err != nil {
+		return nil, err
+	}
+	// Nomor jilid dibandingkan secara numerik seperti nomor panggil: "2" < "10", "2" < "2.5"
+	sort.SliceStable(series.Works, func(i, j int) bool {
+		return utils.CompareCallNumbers(series.Works[i].SeriesVolume, series.Works[j].SeriesVolume) < 0
+	})
+	return series, nil
+}
+
+// CreateSeries membuat seri baru
+func (ws *WorkService) CreateSeries(s *models.Series) error {
+	s.Title = strings.TrimSpace(s.Title)
+	if s.Title == "" {
+		return utils.NewAppError(http.StatusBadRequest, "series title is required")
+	}
+	return ws.workRepository.CreateSeries(s)
+}
+
+// UpdateSeries memperbarui seri
+func (ws *WorkService) UpdateSeries(s *models.Series) error {
+	if _, err := ws.workRepository.GetSeriesByID(s.ID); err != nil {
+		return utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	s.Title = strings.TrimSpace(s.Title)
+	if s.Title == "" {
+		return utils.NewAppError(http.StatusBadRequest, "series title is required")
+	}
+	return ws.workRepository.UpdateSeries(s)
+}
+
+// DeleteSeries menghapus seri; karyanya tetap ada tanpa nomor jilid
+func (ws *WorkService) DeleteSeries(id int) error {
+	if _, err := ws.workRepository.GetSeriesByID(id); err != nil {
+		return utils.NewAppError(http.StatusNotFound, err.Error())
+	}
+	return ws.workRepository.DeleteSeries(id)
+}
+
+// validateWork memastikan karya memiliki judul dan seri yang dirujuk ada
+func (ws *WorkService) validateWork(w *models.Work) error {
+	w.Title = strings.TrimSpace(w.Title)
+	w.SeriesVolume = strings.TrimSpace(w.SeriesVolume)
+	if w.Title == "" {
+		return utils.NewAppError(http.StatusBadRequest, "work title is required")
+	}
+	if w.SeriesID == nil {
+		if w.SeriesVolume != "" {
+			return utils.NewAppError(http.StatusBadRequest, "series_volume requires series_id")
+		}
+		return nil
+	}
+	if _, err := ws.workRepository.GetSeriesByID(*w.SeriesID); err != nil {
+		return utils.NewAppError(http.StatusBadRequest, err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// code128Patterns berisi lebar batang/spasi setiap simbol Code 128, diawali batang; 103-105 adalah start A/B/C dan 106 stop
+var code128Patterns = [...]string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+	"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+	"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+	"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+	"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+	"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+	"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+	"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+	"114131", "311141", "411131", "211412", "211214", "211232", "2331112",
+}
+
+const (
+	code128StartB = 104
+	code128StartC = 105
+	code128Stop   = 106
+)
+
+// Code128 mengodekan text sebagai barcode Code 128 dan mengembalikan modul-modulnya (true = batang).
+// Teks berisi angka dengan panjang genap memakai code set C yang lebih ringkas, selain itu code set B.
+func Code128(text string) ([]bool, error) {
+	if text == "" {
+		return nil, errors.New("barcode text is empty")
+	}
+
+	var symbols []int
+	if isEvenDigits(text) {
+		symbols = append(symbols, code128StartC)
+		for i := 0; i < len(text); i += 2 {
+			symbols = append(symbols, int(text[i]-'0')*10+int(text[i+1]-'0'))
+		}
+	} else {
+		symbols = append(symbols, code128StartB)
+		for i := 0; i < len(text); i++ {
+			if text[i] < 32 || text[i] > 126 {
+				return nil, fmt.Errorf("character %q cannot be encoded in Code 128 set B", text[i])
+			}
+			symbols = append(symbols, int(text[i])-32)
+		}
+	}
+
+	checksum := symbols[0]
+	for i, symbol := range symbols[1:] {
+		checksum += symbol * (i + 1)
+	}
+	symbols = append(symbols, checksum%103, code128Stop)
+
+	var modules []bool
+	for _, symbol := range symbols {
+		for i, width := range code128Patterns[symbol] {
+			for n := 0; n < int(width-'0'); n++ {
+				modules = append(modules, i%2 == 0)
+			}
+		}
+	}
+	return modules, nil
+}
+
+// isEvenDigits mengembalikan true jika text hanya berisi angka dengan jumlah genap
+func isEvenDigits(text string) bool {
+	if len(text)%2 != 0 {
+		return false
+	}
+	for i := 0; i < len(text); i++ {
+		if text[i] < '0' || text[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// RenderPNG menggambar matriks modul (true = gelap) sebagai PNG hitam putih. Setiap modul berukuran
+// moduleWidth x moduleHeight piksel; quiet zone di setiap sisi selebar quiet x moduleWidth piksel.
+func RenderPNG(matrix [][]bool, moduleWidth, moduleHeight, quiet int) ([]byte, error) {
+	width, height := matrixSize(matrix)
+	margin := quiet * moduleWidth
+	img := image.NewGray(image.Rect(0, 0, width*moduleWidth+2*margin, height*moduleHeight+2*margin))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	for y, row := range matrix {
+		for x, dark := range row {
+			if !dark {
+				continue
+			}
+			for py := 0; py < moduleHeight; py++ {
+				for px := 0; px < moduleWidth; px++ {
+					img.SetGray(margin+x*moduleWidth+px, margin+y*moduleHeight+py, color.Gray{})
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderSVG menggambar matriks modul seperti RenderPNG sebagai SVG; modul gelap yang berurutan dalam satu baris
+// digabung menjadi satu persegi panjang
+func RenderSVG(matrix [][]bool, moduleWidth, moduleHeight, quiet int) []byte {
+	width, height := matrixSize(matrix)
+	margin := quiet * moduleWidth
+	totalWidth, totalHeight := width*moduleWidth+2*margin, height*moduleHeight+2*margin
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, totalWidth, totalHeight, totalWidth, totalHeight)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#fff"/><path fill="#000" d="`, totalWidth, totalHeight)
+	for y, row := range matrix {
+		for x := 0; x < len(row); x++ {
+			if !row[x] {
+				continue
+			}
+			start := x
+			for x < len(row) && row[x] {
+				x++
+			}
+			fmt.Fprintf(&buf, "M%d %dh%dv%dh-%dz", margin+start*moduleWidth, margin+y*moduleHeight, (x-start)*moduleWidth, moduleHeight, (x-start)*moduleWidth)
+		}
+	}
+	buf.WriteString(`"/></svg>`)
+	return buf.Bytes()
+}
+
+// matrixSize mengembalikan lebar (baris terpanjang) dan tinggi matriks modul
+func matrixSize(matrix [][]bool) (int, int) {
+	width := 0
+	for _, row := range matrix {
+		width = max(width, len(row))
+	}
+	return width, len(matrix)
+}
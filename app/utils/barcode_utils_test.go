@@ -0,0 +1,113 @@
+package utils
+
+import (
+	"bytes"
+	"image/png"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// decodeCode128 mengubah modul barcode kembali menjadi nilai simbol Code 128 berdasarkan lebar batang dan spasi
+func decodeCode128(t *testing.T, modules []bool) []int {
+	t.Helper()
+	var widths []byte
+	for i := 0; i < len(modules); {
+		run := 1
+		for i+run < len(modules) && modules[i+run] == modules[i] {
+			run++
+		}
+		widths = append(widths, byte('0'+run))
+		i += run
+	}
+
+	var symbols []int
+	for len(widths) > 0 {
+		n := 6
+		if len(widths) == 7 {
+			n = 7 // simbol stop diakhiri batang penutup
+		}
+		if len(widths) < n {
+			t.Fatalf("trailing widths %q do not form a symbol", widths)
+		}
+		symbol := -1
+		for value, pattern := range code128Patterns {
+			if pattern == string(widths[:n]) {
+				symbol = value
+				break
+			}
+		}
+		if symbol < 0 {
+			t.Fatalf("unknown symbol pattern %q", widths[:n])
+		}
+		symbols = append(symbols, symbol)
+		widths = widths[n:]
+	}
+	return symbols
+}
+
+func TestCode128(t *testing.T) {
+	tests := []struct {
+		text string
+		want []int
+	}{
+		// Set C: 105 + 29·1 + 12·2 + 34·3 + 56·4 + 78·5 + 90·6 + 12·7 = 1498, 1498 mod 103 = 56
+		{"29123456789012", []int{code128StartC, 29, 12, 34, 56, 78, 90, 12, 56, code128Stop}},
+		// Set B: 104 + 48·1 + 42·2 + 42·3 + 17·4 + 18·5 + 19·6 + 35·7 = 879, 879 mod 103 = 55
+		{"PJJ123C", []int{code128StartB, 48, 42, 42, 17, 18, 19, 35, 55, code128Stop}},
+		// Angka berjumlah ganjil tidak bisa memakai set C: 104 + 17·1 + 18·2 + 19·3 = 214, 214 mod 103 = 8
+		{"123", []int{code128StartB, 17, 18, 19, 8, code128Stop}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			modules, err := Code128(tt.text)
+			if err != nil {
+				t.Fatalf("Code128: %v", err)
+			}
+			if want := 11*(len(tt.want)-1) + 13; len(modules) != want {
+				t.Errorf("len(modules) = %d, want %d", len(modules), want)
+			}
+			if got := decodeCode128(t, modules); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("symbols = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCode128RejectsUnencodableText(t *testing.T) {
+	for _, text := range []string{"", "Perpustakaan\n", "Pérpustakaan"} {
+		if _, err := Code128(text); err == nil {
+			t.Errorf("Code128(%q) succeeded, want error", text)
+		}
+	}
+}
+
+func TestRenderImages(t *testing.T) {
+	matrix := [][]bool{{true, false, true}, {false, true, false}}
+
+	data, err := RenderPNG(matrix, 2, 3, 1)
+	if err != nil {
+		t.Fatalf("RenderPNG: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode PNG: %v", err)
+	}
+	// 3x2 modul berukuran 2x3 piksel ditambah quiet zone 2 piksel di setiap sisi
+	if bounds := img.Bounds(); bounds.Dx() != 10 || bounds.Dy() != 10 {
+		t.Errorf("PNG size = %dx%d, want 10x10", bounds.Dx(), bounds.Dy())
+	}
+	for _, pixel := range []struct {
+		x, y int
+		dark bool
+	}{{0, 0, false}, {2, 2, true}, {4, 2, false}, {4, 5, true}, {7, 7, false}} {
+		if r, _, _, _ := img.At(pixel.x, pixel.y).RGBA(); (r == 0) != pixel.dark {
+			t.Errorf("pixel (%d, %d) dark = %v, want %v", pixel.x, pixel.y, r == 0, pixel.dark)
+		}
+	}
+
+	svg := string(RenderSVG(matrix, 2, 3, 1))
+	if !strings.HasPrefix(svg, "<svg") || !strings.Contains(svg, `width="10"`) || !strings.Contains(svg, `height="10"`) {
+		t.Errorf("RenderSVG() = %s", svg)
+	}
+}
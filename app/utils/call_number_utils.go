@@ -0,0 +1,138 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Jenis token pada nomor panggil
+const (
+	callNumberInteger  = iota // bilangan bulat, e.g. kelas "813" atau tahun "2005"
+	callNumberFraction        // desimal setelah titik atau angka cutter, dibandingkan digit per digit
+	callNumberAlpha           // huruf, e.g. cutter "HIR" atau "l"
+)
+
+type callNumberToken struct {
+	kind int
+	text string
+}
+
+// tokenizeCallNumber memecah nomor panggil menjadi token angka dan huruf.
+// Angka setelah titik atau langsung setelah huruf (cutter) dianggap pecahan desimal.
+func tokenizeCallNumber(callNumber string) []callNumberToken {
+	var tokens []callNumberToken
+	runes := []rune(strings.ToUpper(strings.TrimSpace(callNumber)))
+
+	afterDot := false
+	for i := 0; i < len(runes); {
+		char := runes[i]
+		switch {
+		case unicode.IsDigit(char):
+			start := i
+			for i < len(runes) && unicode.IsDigit(runes[i]) {
+				i++
+			}
+			kind := callNumberInteger
+			if afterDot || (start > 0 && unicode.IsLetter(runes[start-1])) {
+				kind = callNumberFraction
+			}
+			tokens = append(tokens, callNumberToken{kind: kind, text: string(runes[start:i])})
+			afterDot = false
+		case unicode.IsLetter(char):
+			start := i
+			for i < len(runes) && unicode.IsLetter(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, callNumberToken{kind: callNumberAlpha, text: string(runes[start:i])})
+			afterDot = false
+		case char == '.':
+			// Titik sebelum huruf (e.g. ".H617") hanya pemisah cutter
+			afterDot = true
+			i++
+		default:
+			afterDot = false
+			i++
+		}
+	}
+
+	return tokens
+}
+
+// CompareCallNumbers membandingkan dua nomor panggil (DDC atau lokal) sesuai urutan rak,
+// sehingga "813.54" < "813.6" dan "813.54 H617" < "813.54 H62". Mengembalikan -1, 0, atau 1.
+func CompareCallNumbers(a, b string) int {
+	tokensA := tokenizeCallNumber(a)
+	tokensB := tokenizeCallNumber(b)
+
+	for i := 0; i < len(tokensA) && i < len(tokensB); i++ {
+		if result := compareCallNumberTokens(tokensA[i], tokensB[i]); result != 0 {
+			return result
+		}
+	}
+
+	switch {
+	case len(tokensA) < len(tokensB):
+		return -1
+	case len(tokensA) > len(tokensB):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// compareCallNumberTokens membandingkan dua token; angka selalu diurutkan sebelum huruf
+func compareCallNumberTokens(a, b callNumberToken) int {
+	aNumeric := a.kind != callNumberAlpha
+	bNumeric := b.kind != callNumberAlpha
+	if aNumeric != bNumeric {
+		if aNumeric {
+			return -1
+		}
+		return 1
+	}
+
+	if a.kind == callNumberInteger && b.kind == callNumberInteger {
+		x := strings.TrimLeft(a.text, "0")
+		y := strings.TrimLeft(b.text, "0")
+		if len(x) != len(y) {
+			if len(x) < len(y) {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(x, y)
+	}
+
+	// Pecahan dan huruf dibandingkan per karakter: .54 < .6, H617 < H62
+	return strings.Compare(a.text, b.text)
+}
+
+// CallNumberInRange mengembalikan true jika callNumber berada di antara start dan end (inklusif).
+// Batas kosong berarti tidak dibatasi; end dicocokkan sebagai prefiks sehingga "899" mencakup "899.221".
+func CallNumberInRange(callNumber, start, end string) bool {
+	if callNumber == "" {
+		return false
+	}
+	if start != "" && CompareCallNumbers(callNumber, start) < 0 {
+		return false
+	}
+	if end != "" && CompareCallNumbers(callNumber, end) > 0 && !callNumberHasPrefix(callNumber, end) {
+		return false
+	}
+	return true
+}
+
+// callNumberHasPrefix mengembalikan true jika token prefix merupakan awal dari token callNumber
+func callNumberHasPrefix(callNumber, prefix string) bool {
+	tokens := tokenizeCallNumber(callNumber)
+	prefixTokens := tokenizeCallNumber(prefix)
+	if len(prefixTokens) > len(tokens) {
+		return false
+	}
+	for i, token := range prefixTokens {
+		if compareCallNumberTokens(token, tokens[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
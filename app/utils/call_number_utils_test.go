@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestCompareCallNumbers(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"813.54", "813.6", -1},
+		{"813.54 H617", "813.54 H62", -1},
+		{"813.54 H62", "813.54 H617", 1},
+		{"92", "813", -1},                       // kelas utama dibandingkan sebagai bilangan bulat
+		{"082", "82", 0},                        // nol di depan tidak mengubah urutan
+		{"899.2213 HIR l", "899.2213 hir L", 0}, // huruf tidak membedakan kapital
+		{"899.2213", "899.2213 HIR", -1},
+		{"899.2213 HIR 2005", "899.2213 HIR l", -1}, // angka sebelum huruf
+		{"REF 899.2213", "899.2213", 1},
+		{"PL5089.H57 L37 2005", "PL5089.H57 L4 2005", -1},
+	}
+	for _, tt := range tests {
+		if got := CompareCallNumbers(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareCallNumbers(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestCompareCallNumbersSortsShelfOrder(t *testing.T) {
+	want := []string{"005.133 GO", "092 SOE", "813.54 H617", "813.54 H62", "813.6 MOR", "899.221 PRA b", "899.2213 HIR l", "899.2213 HIR s"}
+	got := []string{"899.2213 HIR s", "813.6 MOR", "092 SOE", "899.2213 HIR l", "813.54 H62", "005.133 GO", "899.221 PRA b", "813.54 H617"}
+	sort.Slice(got, func(i, j int) bool { return CompareCallNumbers(got[i], got[j]) < 0 })
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("sorted = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestCallNumberInRange(t *testing.T) {
+	tests := []struct {
+		callNumber, start, end string
+		want                   bool
+	}{
+		{"899.2213 HIR l", "800", "899", true}, // end dicocokkan sebagai prefiks
+		{"899", "800", "899", true},
+		{"900.1", "800", "899", false},
+		{"799.9", "800", "899", false},
+		{"800", "800", "899", true},
+		{"813.54", "", "", true},
+		{"813.54", "813.6", "", false},
+		{"", "800", "899", false},
+	}
+	for _, tt := range tests {
+		if got := CallNumberInRange(tt.callNumber, tt.start, tt.end); got != tt.want {
+			t.Errorf("CallNumberInRange(%q, %q, %q) = %v, want %v", tt.callNumber, tt.start, tt.end, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// FieldChange describes a single field that differs between two versions of a record
+type FieldChange struct {
+	Field    string      `json:"field"`
+	OldValue interface{} `json:"old_value"`
+	NewValue interface{} `json:"new_value"`
+}
+
+// Snapshot mengubah record menjadi map field JSON -> nilai. Field yang tercantum di omit
+// (misalnya "password") tidak ikut disimpan.
+func Snapshot(record interface{}, omit ...string) (map[string]interface{}, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := make(map[string]interface{})
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+	for _, field := range omit {
+		delete(snapshot, field)
+	}
+	return snapshot, nil
+}
+
+// DiffSnapshots membandingkan dua snapshot dan mengembalikan field yang berubah, diurutkan berdasarkan nama.
+// Snapshot nil dianggap kosong, sehingga pembuatan record menghasilkan semua field terisi sebagai perubahan.
+func DiffSnapshots(oldSnapshot, newSnapshot map[string]interface{}) []FieldChange {
+	fields := make(map[string]bool)
+	for field := range oldSnapshot {
+		fields[field] = true
+	}
+	for field := range newSnapshot {
+		fields[field] = true
+	}
+
+	names := make([]string, 0, len(fields))
+	for field := range fields {
+		names = append(names, field)
+	}
+	sort.Strings(names)
+
+	changes := []FieldChange{}
+	for _, field := range names {
+		oldValue, newValue := oldSnapshot[field], newSnapshot[field]
+		if !reflect.DeepEqual(oldValue, newValue) {
+			changes = append(changes, FieldChange{Field: field, OldValue: oldValue, NewValue: newValue})
+		}
+	}
+	return changes
+}
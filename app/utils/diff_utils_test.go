@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+type snapshotRecord struct {
+	Name     string   `json:"name"`
+	Password string   `json:"password"`
+	Year     int      `json:"year"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+func TestSnapshot(t *testing.T) {
+	snapshot, err := Snapshot(snapshotRecord{Name: "Laskar Pelangi", Password: "rahasia", Year: 2005}, "password")
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+	want := map[string]interface{}{"name": "Laskar Pelangi", "year": float64(2005)}
+	if !reflect.DeepEqual(snapshot, want) {
+		t.Errorf("Snapshot() = %v, want %v", snapshot, want)
+	}
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	before := map[string]interface{}{"name": "Laskar Pelangi", "year": float64(2005), "tags": []interface{}{"novel"}}
+
+	tests := []struct {
+		name   string
+		before map[string]interface{}
+		after  map[string]interface{}
+		want   []FieldChange
+	}{
+		{"unchanged", before, map[string]interface{}{"name": "Laskar Pelangi", "year": float64(2005), "tags": []interface{}{"novel"}}, []FieldChange{}},
+		{"changed value", before, map[string]interface{}{"name": "Sang Pemimpi", "year": float64(2005), "tags": []interface{}{"novel"}},
+			[]FieldChange{{Field: "name", OldValue: "Laskar Pelangi", NewValue: "Sang Pemimpi"}}},
+		{"changed list", before, map[string]interface{}{"name": "Laskar Pelangi", "year": float64(2005), "tags": []interface{}{"novel", "anak"}},
+			[]FieldChange{{Field: "tags", OldValue: []interface{}{"novel"}, NewValue: []interface{}{"novel", "anak"}}}},
+		{"added and removed fields sorted by name", map[string]interface{}{"year": float64(2005)}, map[string]interface{}{"name": "Laskar Pelangi"},
+			[]FieldChange{{Field: "name", OldValue: nil, NewValue: "Laskar Pelangi"}, {Field: "year", OldValue: float64(2005), NewValue: nil}}},
+		{"created record", nil, map[string]interface{}{"year": float64(2005), "name": "Laskar Pelangi"},
+			[]FieldChange{{Field: "name", OldValue: nil, NewValue: "Laskar Pelangi"}, {Field: "year", OldValue: nil, NewValue: float64(2005)}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DiffSnapshots(tt.before, tt.after); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffSnapshots() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
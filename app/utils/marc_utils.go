@@ -0,0 +1,523 @@
+package utils
+
+import (
+	"Restful-Perpustakaan-API/app/common"
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Karakter pemisah pada format MARC 21 (ISO 2709)
+const (
+	marcSubfieldDelimiter = 0x1F
+	marcFieldTerminator   = 0x1E
+	marcRecordTerminator  = 0x1D
+)
+
+// MarcXMLNamespace adalah namespace standar MARCXML
+const MarcXMLNamespace = "http://www.loc.gov/MARC21/slim"
+
+// MarcSubfield represents a single subfield of a MARC data field
+type MarcSubfield struct {
+	Code  string `xml:"code,attr"`
+	Value string `xml:",chardata"`
+}
+
+// MarcField represents a MARC control field (tag 001-009) or data field
+type MarcField struct {
+	Tag       string
+	Ind1      string
+	Ind2      string
+	Value     string // hanya untuk control field
+	Subfields []MarcSubfield
+}
+
+// MarcRecord represents a single bibliographic MARC record
+type MarcRecord struct {
+	Leader string
+	Fields []MarcField
+}
+
+// IsControlField mengembalikan true untuk tag 001-009 yang tidak memiliki indikator dan subfield
+func (f MarcField) IsControlField() bool {
+	return strings.HasPrefix(f.Tag, "00")
+}
+
+// Subfield mengembalikan nilai subfield pertama dengan kode tertentu
+func (f MarcField) Subfield(code string) string {
+	for _, sf := range f.Subfields {
+		if sf.Code == code {
+			return sf.Value
+		}
+	}
+	return ""
+}
+
+// ReadMarc21 membaca semua record MARC 21 (ISO 2709) dari r
+func ReadMarc21(r io.Reader) ([]MarcRecord, error) {
+	br := bufio.NewReader(r)
+
+	var records []MarcRecord
+	for {
+		data, err := br.ReadBytes(marcRecordTerminator)
+		if len(bytes.TrimSpace(data)) > 0 {
+			record, parseErr := parseMarc21Record(data)
+			if parseErr != nil {
+				return nil, fmt.Errorf("record %d: %v", len(records)+1, parseErr)
+			}
+			records = append(records, *record)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return records, nil
+}
+
+// parseMarc21Record mengurai satu record ISO 2709 (leader, directory, dan field)
+func parseMarc21Record(data []byte) (*MarcRecord, error) {
+	data = bytes.TrimLeft(data, "\r\n ")
+	if len(data) < 25 {
+		return nil, errors.New("record too short")
+	}
+
+	leader := string(data[:24])
+	baseAddress, err := strconv.Atoi(leader[12:17])
+	if err != nil || baseAddress < 25 || baseAddress > len(data) {
+		return nil, errors.New("invalid base address of data")
+	}
+
+	directory := data[24 : baseAddress-1]
+	if len(directory)%12 != 0 {
+		return nil, errors.New("invalid directory length")
+	}
+
+	record := &MarcRecord{Leader: leader}
+	for i := 0; i < len(directory); i += 12 {
+		entry := directory[i : i+12]
+		tag := string(entry[:3])
+		length, errLength := strconv.Atoi(string(entry[3:7]))
+		start, errStart := strconv.Atoi(string(entry[7:12]))
+		if errLength != nil || errStart != nil {
+			return nil, fmt.Errorf("invalid directory entry for tag %s", tag)
+		}
+		from := baseAddress + start
+		to := from + length
+		if length < 1 || to > len(data) {
+			return nil, fmt.Errorf("field %s out of range", tag)
+		}
+
+		// Buang field terminator di akhir field
+		raw := data[from : to-1]
+		field := MarcField{Tag: tag}
+		if field.IsControlField() {
+			field.Value = string(raw)
+		} else {
+			if len(raw) < 2 {
+				return nil, fmt.Errorf("field %s missing indicators", tag)
+			}
+			field.Ind1 = string(raw[0])
+			field.Ind2 = string(raw[1])
+			for _, part := range bytes.Split(raw[2:], []byte{marcSubfieldDelimiter}) {
+				if len(part) == 0 {
+					continue
+				}
+				field.Subfields = append(field.Subfields, MarcSubfield{
+					Code:  string(part[0]),
+					Value: string(part[1:]),
+				})
+			}
+		}
+		record.Fields = append(record.Fields, field)
+	}
+
+	return record, nil
+}
+
+// WriteMarc21 menulis satu record ke w dalam format ISO 2709
+func WriteMarc21(w io.Writer, record MarcRecord) error {
+	var directory, fields bytes.Buffer
+	for _, field := range record.Fields {
+		var body bytes.Buffer
+		if field.IsControlField() {
+			body.WriteString(field.Value)
+		} else {
+			body.WriteString(marcIndicator(field.Ind1))
+			body.WriteString(marcIndicator(field.Ind2))
+			for _, sf := range field.Subfields {
+				body.WriteByte(marcSubfieldDelimiter)
+				body.WriteString(sf.Code)
+				body.WriteString(sf.Value)
+			}
+		}
+		body.WriteByte(marcFieldTerminator)
+
+		if body.Len() > 9999 || fields.Len() > 99999 {
+			return fmt.Errorf("field %s too long for ISO 2709", field.Tag)
+		}
+		fmt.Fprintf(&directory, "%3s%04d%05d", field.Tag, body.Len(), fields.Len())
+		fields.Write(body.Bytes())
+	}
+	directory.WriteByte(marcFieldTerminator)
+
+	baseAddress := 24 + directory.Len()
+	recordLength := baseAddress + fields.Len() + 1
+	if recordLength > 99999 {
+		return errors.New("record too long for ISO 2709")
+	}
+
+	leader := []byte(record.Leader)
+	if len(leader) != 24 {
+		leader = []byte(DefaultMarcLeader)
+	}
+	copy(leader[0:5], fmt.Sprintf("%05d", recordLength))
+	copy(leader[12:17], fmt.Sprintf("%05d", baseAddress))
+
+	var out bytes.Buffer
+	out.Write(leader)
+	out.Write(directory.Bytes())
+	out.Write(fields.Bytes())
+	out.WriteByte(marcRecordTerminator)
+
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// marcIndicator mengganti indikator kosong dengan spasi
+func marcIndicator(indicator string) string {
+	if indicator == "" {
+		return " "
+	}
+	return indicator[:1]
+}
+
+// DefaultMarcLeader adalah leader untuk record buku baru (monograf, UTF-8); panjang dan alamat diisi saat ditulis
+const DefaultMarcLeader = "00000nam a2200000 i 4500"
+
+// marcXMLRecord adalah bentuk XML dari satu record MARCXML
+type marcXMLRecord struct {
+	XMLName       xml.Name `xml:"record"`
+	Leader        string   `xml:"leader"`
+	ControlFields []struct {
+		Tag   string `xml:"tag,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"controlfield"`
+	DataFields []struct {
+		Tag       string         `xml:"tag,attr"`
+		Ind1      string         `xml:"ind1,attr"`
+		Ind2      string         `xml:"ind2,attr"`
+		Subfields []MarcSubfield `xml:"subfield"`
+	} `xml:"datafield"`
+}
+
+// ReadMarcXML membaca semua record dari dokumen MARCXML (<collection> atau satu <record>)
+func ReadMarcXML(r io.Reader) ([]MarcRecord, error) {
+	decoder := xml.NewDecoder(r)
+
+	var records []MarcRecord
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "record" {
+			continue
+		}
+
+		var xr marcXMLRecord
+		if err := decoder.DecodeElement(&xr, &start); err != nil {
+			return nil, fmt.Errorf("record %d: %v", len(records)+1, err)
+		}
+
+		// Urutan asli antara control field dan data field tidak penting karena tag control field selalu lebih kecil
+		record := MarcRecord{Leader: xr.Leader}
+		for _, cf := range xr.ControlFields {
+			record.Fields = append(record.Fields, MarcField{Tag: cf.Tag, Value: cf.Value})
+		}
+		for _, df := range xr.DataFields {
+			record.Fields = append(record.Fields, MarcField{
+				Tag:       df.Tag,
+				Ind1:      marcIndicator(df.Ind1),
+				Ind2:      marcIndicator(df.Ind2),
+				Subfields: df.Subfields,
+			})
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// MarcXMLWriter menulis record MARCXML satu per satu di dalam satu elemen <collection>
+type MarcXMLWriter struct {
+	w       io.Writer
+	started bool
+}
+
+// NewMarcXMLWriter membuat MarcXMLWriter baru
+func NewMarcXMLWriter(w io.Writer) *MarcXMLWriter {
+	return &MarcXMLWriter{w: w}
+}
+
+// Write menulis satu record; pembuka <collection> ditulis otomatis pada record pertama
+func (mw *MarcXMLWriter) Write(record MarcRecord) error {
+	if !mw.started {
+		if err := mw.begin(); err != nil {
+			return err
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("  <record>\n")
+	leader := record.Leader
+	if len(leader) != 24 {
+		leader = DefaultMarcLeader
+	}
+	fmt.Fprintf(&buf, "    <leader>%s</leader>\n", xmlEscape(leader))
+	for _, field := range record.Fields {
+		if field.IsControlField() {
+			fmt.Fprintf(&buf, "    <controlfield tag=\"%s\">%s</controlfield>\n", xmlEscape(field.Tag), xmlEscape(field.Value))
+			continue
+		}
+		fmt.Fprintf(&buf, "    <datafield tag=\"%s\" ind1=\"%s\" ind2=\"%s\">\n", xmlEscape(field.Tag), xmlEscape(marcIndicator(field.Ind1)), xmlEscape(marcIndicator(field.Ind2)))
+		for _, sf := range field.Subfields {
+			fmt.Fprintf(&buf, "      <subfield code=\"%s\">%s</subfield>\n", xmlEscape(sf.Code), xmlEscape(sf.Value))
+		}
+		buf.WriteString("    </datafield>\n")
+	}
+	buf.WriteString("  </record>\n")
+
+	_, err := mw.w.Write(buf.Bytes())
+	return err
+}
+
+// Close menutup elemen <collection>; tetap menghasilkan dokumen valid walau tanpa record
+func (mw *MarcXMLWriter) Close() error {
+	if !mw.started {
+		if err := mw.begin(); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(mw.w, "</collection>\n")
+	return err
+}
+
+// begin menulis deklarasi XML dan pembuka <collection>
+func (mw *MarcXMLWriter) begin() error {
+	mw.started = true
+	_, err := io.WriteString(mw.w, xml.Header+"<collection xmlns=\""+MarcXMLNamespace+"\">\n")
+	return err
+}
+
+// xmlEscape meng-escape teks untuk konten atau atribut XML
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+// marcMappedSubfields adalah subfield yang dipetakan ke common.Book per tag
+var marcMappedSubfields = map[string]string{
+	"020": "a",
+	"041": "a",
+	"082": "a2",
+	"090": "ab",
+	"092": "ab",
+	"100": "a",
+	"700": "a",
+	"245": "ab",
+	"250": "a",
+	"260": "bc",
+	"264": "bc",
+	"520": "a",
+	"650": "a",
+}
+
+// marcStructuralTags adalah control field yang tidak berisi data bibliografis
+var marcStructuralTags = map[string]bool{"001": true, "003": true, "005": true, "008": true}
+
+var (
+	marcYearRegex  = regexp.MustCompile(`\d{4}`)
+	marcISBNRegex  = regexp.MustCompile(`^[0-9Xx-]+`)
+	marcTrailRegex = regexp.MustCompile(`[\s/:;,.=]+$`)
+)
+
+// MarcToBook memetakan record MARC ke common.Book dan mengembalikan daftar field yang tidak terpetakan
+// (misalnya "245$c" atau "300")
+func MarcToBook(record MarcRecord) (common.Book, []string) {
+	var book common.Book
+	var authors, subjects, unmapped []string
+	hasPublication260 := false
+	for _, field := range record.Fields {
+		if field.Tag == "260" {
+			hasPublication260 = true
+		}
+	}
+
+	for _, field := range record.Fields {
+		mapped, ok := marcMappedSubfields[field.Tag]
+		if !ok {
+			if !marcStructuralTags[field.Tag] {
+				unmapped = append(unmapped, field.Tag)
+			}
+			continue
+		}
+		// 264 hanya dipakai untuk pernyataan penerbitan (ind2 = 1) dan jika 260 tidak ada
+		if field.Tag == "264" && (hasPublication260 || field.Ind2 != "1") {
+			unmapped = append(unmapped, field.Tag)
+			continue
+		}
+
+		for _, sf := range field.Subfields {
+			if !strings.Contains(mapped, sf.Code) {
+				unmapped = append(unmapped, field.Tag+"$"+sf.Code)
+			}
+		}
+
+		switch field.Tag {
+		case "020":
+			if book.ISBN == "" {
+				book.ISBN = NormalizeISBN(marcISBNRegex.FindString(strings.TrimSpace(field.Subfield("a"))))
+			}
+		case "041":
+			if book.Language == "" {
+				book.Language = strings.TrimSpace(field.Subfield("a"))
+			}
+		case "082":
+			// Tanda segmentasi "/" dari Library of Congress dihapus, e.g. "813/.54" -> "813.54"
+			if book.Classification == "" {
+				book.Classification = strings.ReplaceAll(strings.TrimSpace(field.Subfield("a")), "/", "")
+			}
+		case "090", "092":
+			if book.CallNumber == "" {
+				book.CallNumber = strings.TrimSpace(strings.TrimSpace(field.Subfield("a")) + " " + strings.TrimSpace(field.Subfield("b")))
+			}
+		case "100", "700":
+			if author := cleanMarcValue(field.Subfield("a")); author != "" {
+				authors = append(authors, author)
+			}
+		case "245":
+			book.Title = cleanMarcValue(field.Subfield("a"))
+			if subtitle := cleanMarcValue(field.Subfield("b")); subtitle != "" {
+				book.Title += ": " + subtitle
+			}
+		case "250":
+			book.Edition = cleanMarcValue(field.Subfield("a"))
+		case "260", "264":
+			book.Publisher = cleanMarcValue(field.Subfield("b"))
+			if year, err := strconv.Atoi(marcYearRegex.FindString(field.Subfield("c"))); err == nil {
+				book.PublishedYear = year
+				book.Year = year
+			}
+		case "520":
+			book.Description = strings.TrimSpace(field.Subfield("a"))
+		case "650":
+			if subject := cleanMarcValue(field.Subfield("a")); subject != "" {
+				subjects = append(subjects, subject)
+			}
+		}
+	}
+
+	book.Author = strings.Join(authors, "; ")
+	book.Genre = strings.Join(subjects, "; ")
+	return book, unmapped
+}
+
+// BookToMarc membuat record MARC dari common.Book
+func BookToMarc(book common.Book) MarcRecord {
+	record := MarcRecord{Leader: DefaultMarcLeader}
+	if book.ID != 0 {
+		record.Fields = append(record.Fields, MarcField{Tag: "001", Value: strconv.Itoa(book.ID)})
+	}
+	if book.ISBN != "" {
+		record.Fields = append(record.Fields, marcDataField("020", " ", " ", "a", book.ISBN))
+	}
+	if book.Language != "" {
+		record.Fields = append(record.Fields, marcDataField("041", "0", " ", "a", book.Language))
+	}
+	if book.Classification != "" {
+		record.Fields = append(record.Fields, marcDataField("082", "0", "4", "a", book.Classification))
+	}
+	if book.CallNumber != "" {
+		record.Fields = append(record.Fields, marcDataField("092", " ", " ", "a", book.CallNumber))
+	}
+
+	authors := splitMarcList(book.Author)
+	if len(authors) > 0 {
+		record.Fields = append(record.Fields, marcDataField("100", "1", " ", "a", authors[0]))
+	}
+
+	title := marcDataField("245", "1", "0", "a", book.Title)
+	if len(authors) == 0 {
+		title.Ind1 = "0"
+	}
+	if parts := strings.SplitN(book.Title, ": ", 2); len(parts) == 2 {
+		title.Subfields = []MarcSubfield{{Code: "a", Value: parts[0] + " :"}, {Code: "b", Value: parts[1]}}
+	}
+	record.Fields = append(record.Fields, title)
+	if book.Edition != "" {
+		record.Fields = append(record.Fields, marcDataField("250", " ", " ", "a", book.Edition))
+	}
+
+	year := book.PublishedYear
+	if year == 0 {
+		year = book.Year
+	}
+	if book.Publisher != "" || year != 0 {
+		publication := MarcField{Tag: "264", Ind1: " ", Ind2: "1"}
+		if book.Publisher != "" {
+			publication.Subfields = append(publication.Subfields, MarcSubfield{Code: "b", Value: book.Publisher})
+		}
+		if year != 0 {
+			publication.Subfields = append(publication.Subfields, MarcSubfield{Code: "c", Value: strconv.Itoa(year)})
+		}
+		record.Fields = append(record.Fields, publication)
+	}
+
+	if book.Description != "" {
+		record.Fields = append(record.Fields, marcDataField("520", " ", " ", "a", book.Description))
+	}
+	for _, subject := range splitMarcList(book.Genre) {
+		record.Fields = append(record.Fields, marcDataField("650", " ", "4", "a", subject))
+	}
+	for _, author := range authors[min(1, len(authors)):] {
+		record.Fields = append(record.Fields, marcDataField("700", "1", " ", "a", author))
+	}
+
+	return record
+}
+
+// marcDataField membuat data field dengan satu subfield
+func marcDataField(tag, ind1, ind2, code, value string) MarcField {
+	return MarcField{Tag: tag, Ind1: ind1, Ind2: ind2, Subfields: []MarcSubfield{{Code: code, Value: value}}}
+}
+
+// splitMarcList memecah daftar yang dipisahkan "; " menjadi nilai-nilai terpisah
+func splitMarcList(value string) []string {
+	var result []string
+	for _, part := range strings.Split(value, ";") {
+		if part = strings.TrimSpace(part); part != "" {
+			result = append(result, part)
+		}
+	}
+	return result
+}
+
+// cleanMarcValue membuang tanda baca ISBD di akhir nilai (" /", " :", ",", ".")
+func cleanMarcValue(value string) string {
+	return marcTrailRegex.ReplaceAllString(strings.TrimSpace(value), "")
+}
@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"Restful-Perpustakaan-API/app/common"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func marcTestBook() common.Book {
+	return common.Book{
+		ID:             12,
+		Title:          "Laskar Pelangi: Sebuah Novel",
+		Author:         "Andrea Hirata; Angie Kilbane",
+		Publisher:      "Bentang Pustaka",
+		PublishedYear:  2005,
+		Year:           2005,
+		ISBN:           "9789793062792",
+		Genre:          "Fiksi Indonesia; Pendidikan",
+		Description:    "Kisah sepuluh anak Belitung & guru mereka <Bu Mus>.",
+		Classification: "899.2213",
+		CallNumber:     "899.2213 HIR l",
+		Edition:        "Cetakan ke-5",
+		Language:       "ind",
+	}
+}
+
+func TestMarcRoundTrip(t *testing.T) {
+	book := marcTestBook()
+	// ID hanya ditulis sebagai control field 001 dan tidak dibaca kembali
+	want := book
+	want.ID = 0
+
+	tests := []struct {
+		name  string
+		write func(buf *bytes.Buffer, records ...MarcRecord) error
+		read  func(buf *bytes.Buffer) ([]MarcRecord, error)
+	}{
+		{
+			name: "MARC 21",
+			write: func(buf *bytes.Buffer, records ...MarcRecord) error {
+				for _, record := range records {
+					if err := WriteMarc21(buf, record); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			read: func(buf *bytes.Buffer) ([]MarcRecord, error) { return ReadMarc21(buf) },
+		},
+		{
+			name: "MARCXML",
+			write: func(buf *bytes.Buffer, records ...MarcRecord) error {
+				mw := NewMarcXMLWriter(buf)
+				for _, record := range records {
+					if err := mw.Write(record); err != nil {
+						return err
+					}
+				}
+				return mw.Close()
+			},
+			read: func(buf *bytes.Buffer) ([]MarcRecord, error) { return ReadMarcXML(buf) },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.write(&buf, BookToMarc(book), BookToMarc(common.Book{Title: "Sang Pemimpi"})); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			records, err := tt.read(&buf)
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			if len(records) != 2 {
+				t.Fatalf("read %d records, want 2", len(records))
+			}
+
+			got, unmapped := MarcToBook(records[0])
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("MarcToBook() = %+v, want %+v", got, want)
+			}
+			if len(unmapped) != 0 {
+				t.Errorf("unmapped = %v, want none", unmapped)
+			}
+			if second, _ := MarcToBook(records[1]); second.Title != "Sang Pemimpi" {
+				t.Errorf("second title = %q, want %q", second.Title, "Sang Pemimpi")
+			}
+		})
+	}
+}
+
+func TestMarcToBook(t *testing.T) {
+	record := MarcRecord{
+		Leader: DefaultMarcLeader,
+		Fields: []MarcField{
+			{Tag: "001", Value: "ocm12345"},
+			{Tag: "020", Ind1: " ", Ind2: " ", Subfields: []MarcSubfield{{Code: "a", Value: "979-3062-79-7 (pbk.)"}}},
+			{Tag: "082", Ind1: "0", Ind2: "4", Subfields: []MarcSubfield{{Code: "a", Value: "899.2/213"}, {Code: "2", Value: "23"}}},
+			{Tag: "100", Ind1: "1", Ind2: " ", Subfields: []MarcSubfield{{Code: "a", Value: "Hirata, Andrea,"}}},
+			{Tag: "245", Ind1: "1", Ind2: "0", Subfields: []MarcSubfield{{Code: "a", Value: "Laskar pelangi /"}, {Code: "c", Value: "Andrea Hirata."}}},
+			{Tag: "260", Ind1: " ", Ind2: " ", Subfields: []MarcSubfield{{Code: "a", Value: "Yogyakarta :"}, {Code: "b", Value: "Bentang,"}, {Code: "c", Value: "c2005."}}},
+			{Tag: "264", Ind1: " ", Ind2: "4", Subfields: []MarcSubfield{{Code: "c", Value: "©2005"}}},
+			{Tag: "300", Ind1: " ", Ind2: " ", Subfields: []MarcSubfield{{Code: "a", Value: "xiv, 529 p."}}},
+		},
+	}
+
+	book, unmapped := MarcToBook(record)
+	if book.ISBN != "9793062797" || book.Classification != "899.2213" || book.Author != "Hirata, Andrea" ||
+		book.Title != "Laskar pelangi" || book.Publisher != "Bentang" || book.PublishedYear != 2005 {
+		t.Errorf("MarcToBook() = %+v", book)
+	}
+	wantUnmapped := []string{"245$c", "260$a", "264", "300"}
+	if !reflect.DeepEqual(unmapped, wantUnmapped) {
+		t.Errorf("unmapped = %v, want %v", unmapped, wantUnmapped)
+	}
+}
+
+func TestReadMarc21RejectsMalformedRecords(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteMarc21(&buf, BookToMarc(marcTestBook())); err != nil {
+		t.Fatalf("WriteMarc21: %v", err)
+	}
+	valid := buf.String()
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"too short", "00026nam"},
+		{"invalid base address", valid[:12] + "abcde" + valid[17:]},
+		{"field out of range", valid[:len(valid)/2] + string(rune(marcRecordTerminator))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadMarc21(strings.NewReader(tt.data)); err == nil {
+				t.Errorf("ReadMarc21() succeeded, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// NormalizeEmail menyeragamkan alamat email untuk mencari anggota ganda: huruf kecil, tanpa tag "+..." dan,
+// untuk Gmail, tanpa titik di bagian lokal karena Gmail mengabaikannya
+func NormalizeEmail(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return email
+	}
+	local, domain := email[:at], email[at+1:]
+	if plus := strings.Index(local, "+"); plus > 0 {
+		local = local[:plus]
+	}
+	if domain == "gmail.com" || domain == "googlemail.com" {
+		local = strings.ReplaceAll(local, ".", "")
+		domain = "gmail.com"
+	}
+	return local + "@" + domain
+}
+
+// NormalizePhone menyisakan digit nomor telepon dan menulis kode negara +62 sebagai awalan 0,
+// sehingga "+62 812-3456-789" dan "0812 3456 789" dianggap sama. Nomor yang terlalu pendek dikosongkan.
+func NormalizePhone(phone string) string {
+	var digits strings.Builder
+	for _, char := range phone {
+		if char >= '0' && char <= '9' {
+			digits.WriteRune(char)
+		}
+	}
+	normalized := digits.String()
+	if strings.HasPrefix(normalized, "62") {
+		normalized = "0" + normalized[2:]
+	}
+	if len(normalized) < 7 {
+		return ""
+	}
+	return normalized
+}
+
+// NormalizeName menyeragamkan nama: huruf kecil, tanda baca dibuang dan kata-kata diurutkan,
+// sehingga "Santoso, Budi" dan "budi santoso" menjadi sama
+func NormalizeName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(char rune) bool {
+		return !unicode.IsLetter(char) && !unicode.IsDigit(char)
+	})
+	sort.Strings(words)
+	return strings.Join(words, " ")
+}
+
+// NameSimilarity mengembalikan kemiripan dua nama antara 0 dan 1 berdasarkan jarak Levenshtein
+// dari nama yang sudah dinormalisasi
+func NameSimilarity(a, b string) float64 {
+	ra, rb := []rune(NormalizeName(a)), []rune(NormalizeName(b))
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// levenshtein menghitung jumlah minimum sisipan, hapusan dan penggantian huruf untuk mengubah a menjadi b
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func minInt(values ...int) int {
+	min := values[0]
+	for _, v := range values[1:] {
+		if v < min {
+			min = v
+		}
+	}
+	return min
+}
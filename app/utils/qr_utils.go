@@ -0,0 +1,352 @@
+package utils
+
+import "errors"
+
+// qrVersion berisi kapasitas QR code versi 1-3 pada tingkat koreksi galat M (satu blok Reed-Solomon)
+type qrVersion struct {
+	dataCodewords int
+	ecCodewords   int
+	alignment     int // posisi pusat pola alignment; 0 jika tidak ada
+}
+
+var qrVersions = []qrVersion{
+	{dataCodewords: 16, ecCodewords: 10},
+	{dataCodewords: 28, ecCodewords: 16, alignment: 18},
+	{dataCodewords: 44, ecCodewords: 26, alignment: 22},
+}
+
+// qrCode menyimpan modul QR code yang sedang dibuat dan penanda modul fungsi (finder, timing, format)
+type qrCode struct {
+	size       int
+	modules    [][]bool
+	isFunction [][]bool
+}
+
+// QRCode mengodekan text (mode byte, koreksi galat M) sebagai QR code versi terkecil yang cukup, hingga versi 3
+// (42 byte), dan mengembalikan matriks modulnya (true = gelap)
+func QRCode(text string) ([][]bool, error) {
+	data := []byte(text)
+	version := 0
+	for v, info := range qrVersions {
+		// 4 bit mode + 8 bit panjang + data
+		if 12+8*len(data) <= 8*info.dataCodewords {
+			version = v + 1
+			break
+		}
+	}
+	if version == 0 {
+		return nil, errors.New("text is too long for a QR code")
+	}
+	info := qrVersions[version-1]
+
+	codewords := qrDataCodewords(data, info.dataCodewords)
+	codewords = append(codewords, reedSolomonRemainder(codewords, reedSolomonDivisor(info.ecCodewords))...)
+
+	qr := newQRCode(version)
+	qr.drawFunctionPatterns(info)
+	qr.drawCodewords(codewords)
+
+	// Pilih mask dengan penalti terkecil sesuai spesifikasi
+	bestMask, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		qr.applyMask(mask)
+		qr.drawFormatBits(mask)
+		if penalty := qr.penalty(); bestPenalty < 0 || penalty < bestPenalty {
+			bestMask, bestPenalty = mask, penalty
+		}
+		qr.applyMask(mask) // XOR dua kali mengembalikan modul data
+	}
+	qr.applyMask(bestMask)
+	qr.drawFormatBits(bestMask)
+	return qr.modules, nil
+}
+
+// qrDataCodewords menyusun bit mode byte, panjang, data, terminator dan byte pengisi menjadi capacity codeword
+func qrDataCodewords(data []byte, capacity int) []byte {
+	var bits []bool
+	appendBits := func(value, length int) {
+		for i := length - 1; i >= 0; i-- {
+			bits = append(bits, (value>>i)&1 == 1)
+		}
+	}
+	appendBits(0x4, 4) // mode byte
+	appendBits(len(data), 8)
+	for _, b := range data {
+		appendBits(int(b), 8)
+	}
+	appendBits(0, min(4, capacity*8-len(bits)))
+	for len(bits)%8 != 0 {
+		bits = append(bits, false)
+	}
+
+	codewords := make([]byte, 0, capacity)
+	for i := 0; i < len(bits); i += 8 {
+		var b byte
+		for j := 0; j < 8; j++ {
+			if bits[i+j] {
+				b |= 1 << (7 - j)
+			}
+		}
+		codewords = append(codewords, b)
+	}
+	for pad := byte(0xEC); len(codewords) < capacity; pad ^= 0xEC ^ 0x11 {
+		codewords = append(codewords, pad)
+	}
+	return codewords
+}
+
+func newQRCode(version int) *qrCode {
+	size := 17 + 4*version
+	qr := &qrCode{size: size, modules: make([][]bool, size), isFunction: make([][]bool, size)}
+	for y := 0; y < size; y++ {
+		qr.modules[y] = make([]bool, size)
+		qr.isFunction[y] = make([]bool, size)
+	}
+	return qr
+}
+
+func (qr *qrCode) setFunction(x, y int, dark bool) {
+	qr.modules[y][x] = dark
+	qr.isFunction[y][x] = true
+}
+
+// drawFunctionPatterns menggambar pola timing, finder beserta separator, alignment dan modul gelap,
+// serta mencadangkan area format
+func (qr *qrCode) drawFunctionPatterns(info qrVersion) {
+	for i := 0; i < qr.size; i++ {
+		qr.setFunction(6, i, i%2 == 0)
+		qr.setFunction(i, 6, i%2 == 0)
+	}
+	for _, center := range [][2]int{{3, 3}, {qr.size - 4, 3}, {3, qr.size - 4}} {
+		for dy := -4; dy <= 4; dy++ {
+			for dx := -4; dx <= 4; dx++ {
+				x, y := center[0]+dx, center[1]+dy
+				if x < 0 || x >= qr.size || y < 0 || y >= qr.size {
+					continue
+				}
+				distance := max(abs(dx), abs(dy))
+				qr.setFunction(x, y, distance != 2 && distance != 4)
+			}
+		}
+	}
+	if info.alignment > 0 {
+		for dy := -2; dy <= 2; dy++ {
+			for dx := -2; dx <= 2; dx++ {
+				qr.setFunction(info.alignment+dx, info.alignment+dy, max(abs(dx), abs(dy)) != 1)
+			}
+		}
+	}
+	qr.drawFormatBits(0)
+}
+
+// drawFormatBits menggambar kedua salinan informasi format (koreksi galat M dan mask) beserta modul gelap
+func (qr *qrCode) drawFormatBits(mask int) {
+	// Tingkat koreksi galat M dikodekan sebagai 00
+	data := mask
+	remainder := data
+	for i := 0; i < 10; i++ {
+		remainder = (remainder << 1) ^ ((remainder >> 9) * 0x537)
+	}
+	bits := (data<<10 | remainder) ^ 0x5412
+	bit := func(i int) bool { return (bits>>i)&1 == 1 }
+
+	for i := 0; i <= 5; i++ {
+		qr.setFunction(8, i, bit(i))
+	}
+	qr.setFunction(8, 7, bit(6))
+	qr.setFunction(8, 8, bit(7))
+	qr.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		qr.setFunction(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		qr.setFunction(qr.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		qr.setFunction(8, qr.size-15+i, bit(i))
+	}
+	qr.setFunction(8, qr.size-8, true)
+}
+
+// drawCodewords menempatkan bit codeword secara zig-zag dua kolom dari kanan bawah, melewati modul fungsi
+func (qr *qrCode) drawCodewords(codewords []byte) {
+	i := 0
+	for right := qr.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < qr.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = qr.size - 1 - vert
+				}
+				if !qr.isFunction[y][x] && i < len(codewords)*8 {
+					qr.modules[y][x] = (codewords[i/8]>>(7-i%8))&1 == 1
+					i++
+				}
+			}
+		}
+	}
+}
+
+// applyMask membalik modul data yang memenuhi pola mask; memanggilnya dua kali membatalkan mask
+func (qr *qrCode) applyMask(mask int) {
+	for y := 0; y < qr.size; y++ {
+		for x := 0; x < qr.size; x++ {
+			if qr.isFunction[y][x] {
+				continue
+			}
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert {
+				qr.modules[y][x] = !qr.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty menghitung skor penalti mask: deretan warna sama, blok 2x2, pola mirip finder dan keseimbangan gelap/terang
+func (qr *qrCode) penalty() int {
+	result := 0
+	for _, horizontal := range []bool{true, false} {
+		for a := 0; a < qr.size; a++ {
+			runColor, run := false, 0
+			history := make([]int, 7)
+			for b := 0; b < qr.size; b++ {
+				module := qr.modules[a][b]
+				if !horizontal {
+					module = qr.modules[b][a]
+				}
+				if module == runColor {
+					run++
+					if run == 5 {
+						result += 3
+					} else if run > 5 {
+						result++
+					}
+					continue
+				}
+				qr.addRunHistory(run, history)
+				if !runColor {
+					result += qr.finderLikePatterns(history) * 40
+				}
+				runColor, run = module, 1
+			}
+			if runColor {
+				qr.addRunHistory(run, history)
+				run = 0
+			}
+			qr.addRunHistory(run+qr.size, history)
+			result += qr.finderLikePatterns(history) * 40
+		}
+	}
+
+	dark := 0
+	for y := 0; y < qr.size; y++ {
+		for x := 0; x < qr.size; x++ {
+			if qr.modules[y][x] {
+				dark++
+			}
+			if x < qr.size-1 && y < qr.size-1 {
+				c := qr.modules[y][x]
+				if c == qr.modules[y][x+1] && c == qr.modules[y+1][x] && c == qr.modules[y+1][x+1] {
+					result += 3
+				}
+			}
+		}
+	}
+	total := qr.size * qr.size
+	result += ((abs(dark*20-total*10)+total-1)/total - 1) * 10
+	return result
+}
+
+// addRunHistory menambahkan panjang deretan ke riwayat; deretan terang pertama diperpanjang dengan quiet zone
+func (qr *qrCode) addRunHistory(run int, history []int) {
+	if history[0] == 0 {
+		run += qr.size
+	}
+	copy(history[1:], history[:len(history)-1])
+	history[0] = run
+}
+
+// finderLikePatterns menghitung pola 1:1:3:1:1 dengan area terang di salah satu sisi pada riwayat deretan
+func (qr *qrCode) finderLikePatterns(history []int) int {
+	n := history[1]
+	core := n > 0 && history[2] == n && history[3] == n*3 && history[4] == n && history[5] == n
+	count := 0
+	if core && history[0] >= n*4 && history[6] >= n {
+		count++
+	}
+	if core && history[6] >= n*4 && history[0] >= n {
+		count++
+	}
+	return count
+}
+
+// reedSolomonDivisor menghitung polinomial generator Reed-Solomon berderajat degree di GF(256)
+func reedSolomonDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+	return result
+}
+
+// reedSolomonRemainder menghitung codeword koreksi galat untuk data
+func reedSolomonRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i := range result {
+			result[i] ^= gfMultiply(divisor[i], factor)
+		}
+	}
+	return result
+}
+
+// gfMultiply mengalikan dua elemen GF(256) dengan polinomial reduksi 0x11D
+func gfMultiply(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>i)&1) * int(x)
+	}
+	return byte(z)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
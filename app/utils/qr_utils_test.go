@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+// decodeQRCode membaca kembali teks dari matriks QRCode: mask dari informasi format, codeword dalam urutan
+// zig-zag, sindrom Reed-Solomon, lalu mode, panjang dan data
+func decodeQRCode(t *testing.T, modules [][]bool) string {
+	t.Helper()
+	version := (len(modules) - 17) / 4
+	if version < 1 || version > len(qrVersions) || len(modules) != 17+4*version {
+		t.Fatalf("unexpected QR size %d", len(modules))
+	}
+	info := qrVersions[version-1]
+
+	// Informasi format di samping finder kiri atas, dimulai dari bit 14
+	format := 0
+	for _, pos := range [][2]int{{0, 8}, {1, 8}, {2, 8}, {3, 8}, {4, 8}, {5, 8}, {7, 8}, {8, 8}, {8, 7}, {8, 5}, {8, 4}, {8, 3}, {8, 2}, {8, 1}, {8, 0}} {
+		format <<= 1
+		if modules[pos[1]][pos[0]] {
+			format |= 1
+		}
+	}
+	format ^= 0x5412
+	remainder := format >> 10
+	for i := 0; i < 10; i++ {
+		remainder = (remainder << 1) ^ ((remainder >> 9) * 0x537)
+	}
+	if remainder != format&0x3FF {
+		t.Fatalf("format bits %015b fail the BCH check", format)
+	}
+	if level := format >> 13; level != 0 {
+		t.Fatalf("error correction level bits = %02b, want 00 (M)", level)
+	}
+
+	// Salin modul ke tata letak yang sama agar modul fungsi dikenali, lalu buka mask
+	qr := newQRCode(version)
+	qr.drawFunctionPatterns(info)
+	for y := range modules {
+		copy(qr.modules[y], modules[y])
+	}
+	qr.applyMask(format >> 10 & 7)
+
+	total := info.dataCodewords + info.ecCodewords
+	codewords := make([]byte, total)
+	i := 0
+	for right := qr.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < qr.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x, y := right-j, vert
+				if (right+1)&2 == 0 {
+					y = qr.size - 1 - vert
+				}
+				if !qr.isFunction[y][x] && i < total*8 {
+					if qr.modules[y][x] {
+						codewords[i/8] |= 1 << (7 - i%8)
+					}
+					i++
+				}
+			}
+		}
+	}
+
+	// Polinomial codeword harus bernilai nol di setiap akar generator α^0..α^(ec-1)
+	root := byte(1)
+	for k := 0; k < info.ecCodewords; k++ {
+		var syndrome byte
+		for _, c := range codewords {
+			syndrome = gfMultiply(syndrome, root) ^ c
+		}
+		if syndrome != 0 {
+			t.Fatalf("syndrome %d = %#x, want 0", k, syndrome)
+		}
+		root = gfMultiply(root, 0x02)
+	}
+
+	data := codewords[:info.dataCodewords]
+	if mode := data[0] >> 4; mode != 0x4 {
+		t.Fatalf("mode = %#x, want byte mode", mode)
+	}
+	length := int(data[0]&0x0F)<<4 | int(data[1]>>4)
+	text := make([]byte, length)
+	for n := range text {
+		text[n] = data[1+n]<<4 | data[2+n]>>4
+	}
+	return string(text)
+}
+
+func TestQRCode(t *testing.T) {
+	tests := []struct {
+		text string
+		size int
+	}{
+		{"29123456789012", 21},
+		// Batas kapasitas versi 1 (14 byte) dan versi 2 (26 byte)
+		{strings.Repeat("7", 15), 25},
+		{strings.Repeat("7", 26), 25},
+		{"https://perpustakaan.id/cards/29123456789", 29},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			modules, err := QRCode(tt.text)
+			if err != nil {
+				t.Fatalf("QRCode: %v", err)
+			}
+			if len(modules) != tt.size {
+				t.Fatalf("size = %d, want %d", len(modules), tt.size)
+			}
+			for _, row := range modules {
+				if len(row) != tt.size {
+					t.Fatalf("row length = %d, want %d", len(row), tt.size)
+				}
+			}
+			// Pojok finder kiri atas, kanan atas dan kiri bawah selalu gelap
+			if !modules[0][0] || !modules[0][tt.size-1] || !modules[tt.size-1][0] {
+				t.Errorf("finder pattern corners are not dark")
+			}
+			if got := decodeQRCode(t, modules); got != tt.text {
+				t.Errorf("decoded text = %q, want %q", got, tt.text)
+			}
+		})
+	}
+}
+
+func TestQRCodeRejectsLongText(t *testing.T) {
+	if _, err := QRCode(strings.Repeat("a", 42)); err != nil {
+		t.Errorf("QRCode() of 42 bytes: %v", err)
+	}
+	if _, err := QRCode(strings.Repeat("a", 43)); err == nil {
+		t.Errorf("QRCode() of 43 bytes succeeded, want error")
+	}
+}
@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// SignURL menambahkan parameter expires dan signature (HMAC-SHA256 atas path dan waktu kedaluwarsa)
+// ke path, sehingga URL dapat dibuka tanpa token sampai waktu expires
+func SignURL(secret []byte, path string, expires time.Time) string {
+	exp := strconv.FormatInt(expires.Unix(), 10)
+	query := url.Values{}
+	query.Set("expires", exp)
+	query.Set("signature", urlSignature(secret, path, exp))
+	return path + "?" + query.Encode()
+}
+
+// VerifySignedURL memeriksa signature dan masa berlaku URL yang dibuat SignURL
+func VerifySignedURL(secret []byte, path string, query url.Values, now time.Time) bool {
+	exp := query.Get("expires")
+	expires, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || now.Unix() >= expires {
+		return false
+	}
+	expected := urlSignature(secret, path, exp)
+	return hmac.Equal([]byte(expected), []byte(query.Get("signature")))
+}
+
+func urlSignature(secret []byte, path, expires string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(path + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerifySignedURL(t *testing.T) {
+	secret := []byte("signing-key")
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	path := "/digital-loans/7/download"
+
+	signed := SignURL(secret, path, now.Add(time.Hour))
+	parsed, err := url.Parse(signed)
+	if err != nil {
+		t.Fatalf("SignURL returned an invalid URL %q: %v", signed, err)
+	}
+	if parsed.Path != path {
+		t.Fatalf("path = %q, want %q", parsed.Path, path)
+	}
+	valid := parsed.Query()
+
+	with := func(key, value string) url.Values {
+		query := url.Values{}
+		for k, v := range valid {
+			query[k] = v
+		}
+		query.Set(key, value)
+		return query
+	}
+	signature := valid.Get("signature")
+	flipped := "0"
+	if strings.HasPrefix(signature, "0") {
+		flipped = "1"
+	}
+
+	tests := []struct {
+		name   string
+		secret []byte
+		path   string
+		query  url.Values
+		now    time.Time
+		want   bool
+	}{
+		{"valid before expiry", secret, path, valid, now, true},
+		{"valid one second before expiry", secret, path, valid, now.Add(time.Hour - time.Second), true},
+		{"expired at expiry", secret, path, valid, now.Add(time.Hour), false},
+		{"expired after expiry", secret, path, valid, now.Add(2 * time.Hour), false},
+		{"other path", secret, "/digital-loans/8/download", valid, now, false},
+		{"other secret", []byte("other-key"), path, valid, now, false},
+		{"extended expiry", secret, path, with("expires", strconv.FormatInt(now.Add(24*time.Hour).Unix(), 10)), now, false},
+		{"tampered signature", secret, path, with("signature", flipped+signature[1:]), now, false},
+		{"missing signature", secret, path, with("signature", ""), now, false},
+		{"missing expiry", secret, path, with("expires", ""), now, false},
+		{"malformed expiry", secret, path, with("expires", "tomorrow"), now, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifySignedURL(tt.secret, tt.path, tt.query, tt.now); got != tt.want {
+				t.Errorf("VerifySignedURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
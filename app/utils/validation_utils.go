@@ -2,6 +2,7 @@ package utils
 
 import (
 	"regexp"
+	"strings"
 	"unicode"
 )
 
@@ -48,3 +49,85 @@ func ValidatePassword(password string) bool {
 func ValidateStringLength(str string, minLength, maxLength int) bool {
 	return len(str) >= minLength && len(str) <= maxLength
 }
+
+// NormalizeISBN menghapus tanda hubung dan spasi dari ISBN serta menyeragamkan huruf X
+func NormalizeISBN(isbn string) string {
+	var b strings.Builder
+	for _, char := range isbn {
+		switch {
+		case unicode.IsDigit(char):
+			b.WriteRune(char)
+		case char == 'x' || char == 'X':
+			b.WriteRune('X')
+		}
+	}
+	return b.String()
+}
+
+// ValidateISBN memvalidasi ISBN-10 atau ISBN-13 beserta check digit-nya
+func ValidateISBN(isbn string) bool {
+	isbn = NormalizeISBN(isbn)
+
+	switch len(isbn) {
+	case 10:
+		sum := 0
+		for i, char := range isbn {
+			var digit int
+			switch {
+			case char == 'X' && i == 9:
+				digit = 10
+			case unicode.IsDigit(char):
+				digit = int(char - '0')
+			default:
+				return false
+			}
+			sum += digit * (10 - i)
+		}
+		return sum%11 == 0
+	case 13:
+		sum := 0
+		for i, char := range isbn {
+			if !unicode.IsDigit(char) {
+				return false
+			}
+			digit := int(char - '0')
+			if i%2 == 1 {
+				digit *= 3
+			}
+			sum += digit
+		}
+		return sum%10 == 0
+	default:
+		return false
+	}
+}
+
+// LuhnCheckDigit menghitung check digit Luhn (mod 10) untuk deretan angka digits
+func LuhnCheckDigit(digits string) byte {
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		digit := int(digits[i] - '0')
+		// Digit paling kanan dari payload menempati posisi yang digandakan setelah check digit ditambahkan
+		if (len(digits)-1-i)%2 == 0 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// ValidateCardNumber memvalidasi nomor kartu anggota: hanya angka dengan check digit Luhn di posisi terakhir
+func ValidateCardNumber(number string) bool {
+	if len(number) < 2 {
+		return false
+	}
+	for _, char := range number {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+	return LuhnCheckDigit(number[:len(number)-1]) == number[len(number)-1]
+}
@@ -0,0 +1,42 @@
+package utils
+
+import "testing"
+
+func TestLuhnCheckDigit(t *testing.T) {
+	tests := []struct {
+		digits string
+		want   byte
+	}{
+		{"7992739871", '3'},
+		{"2900000000000", '7'},
+		{"2912345678901", '2'},
+		{"0", '0'},
+		{"", '0'},
+	}
+	for _, tt := range tests {
+		if got := LuhnCheckDigit(tt.digits); got != tt.want {
+			t.Errorf("LuhnCheckDigit(%q) = %q, want %q", tt.digits, got, tt.want)
+		}
+	}
+}
+
+func TestValidateCardNumber(t *testing.T) {
+	tests := []struct {
+		number string
+		want   bool
+	}{
+		{"79927398713", true},
+		{"29123456789012", true},
+		{"79927398710", false},    // check digit salah
+		{"79927389713", false},    // dua angka tertukar
+		{"2912345678901a", false}, // bukan angka
+		{"29 123456789012", false},
+		{"0", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := ValidateCardNumber(tt.number); got != tt.want {
+			t.Errorf("ValidateCardNumber(%q) = %v, want %v", tt.number, got, tt.want)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"time"
 
 	"Restful-Perpustakaan-API/app/config"
@@ -37,7 +38,7 @@ func main() {
 	handlers := initializeHandlers(services)
 
 	router := mux.NewRouter()
-	registerRoutes(router, handlers)
+	registerRoutes(router, handlers, services["auth"].IsAdmin)
 
 	log.Fatal(startServer(router, cfg))
 }
@@ -46,11 +47,30 @@ func main() {
 func initializeRepositories(db *sql.DB) map[string]repositories.Repository {
 	repos := make(map[string]repositories.Repository)
 
-	repos["book"] = repositories.NewBookRepository(db)
-	repos["member"] = repositories.NewMemberRepository(db)
+	repos["history"] = repositories.NewHistoryRepository(db)
+	repos["book"] = repositories.NewObservableBookRepository(repositories.NewVersionedBookRepository(repositories.NewBookRepository(db), repos["history"]))
+	repos["member"] = repositories.NewVersionedMemberRepository(repositories.NewMemberRepository(db), repos["history"])
 	repos["loan"] = repositories.NewLoanRepository(db)
 	repos["notification"] = repositories.NewNotificationRepository(db)
 	repos["review"] = repositories.NewReviewRepository(db)
+	repos["location"] = repositories.NewLocationRepository(db)
+	repos["copy"] = repositories.NewCopyRepository(db)
+	repos["work"] = repositories.NewWorkRepository(db)
+	repos["hold"] = repositories.NewHoldRepository(db)
+	repos["digital"] = repositories.NewDigitalRepository(db)
+	repos["readingHistory"] = repositories.NewReadingHistoryRepository(db)
+	repos["readingList"] = repositories.NewReadingListRepository(db)
+	repos["course"] = repositories.NewCourseRepository(db)
+	repos["suggestion"] = repositories.NewSuggestionRepository(db)
+	repos["acquisition"] = repositories.NewAcquisitionRepository(db)
+	repos["serial"] = repositories.NewSerialRepository(db)
+	repos["membership"] = repositories.NewVersionedMembershipRepository(repositories.NewMembershipRepository(db), repos["member"], repos["history"])
+	repos["card"] = repositories.NewCardRepository(db)
+	repos["household"] = repositories.NewVersionedHouseholdRepository(repositories.NewHouseholdRepository(db), repos["member"], repos["history"])
+	repos["profile"] = repositories.NewVersionedProfileRepository(repositories.NewProfileRepository(db), repos["member"], repos["history"])
+	repos["dataSubject"] = repositories.NewDataSubjectRepository(db)
+	repos["duplicate"] = repositories.NewVersionedDuplicateRepository(repositories.NewDuplicateRepository(db), repos["member"], repos["history"])
+	repos["block"] = repositories.NewBlockRepository(db)
 
 	return repos
 }
@@ -59,17 +79,118 @@ func initializeRepositories(db *sql.DB) map[string]repositories.Repository {
 func initializeServices(repos map[string]repositories.Repository, cfg config.Config) map[string]services.Service {
 	services := make(map[string]services.Service)
 
-	services["book"] = services.NewBookService(repos["book"].(repositories.BookRepository))
-	services["member"] = services.NewMemberService(repos["member"])
-	services["loan"] = services.NewLoanService(repos["loan"])
+	services["member"] = services.NewMemberService(repos["member"], cfg.MembershipMonths)
+	services["block"] = services.NewBlockService(repos["block"], repos["member"], repos["notification"], float64(cfg.FineBlockThreshold))
+	services["hold"] = services.NewHoldService(repos["hold"], repos["book"], repos["work"], repos["member"], repos["notification"], services["block"])
+	services["loan"] = services.NewLoanService(repos["loan"], repos["digital"], repos["member"], repos["course"], repos["copy"], services["hold"], services["block"], initializeLoanPolicy(cfg))
 	services["notification"] = services.NewNotificationService(repos["notification"])
 	services["review"] = services.NewReviewService(repos["review"])
 	services["auth"] = services.NewAuthService(repos["member"], []byte(cfg.JWTSecretKey))
-	services["admin"] = services.NewAdminService(repos["member"], repos["book"], repos["member"], repos["loan"])
+	services["admin"] = services.NewAdminService(repos["member"], repos["book"], repos["member"], repos["loan"], repos["acquisition"])
+	storage := initializeFileStorage(cfg)
+	services["cover"] = services.NewCoverService(repos["book"], storage, cfg.CoverMaxSize)
+	services["enrichment"] = services.NewEnrichmentService(repos["book"], initializeMetadataProviders(cfg), time.Duration(cfg.MetadataTimeout)*time.Second)
+	services["location"] = services.NewLocationService(repos["location"], repos["copy"])
+	services["copy"] = services.NewCopyService(repos["copy"], repos["book"], repos["location"])
+	services["work"] = services.NewWorkService(repos["work"], repos["book"])
+	services["suggestion"] = services.NewSuggestionService(repos["suggestion"], repos["book"], repos["notification"], services["hold"])
+	// Buku hasil usulan pengadaan yang sudah diterima langsung direservasi untuk pengusulnya, lewat jalur mana pun bukunya dibuat
+	repos["book"].(repositories.ObservableBookRepository).OnBookCreated(services["suggestion"].BookCatalogued)
+	services["book"] = services.NewBookService(repos["book"].(repositories.BookRepository), repos["loan"])
+	services["acquisition"] = services.NewAcquisitionService(repos["acquisition"], repos["book"], services["copy"])
+	services["serial"] = services.NewSerialService(repos["serial"], repos["acquisition"], repos["member"], repos["notification"], services["book"], services["copy"])
+	services["trash"] = services.NewTrashService(repos["book"], repos["member"], time.Duration(cfg.TrashRetentionDays)*24*time.Hour)
+	services["history"] = services.NewHistoryService(repos["history"], repos["book"], repos["member"])
+	services["digital"] = services.NewDigitalService(repos["digital"], repos["book"], repos["member"], repos["loan"], storage, services["block"], services.DigitalConfig{
+		LoanPeriod: time.Duration(cfg.DigitalLoanDays) * 24 * time.Hour,
+		LinkTTL:    time.Duration(cfg.DownloadLinkTTLMinutes) * time.Minute,
+		MaxSize:    cfg.DigitalMaxSize,
+		SigningKey: []byte(cfg.DownloadSigningKey),
+		MaxLoans:   cfg.MaxActiveLoans,
+	})
+	services["readingHistory"] = services.NewReadingHistoryService(repos["readingHistory"])
+	services["recommendation"] = services.NewRecommendationService(repos["book"], services["readingHistory"])
+	services["duplicate"] = services.NewDuplicateService(repos["duplicate"], repos["member"], repos["household"])
+	services["roster"] = services.NewRosterService(repos["member"], repos["profile"], initializeMailer(cfg), cfg.MembershipMonths, cfg.PasswordSetupURL, time.Duration(cfg.PasswordSetupHours)*time.Hour)
+	services["course"] = services.NewCourseService(repos["course"], repos["book"], repos["copy"])
+	services["readingList"] = services.NewReadingListService(repos["readingList"], repos["book"], repos["member"], repos["copy"], repos["loan"], services["hold"])
+	services["membership"] = services.NewMembershipService(repos["membership"], repos["member"], repos["notification"], services.MembershipConfig{
+		Months:         cfg.MembershipMonths,
+		RenewalFee:     float64(cfg.MembershipRenewalFee),
+		ReminderBefore: time.Duration(cfg.MembershipReminderDays) * 24 * time.Hour,
+	})
+	services["card"] = services.NewCardService(repos["card"], repos["member"])
+	services["household"] = services.NewHouseholdService(repos["household"], repos["member"], services["loan"], services["hold"])
+	services["inactivity"] = services.NewInactivityService(repos["member"], repos["membership"], repos["notification"], initializeMailer(cfg))
+	services["profile"] = services.NewProfileService(repos["profile"], repos["member"], repos["notification"], services["loan"], services["hold"], services["block"], initializeMailer(cfg), time.Duration(cfg.EmailVerificationHours)*time.Hour)
+	services["dataSubject"] = services.NewDataSubjectService(repos["dataSubject"], repos["profile"], repos["member"], repos["membership"], repos["review"], repos["notification"], services["loan"], services["digital"], services["readingHistory"])
+	services["trash"].StartPurgeJob(time.Duration(cfg.TrashPurgeIntervalHours) * time.Hour)
+	services["readingHistory"].StartAnonymizeJob(time.Duration(cfg.AnonymizeIntervalMinutes) * time.Minute)
+	services["membership"].StartExpiryJob(time.Duration(cfg.MembershipCheckIntervalHours) * time.Hour)
+	services["block"].StartFineBlockJob(time.Duration(cfg.BlockCheckIntervalHours) * time.Hour)
 
 	return services
 }
 
+// initializeLoanPolicy initializes the loan policy from the lending configuration.
+func initializeLoanPolicy(cfg config.Config) services.LoanPolicy {
+	periods, err := services.ParseLoanPeriods(cfg.LoanPeriods)
+	if err != nil {
+		log.Fatalf("Invalid LOAN_PERIODS: %v", err)
+	}
+	return services.LoanPolicy{
+		DefaultPeriod:    time.Duration(cfg.DefaultLoanDays) * 24 * time.Hour,
+		Periods:          periods,
+		OvernightDueHour: cfg.ReserveOvernightDueHour,
+		MaxLoans:         cfg.MaxActiveLoans,
+		MaxRenewals:      cfg.MaxRenewals,
+	}
+}
+
+// initializeFileStorage initializes the file storage selected by the STORAGE_DRIVER configuration.
+func initializeFileStorage(cfg config.Config) repositories.FileStorage {
+	if cfg.StorageDriver == "s3" {
+		return repositories.NewS3FileStorage(repositories.S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+		})
+	}
+	return repositories.NewLocalFileStorage(cfg.StorageLocalPath)
+}
+
+// initializeMailer initializes the mailer selected by the EMAIL_DRIVER configuration.
+func initializeMailer(cfg config.Config) services.Mailer {
+	if cfg.EmailDriver == "smtp" {
+		return services.NewSMTPMailer(cfg.EmailHost, cfg.EmailPort, cfg.EmailUser, cfg.EmailPassword, cfg.EmailFrom)
+	}
+	return services.NewLogMailer()
+}
+
+// initializeMetadataProviders initializes the metadata providers listed in METADATA_PROVIDERS, in priority order.
+func initializeMetadataProviders(cfg config.Config) []services.MetadataProvider {
+	var transport http.RoundTripper
+	if cfg.MetadataFixtureDir != "" {
+		transport = &services.FixtureTransport{Dir: cfg.MetadataFixtureDir}
+	}
+
+	var providers []services.MetadataProvider
+	for _, name := range strings.Split(cfg.MetadataProviders, ",") {
+		switch strings.TrimSpace(name) {
+		case "openlibrary":
+			providers = append(providers, services.NewOpenLibraryProvider(cfg.OpenLibraryBaseURL, time.Duration(cfg.MetadataTimeout)*time.Second, cfg.MetadataRetries, transport))
+		case "mirror":
+			providers = append(providers, services.NewMirrorFileProvider(cfg.MetadataMirrorPath))
+		case "":
+		default:
+			log.Printf("Unknown metadata provider %q ignored", name)
+		}
+	}
+	return providers
+}
+
 // initializeHandlers initializes the handlers with the given services.
 func initializeHandlers(services map[string]services.Service) map[string]handlers.Handler {
 	handlers := make(map[string]handlers.Handler)
@@ -81,23 +202,100 @@ func initializeHandlers(services map[string]services.Service) map[string]handler
 	handlers["review"] = handlers.NewReviewHandler(services["review"])
 	handlers["auth"] = handlers.NewAuthHandler(services["auth"])
 	handlers["admin"] = handlers.NewAdminHandler(services["admin"])
+	handlers["cover"] = handlers.NewCoverHandler(services["cover"])
+	handlers["enrichment"] = handlers.NewEnrichmentHandler(services["enrichment"])
+	handlers["location"] = handlers.NewLocationHandler(services["location"])
+	handlers["copy"] = handlers.NewCopyHandler(services["copy"])
+	handlers["work"] = handlers.NewWorkHandler(services["work"])
+	handlers["hold"] = handlers.NewHoldHandler(services["hold"])
+	handlers["trash"] = handlers.NewTrashHandler(services["trash"], services["book"], services["member"])
+	handlers["history"] = handlers.NewHistoryHandler(services["history"])
+	handlers["digital"] = handlers.NewDigitalHandler(services["digital"])
+	handlers["readingHistory"] = handlers.NewReadingHistoryHandler(services["readingHistory"])
+	handlers["recommendation"] = handlers.NewRecommendationHandler(services["recommendation"])
+	handlers["readingList"] = handlers.NewReadingListHandler(services["readingList"])
+	handlers["course"] = handlers.NewCourseHandler(services["course"])
+	handlers["suggestion"] = handlers.NewSuggestionHandler(services["suggestion"])
+	handlers["acquisition"] = handlers.NewAcquisitionHandler(services["acquisition"])
+	handlers["serial"] = handlers.NewSerialHandler(services["serial"])
+	handlers["membership"] = handlers.NewMembershipHandler(services["membership"])
+	handlers["card"] = handlers.NewCardHandler(services["card"])
+	handlers["household"] = handlers.NewHouseholdHandler(services["household"])
+	handlers["profile"] = handlers.NewProfileHandler(services["profile"])
+	handlers["dataSubject"] = handlers.NewDataSubjectHandler(services["dataSubject"])
+	handlers["duplicate"] = handlers.NewDuplicateHandler(services["duplicate"])
+	handlers["roster"] = handlers.NewRosterHandler(services["roster"])
+	handlers["block"] = handlers.NewBlockHandler(services["block"])
+	handlers["inactivity"] = handlers.NewInactivityHandler(services["inactivity"])
 
 	return handlers
 }
 
-// registerRoutes registers the routes with the given router and handlers.
-func registerRoutes(router *mux.Router, handlers map[string]handlers.Handler) {
+// registerRoutes registers the routes with the given router and handlers; isAdmin decides who may use the admin routes.
+func registerRoutes(router *mux.Router, handlers map[string]handlers.Handler, isAdmin func(subject string) (bool, error)) {
 	// Book routes
 	router.HandleFunc("/books", handlers["book"].GetAllBooks).Methods("GET")
 	router.HandleFunc("/books/{id}", handlers["book"].GetBookByID).Methods("GET")
 	router.HandleFunc("/books/search", handlers["book"].SearchBooks).Methods("GET")
 	router.HandleFunc("/books/{id}/reviews", handlers["review"].GetReviewsForBook).Methods("GET")
+	router.HandleFunc("/books/{id}/marc", handlers["book"].ExportMarc).Methods("GET")
+	router.HandleFunc("/books/{id}/cover", handlers["cover"].GetCover).Methods("GET")
+	router.HandleFunc("/books/{id}/copies", handlers["copy"].GetCopiesByBookID).Methods("GET")
+	router.HandleFunc("/books/{id}/holds", handlers["hold"].GetHoldQueue).Methods("GET")
+	router.HandleFunc("/books/{id}/digital", handlers["digital"].GetDigitalItems).Methods("GET")
+
+	// Digital lending routes; the download route is authorized by its signature
+	router.HandleFunc("/digital-loans/{id}/download", handlers["digital"].Download).Methods("GET")
+
+	// Peminjaman dan tautan unduhan baru hanya untuk anggota yang login; unduhannya sendiri diamankan oleh tanda tangan tautan
+	digitalLoanRouter := router.NewRoute().Subrouter()
+	digitalLoanRouter.Use(middleware.AuthMiddleware)
+	digitalLoanRouter.HandleFunc("/digital/{id}/loans", handlers["digital"].BorrowDigitalItem).Methods("POST")
+	digitalLoanRouter.HandleFunc("/digital-loans/{id}/link", handlers["digital"].CreateDownloadLink).Methods("POST")
+
+	// Work and series routes
+	router.HandleFunc("/works", handlers["work"].GetAllWorks).Methods("GET")
+	router.HandleFunc("/works/{id}", handlers["work"].GetWorkByID).Methods("GET")
+	router.HandleFunc("/works/{id}/rating", handlers["work"].GetWorkRating).Methods("GET")
+	router.HandleFunc("/series", handlers["work"].GetAllSeries).Methods("GET")
+	router.HandleFunc("/series/{id}", handlers["work"].GetSeriesByID).Methods("GET")
+
+	// Rute khusus admin di luar /admin: riwayat versi memuat data pribadi anggota dan pengayaan ISBN dapat membuat buku
+	staffRouter := router.NewRoute().Subrouter()
+	staffRouter.Use(middleware.AuthMiddleware, middleware.AdminMiddleware(isAdmin))
+	staffRouter.HandleFunc("/books/{id}/history", handlers["history"].GetBookHistory).Methods("GET")
+	staffRouter.HandleFunc("/books/{id}/history/diff", handlers["history"].DiffBookVersions).Methods("GET")
+	staffRouter.HandleFunc("/books/{id}/history/{version}/revert", handlers["history"].RevertBook).Methods("POST")
+	staffRouter.HandleFunc("/members/{id}/history", handlers["history"].GetMemberHistory).Methods("GET")
+	staffRouter.HandleFunc("/members/{id}/history/diff", handlers["history"].DiffMemberVersions).Methods("GET")
+	staffRouter.HandleFunc("/members/{id}/history/{version}/revert", handlers["history"].RevertMember).Methods("POST")
+	staffRouter.HandleFunc("/books/enrich/{isbn}", handlers["enrichment"].EnrichISBN).Methods("POST")
+
+	// Hold routes; the token identifies the member placing, cancelling or viewing holds
+	holdRouter := router.NewRoute().Subrouter()
+	holdRouter.Use(middleware.AuthMiddleware)
+	holdRouter.HandleFunc("/holds", handlers["hold"].PlaceHold).Methods("POST")
+	holdRouter.HandleFunc("/holds/{id}", handlers["hold"].CancelHold).Methods("DELETE")
+	holdRouter.HandleFunc("/works/{id}/holds", handlers["hold"].PlaceWorkHold).Methods("POST")
+	holdRouter.HandleFunc("/members/{id}/holds", handlers["hold"].GetHoldsByMemberID).Methods("GET")
+
+	// Course reserve routes
+	router.HandleFunc("/terms", handlers["course"].GetAllTerms).Methods("GET")
+	router.HandleFunc("/terms/{id}", handlers["course"].GetTermByID).Methods("GET")
+	router.HandleFunc("/courses", handlers["course"].GetAllCourses).Methods("GET")
+	router.HandleFunc("/courses/{id}", handlers["course"].GetCourseByID).Methods("GET")
+	router.HandleFunc("/courses/{id}/reserves", handlers["course"].GetReserves).Methods("GET")
+
+	// Location routes
+	router.HandleFunc("/locations", handlers["location"].GetAllLocations).Methods("GET")
+	router.HandleFunc("/locations/{id}", handlers["location"].GetLocationByID).Methods("GET")
+	router.HandleFunc("/locations/{id}/books", handlers["location"].GetShelfList).Methods("GET")
 
 	// Member routes
-	router.HandleFunc("/members", handlers["member"].GetAllMembers).Methods("GET")
+	router.HandleFunc("/members", handlers["member"].SearchMembers).Methods("GET")
 	router.HandleFunc("/members/{id}", handlers["member"].GetMemberByID).Methods("GET")
-	router.HandleFunc("/members/search", handlers["member"].SearchMembers).Methods("GET")
 	router.HandleFunc("/members/{id}/loans", handlers["loan"].GetLoansByMemberID).Methods("GET")
+	router.HandleFunc("/members/{id}/digital-loans", handlers["digital"].GetDigitalLoansByMemberID).Methods("GET")
 
 	// Loan routes
 	router.HandleFunc("/loans", handlers["loan"].GetAllLoans).Methods("GET")
@@ -105,10 +303,45 @@ func registerRoutes(router *mux.Router, handlers map[string]handlers.Handler) {
 	router.HandleFunc("/loans/overdue", handlers["loan"].GetOverdueLoans).Methods("GET")
 	router.HandleFunc("/loans/member/{memberId}", handlers["loan"].GetLoansByMemberID).Methods("GET")
 
+	// Anggota hanya boleh memperpanjang peminjamannya sendiri; perpanjangan di meja layanan memakai rute admin
+	loanRouter := router.NewRoute().Subrouter()
+	loanRouter.Use(middleware.AuthMiddleware)
+	loanRouter.HandleFunc("/loans/{id}/renew", handlers["loan"].RenewMyLoan).Methods("POST")
+
 	// Notification routes
 	router.HandleFunc("/notifications", handlers["notification"].GetAllNotifications).Methods("GET")
 	router.HandleFunc("/notifications/member/{memberId}", handlers["notification"].GetNotificationsByMemberID).Methods("GET")
 
+	// Reading list routes; a token is optional and identifies the owner, anonymous visitors only see public lists
+	listRouter := router.NewRoute().Subrouter()
+	listRouter.Use(middleware.OptionalAuthMiddleware)
+	listRouter.HandleFunc("/lists", handlers["readingList"].GetPublicLists).Methods("GET")
+	listRouter.HandleFunc("/lists/shared/{token}", handlers["readingList"].GetSharedList).Methods("GET")
+	listRouter.HandleFunc("/lists/shared/{token}/holds", handlers["readingList"].PlaceSharedHolds).Methods("POST")
+	listRouter.HandleFunc("/members/{id}/lists", handlers["readingList"].GetLists).Methods("GET")
+	listRouter.HandleFunc("/members/{id}/lists", handlers["readingList"].CreateList).Methods("POST")
+	listRouter.HandleFunc("/members/{id}/lists/{listId}", handlers["readingList"].GetList).Methods("GET")
+	listRouter.HandleFunc("/members/{id}/lists/{listId}", handlers["readingList"].UpdateList).Methods("PUT")
+	listRouter.HandleFunc("/members/{id}/lists/{listId}", handlers["readingList"].DeleteList).Methods("DELETE")
+	listRouter.HandleFunc("/members/{id}/lists/{listId}/share", handlers["readingList"].RegenerateShareLink).Methods("POST")
+	listRouter.HandleFunc("/members/{id}/lists/{listId}/order", handlers["readingList"].ReorderItems).Methods("PUT")
+	listRouter.HandleFunc("/members/{id}/lists/{listId}/items", handlers["readingList"].AddItem).Methods("POST")
+	listRouter.HandleFunc("/members/{id}/lists/{listId}/items/{itemId}", handlers["readingList"].UpdateItem).Methods("PUT")
+	listRouter.HandleFunc("/members/{id}/lists/{listId}/items/{itemId}", handlers["readingList"].RemoveItem).Methods("DELETE")
+	listRouter.HandleFunc("/members/{id}/lists/{listId}/holds", handlers["readingList"].PlaceHolds).Methods("POST")
+
+	// Purchase suggestion routes; a token identifies the requester or voter
+	suggestionRouter := router.NewRoute().Subrouter()
+	suggestionRouter.Use(middleware.OptionalAuthMiddleware)
+	suggestionRouter.HandleFunc("/suggestions", handlers["suggestion"].GetSuggestions).Methods("GET")
+	suggestionRouter.HandleFunc("/suggestions", handlers["suggestion"].CreateSuggestion).Methods("POST")
+	suggestionRouter.HandleFunc("/suggestions/{id}", handlers["suggestion"].GetSuggestionByID).Methods("GET")
+	suggestionRouter.HandleFunc("/suggestions/{id}/votes", handlers["suggestion"].Vote).Methods("POST")
+	suggestionRouter.HandleFunc("/suggestions/{id}/votes", handlers["suggestion"].Unvote).Methods("DELETE")
+
+	// Recommendation routes
+	router.HandleFunc("/recommendations", handlers["recommendation"].GetRecommendations).Methods("GET")
+
 	// Review routes
 	router.HandleFunc("/reviews", handlers["review"].GetAllReviews).Methods("GET")
 	router.HandleFunc("/reviews/book/{bookId}/average-rating", handlers["review"].GetAverageRatingForBook).Methods("GET")
@@ -116,6 +349,7 @@ func registerRoutes(router *mux.Router, handlers map[string]handlers.Handler) {
 	// Auth routes
 	router.HandleFunc("/login", handlers["auth"].Login).Methods("POST")
 	router.HandleFunc("/register", handlers["auth"].Register).Methods("POST")
+	router.HandleFunc("/password/setup", handlers["roster"].SetupPassword).Methods("POST")
 
 	// Admin routes (publicly accessible)
 	router.HandleFunc("/admin/dashboard", handlers["admin"].GetDashboardData).Methods("GET")
@@ -129,6 +363,160 @@ func registerRoutes(router *mux.Router, handlers map[string]handlers.Handler) {
 	// Register routes that require authentication
 	authenticatedRouter.HandleFunc("/admin/dashboard", handlers["admin"].GetDashboardData).Methods("GET")
 	authenticatedRouter.HandleFunc("/admin/books", handlers["admin"].ManageBooks).Methods("GET", "POST", "PUT", "DELETE")
+
+	// Routes for the logged-in member; the member is identified by the JWT subject
+	meRouter := router.PathPrefix("/me").Subrouter()
+	meRouter.Use(middleware.AuthMiddleware)
+	meRouter.HandleFunc("", handlers["profile"].GetMyProfile).Methods("GET")
+	meRouter.HandleFunc("", handlers["profile"].UpdateMyProfile).Methods("PATCH")
+	meRouter.HandleFunc("/password", handlers["profile"].ChangeMyPassword).Methods("PUT")
+	meRouter.HandleFunc("/email", handlers["profile"].RequestEmailChange).Methods("POST")
+	meRouter.HandleFunc("/email/verify", handlers["profile"].VerifyEmailChange).Methods("POST")
+	meRouter.HandleFunc("/dashboard", handlers["profile"].GetMyDashboard).Methods("GET")
+	meRouter.HandleFunc("/blocks", handlers["block"].GetMyBlocks).Methods("GET")
+	meRouter.HandleFunc("/card", handlers["card"].GetMyCard).Methods("GET")
+	meRouter.HandleFunc("/export", handlers["dataSubject"].ExportMyData).Methods("GET")
+	meRouter.HandleFunc("/erasure", handlers["dataSubject"].GetMyErasureRequests).Methods("GET")
+	meRouter.HandleFunc("/erasure", handlers["dataSubject"].RequestErasure).Methods("POST")
+	meRouter.HandleFunc("/reading-history", handlers["readingHistory"].GetMyReadingHistory).Methods("GET")
+	meRouter.HandleFunc("/recommendations", handlers["recommendation"].GetMyRecommendations).Methods("GET")
+	meRouter.HandleFunc("/privacy", handlers["readingHistory"].GetMyPrivacySettings).Methods("GET")
+	meRouter.HandleFunc("/privacy", handlers["readingHistory"].UpdateMyPrivacySettings).Methods("PUT")
+	meRouter.HandleFunc("/suggestions", handlers["suggestion"].GetMySuggestions).Methods("GET")
+	meRouter.HandleFunc("/household", handlers["household"].GetMyHousehold).Methods("GET")
+	meRouter.HandleFunc("/household/loans", handlers["household"].GetDependentLoans).Methods("GET")
+	meRouter.HandleFunc("/household/fines", handlers["household"].GetMyHouseholdFines).Methods("GET")
+	meRouter.HandleFunc("/household/dependents/{memberId}/consent", handlers["household"].GiveConsent).Methods("PUT")
+	meRouter.HandleFunc("/household/dependents/{memberId}/consent", handlers["household"].WithdrawConsent).Methods("DELETE")
+	meRouter.HandleFunc("/household/dependents/{memberId}/holds", handlers["household"].PlaceHoldFor).Methods("POST")
+	meRouter.HandleFunc("/household/dependents/{memberId}/loans/{loanId}/renew", handlers["household"].RenewFor).Methods("POST")
+
+	// Admin routes; only members with the admin role may use them and the JWT subject is recorded as the actor
+	adminRouter := router.PathPrefix("/admin").Subrouter()
+	adminRouter.Use(middleware.AuthMiddleware, middleware.AdminMiddleware(isAdmin))
+	adminRouter.HandleFunc("/erasure-requests", handlers["dataSubject"].GetErasureRequests).Methods("GET")
+	adminRouter.HandleFunc("/erasure-requests/{id}/approve", handlers["dataSubject"].ApproveErasure).Methods("POST")
+	adminRouter.HandleFunc("/erasure-requests/{id}/reject", handlers["dataSubject"].RejectErasure).Methods("POST")
+	adminRouter.HandleFunc("/members/duplicates", handlers["duplicate"].GetDuplicates).Methods("GET")
+	adminRouter.HandleFunc("/members/duplicates/dismiss", handlers["duplicate"].DismissDuplicate).Methods("POST")
+	adminRouter.HandleFunc("/members/{id}/merge", handlers["duplicate"].MergeMember).Methods("POST")
+	adminRouter.HandleFunc("/members/{id}/merges", handlers["duplicate"].GetMerges).Methods("GET")
+	adminRouter.HandleFunc("/books/{id}", handlers["book"].UpdateBook).Methods("PUT")
+	adminRouter.HandleFunc("/books/{id}", handlers["trash"].DeleteBook).Methods("DELETE")
+	adminRouter.HandleFunc("/members/{id}", handlers["trash"].DeleteMember).Methods("DELETE")
+	adminRouter.HandleFunc("/trash", handlers["trash"].GetTrash).Methods("GET")
+	adminRouter.HandleFunc("/trash/books/{id}/restore", handlers["trash"].RestoreBook).Methods("POST")
+	adminRouter.HandleFunc("/trash/members/{id}/restore", handlers["trash"].RestoreMember).Methods("POST")
+	adminRouter.HandleFunc("/trash/purge", handlers["trash"].Purge).Methods("POST")
+	adminRouter.HandleFunc("/members/import", handlers["roster"].ImportMembers).Methods("POST")
+	adminRouter.HandleFunc("/members/{id}/blocks", handlers["block"].GetMemberBlocks).Methods("GET")
+	adminRouter.HandleFunc("/members/{id}/blocks", handlers["block"].CreateBlock).Methods("POST")
+	adminRouter.HandleFunc("/blocks", handlers["block"].GetActiveBlocks).Methods("GET")
+	adminRouter.HandleFunc("/blocks/refresh", handlers["block"].RefreshFineBlocks).Methods("POST")
+	adminRouter.HandleFunc("/blocks/{id}/lift", handlers["block"].LiftBlock).Methods("POST")
+	adminRouter.HandleFunc("/reports/inactive-members", handlers["inactivity"].GetInactiveMembers).Methods("GET")
+	adminRouter.HandleFunc("/reports/inactive-members/actions", handlers["inactivity"].ApplyAction).Methods("POST")
+	adminRouter.HandleFunc("/members/{id}/membership", handlers["membership"].GetMembership).Methods("GET")
+	adminRouter.HandleFunc("/members/{id}/membership/status", handlers["membership"].UpdateStatus).Methods("PUT")
+	adminRouter.HandleFunc("/members/{id}/membership/renew", handlers["membership"].Renew).Methods("POST")
+	adminRouter.HandleFunc("/members/{id}/membership/renewals", handlers["membership"].GetRenewals).Methods("GET")
+	adminRouter.HandleFunc("/members/{id}/fines", handlers["membership"].GetFineLedger).Methods("GET")
+	adminRouter.HandleFunc("/memberships/expire", handlers["membership"].RunExpiry).Methods("POST")
+
+	// Serial routes
+	adminRouter.HandleFunc("/serials", handlers["serial"].GetAllSerials).Methods("GET")
+	adminRouter.HandleFunc("/serials", handlers["serial"].CreateSerial).Methods("POST")
+	adminRouter.HandleFunc("/serials/claims", handlers["serial"].GetClaims).Methods("GET")
+	adminRouter.HandleFunc("/serials/{id}", handlers["serial"].GetSerialByID).Methods("GET")
+	adminRouter.HandleFunc("/serials/{id}", handlers["serial"].UpdateSerial).Methods("PUT")
+	adminRouter.HandleFunc("/serials/{id}", handlers["serial"].DeleteSerial).Methods("DELETE")
+	adminRouter.HandleFunc("/serials/{id}/issues", handlers["serial"].GetIssues).Methods("GET")
+	adminRouter.HandleFunc("/serials/{id}/issues/predict", handlers["serial"].PredictIssues).Methods("POST")
+	adminRouter.HandleFunc("/serials/{id}/routing", handlers["serial"].GetRouting).Methods("GET")
+	adminRouter.HandleFunc("/serials/{id}/routing", handlers["serial"].SetRouting).Methods("PUT")
+	adminRouter.HandleFunc("/serial-issues/{id}/checkin", handlers["serial"].CheckInIssue).Methods("POST")
+	adminRouter.HandleFunc("/serial-issues/{id}/claim", handlers["serial"].ClaimIssue).Methods("POST")
+	adminRouter.HandleFunc("/suggestions/{id}/status", handlers["suggestion"].UpdateStatus).Methods("PUT")
+	adminRouter.HandleFunc("/books/enrich", handlers["enrichment"].StartReenrichment).Methods("POST")
+	adminRouter.HandleFunc("/books/enrich/{id}", handlers["enrichment"].GetReenrichmentJob).Methods("GET")
+	adminRouter.HandleFunc("/books/import", handlers["book"].ImportBooks).Methods("POST")
+	adminRouter.HandleFunc("/books/export", handlers["book"].ExportBooks).Methods("GET")
+	adminRouter.HandleFunc("/books/import/marc", handlers["book"].ImportMarc).Methods("POST")
+	adminRouter.HandleFunc("/books/export/marc", handlers["book"].ExportCatalogMarc).Methods("GET")
+
+	// Acquisition routes
+	adminRouter.HandleFunc("/vendors", handlers["acquisition"].GetAllVendors).Methods("GET")
+	adminRouter.HandleFunc("/vendors", handlers["acquisition"].CreateVendor).Methods("POST")
+	adminRouter.HandleFunc("/vendors/{id}", handlers["acquisition"].GetVendorByID).Methods("GET")
+	adminRouter.HandleFunc("/vendors/{id}", handlers["acquisition"].UpdateVendor).Methods("PUT")
+	adminRouter.HandleFunc("/vendors/{id}", handlers["acquisition"].DeleteVendor).Methods("DELETE")
+	adminRouter.HandleFunc("/funds", handlers["acquisition"].GetFunds).Methods("GET")
+	adminRouter.HandleFunc("/funds", handlers["acquisition"].CreateFund).Methods("POST")
+	adminRouter.HandleFunc("/funds/{id}", handlers["acquisition"].GetFundByID).Methods("GET")
+	adminRouter.HandleFunc("/funds/{id}", handlers["acquisition"].UpdateFund).Methods("PUT")
+	adminRouter.HandleFunc("/funds/{id}", handlers["acquisition"].DeleteFund).Methods("DELETE")
+	adminRouter.HandleFunc("/orders", handlers["acquisition"].GetOrders).Methods("GET")
+	adminRouter.HandleFunc("/orders", handlers["acquisition"].CreateOrder).Methods("POST")
+	adminRouter.HandleFunc("/orders/{id}", handlers["acquisition"].GetOrderByID).Methods("GET")
+	adminRouter.HandleFunc("/orders/{id}/lines", handlers["acquisition"].AddOrderLine).Methods("POST")
+	adminRouter.HandleFunc("/orders/{id}/cancel", handlers["acquisition"].CancelOrder).Methods("POST")
+	adminRouter.HandleFunc("/orders/{id}/receive", handlers["acquisition"].ReceiveOrder).Methods("POST")
+	adminRouter.HandleFunc("/reports/budget", handlers["acquisition"].GetBudgetReport).Methods("GET")
+
+	// Desk loan routes
+	adminRouter.HandleFunc("/loans/{id}/renew", handlers["loan"].RenewLoan).Methods("POST")
+
+	// Library card routes; the desk scanner and kiosk look cards up with a staff token
+	adminRouter.HandleFunc("/cards/{number}", handlers["card"].LookupCard).Methods("GET")
+	adminRouter.HandleFunc("/members/{id}/card", handlers["card"].GetCard).Methods("GET")
+	adminRouter.HandleFunc("/members/{id}/cards", handlers["card"].GetCards).Methods("GET")
+	adminRouter.HandleFunc("/members/{id}/card", handlers["card"].IssueCard).Methods("POST")
+	adminRouter.HandleFunc("/members/{id}/card/replace", handlers["card"].ReplaceCard).Methods("POST")
+
+	// Household routes
+	adminRouter.HandleFunc("/households", handlers["household"].GetHouseholds).Methods("GET")
+	adminRouter.HandleFunc("/households", handlers["household"].CreateHousehold).Methods("POST")
+	adminRouter.HandleFunc("/households/{id}", handlers["household"].GetHousehold).Methods("GET")
+	adminRouter.HandleFunc("/households/{id}", handlers["household"].DeleteHousehold).Methods("DELETE")
+	adminRouter.HandleFunc("/households/{id}/fines", handlers["household"].GetHouseholdFines).Methods("GET")
+	adminRouter.HandleFunc("/households/{id}/members", handlers["household"].AddMember).Methods("POST")
+	adminRouter.HandleFunc("/households/{id}/members/{memberId}", handlers["household"].UpdateMember).Methods("PUT")
+	adminRouter.HandleFunc("/households/{id}/members/{memberId}", handlers["household"].RemoveMember).Methods("DELETE")
+
+	// Cover upload route
+	adminRouter.HandleFunc("/books/{id}/cover", handlers["cover"].UploadCover).Methods("PUT")
+
+	// Copy and location routes
+	adminRouter.HandleFunc("/books/{id}/copies", handlers["copy"].CreateCopy).Methods("POST")
+	adminRouter.HandleFunc("/copies/{id}", handlers["copy"].UpdateCopy).Methods("PUT")
+	adminRouter.HandleFunc("/copies/{id}", handlers["copy"].DeleteCopy).Methods("DELETE")
+	adminRouter.HandleFunc("/locations", handlers["location"].CreateLocation).Methods("POST")
+	adminRouter.HandleFunc("/locations/{id}", handlers["location"].UpdateLocation).Methods("PUT")
+	adminRouter.HandleFunc("/locations/{id}", handlers["location"].DeleteLocation).Methods("DELETE")
+
+	// Work and series routes
+	adminRouter.HandleFunc("/works", handlers["work"].CreateWork).Methods("POST")
+	adminRouter.HandleFunc("/works/{id}", handlers["work"].UpdateWork).Methods("PUT")
+	adminRouter.HandleFunc("/works/{id}", handlers["work"].DeleteWork).Methods("DELETE")
+	adminRouter.HandleFunc("/works/{id}/editions", handlers["work"].AddEdition).Methods("POST")
+	adminRouter.HandleFunc("/works/{id}/editions/{bookId}", handlers["work"].RemoveEdition).Methods("DELETE")
+	adminRouter.HandleFunc("/series", handlers["work"].CreateSeries).Methods("POST")
+	adminRouter.HandleFunc("/series/{id}", handlers["work"].UpdateSeries).Methods("PUT")
+	adminRouter.HandleFunc("/series/{id}", handlers["work"].DeleteSeries).Methods("DELETE")
+
+	// Digital item routes
+	adminRouter.HandleFunc("/books/{id}/digital", handlers["digital"].UploadDigitalItem).Methods("POST")
+	adminRouter.HandleFunc("/digital/{id}", handlers["digital"].DeleteDigitalItem).Methods("DELETE")
+
+	// Course reserve routes
+	adminRouter.HandleFunc("/terms", handlers["course"].CreateTerm).Methods("POST")
+	adminRouter.HandleFunc("/terms/{id}", handlers["course"].UpdateTerm).Methods("PUT")
+	adminRouter.HandleFunc("/terms/{id}", handlers["course"].DeleteTerm).Methods("DELETE")
+	adminRouter.HandleFunc("/courses", handlers["course"].CreateCourse).Methods("POST")
+	adminRouter.HandleFunc("/courses/{id}", handlers["course"].UpdateCourse).Methods("PUT")
+	adminRouter.HandleFunc("/courses/{id}", handlers["course"].DeleteCourse).Methods("DELETE")
+	adminRouter.HandleFunc("/courses/{id}/reserves", handlers["course"].AddReserve).Methods("POST")
+	adminRouter.HandleFunc("/reserves/{id}", handlers["course"].DeleteReserve).Methods("DELETE")
 }
 
 // startServer starts the server with the given router and configuration.
@@ -252,20 +252,6 @@ func (db *Database) DeleteLoanHistory(id int) error {
 	return errors.New("loan history not found")
 }
 
-// Example method to find members by name
-func (db *Database) GetMembersByName(name string) ([]Member, error) {
-	var result []Member
-	for _, m := range db.members {
-		if m.Name == name {
-			result = append(result, m)
-		}
-	}
-	if len(result) == 0 {
-		return nil, errors.New("member not found")
-	}
-	return result, nil
-}
-
 // Example method to find books by title
 func (db *Database) GetBooksByTitle(title string) ([]Book, error) {
 	var result []Book
@@ -290,56 +276,6 @@ func (db *Database) GetMemberByEmail(email string) (*Member, error) {
 	return nil, errors.New("member not found")
 }
 
-// GetMemberByAddress returns a member by address
-func (db *Database) GetMemberByAddress(address string) (*Member, error) {
-	for _, m := range db.members {
-		if m.Address == address {
-			return &m, nil
-		}
-	}
-	return nil, errors.New("member not found")
-}
-
-// GetMemberByPhoneNumber returns a member by phone number
-func (db *Database) GetMemberByPhoneNumber(phoneNumber string) (*Member, error) {
-	for _, m := range db.members {
-		if m.PhoneNumber == phoneNumber {
-			return &m, nil
-		}
-	}
-	return nil, errors.New("member not found")
-}
-
-// GetMemberByGender returns a member by gender
-func (db *Database) GetMemberByGender(gender string) (*Member, error) {
-	for _, m := range db.members {
-		if m.Gender == gender {
-			return &m, nil
-		}
-	}
-	return nil, errors.New("member not found")
-}
-
-// GetMemberByMembershipType returns a member by membership type
-func (db *Database) GetMembersByMembershipType(membershipType string) (*Member, error) {
-	for _, m := range db.members {
-		if m.MembershipType == membershipType {
-			return &m, nil
-		}
-	}
-	return nil, errors.New("member not found")
-}
-
-// GetMembersByRegistrationDate returns a member by registration date
-func (db *Database) GetMembersByRegistrationDate(registrationDate time.Time) (*Member, error) {
-	for _, m := range db.members {
-		if m.RegistrationDate == registrationDate {
-			return &m, nil
-		}
-	}
-	return nil, errors.New("member not found")
-}
-
 // GetAllNotifications returns all notifications
 func (db *Database) GetAllNotifications() ([]Notification, error) {
 	return db.notifications, nil
@@ -398,11 +334,6 @@ func (db *Database) DeleteNotification(id int) error {
 	return errors.New("notification not found")
 }
 
-func (db *Database) GetMembersByLastLoginDate(date time.Time) (interface{}, interface{}) {
-	return db.members, nil
-
-}
-
 // GetAllMembers returns all members
 func (db *Database) GetAllMembers() ([]Member, error) {
 	return db.members, nil